@@ -84,6 +84,7 @@ func runHookPreReceive(c *cli.Context) error {
 	// the environment setted on serv command
 	repoID, _ := strconv.ParseInt(os.Getenv(models.ProtectedBranchRepoID), 10, 64)
 	isWiki := (os.Getenv(models.EnvRepoIsWiki) == "true")
+	userID, _ := strconv.ParseInt(os.Getenv(models.EnvPusherID), 10, 64)
 	//username := os.Getenv(models.EnvRepoUsername)
 	//reponame := os.Getenv(models.EnvRepoName)
 	//repoPath := models.RepoPath(username, reponame)
@@ -104,7 +105,7 @@ func runHookPreReceive(c *cli.Context) error {
 			continue
 		}
 
-		//oldCommitID := string(fields[0])
+		oldCommitID := string(fields[0])
 		newCommitID := string(fields[1])
 		refFullName := string(fields[2])
 
@@ -126,8 +127,12 @@ func runHookPreReceive(c *cli.Context) error {
 			log.GitLogger.Fatal(2, "retrieve protected branches information failed")
 		}
 
-		if protectBranch != nil {
-			if !protectBranch.CanPush {
+		if protectBranch != nil && protectBranch.ID > 0 {
+			canPush, err := private.CanUserPush(protectBranch.ID, userID)
+			if err != nil {
+				fail("Internal error", "Failed to check push permission of protected branch %s: %v", branchName, err)
+			}
+			if !canPush {
 				// check and deletion
 				if newCommitID == git.EmptySHA {
 					fail(fmt.Sprintf("branch %s is protected from deletion", branchName), "")
@@ -136,12 +141,37 @@ func runHookPreReceive(c *cli.Context) error {
 					//fail(fmt.Sprintf("branch %s is protected from force push", branchName), "")
 				}
 			}
+
+			if protectBranch.RequireSignedCommits && newCommitID != git.EmptySHA {
+				verifyPushedCommits(repoID, branchName, oldCommitID, newCommitID)
+			}
 		}
 	}
 
 	return nil
 }
 
+// verifyPushedCommits rejects the push if one of the new commits of the
+// protected branch is not signed by a known key.
+func verifyPushedCommits(repoID int64, branchName, oldCommitID, newCommitID string) {
+	err := private.VerifyCommits(repoID, private.VerifyCommitsOptions{
+		OldCommitID:                     oldCommitID,
+		NewCommitID:                     newCommitID,
+		GitObjectDirectory:              os.Getenv("GIT_OBJECT_DIRECTORY"),
+		GitAlternativeObjectDirectories: os.Getenv("GIT_ALTERNATE_OBJECT_DIRECTORIES"),
+		GitQuarantinePath:               os.Getenv("GIT_QUARANTINE_PATH"),
+	})
+	if models.IsErrUnverifiedCommit(err) {
+		unverified := err.(models.ErrUnverifiedCommit)
+		if unverified.Reason == "gpg.error.not_signed_commit" {
+			fail(fmt.Sprintf("protected branch %s requires signed commits, commit %s is not signed", branchName, unverified.ID), "")
+		}
+		fail(fmt.Sprintf("protected branch %s requires signed commits, commit %s is not signed by a known key", branchName, unverified.ID), "")
+	} else if err != nil {
+		fail("Internal error", "Failed to verify the pushed commits of protected branch %s: %v", branchName, err)
+	}
+}
+
 func runHookUpdate(c *cli.Context) error {
 	if len(os.Getenv("SSH_ORIGINAL_COMMAND")) == 0 {
 		return nil
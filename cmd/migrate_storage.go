@@ -0,0 +1,176 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/urfave/cli"
+)
+
+// CmdMigrateStorage represents the available migrate-storage sub-command.
+var CmdMigrateStorage = cli.Command{
+	Name:  "migrate-storage",
+	Usage: "Migrate stored files to another storage",
+	Description: `Copies attachments (including release assets), LFS objects or avatars
+from the currently configured storage to the given one. Change the storage settings
+in the configuration afterwards to start using the new storage.`,
+	Action: runMigrateStorage,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "type, t",
+			Value: "",
+			Usage: "Kind of files to migrate: 'attachments', 'lfs' or 'avatars'",
+		},
+		cli.StringFlag{
+			Name:  "storage, s",
+			Value: setting.LocalStorageType,
+			Usage: "New storage type: 'local' or 'minio'",
+		},
+		cli.StringFlag{
+			Name:  "path, p",
+			Value: "",
+			Usage: "New storage path when the storage type is local",
+		},
+		cli.StringFlag{
+			Name:  "minio-endpoint",
+			Value: "",
+			Usage: "Minio storage endpoint",
+		},
+		cli.StringFlag{
+			Name:  "minio-access-key-id",
+			Value: "",
+			Usage: "Minio storage access key ID",
+		},
+		cli.StringFlag{
+			Name:  "minio-secret-access-key",
+			Value: "",
+			Usage: "Minio storage secret access key",
+		},
+		cli.StringFlag{
+			Name:  "minio-bucket",
+			Value: "",
+			Usage: "Minio storage bucket",
+		},
+		cli.StringFlag{
+			Name:  "minio-location",
+			Value: "us-east-1",
+			Usage: "Minio storage location to create the bucket in",
+		},
+		cli.StringFlag{
+			Name:  "minio-base-path",
+			Value: "",
+			Usage: "Minio storage base path inside the bucket",
+		},
+		cli.BoolFlag{
+			Name:  "minio-use-ssl",
+			Usage: "Enable SSL for minio",
+		},
+		cli.StringFlag{
+			Name:  "config, c",
+			Value: "custom/conf/app.ini",
+			Usage: "Custom configuration file path",
+		},
+	},
+}
+
+func migrateAttachments(dstStorage storage.ObjectStorage) error {
+	return models.IterateAttachment(func(attach *models.Attachment) error {
+		_, err := storage.Copy(dstStorage, attach.RelativePath(), storage.Attachments, attach.RelativePath())
+		return err
+	})
+}
+
+func migrateLFS(dstStorage storage.ObjectStorage) error {
+	return models.IterateLFS(func(mo *models.LFSMetaObject) error {
+		_, err := storage.Copy(dstStorage, mo.RelativePath(), storage.LFS, mo.RelativePath())
+		// The same object may be referenced by several repositories
+		if storage.IsErrObjectNotExist(err) {
+			fmt.Printf("LFS object %s is missing, skipped\n", mo.Oid)
+			return nil
+		}
+		return err
+	})
+}
+
+func migrateAvatars(dstStorage storage.ObjectStorage) error {
+	return models.IterateUserWithAvatar(func(u *models.User) error {
+		_, err := storage.Copy(dstStorage, u.CustomAvatarRelativePath(), storage.Avatars, u.CustomAvatarRelativePath())
+		// Users relying on Gravatar have an avatar name but no file
+		if storage.IsErrObjectNotExist(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+func runMigrateStorage(ctx *cli.Context) error {
+	if ctx.IsSet("config") {
+		setting.CustomConf = ctx.String("config")
+	}
+
+	setting.NewContext()
+	models.LoadConfigs()
+
+	setting.NewXORMLogService(false)
+	if err := models.SetEngine(); err != nil {
+		return fmt.Errorf("models.SetEngine: %v", err)
+	}
+
+	if err := storage.Init(); err != nil {
+		return fmt.Errorf("storage.Init: %v", err)
+	}
+
+	var current setting.Storage
+	var migrate func(storage.ObjectStorage) error
+	tp := strings.ToLower(ctx.String("type"))
+	switch tp {
+	case "attachments":
+		current, migrate = setting.AttachmentStorage, migrateAttachments
+	case "lfs":
+		current, migrate = setting.LFSStorage, migrateLFS
+	case "avatars":
+		current, migrate = setting.AvatarStorage, migrateAvatars
+	default:
+		return fmt.Errorf("Unsupported storage: %s", ctx.String("type"))
+	}
+
+	cfg := setting.Storage{
+		Type: strings.ToLower(ctx.String("storage")),
+		Path: ctx.String("path"),
+	}
+	cfg.Minio.Endpoint = ctx.String("minio-endpoint")
+	cfg.Minio.AccessKeyID = ctx.String("minio-access-key-id")
+	cfg.Minio.SecretAccessKey = ctx.String("minio-secret-access-key")
+	cfg.Minio.Bucket = ctx.String("minio-bucket")
+	cfg.Minio.Location = ctx.String("minio-location")
+	cfg.Minio.BasePath = ctx.String("minio-base-path")
+	cfg.Minio.UseSSL = ctx.Bool("minio-use-ssl")
+
+	if cfg.Type == setting.LocalStorageType {
+		if cfg.Path == "" {
+			return fmt.Errorf("Path is not specified")
+		} else if current.Type == setting.LocalStorageType && cfg.Path == current.Path {
+			return fmt.Errorf("Path is the current storage path of %s", tp)
+		}
+	}
+
+	dstStorage, err := storage.NewStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("NewStorage: %v", err)
+	}
+
+	if err := migrate(dstStorage); err != nil {
+		return fmt.Errorf("Migrate %s: %v", tp, err)
+	}
+
+	fmt.Printf("Migrated %s to the %s storage successfully\n", tp, cfg.Type)
+	return nil
+}
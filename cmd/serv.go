@@ -194,12 +194,12 @@ func runServ(c *cli.Context) error {
 		fail("mirror repository is read-only", "")
 	}
 
-	// Allow anonymous clone for public repositories.
+	// Allow anonymous clone for public repositories of public owners.
 	var (
 		keyID int64
 		user  *models.User
 	)
-	if requestedMode == models.AccessModeWrite || repo.IsPrivate {
+	if requestedMode == models.AccessModeWrite || repo.IsPrivate || repoUser.Visibility != models.VisibleTypePublic {
 		keys := strings.Split(c.Args()[0], "-")
 		if len(keys) != 2 {
 			fail("Key ID format error", "Invalid key argument: %s", c.Args()[0])
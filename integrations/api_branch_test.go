@@ -8,7 +8,8 @@ import (
 	"net/http"
 	"testing"
 
-	api "code.gitea.io/sdk/gitea"
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,3 +43,112 @@ func TestAPIGetBranch(t *testing.T) {
 		testAPIGetBranch(t, test.BranchName, test.Exists)
 	}
 }
+
+func TestAPICreateDeleteBranch(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName:    "feature/api",
+		OldBranchName: "develop",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var branch api.Branch
+	DecodeJSON(t, resp, &branch)
+	assert.Equal(t, "feature/api", branch.Name)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/feature/api"), http.StatusOK)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "feature/api",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName:    "from-unknown",
+		OldBranchName: "unknown",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "invalid:name",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/feature/api")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	session.MakeRequest(t, req, http.StatusNotFound)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/feature/api"), http.StatusNotFound)
+
+	// a deleted branch can be created again
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches", &api.CreateBranchOption{
+		BranchName: "feature/api",
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/master")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.AddProtectedBranch("develop", true))
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/develop")
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branches/feature/1")
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIBranchProtection(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName:             "feature/1",
+		EnablePush:             true,
+		EnablePushWhitelist:    true,
+		PushWhitelistUsernames: []string{"user2", "user5"},
+		RequiredApprovals:      1,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var bp api.BranchProtection
+	DecodeJSON(t, resp, &bp)
+	assert.Equal(t, "feature/1", bp.BranchName)
+	assert.True(t, bp.EnablePushWhitelist)
+	// users without write access are dropped from the whitelist
+	assert.Equal(t, []string{"user2"}, bp.PushWhitelistUsernames)
+	assert.EqualValues(t, 1, bp.RequiredApprovals)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName: "feature/1",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName: "unknown",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	enableStatusCheck := true
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/branch_protections/feature/1", &api.EditBranchProtectionOption{
+		EnableStatusCheck:   &enableStatusCheck,
+		StatusCheckContexts: []string{"ci/build"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	bp = api.BranchProtection{}
+	DecodeJSON(t, resp, &bp)
+	assert.True(t, bp.EnableStatusCheck)
+	assert.Equal(t, []string{"ci/build"}, bp.StatusCheckContexts)
+	assert.Equal(t, []string{"user2"}, bp.PushWhitelistUsernames)
+	assert.EqualValues(t, 1, bp.RequiredApprovals)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var bps []*api.BranchProtection
+	DecodeJSON(t, resp, &bps)
+	assert.Len(t, bps, 1)
+
+	loginUser(t, "user4").MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections"),
+		http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/branch_protections/feature/1")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections/feature/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -10,7 +10,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -80,6 +80,12 @@ func TestAPICreateComment(t *testing.T) {
 	DecodeJSON(t, resp, &updatedComment)
 	assert.EqualValues(t, commentBody, updatedComment.Body)
 	models.AssertExistsAndLoadBean(t, &models.Comment{ID: updatedComment.ID, IssueID: issue.ID, Content: commentBody})
+	models.AssertExistsAndLoadBean(t, &models.Action{
+		OpType:    models.ActionCommentIssue,
+		ActUserID: repoOwner.ID,
+		RepoID:    repo.ID,
+		CommentID: updatedComment.ID,
+	})
 }
 
 func TestAPIEditComment(t *testing.T) {
@@ -0,0 +1,87 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueReactions(t *testing.T) {
+	prepareTestEnv(t)
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: issue.RepoID}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/reactions",
+		owner.Name, repo.Name, issue.Index)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.EditReactionOption{Reaction: "heart"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiReaction api.Reaction
+	DecodeJSON(t, resp, &apiReaction)
+	assert.EqualValues(t, "heart", apiReaction.Reaction)
+	assert.EqualValues(t, owner.ID, apiReaction.User.ID)
+	models.AssertExistsAndLoadBean(t, &models.Reaction{Type: "heart", IssueID: issue.ID, UserID: owner.ID})
+
+	// adding the same reaction again is not an error
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.EditReactionOption{Reaction: "heart"})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.EditReactionOption{Reaction: "not-an-emoji"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiReactions []*api.Reaction
+	DecodeJSON(t, resp, &apiReactions)
+	if assert.Len(t, apiReactions, 1) {
+		assert.EqualValues(t, "heart", apiReactions[0].Reaction)
+	}
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.EditReactionOption{Reaction: "heart"})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Reaction{Type: "heart", IssueID: issue.ID})
+}
+
+func TestAPICommentReactions(t *testing.T) {
+	prepareTestEnv(t)
+
+	comment := models.AssertExistsAndLoadBean(t, &models.Comment{},
+		models.Cond("type = ?", models.CommentTypeComment)).(*models.Comment)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: comment.IssueID}).(*models.Issue)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: issue.RepoID}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/comments/%d/reactions",
+		owner.Name, repo.Name, comment.ID)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.EditReactionOption{Reaction: "+1"})
+	session.MakeRequest(t, req, http.StatusCreated)
+	models.AssertExistsAndLoadBean(t, &models.Reaction{Type: "+1", IssueID: issue.ID, CommentID: comment.ID, UserID: owner.ID})
+
+	req = NewRequest(t, "GET", urlStr)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiReactions []*api.Reaction
+	DecodeJSON(t, resp, &apiReactions)
+	assert.Len(t, apiReactions, 1)
+
+	// the comment cannot be reached through another repository
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo2/issues/comments/%d/reactions", comment.ID)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.EditReactionOption{Reaction: "+1"})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Reaction{Type: "+1", CommentID: comment.ID})
+}
@@ -9,7 +9,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"fmt"
 	"github.com/stretchr/testify/assert"
@@ -44,20 +44,74 @@ func TestAPICreateIssue(t *testing.T) {
 
 	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues?state=all", owner.Name, repo.Name)
 	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
-		Body:     body,
-		Title:    title,
-		Assignee: owner.Name,
+		Body:      body,
+		Title:     title,
+		Assignees: []string{owner.Name},
 	})
 	resp := session.MakeRequest(t, req, http.StatusCreated)
 	var apiIssue api.Issue
 	DecodeJSON(t, resp, &apiIssue)
 	assert.Equal(t, apiIssue.Body, body)
 	assert.Equal(t, apiIssue.Title, title)
+	if assert.Len(t, apiIssue.Assignees, 1) {
+		assert.Equal(t, owner.Name, apiIssue.Assignees[0].UserName)
+	}
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{
+		RepoID:  repo.ID,
+		Content: body,
+		Title:   title,
+	}).(*models.Issue)
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: owner.ID})
+	models.AssertExistsAndLoadBean(t, &models.Action{
+		OpType:    models.ActionCreateIssue,
+		ActUserID: owner.ID,
+		RepoID:    repo.ID,
+		Content:   fmt.Sprintf("%d|%s", issue.Index, title),
+	})
+}
+
+func TestAPIEditIssueAssignees(t *testing.T) {
+	prepareTestEnv(t)
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: issue.RepoID}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: 1})
+
+	session := loginUser(t, owner.Name)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", owner.Name, repo.Name, issue.Index)
+	req := NewRequestWithJSON(t, "PATCH", urlStr, &api.EditIssueOption{
+		Assignees: []string{owner.Name, "user1"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.Len(t, apiIssue.Assignees, 2)
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: 1})
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: owner.ID})
 
-	models.AssertExistsAndLoadBean(t, &models.Issue{
-		RepoID:     repo.ID,
-		AssigneeID: owner.ID,
-		Content:    body,
-		Title:      title,
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditIssueOption{
+		Assignees: []string{"user1"},
 	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiIssue)
+	if assert.Len(t, apiIssue.Assignees, 1) {
+		assert.Equal(t, "user1", apiIssue.Assignees[0].UserName)
+	}
+	models.AssertNotExistsBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: owner.ID})
+
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditIssueOption{
+		Assignees: []string{"nonexistent"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditIssueOption{
+		Assignees: []string{},
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiIssue)
+	assert.Len(t, apiIssue.Assignees, 0)
+	models.AssertNotExistsBean(t, &models.IssueAssignees{IssueID: issue.ID})
 }
@@ -0,0 +1,53 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIListTrackedTimes(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/times", owner.Name, repo.Name)
+
+	req := NewRequest(t, "GET", urlStr)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiTimes []*api.TrackedTime
+	DecodeJSON(t, resp, &apiTimes)
+	assert.Len(t, apiTimes, 4)
+
+	req = NewRequest(t, "GET", urlStr+"?since=2000-01-01T00:00:01Z&before=2000-01-01T00:00:02Z")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiTimes = nil
+	DecodeJSON(t, resp, &apiTimes)
+	if assert.Len(t, apiTimes, 1) {
+		assert.EqualValues(t, 2, apiTimes[0].ID)
+		assert.EqualValues(t, 3661, apiTimes[0].Time)
+	}
+
+	req = NewRequest(t, "GET", urlStr+"?since=yesterday")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/user/times?since=2000-01-01T00:00:02Z")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiTimes = nil
+	DecodeJSON(t, resp, &apiTimes)
+	if assert.Len(t, apiTimes, 2) {
+		assert.EqualValues(t, 3, apiTimes[0].ID)
+		assert.EqualValues(t, 5, apiTimes[1].ID)
+	}
+}
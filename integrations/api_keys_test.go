@@ -8,7 +8,7 @@ import (
 	"net/http"
 	"testing"
 
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 func TestViewDeployKeysNoLogin(t *testing.T) {
@@ -0,0 +1,93 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPINotifications(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user1")
+	req := NewRequest(t, "GET", "/api/v1/notifications")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var threads []*api.NotificationThread
+	DecodeJSON(t, resp, &threads)
+	if assert.Len(t, threads, 1) {
+		assert.EqualValues(t, 1, threads[0].ID)
+		assert.True(t, threads[0].Unread)
+		assert.Equal(t, "Issue", threads[0].Subject.Type)
+		assert.Equal(t, "user2/repo1", threads[0].Repository.FullName)
+	}
+	assert.Equal(t, "1", resp.Headers.Get("X-Total-Count"))
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?before=2000-01-01T00:00:00Z")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	threads = nil
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 0)
+	req = NewRequest(t, "GET", "/api/v1/notifications?since=yesterday")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/notifications/new")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var count api.NotificationCount
+	DecodeJSON(t, resp, &count)
+	assert.EqualValues(t, 1, count.New)
+
+	req = NewRequest(t, "PUT", "/api/v1/notifications/threads/1?to-status=pinned")
+	session.MakeRequest(t, req, http.StatusResetContent)
+	req = NewRequest(t, "GET", "/api/v1/notifications/threads/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var thread api.NotificationThread
+	DecodeJSON(t, resp, &thread)
+	assert.True(t, thread.Pinned)
+	assert.False(t, thread.Unread)
+	req = NewRequest(t, "PUT", "/api/v1/notifications/threads/1?to-status=unknown")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "PUT", "/api/v1/notifications/threads/1?to-status=unread")
+	session.MakeRequest(t, req, http.StatusResetContent)
+	req = NewRequest(t, "PUT", "/api/v1/repos/user2/repo1/notifications")
+	session.MakeRequest(t, req, http.StatusResetContent)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 1, Status: models.NotificationStatusRead})
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/notifications")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	threads = nil
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 0)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/notifications?all=true")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	threads = nil
+	DecodeJSON(t, resp, &threads)
+	assert.Len(t, threads, 1)
+}
+
+func TestAPINotificationsOfAnotherUser(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/api/v1/notifications/threads/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "PUT", "/api/v1/notifications/threads/1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	models.AssertExistsAndLoadBean(t, &models.Notification{ID: 1, Status: models.NotificationStatusUnread})
+
+	req = NewRequest(t, "GET", "/api/v1/notifications?all=true")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var threads []*api.NotificationThread
+	DecodeJSON(t, resp, &threads)
+	if assert.Len(t, threads, 1) {
+		assert.EqualValues(t, 2, threads[0].ID)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIProjects(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 3}).(*models.Issue)
+
+	session := loginUser(t, owner.Name)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/projects", owner.Name, repo.Name)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateProjectOption{Title: "Roadmap"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiProject api.Project
+	DecodeJSON(t, resp, &apiProject)
+	assert.EqualValues(t, "Roadmap", apiProject.Title)
+	assert.EqualValues(t, api.StateOpen, apiProject.State)
+	assert.EqualValues(t, owner.ID, apiProject.Creator.ID)
+	projectURL := fmt.Sprintf("%s/%d", urlStr, apiProject.ID)
+
+	req = NewRequestWithJSON(t, "POST", projectURL+"/boards", &api.CreateProjectBoardOption{
+		Title:        "Done",
+		ClosedColumn: true,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiBoard api.ProjectBoard
+	DecodeJSON(t, resp, &apiBoard)
+	assert.True(t, apiBoard.ClosedColumn)
+
+	req = NewRequest(t, "GET", projectURL+"/boards")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiBoards []*api.ProjectBoard
+	DecodeJSON(t, resp, &apiBoards)
+	if assert.Len(t, apiBoards, 1) {
+		assert.EqualValues(t, apiBoard.ID, apiBoards[0].ID)
+	}
+
+	// adding the issue puts it on the default board
+	req = NewRequestWithJSON(t, "POST", projectURL+"/issues", &api.MoveProjectIssueOption{Issue: issue.Index})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", projectURL+"/boards/0/issues")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, issue.Index, apiIssues[0].Index)
+	}
+
+	// moving it to the closed column closes it
+	req = NewRequestWithJSON(t, "POST", projectURL+"/issues", &api.MoveProjectIssueOption{
+		Issue:   issue.Index,
+		BoardID: apiBoard.ID,
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	assert.True(t, models.AssertExistsAndLoadBean(t, &models.Issue{ID: issue.ID}).(*models.Issue).IsClosed)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: issue.ID, ProjectBoardID: apiBoard.ID})
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/issues/%d", projectURL, issue.Index))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.ProjectIssue{IssueID: issue.ID})
+
+	state := string(api.StateClosed)
+	req = NewRequestWithJSON(t, "PATCH", projectURL, &api.EditProjectOption{State: &state})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiProject)
+	assert.EqualValues(t, api.StateClosed, apiProject.State)
+
+	req = NewRequest(t, "DELETE", projectURL)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Project{ID: apiProject.ID})
+	models.AssertNotExistsBean(t, &models.ProjectBoard{ID: apiBoard.ID})
+}
+
+func TestAPIProjectsNotWriter(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user4")
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/projects/1/boards")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/projects/1/issues", &api.MoveProjectIssueOption{Issue: 1, BoardID: 2})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPushMirror(t *testing.T) {
+	prepareTestEnv(t)
+
+	remotePath, err := ioutil.TempDir("", "push-mirror")
+	assert.NoError(t, err)
+	defer os.RemoveAll(remotePath)
+	_, err = git.NewCommand("init", "--bare").RunInDir(remotePath)
+	assert.NoError(t, err)
+
+	defer func(importLocalPaths bool) {
+		setting.ImportLocalPaths = importLocalPaths
+	}(setting.ImportLocalPaths)
+	setting.ImportLocalPaths = true
+
+	// only site administrators may push to local paths
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/push_mirrors", &api.CreatePushMirrorOption{
+		RemoteAddress: remotePath,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	session = loginUser(t, "user1")
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/push_mirrors", &api.CreatePushMirrorOption{
+		RemoteAddress: remotePath,
+		Interval:      "1s",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/push_mirrors", &api.CreatePushMirrorOption{
+		RemoteAddress: remotePath,
+		Interval:      "0",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var mirror api.PushMirror
+	DecodeJSON(t, resp, &mirror)
+	assert.Equal(t, remotePath, mirror.RemoteAddress)
+	assert.Empty(t, mirror.Interval)
+	assert.True(t, strings.HasPrefix(mirror.RemoteName, "remote_mirror_"))
+
+	// the git data is pushed to the new mirror in the background
+	mirrorURL := fmt.Sprintf("/api/v1/repos/user2/repo1/push_mirrors/%d", mirror.ID)
+	for i := 0; i < 50 && mirror.LastUpdate == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		req = NewRequest(t, "GET", mirrorURL)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &mirror)
+	}
+	assert.NotNil(t, mirror.LastUpdate)
+	assert.Empty(t, mirror.LastError)
+	stdout, err := git.NewCommand("rev-parse", "refs/heads/master").RunInDir(remotePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", strings.TrimSpace(stdout))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/push_mirrors")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var mirrors []*api.PushMirror
+	DecodeJSON(t, resp, &mirrors)
+	assert.Len(t, mirrors, 1)
+
+	req = NewRequest(t, "POST", mirrorURL+"/sync")
+	session.MakeRequest(t, req, http.StatusAccepted)
+
+	req = NewRequest(t, "GET", "/user2/repo1/settings")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, string(resp.Body), remotePath)
+
+	req = NewRequest(t, "DELETE", mirrorURL)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", mirrorURL)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// other users may not manage the push mirrors
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/push_mirrors")
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
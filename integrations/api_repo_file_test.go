@@ -0,0 +1,193 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const readmeSHA = "4b4851ad51df6a7d9f25c979345979eaeb5b349f"
+
+func TestAPIGetContents(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var file api.ContentsResponse
+	DecodeJSON(t, resp, &file)
+	assert.Equal(t, "README.md", file.Name)
+	assert.Equal(t, "README.md", file.Path)
+	assert.Equal(t, "file", file.Type)
+	assert.Equal(t, readmeSHA, file.SHA)
+	assert.Equal(t, "base64", file.Encoding)
+	content, err := base64.StdEncoding.DecodeString(file.Content)
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(content), file.Size)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var entries []*api.ContentsResponse
+	DecodeJSON(t, resp, &entries)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "README.md", entries[0].Path)
+		assert.Empty(t, entries[0].Content)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=develop")
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=unknown")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/unknown.md")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPICreateUpdateDeleteFile(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	urlStr := "/api/v1/repos/user2/repo1/contents/docs/new.txt"
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateFileOptions{
+		FileOptions: api.FileOptions{
+			Message: "Add new file",
+			Author:  api.IdentityOptions{Name: "Author", Email: "author@example.com"},
+		},
+		Content: base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var created api.FileResponse
+	DecodeJSON(t, resp, &created)
+	assert.Equal(t, "docs/new.txt", created.Content.Path)
+	assert.EqualValues(t, 5, created.Content.Size)
+	assert.Equal(t, "Add new file\n", created.Commit.Message)
+	assert.Equal(t, "author@example.com", created.Commit.Author.Email)
+
+	// the file can not be created twice
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("Hello again")),
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// stale updates are rejected
+	req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdateFileOptions{
+		SHA:     readmeSHA,
+		Content: base64.StdEncoding.EncodeToString([]byte("Stale")),
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdateFileOptions{
+		FileOptions: api.FileOptions{NewBranchName: "new-branch"},
+		SHA:         created.Content.SHA,
+		Content:     base64.StdEncoding.EncodeToString([]byte("Hello world")),
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var updated api.FileResponse
+	DecodeJSON(t, resp, &updated)
+	assert.EqualValues(t, 11, updated.Content.Size)
+	assert.NotEqual(t, created.Content.SHA, updated.Content.SHA)
+
+	req = NewRequest(t, "GET", urlStr+"?ref=master")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var file api.ContentsResponse
+	DecodeJSON(t, resp, &file)
+	assert.Equal(t, created.Content.SHA, file.SHA)
+
+	req = NewRequest(t, "GET", urlStr+"?ref=new-branch")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &file)
+	assert.Equal(t, updated.Content.SHA, file.SHA)
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.DeleteFileOptions{
+		FileOptions: api.FileOptions{BranchName: "new-branch"},
+		SHA:         created.Content.SHA,
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.DeleteFileOptions{
+		FileOptions: api.FileOptions{BranchName: "new-branch"},
+		SHA:         updated.Content.SHA,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var deleted api.FileResponse
+	DecodeJSON(t, resp, &deleted)
+	assert.Nil(t, deleted.Content)
+
+	req = NewRequest(t, "GET", urlStr+"?ref=new-branch")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPICreateFileOnProtectedBranch(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.AddProtectedBranch("master", false))
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", &api.CreateFileOptions{
+		FileOptions: api.FileOptions{NewBranchName: "protected-new-branch"},
+		Content:     base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	// whitelisted users can not commit directly if the branch requires approvals
+	protectBranch, err := models.GetProtectedBranchBy(repo.ID, "master")
+	assert.NoError(t, err)
+	protectBranch.CanPush = true
+	protectBranch.EnableWhitelist = true
+	protectBranch.RequiredApprovals = 1
+	assert.NoError(t, models.UpdateProtectBranch(repo, protectBranch, models.UpdateProtectBranchOptions{
+		WhitelistUserIDs: []int64{2},
+	}))
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/new.txt", &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	protectBranch.RequiredApprovals = 0
+	assert.NoError(t, models.UpdateProtectBranch(repo, protectBranch, models.UpdateProtectBranchOptions{
+		WhitelistUserIDs: []int64{2},
+	}))
+	session.MakeRequest(t, req, http.StatusCreated)
+}
+
+func TestAPIChangeFileInvalidPath(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	for _, treePath := range []string{"docs/../../outside.txt", ".git/config", "docs/../.git/hooks/update"} {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+treePath, &api.CreateFileOptions{
+			Content: base64.StdEncoding.EncodeToString([]byte("Hello")),
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/README.md", &api.UpdateFileOptions{
+		FromPath: "../../README.md",
+		SHA:      readmeSHA,
+		Content:  base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the SHA of the changed file is required
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/README.md", &api.UpdateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("Hello")),
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/user2/repo1/contents/README.md", &api.DeleteFileOptions{})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
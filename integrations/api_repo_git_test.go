@@ -0,0 +1,164 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	repo1CommitSHA = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	repo1TreeSHA   = "2a2f1d4670728a2e10049e345bd7a276468beab6"
+)
+
+func TestAPIGitCommitsAndTrees(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	for _, treePath := range []string{"docs/a.txt", "docs/b.txt", "c.txt"} {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+treePath, &api.CreateFileOptions{
+			Content: base64.StdEncoding.EncodeToString([]byte(treePath)),
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+	}
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits?limit=2")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var commits []*api.Commit
+	DecodeJSON(t, resp, &commits)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "4", resp.Headers.Get("X-Total-Count"))
+	assert.Contains(t, resp.Headers.Get("Link"), "limit=2&page=2")
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits?path=docs")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	commits = nil
+	DecodeJSON(t, resp, &commits)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, "Add 'docs/b.txt'\n", commits[0].RepoCommit.Message)
+		assert.Equal(t, "user2", commits[0].Author.UserName)
+		assert.Len(t, commits[0].Parents, 1)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits?sha="+repo1CommitSHA)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	commits = nil
+	DecodeJSON(t, resp, &commits)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, repo1CommitSHA, commits[0].SHA)
+		assert.Equal(t, repo1TreeSHA, commits[0].RepoCommit.Tree.SHA)
+		assert.Empty(t, commits[0].Parents)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+repo1CommitSHA)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var commit api.Commit
+	DecodeJSON(t, resp, &commit)
+	assert.Equal(t, repo1CommitSHA, commit.SHA)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+repo1TreeSHA)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/master")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var tree api.GitTreeResponse
+	DecodeJSON(t, resp, &tree)
+	assert.Len(t, tree.Entries, 3)
+	assert.False(t, tree.Truncated)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/master?recursive=1&per_page=4")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	tree = api.GitTreeResponse{}
+	DecodeJSON(t, resp, &tree)
+	assert.Equal(t, 5, tree.TotalCount)
+	assert.True(t, tree.Truncated)
+	paths := make([]string, len(tree.Entries))
+	for i, entry := range tree.Entries {
+		paths[i] = entry.Path
+	}
+	assert.Equal(t, []string{"README.md", "c.txt", "docs", "docs/a.txt"}, paths)
+	assert.Equal(t, "tree", tree.Entries[2].Type)
+	assert.Equal(t, "040000", tree.Entries[2].Mode)
+	assert.EqualValues(t, 5, tree.Entries[1].Size)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/"+repo1TreeSHA)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	tree = api.GitTreeResponse{}
+	DecodeJSON(t, resp, &tree)
+	assert.Equal(t, repo1TreeSHA, tree.SHA)
+	if assert.Len(t, tree.Entries, 1) {
+		assert.Equal(t, readmeSHA, tree.Entries[0].SHA)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/blobs/"+readmeSHA)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var blob api.GitBlobResponse
+	DecodeJSON(t, resp, &blob)
+	assert.Equal(t, readmeSHA, blob.SHA)
+	assert.Equal(t, "base64", blob.Encoding)
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(content), blob.Size)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/blobs/"+repo1TreeSHA)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIGitRefsAndTags(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	_, err := git.NewCommand("-c", "user.name=Tagger", "-c", "user.email=tagger@example.com",
+		"tag", "-a", "v1.0", "-m", "First release", "master").RunInDir(repo.RepoPath())
+	assert.NoError(t, err)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var refs []*api.Reference
+	DecodeJSON(t, resp, &refs)
+	assert.Len(t, refs, 5)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs/heads/feature")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	refs = nil
+	DecodeJSON(t, resp, &refs)
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, "refs/heads/feature/1", refs[0].Ref)
+		assert.Equal(t, "commit", refs[0].Object.Type)
+		assert.Equal(t, repo1CommitSHA, refs[0].Object.SHA)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs/tags")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	refs = nil
+	DecodeJSON(t, resp, &refs)
+	if !assert.Len(t, refs, 1) {
+		return
+	}
+	assert.Equal(t, "tag", refs[0].Object.Type)
+	assert.True(t, strings.HasSuffix(refs[0].Object.URL, "/git/tags/"+refs[0].Object.SHA))
+
+	req = NewRequest(t, "GET", refs[0].Object.URL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var tag api.AnnotatedTag
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, "v1.0", tag.Tag)
+	assert.Equal(t, "First release\n", tag.Message)
+	assert.Equal(t, "tagger@example.com", tag.Tagger.Email)
+	assert.Equal(t, "commit", tag.Object.Type)
+	assert.Equal(t, repo1CommitSHA, tag.Object.SHA)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/tags/"+repo1CommitSHA)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICreateDeleteTag(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v1.0",
+		Target:  "master",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var tag api.Tag
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, "v1.0", tag.Name)
+	assert.Equal(t, repo1CommitSHA, tag.ID)
+	assert.Equal(t, repo1CommitSHA, tag.Commit.SHA)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v1.0",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "release/v1.1",
+		Target:  repo1CommitSHA,
+		Message: "Release v1.1",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	tag = api.Tag{}
+	DecodeJSON(t, resp, &tag)
+	assert.Equal(t, "Release v1.1", tag.Message)
+	assert.NotEqual(t, repo1CommitSHA, tag.ID)
+	assert.Equal(t, repo1CommitSHA, tag.Commit.SHA)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+		TagName: "v2.0",
+		Target:  "unknown",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// names which git does not accept or which would be taken for options
+	for _, name := range []string{"--v1.0", "----force", "-f", "v2..0"} {
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/tags", &api.CreateTagOption{
+			TagName: name,
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var tags []*api.Tag
+	DecodeJSON(t, resp, &tags)
+	assert.Len(t, tags, 2)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags/release/v1.1")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// tags of releases are deleted along with their release
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	assert.NoError(t, models.CreateRelease(gitRepo, &models.Release{
+		RepoID:      repo.ID,
+		PublisherID: 2,
+		TagName:     "v1.0",
+		Target:      "master",
+		Title:       "v1.0",
+	}, nil))
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/v1.0")
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/release/v1.1")
+	session.MakeRequest(t, req, http.StatusNoContent)
+	session.MakeRequest(t, req, http.StatusNotFound)
+	models.AssertExistsAndLoadBean(t, &models.Action{RepoID: 1, OpType: models.ActionPushTag, RefName: "v1.0"})
+
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/tags/v1.0")
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -10,7 +10,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -52,6 +52,33 @@ func TestAPISearchRepoNotLogin(t *testing.T) {
 	}
 }
 
+func TestAPIListReposWithRestrictedToken(t *testing.T) {
+	prepareTestEnv(t)
+	token := &models.AccessToken{
+		UID:     2,
+		Name:    "restricted",
+		Scopes:  []string{models.AccessTokenScopeAll},
+		RepoIDs: []int64{1},
+	}
+	assert.NoError(t, models.NewAccessToken(token))
+
+	req := NewRequestf(t, "GET", "/api/v1/user/repos?token=%s", token.Sha1)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiRepos []api.Repository
+	DecodeJSON(t, resp, &apiRepos)
+	if assert.Len(t, apiRepos, 1) {
+		assert.EqualValues(t, 1, apiRepos[0].ID)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/search?uid=2&token=%s", token.Sha1)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var body api.SearchResults
+	DecodeJSON(t, resp, &body)
+	if assert.Len(t, body.Data, 1) {
+		assert.EqualValues(t, 1, body.Data[0].ID)
+	}
+}
+
 func TestAPIViewRepo(t *testing.T) {
 	prepareTestEnv(t)
 
@@ -0,0 +1,77 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueDependencies(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	// the dependencies are not shown unless they are enabled
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 0, htmlDoc.doc.Find(".ui.dependencies").Length())
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/settings", map[string]string{
+		"_csrf":               htmlDoc.GetCSRF(),
+		"action":              "advanced",
+		"enable_issues":       "on",
+		"enable_dependencies": "on",
+		"enable_pulls":        "on",
+		"pulls_allow_merge":   "on",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	link, exists := htmlDoc.doc.Find(".ui.dependencies form").Attr("action")
+	assert.True(t, exists, "The template has changed")
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":      htmlDoc.GetCSRF(),
+		"dependency": "#2",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 2})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(`.ui.dependencies a[href$="/user2/repo1/pulls/2"]`).Length())
+
+	// the issue cannot be closed while the pull request is open
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/status", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"action":    "close",
+		"issue_ids": "1",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1, IsClosed: false})
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/dependency/delete", map[string]string{
+		"_csrf":         htmlDoc.GetCSRF(),
+		"dependency_id": "2",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 2})
+
+	// only writers may change the dependencies
+	session = loginUser(t, "user4")
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/dependency/add", map[string]string{
+		"_csrf":      GetCSRF(t, session, "/user2/repo1/issues/1"),
+		"dependency": "#2",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	models.AssertNotExistsBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 2})
+}
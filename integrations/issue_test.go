@@ -97,3 +97,36 @@ func TestNewIssue(t *testing.T) {
 	session := loginUser(t, "user2")
 	testNewIssue(t, session, "user2", "repo1", "Title")
 }
+
+func TestUpdateIssueAssignees(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".ui.assignees.list .item a").Length())
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/assignee", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"action":    "attach",
+		"issue_ids": "1",
+		"id":        "2",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: 1, AssigneeID: 1})
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: 1, AssigneeID: 2})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 2, htmlDoc.doc.Find(".ui.assignees.list .item a").Length())
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/assignee", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"action":    "clear",
+		"issue_ids": "1",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertNotExistsBean(t, &models.IssueAssignees{IssueID: 1})
+}
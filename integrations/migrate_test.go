@@ -0,0 +1,81 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateTrackerItems(t *testing.T) {
+	prepareTestEnv(t)
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	sourceRepo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	const sha = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	created := time.Date(2017, 11, 9, 17, 0, 29, 0, time.UTC)
+	closed := time.Date(2017, 11, 12, 20, 22, 22, 0, time.UTC)
+
+	uploader := migrations.NewGiteaLocalUploader(user2, user2, "migrated", "github")
+	assert.NoError(t, uploader.CreateRepo(&migrations.Repository{Name: "repo1"}, migrations.MigrateOptions{
+		RemoteURL: sourceRepo.RepoPath(),
+	}))
+	assert.NoError(t, uploader.CreateMilestones(&migrations.Milestone{Title: "1.0.0", State: "closed", Closed: &closed}))
+	assert.NoError(t, uploader.CreateLabels(&migrations.Label{Name: "bug", Color: "d73a4a"}))
+	assert.NoError(t, uploader.CreateReleases(
+		&migrations.Release{TagName: "v1.0", TargetCommitish: sha, Name: "missing tag", PublisherName: "someone", Created: created},
+	))
+	assert.NoError(t, uploader.CreateIssues(
+		&migrations.Issue{Number: 1, PosterID: 18600385, PosterName: "someone", Title: "unmapped author",
+			Content: "issue content", Milestone: "1.0.0", State: "closed", Created: created, Closed: &closed,
+			Labels: []*migrations.Label{{Name: "bug"}}},
+		&migrations.Issue{Number: 3, PosterName: "user4", PosterEmail: "user4@example.com", Title: "mapped author",
+			State: "open", Created: created},
+	))
+	assert.NoError(t, uploader.CreateComments(&migrations.Comment{IssueNumber: 1, PosterName: "someone", Content: "a comment", Created: created}))
+	assert.Error(t, uploader.CreateComments(&migrations.Comment{IssueNumber: 2, Content: "no such issue", Created: created}))
+	assert.NoError(t, uploader.CreatePullRequests(
+		&migrations.PullRequest{Number: 4, PosterName: "someone", Title: "open pull request", State: "open", Created: created,
+			Head: migrations.PullRequestBranch{Ref: "feature/1", SHA: sha},
+			Base: migrations.PullRequestBranch{Ref: "master", SHA: sha}},
+		&migrations.PullRequest{Number: 5, PosterName: "someone", Title: "merged pull request", State: "closed", Created: created,
+			Merged: true, MergedTime: &closed, MergeCommitSHA: sha,
+			Head: migrations.PullRequestBranch{Ref: "develop", SHA: sha},
+			Base: migrations.PullRequestBranch{Ref: "master", SHA: sha}},
+	))
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: user2.ID, Name: "migrated"}).(*models.Repository)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}).(*models.Issue)
+	assert.EqualValues(t, user2.ID, issue.PosterID)
+	assert.EqualValues(t, created.Unix(), issue.CreatedUnix)
+	assert.True(t, issue.IsClosed)
+	models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 3, PosterID: 4})
+	models.AssertExistsAndLoadBean(t, &models.Release{RepoID: repo.ID, TagName: "v1.0", IsDraft: true})
+	merged := models.AssertExistsAndLoadBean(t, &models.PullRequest{BaseRepoID: repo.ID, Index: 5}).(*models.PullRequest)
+	assert.True(t, merged.HasMerged)
+	assert.EqualValues(t, repo.ID, merged.HeadRepoID)
+
+	_, err := git.NewCommand("rev-parse", "--verify", "refs/pull/4/head").RunInDir(repo.RepoPath())
+	assert.NoError(t, err)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/migrated/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, string(resp.Body), "Originally posted by someone")
+	for _, link := range []string{"/user2/migrated/pulls/4", "/user2/migrated/pulls/4/files", "/user2/migrated/pulls/5", "/user2/migrated/releases"} {
+		req = NewRequest(t, "GET", link)
+		session.MakeRequest(t, req, http.StatusOK)
+	}
+
+	// new issues are numbered after the migrated ones
+	testNewIssue(t, session, "user2", "migrated", "new issue")
+	models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 6, Title: "new issue"})
+}
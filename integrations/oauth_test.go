@@ -0,0 +1,196 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const defaultAuthorize = "/login/oauth/authorize?client_id=da7da3ba-9a13-4167-856f-3899de0b0138&redirect_uri=a&response_type=code&state=thestate"
+
+func TestNoClientID(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/login/oauth/authorize")
+	ctx := loginUser(t, "user2")
+	ctx.MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestLoginRedirect(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/login/oauth/authorize")
+	resp := MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Headers.Get("Location"), "/user/login")
+}
+
+func TestShowAuthorize(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", defaultAuthorize)
+	ctx := loginUser(t, "user4")
+	resp := ctx.MakeRequest(t, req, http.StatusOK)
+
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 1, htmlDoc.doc.Find("#authorize-app").Length())
+	assert.Equal(t, "da7da3ba-9a13-4167-856f-3899de0b0138", htmlDoc.GetInputValueByName("client_id"))
+}
+
+func TestGrantApplicationUsesRequestedScope(t *testing.T) {
+	prepareTestEnv(t)
+	ctx := loginUser(t, "user4")
+	csrf := GetCSRF(t, ctx, defaultAuthorize+"&scope=openid")
+
+	// the scope is taken from the authorization request, not from the consent form
+	req := NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        csrf,
+		"client_id":    "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"redirect_uri": "a",
+		"state":        "thestate",
+		"scope":        "openid all",
+	})
+	resp := ctx.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Headers.Get("Location"), "a?code=")
+
+	grant := models.AssertExistsAndLoadBean(t, &models.OAuth2Grant{UserID: 4, ApplicationID: 1}).(*models.OAuth2Grant)
+	assert.Equal(t, "openid", grant.Scope)
+}
+
+func TestRedirectWithExistingGrant(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", defaultAuthorize)
+	ctx := loginUser(t, "user1")
+	resp := ctx.MakeRequest(t, req, http.StatusFound)
+	location := resp.Headers.Get("Location")
+	assert.Contains(t, location, "a?code=")
+	assert.Contains(t, location, "state=thestate")
+}
+
+func TestAccessTokenExchange(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	})
+	resp := MakeRequest(t, req, http.StatusOK)
+	parsed := new(user.AccessTokenResponse)
+	assert.NoError(t, json.Unmarshal(resp.Body, parsed))
+	assert.True(t, len(parsed.AccessToken) > 10)
+	assert.True(t, len(parsed.RefreshToken) > 10)
+	assert.True(t, len(parsed.IDToken) > 10)
+
+	// the grant has no API scope, so the access token is not accepted by the API
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.Header.Set("Authorization", "Bearer "+parsed.AccessToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// only the claims of the granted profile scope are returned
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	req.Header.Set("Authorization", "Bearer "+parsed.AccessToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var info map[string]string
+	assert.NoError(t, json.Unmarshal(resp.Body, &info))
+	assert.Equal(t, "1", info["sub"])
+	assert.Equal(t, "user1", info["preferred_username"])
+	assert.NotContains(t, info, "email")
+
+	// the authorization code can only be used once
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	})
+	MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestAccessTokenExchangeWithBasicAuth(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	})
+	req.SetBasicAuth("da7da3ba-9a13-4167-856f-3899de0b0138", "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=")
+	MakeRequest(t, req, http.StatusOK)
+}
+
+func TestAccessTokenExchangeWithInvalidCredentials(t *testing.T) {
+	prepareTestEnv(t)
+	// invalid client secret
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "???",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	})
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	// invalid code verifier
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "invalid",
+	})
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	// unsupported grant type
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "password",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+	})
+	MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestRefreshTokenInvalidation(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"redirect_uri":  "a",
+		"code":          "authcode",
+		"code_verifier": "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	})
+	resp := MakeRequest(t, req, http.StatusOK)
+	parsed := new(user.AccessTokenResponse)
+	assert.NoError(t, json.Unmarshal(resp.Body, parsed))
+
+	refreshReq := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"refresh_token": parsed.RefreshToken,
+	})
+	MakeRequest(t, refreshReq, http.StatusOK)
+}
+
+func TestOIDCWellKnown(t *testing.T) {
+	prepareTestEnv(t)
+	req := NewRequest(t, "GET", "/.well-known/openid-configuration")
+	resp := MakeRequest(t, req, http.StatusOK)
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(resp.Body, &doc))
+	assert.Contains(t, doc["authorization_endpoint"], "/login/oauth/authorize")
+	assert.Contains(t, doc["token_endpoint"], "/login/oauth/access_token")
+}
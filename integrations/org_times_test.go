@@ -0,0 +1,50 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgTimes(t *testing.T) {
+	prepareTestEnv(t)
+
+	// user2 owns the organization user3
+	session := loginUser(t, "user2")
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/issues", &api.CreateIssueOption{Title: "Billable"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/issues/1/times", &api.AddTimeOption{Time: 3600})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.TrackedTime{IssueID: apiIssue.ID, UserID: 2})
+
+	req = NewRequest(t, "GET", "/org/user3/times")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, "repo3", strings.TrimSpace(htmlDoc.doc.Find("tbody td a").First().Text()))
+	assert.EqualValues(t, "1h", strings.TrimSpace(htmlDoc.doc.Find("tfoot th").Last().Text()))
+
+	req = NewRequest(t, "GET", "/org/user3/times/export")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.EqualValues(t, "repository,milestone,user,seconds,time\nuser3/repo3,,user2,3600,1h\n", string(resp.Body))
+
+	// no time was tracked before the issue was created
+	req = NewRequest(t, "GET", "/org/user3/times/export?before=2000-01-01")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.EqualValues(t, "repository,milestone,user,seconds,time\n", string(resp.Body))
+
+	// the report is only visible to owners
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "GET", "/org/user3/times")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewProject(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/projects")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".milestone.list .item").Length())
+
+	req = NewRequest(t, "GET", "/user2/repo1/projects/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	// the default board comes first
+	assert.EqualValues(t, 4, htmlDoc.doc.Find(".project.board .board-column").Length())
+	assert.EqualValues(t, 2, htmlDoc.doc.Find(`.board-column[data-id="1"] .board-card`).Length())
+}
+
+func TestMoveProjectIssue(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/projects/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	link, exists := htmlDoc.doc.Find(".project.board").Attr("data-url")
+	assert.True(t, exists, "The template has changed")
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":    htmlDoc.GetCSRF(),
+		"issue":    "1",
+		"board":    "3",
+		"position": "0",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var result struct {
+		Ok bool `json:"ok"`
+	}
+	DecodeJSON(t, resp, &result)
+	assert.True(t, result.Ok)
+	assert.True(t, models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue).IsClosed)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: 1, ProjectBoardID: 3, Sorting: 0})
+}
+
+func TestUpdateIssueProject(t *testing.T) {
+	prepareTestEnv(t)
+
+	testUpdate := func(session *TestSession, issueID string, expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", "/user2/repo1/issues/projects", map[string]string{
+			"_csrf":     GetCSRF(t, session, "/user2/repo1/issues/1"),
+			"issue_ids": issueID,
+			"id":        "0",
+		})
+		session.MakeRequest(t, req, expectedStatus)
+	}
+
+	// readers may not change the project of an issue
+	testUpdate(loginUser(t, "user4"), "1", http.StatusNotFound)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{IssueID: 1, ProjectID: 1})
+
+	// issues of other repositories are not changed
+	session := loginUser(t, "user2")
+	testUpdate(session, "4", http.StatusNotFound)
+
+	testUpdate(session, "1", http.StatusOK)
+	models.AssertNotExistsBean(t, &models.ProjectIssue{IssueID: 1})
+}
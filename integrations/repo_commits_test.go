@@ -9,9 +9,9 @@ import (
 	"path"
 	"testing"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"github.com/stretchr/testify/assert"
+
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 func TestRepoCommits(t *testing.T) {
@@ -0,0 +1,90 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func resultFilenames(t testing.TB, doc *HTMLDoc) []string {
+	filenameSelections := doc.doc.Find(".repository.search").Find(".repo-search-result").Find(".header").Find("span.file-name")
+	result := make([]string, filenameSelections.Length())
+	filenameSelections.Each(func(i int, selection *goquery.Selection) {
+		result[i] = selection.Text()
+	})
+	return result
+}
+
+// searchCode requests the search page until the indexer has caught up, as the
+// user of given session or anonymously if it is nil
+func searchCode(t *testing.T, session *TestSession, url string) []string {
+	for i := 0; i < 50; i++ {
+		req := NewRequest(t, "GET", url)
+		var resp *TestResponse
+		if session != nil {
+			resp = session.MakeRequest(t, req, http.StatusOK)
+		} else {
+			resp = MakeRequest(t, req, http.StatusOK)
+		}
+		if filenames := resultFilenames(t, NewHTMLParser(t, resp.Body)); len(filenames) > 0 {
+			return filenames
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestSearchRepo(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	models.UpdateRepoIndexer(repo)
+
+	assert.EqualValues(t, []string{"README.md"}, searchCode(t, nil, "/user2/repo1/search?q=Description"))
+	assert.EqualValues(t, []string{"README.md"}, searchCode(t, nil, "/user2/repo1/search?q=readme&t=filename"))
+
+	req := NewRequest(t, "GET", "/user2/repo1/search?q=nonexistent")
+	resp := MakeRequest(t, req, http.StatusOK)
+	assert.Empty(t, resultFilenames(t, NewHTMLParser(t, resp.Body)))
+}
+
+func TestExploreCode(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	models.UpdateRepoIndexer(repo)
+
+	assert.EqualValues(t, []string{"README.md"}, searchCode(t, nil, "/explore/code?q=Description"))
+}
+
+func TestExploreCodePrivateOrg(t *testing.T) {
+	prepareTestEnv(t)
+
+	// the public repository of a private organization must not be found
+	// by users who can not see the organization
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	repo.IsPrivate = false
+	assert.NoError(t, models.UpdateRepository(repo, true))
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	org.Visibility = models.VisibleTypePrivate
+	assert.NoError(t, models.UpdateUser(org))
+	models.UpdateRepoIndexer(repo)
+
+	// user4 is a member of the organization
+	assert.Contains(t, searchCode(t, loginUser(t, "user4"), "/explore/code?q=repo3"), "README.md")
+
+	req := NewRequest(t, "GET", "/explore/code?q=repo3")
+	resp := MakeRequest(t, req, http.StatusOK)
+	assert.Empty(t, resultFilenames(t, NewHTMLParser(t, resp.Body)))
+	resp = loginUser(t, "user5").MakeRequest(t, req, http.StatusOK)
+	assert.Empty(t, resultFilenames(t, NewHTMLParser(t, resp.Body)))
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateOrgVisibility(t *testing.T) {
+	prepareTestEnv(t)
+
+	// user2 owns the organization user3
+	session := loginUser(t, "user2")
+	req := NewRequestWithValues(t, "POST", "/org/user3/settings", map[string]string{
+		"_csrf":      GetCSRF(t, session, "/org/user3/settings"),
+		"name":       "user3",
+		"visibility": "private",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	org := models.AssertExistsAndLoadBean(t, &models.User{Name: "user3"}).(*models.User)
+	assert.Equal(t, models.VisibleTypePrivate, org.Visibility)
+
+	for _, urlStr := range []string{"/user3", "/api/v1/orgs/user3", "/api/v1/orgs/user3/members"} {
+		req = NewRequest(t, "GET", urlStr)
+		MakeRequest(t, req, http.StatusNotFound)
+	}
+	for _, urlStr := range []string{"/user3", "/org/user3/members", "/api/v1/orgs/user3", "/api/v1/orgs/user3/members"} {
+		req = NewRequest(t, "GET", urlStr)
+		loginUser(t, "user5").MakeRequest(t, req, http.StatusNotFound)
+	}
+
+	// user4 is a member of user3
+	memberSession := loginUser(t, "user4")
+	req = NewRequest(t, "GET", "/user3")
+	memberSession.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3")
+	resp := memberSession.MakeRequest(t, req, http.StatusOK)
+	var apiOrg api.Organization
+	DecodeJSON(t, resp, &apiOrg)
+	assert.Equal(t, "private", apiOrg.Visibility)
+
+	req = NewRequest(t, "GET", "/explore/organizations")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 0, htmlDoc.doc.Find(`a[href="/user3"]`).Length())
+
+	req = NewRequest(t, "GET", "/api/v1/users/user2/orgs")
+	resp = loginUser(t, "user5").MakeRequest(t, req, http.StatusOK)
+	var apiOrgs []*api.Organization
+	DecodeJSON(t, resp, &apiOrgs)
+	assert.Empty(t, apiOrgs)
+}
+
+func TestLimitedUserVisibility(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithValues(t, "POST", "/user/settings", map[string]string{
+		"_csrf":      GetCSRF(t, session, "/user/settings"),
+		"name":       "user2",
+		"email":      "user2@example.com",
+		"visibility": "limited",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	user := models.AssertExistsAndLoadBean(t, &models.User{Name: "user2"}).(*models.User)
+	assert.Equal(t, models.VisibleTypeLimited, user.Visibility)
+
+	// anonymous visitors can not see user2 nor its repositories
+	for _, urlStr := range []string{"/user2", "/user2/repo1", "/api/v1/users/user2", "/api/v1/repos/user2/repo1"} {
+		req = NewRequest(t, "GET", urlStr)
+		MakeRequest(t, req, http.StatusNotFound)
+	}
+	req = NewRequest(t, "GET", "/api/v1/users/search?q=user2")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var results struct {
+		Data []*api.User `json:"data"`
+	}
+	DecodeJSON(t, resp, &results)
+	assert.Empty(t, results.Data)
+
+	// signed in users can
+	userSession := loginUser(t, "user5")
+	for _, urlStr := range []string{"/user2", "/user2/repo1/issues", "/api/v1/users/user2", "/api/v1/repos/user2/repo1"} {
+		req = NewRequest(t, "GET", urlStr)
+		userSession.MakeRequest(t, req, http.StatusOK)
+	}
+	req = NewRequest(t, "GET", "/api/v1/users/search?q=user2")
+	resp = userSession.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	assert.Len(t, results.Data, 1)
+}
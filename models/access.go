@@ -59,10 +59,31 @@ type Access struct {
 	Mode   AccessMode
 }
 
+// isOwnerVisible returns true if the owner of given repository can be seen by
+// given user, userID is 0 for anonymous visitors.
+func isOwnerVisible(e Engine, userID int64, repo *Repository) (bool, error) {
+	if err := repo.getOwner(e); err != nil {
+		return false, err
+	}
+	switch repo.Owner.Visibility {
+	case VisibleTypePublic:
+		return true, nil
+	case VisibleTypeLimited:
+		return userID > 0, nil
+	}
+	return userID > 0 && (userID == repo.OwnerID || isOrganizationMember(e, repo.OwnerID, userID)), nil
+}
+
 func accessLevel(e Engine, userID int64, repo *Repository) (AccessMode, error) {
 	mode := AccessModeNone
 	if !repo.IsPrivate {
-		mode = AccessModeRead
+		// public repositories are hidden together with their owner
+		visible, err := isOwnerVisible(e, userID, repo)
+		if err != nil {
+			return AccessModeNone, err
+		} else if visible {
+			mode = AccessModeRead
+		}
 	}
 
 	if userID == 0 {
@@ -42,6 +42,35 @@ func TestAccessLevel(t *testing.T) {
 	assert.Equal(t, AccessModeNone, level)
 }
 
+func TestAccessLevel_OwnerVisibility(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// make a repository of organization user3 public
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	repo.IsPrivate = false
+	org := AssertExistsAndLoadBean(t, &User{ID: repo.OwnerID}).(*User)
+
+	test := func(visibility VisibleType, userID int64, expected AccessMode) {
+		repo.Owner = nil
+		_, err := x.ID(org.ID).Cols("visibility").Update(&User{Visibility: visibility})
+		assert.NoError(t, err)
+		level, err := AccessLevel(userID, repo)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, level)
+	}
+	test(VisibleTypePublic, 0, AccessModeRead)
+	test(VisibleTypeLimited, 0, AccessModeNone)
+	test(VisibleTypeLimited, 5, AccessModeRead)
+	test(VisibleTypePrivate, 5, AccessModeNone)
+	// explicit accesses are kept
+	test(VisibleTypePrivate, 4, AccessModeWrite)
+
+	// members without explicit access can still read
+	_, err := x.Insert(&OrgUser{UID: 8, OrgID: org.ID})
+	assert.NoError(t, err)
+	test(VisibleTypePrivate, 8, AccessModeRead)
+}
+
 func TestHasAccess(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -5,7 +5,6 @@
 package models
 
 import (
-	"encoding/json"
 	"fmt"
 	"path"
 	"regexp"
@@ -18,11 +17,11 @@ import (
 	"github.com/go-xorm/xorm"
 
 	"code.gitea.io/git"
-	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // ActionType represents the type of an action.
@@ -398,8 +397,9 @@ func (pc *PushCommits) AvatarLink(email string) string {
 	return pc.avatars[email]
 }
 
-// UpdateIssuesCommit checks if issues are manipulated by commit message.
-func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) error {
+// UpdateIssuesCommit checks if issues are manipulated by commit message, it returns
+// the issues closed or reopened by the commits.
+func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) (changed []*Issue, err error) {
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -427,7 +427,7 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 				if IsErrIssueNotExist(err) || err == errMissingIssueNumber || err == errInvalidIssueNumber {
 					continue
 				}
-				return err
+				return changed, err
 			}
 
 			if refMarked[issue.ID] {
@@ -437,7 +437,7 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 
 			message := fmt.Sprintf(`<a href="%s/commit/%s">%s</a>`, repo.Link(), c.Sha1, c.Message)
 			if err = CreateRefComment(doer, repo, issue, message, c.Sha1); err != nil {
-				return err
+				return changed, err
 			}
 		}
 
@@ -465,7 +465,7 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 				if IsErrIssueNotExist(err) || err == errMissingIssueNumber || err == errInvalidIssueNumber {
 					continue
 				}
-				return err
+				return changed, err
 			}
 
 			if refMarked[issue.ID] {
@@ -478,8 +478,14 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 			}
 
 			if err = issue.ChangeStatus(doer, repo, true); err != nil {
-				return err
+				// Don't return an error when dependencies are open as this would let the push fail
+				if IsErrDependenciesLeft(err) {
+					log.Trace("Issue [%d] not closed by commit %s: %v", issue.ID, c.Sha1, err)
+					continue
+				}
+				return changed, err
 			}
+			changed = append(changed, issue)
 		}
 
 		// It is conflict to have close and reopen at same time, so refsMarked doesn't need to reinit here.
@@ -505,7 +511,7 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 				if IsErrIssueNotExist(err) || err == errMissingIssueNumber || err == errInvalidIssueNumber {
 					continue
 				}
-				return err
+				return changed, err
 			}
 
 			if refMarked[issue.ID] {
@@ -518,11 +524,12 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 			}
 
 			if err = issue.ChangeStatus(doer, repo, false); err != nil {
-				return err
+				return changed, err
 			}
+			changed = append(changed, issue)
 		}
 	}
-	return nil
+	return changed, nil
 }
 
 // CommitRepoActionOptions represent options of a new commit action.
@@ -536,131 +543,70 @@ type CommitRepoActionOptions struct {
 	Commits     *PushCommits
 }
 
-// CommitRepoAction adds new commit action to the repository, and prepare
-// corresponding webhooks.
-func CommitRepoAction(opts CommitRepoActionOptions) error {
+// Push represents an update of a branch or tag of a repository, by a git push or
+// by a commit of Gitea, to be notified to the watchers and webhooks.
+type Push struct {
+	Pusher      *User
+	Repo        *Repository
+	RefFullName string
+	OldCommitID string
+	NewCommitID string
+	Commits     *PushCommits
+	// ChangedIssues are the issues closed or reopened by the commits
+	ChangedIssues []*Issue
+}
+
+// CommitRepoAction updates the repository and the issues referenced by the commits
+// of a push to it, the returned push has to be notified by the caller.
+func CommitRepoAction(opts CommitRepoActionOptions) (*Push, error) {
 	pusher, err := GetUserByName(opts.PusherName)
 	if err != nil {
-		return fmt.Errorf("GetUserByName [%s]: %v", opts.PusherName, err)
+		return nil, fmt.Errorf("GetUserByName [%s]: %v", opts.PusherName, err)
 	}
 
 	repo, err := GetRepositoryByName(opts.RepoOwnerID, opts.RepoName)
 	if err != nil {
-		return fmt.Errorf("GetRepositoryByName [owner_id: %d, name: %s]: %v", opts.RepoOwnerID, opts.RepoName, err)
+		return nil, fmt.Errorf("GetRepositoryByName [owner_id: %d, name: %s]: %v", opts.RepoOwnerID, opts.RepoName, err)
 	}
 
 	// Change repository bare status and update last updated time.
 	repo.IsBare = repo.IsBare && opts.Commits.Len <= 0
 	if err = UpdateRepository(repo, false); err != nil {
-		return fmt.Errorf("UpdateRepository: %v", err)
+		return nil, fmt.Errorf("UpdateRepository: %v", err)
 	}
 
-	isNewBranch := false
-	opType := ActionCommitRepo
+	push := &Push{
+		Pusher:      pusher,
+		Repo:        repo,
+		RefFullName: opts.RefFullName,
+		OldCommitID: opts.OldCommitID,
+		NewCommitID: opts.NewCommitID,
+		Commits:     opts.Commits,
+	}
 	// Check it's tag push or branch.
 	if strings.HasPrefix(opts.RefFullName, git.TagPrefix) {
-		opType = ActionPushTag
-		opts.Commits = &PushCommits{}
+		push.Commits = &PushCommits{}
 	} else {
 		// if not the first commit, set the compare URL.
-		if opts.OldCommitID == git.EmptySHA {
-			isNewBranch = true
-		} else {
-			opts.Commits.CompareURL = repo.ComposeCompareURL(opts.OldCommitID, opts.NewCommitID)
+		if opts.OldCommitID != git.EmptySHA {
+			push.Commits.CompareURL = repo.ComposeCompareURL(opts.OldCommitID, opts.NewCommitID)
 		}
 
-		if err = UpdateIssuesCommit(pusher, repo, opts.Commits.Commits); err != nil {
+		if push.ChangedIssues, err = UpdateIssuesCommit(pusher, repo, push.Commits.Commits); err != nil {
 			log.Error(4, "updateIssuesCommit: %v", err)
 		}
 	}
 
-	if len(opts.Commits.Commits) > setting.UI.FeedMaxCommitNum {
-		opts.Commits.Commits = opts.Commits.Commits[:setting.UI.FeedMaxCommitNum]
-	}
-
-	data, err := json.Marshal(opts.Commits)
-	if err != nil {
-		return fmt.Errorf("Marshal: %v", err)
+	if len(push.Commits.Commits) > setting.UI.FeedMaxCommitNum {
+		push.Commits.Commits = push.Commits.Commits[:setting.UI.FeedMaxCommitNum]
 	}
-
-	refName := git.RefEndName(opts.RefFullName)
-	if err = NotifyWatchers(&Action{
-		ActUserID: pusher.ID,
-		ActUser:   pusher,
-		OpType:    opType,
-		Content:   string(data),
-		RepoID:    repo.ID,
-		Repo:      repo,
-		RefName:   refName,
-		IsPrivate: repo.IsPrivate,
-	}); err != nil {
-		return fmt.Errorf("NotifyWatchers: %v", err)
-	}
-
-	defer func() {
-		go HookQueue.Add(repo.ID)
-	}()
-
-	apiPusher := pusher.APIFormat()
-	apiRepo := repo.APIFormat(AccessModeNone)
-
-	var shaSum string
-	switch opType {
-	case ActionCommitRepo: // Push
-		if err = PrepareWebhooks(repo, HookEventPush, &api.PushPayload{
-			Ref:        opts.RefFullName,
-			Before:     opts.OldCommitID,
-			After:      opts.NewCommitID,
-			CompareURL: setting.AppURL + opts.Commits.CompareURL,
-			Commits:    opts.Commits.ToAPIPayloadCommits(repo.HTMLURL()),
-			Repo:       apiRepo,
-			Pusher:     apiPusher,
-			Sender:     apiPusher,
-		}); err != nil {
-			return fmt.Errorf("PrepareWebhooks: %v", err)
-		}
-
-		if isNewBranch {
-			gitRepo, err := git.OpenRepository(repo.RepoPath())
-			if err != nil {
-				log.Error(4, "OpenRepository[%s]: %v", repo.RepoPath(), err)
-			}
-			shaSum, err = gitRepo.GetBranchCommitID(refName)
-			if err != nil {
-				log.Error(4, "GetBranchCommitID[%s]: %v", opts.RefFullName, err)
-			}
-			return PrepareWebhooks(repo, HookEventCreate, &api.CreatePayload{
-				Ref:     refName,
-				Sha:     shaSum,
-				RefType: "branch",
-				Repo:    apiRepo,
-				Sender:  apiPusher,
-			})
-		}
-
-	case ActionPushTag: // Create
-		gitRepo, err := git.OpenRepository(repo.RepoPath())
-		if err != nil {
-			log.Error(4, "OpenRepository[%s]: %v", repo.RepoPath(), err)
-		}
-		shaSum, err = gitRepo.GetTagCommitID(refName)
-		if err != nil {
-			log.Error(4, "GetTagCommitID[%s]: %v", opts.RefFullName, err)
-		}
-		return PrepareWebhooks(repo, HookEventCreate, &api.CreatePayload{
-			Ref:     refName,
-			Sha:     shaSum,
-			RefType: "tag",
-			Repo:    apiRepo,
-			Sender:  apiPusher,
-		})
-	}
-
-	return nil
+	return push, nil
 }
 
-func transferRepoAction(e Engine, doer, oldOwner *User, repo *Repository) (err error) {
-	if err = notifyWatchers(e, &Action{
+// TransferRepoAction adds new action for transferring repository,
+// the Owner field of repository is assumed to be new owner.
+func TransferRepoAction(doer, oldOwner *User, repo *Repository) error {
+	return NotifyWatchers(&Action{
 		ActUserID: doer.ID,
 		ActUser:   doer,
 		OpType:    ActionTransferRepo,
@@ -668,24 +614,7 @@ func transferRepoAction(e Engine, doer, oldOwner *User, repo *Repository) (err e
 		Repo:      repo,
 		IsPrivate: repo.IsPrivate,
 		Content:   path.Join(oldOwner.Name, repo.Name),
-	}); err != nil {
-		return fmt.Errorf("notifyWatchers: %v", err)
-	}
-
-	// Remove watch for organization.
-	if oldOwner.IsOrganization() {
-		if err = watchRepo(e, oldOwner.ID, repo.ID, false); err != nil {
-			return fmt.Errorf("watchRepo [false]: %v", err)
-		}
-	}
-
-	return nil
-}
-
-// TransferRepoAction adds new action for transferring repository,
-// the Owner field of repository is assumed to be new owner.
-func TransferRepoAction(doer, oldOwner *User, repo *Repository) error {
-	return transferRepoAction(x, doer, oldOwner, repo)
+	})
 }
 
 func mergePullRequestAction(e Engine, doer *User, repo *Repository, issue *Issue) error {
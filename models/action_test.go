@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/git"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
@@ -195,7 +196,12 @@ func TestUpdateIssuesCommit(t *testing.T) {
 
 	AssertNotExistsBean(t, commentBean)
 	AssertNotExistsBean(t, &Issue{RepoID: repo.ID, Index: 2}, "is_closed=1")
-	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits))
+	changed, err := UpdateIssuesCommit(user, repo, pushCommits)
+	assert.NoError(t, err)
+	if assert.Len(t, changed, 1) {
+		assert.EqualValues(t, 2, changed[0].Index)
+		assert.True(t, changed[0].IsClosed)
+	}
 	AssertExistsAndLoadBean(t, commentBean)
 	AssertExistsAndLoadBean(t, issueBean, "is_closed=1")
 	CheckConsistencyFor(t, &Action{})
@@ -229,27 +235,22 @@ func TestCommitRepoAction(t *testing.T) {
 	}
 	pushCommits.Len = len(pushCommits.Commits)
 
-	actionBean := &Action{
-		OpType:    ActionCommitRepo,
-		ActUserID: user.ID,
-		ActUser:   user,
-		RepoID:    repo.ID,
-		Repo:      repo,
-		RefName:   "refName",
-		IsPrivate: repo.IsPrivate,
-	}
-	AssertNotExistsBean(t, actionBean)
-	assert.NoError(t, CommitRepoAction(CommitRepoActionOptions{
+	push, err := CommitRepoAction(CommitRepoActionOptions{
 		PusherName:  user.Name,
 		RepoOwnerID: user.ID,
 		RepoName:    repo.Name,
-		RefFullName: "refName",
+		RefFullName: git.BranchPrefix + "master",
 		OldCommitID: "oldCommitID",
 		NewCommitID: "newCommitID",
 		Commits:     pushCommits,
-	}))
-	AssertExistsAndLoadBean(t, actionBean)
-	CheckConsistencyFor(t, &Action{})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, push.Pusher.ID)
+	assert.Equal(t, repo.ID, push.Repo.ID)
+	assert.Equal(t, git.BranchPrefix+"master", push.RefFullName)
+	assert.Equal(t, repo.ComposeCompareURL("oldCommitID", "newCommitID"), push.Commits.CompareURL)
+	assert.Len(t, push.Commits.Commits, 2)
+	assert.Empty(t, push.ChangedIssues)
 }
 
 func TestTransferRepoAction(t *testing.T) {
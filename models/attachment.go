@@ -5,17 +5,17 @@
 package models
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
 	"path"
 	"time"
 
 	"github.com/go-xorm/xorm"
 	gouuid "github.com/satori/go.uuid"
 
-	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 )
 
 // Attachment represent a attachment of issue/comment/release.
@@ -58,15 +58,15 @@ func (a *Attachment) IncreaseDownloadCount() error {
 	return nil
 }
 
-// AttachmentLocalPath returns where attachment is stored in local file
-// system based on given UUID.
-func AttachmentLocalPath(uuid string) string {
-	return path.Join(setting.AttachmentPath, uuid[0:1], uuid[1:2], uuid)
+// AttachmentRelativePath returns the relative path of the attachment
+// inside the attachments storage based on given UUID.
+func AttachmentRelativePath(uuid string) string {
+	return path.Join(uuid[0:1], uuid[1:2], uuid)
 }
 
-// LocalPath returns where attachment is stored in local file system.
-func (a *Attachment) LocalPath() string {
-	return AttachmentLocalPath(a.UUID)
+// RelativePath returns the relative path of the attachment inside the attachments storage.
+func (a *Attachment) RelativePath() string {
+	return AttachmentRelativePath(a.UUID)
 }
 
 // NewAttachment creates a new attachment object.
@@ -76,21 +76,8 @@ func NewAttachment(name string, buf []byte, file multipart.File) (_ *Attachment,
 		Name: name,
 	}
 
-	localPath := attach.LocalPath()
-	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
-		return nil, fmt.Errorf("MkdirAll: %v", err)
-	}
-
-	fw, err := os.Create(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("Create: %v", err)
-	}
-	defer fw.Close()
-
-	if _, err = fw.Write(buf); err != nil {
-		return nil, fmt.Errorf("Write: %v", err)
-	} else if _, err = io.Copy(fw, file); err != nil {
-		return nil, fmt.Errorf("Copy: %v", err)
+	if _, err = storage.Attachments.Save(attach.RelativePath(), io.MultiReader(bytes.NewReader(buf), file)); err != nil {
+		return nil, fmt.Errorf("Save: %v", err)
 	}
 
 	if _, err := x.Insert(attach); err != nil {
@@ -152,7 +139,7 @@ func DeleteAttachment(a *Attachment, remove bool) error {
 func DeleteAttachments(attachments []*Attachment, remove bool) (int, error) {
 	for i, a := range attachments {
 		if remove {
-			if err := os.Remove(a.LocalPath()); err != nil {
+			if err := storage.Attachments.Delete(a.RelativePath()); err != nil {
 				return i, err
 			}
 		}
@@ -186,3 +173,10 @@ func DeleteAttachmentsByComment(commentID int64, remove bool) (int, error) {
 
 	return DeleteAttachments(attachments, remove)
 }
+
+// IterateAttachment iterates over all attachments, release assets included.
+func IterateAttachment(f func(attach *Attachment) error) error {
+	return x.Iterate(new(Attachment), func(idx int, bean interface{}) error {
+		return f(bean.(*Attachment))
+	})
+}
@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
 )
 
 const (
@@ -17,14 +20,29 @@ const (
 
 // ProtectedBranch struct
 type ProtectedBranch struct {
-	ID          int64  `xorm:"pk autoincr"`
-	RepoID      int64  `xorm:"UNIQUE(s)"`
-	BranchName  string `xorm:"UNIQUE(s)"`
-	CanPush     bool
-	Created     time.Time `xorm:"-"`
-	CreatedUnix int64
-	Updated     time.Time `xorm:"-"`
-	UpdatedUnix int64
+	ID         int64  `xorm:"pk autoincr"`
+	RepoID     int64  `xorm:"UNIQUE(s)"`
+	BranchName string `xorm:"UNIQUE(s)"`
+	CanPush    bool
+	// EnableWhitelist restricts pushing to the whitelisted users and teams
+	EnableWhitelist  bool    `xorm:"NOT NULL DEFAULT false"`
+	WhitelistUserIDs []int64 `xorm:"JSON TEXT"`
+	WhitelistTeamIDs []int64 `xorm:"JSON TEXT"`
+	// EnableMergeWhitelist restricts merging pull requests to the whitelisted users and teams
+	EnableMergeWhitelist  bool    `xorm:"NOT NULL DEFAULT false"`
+	MergeWhitelistUserIDs []int64 `xorm:"JSON TEXT"`
+	MergeWhitelistTeamIDs []int64 `xorm:"JSON TEXT"`
+	// RequiredApprovals is the number of approving reviews needed to merge a pull request
+	RequiredApprovals int64 `xorm:"NOT NULL DEFAULT 0"`
+	// EnableStatusCheck requires the given commit status contexts to be successful to merge a pull request
+	EnableStatusCheck   bool     `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts []string `xorm:"JSON TEXT"`
+	// RequireSignedCommits rejects commits which are not signed by a known key
+	RequireSignedCommits bool      `xorm:"NOT NULL DEFAULT false"`
+	Created              time.Time `xorm:"-"`
+	CreatedUnix          int64
+	Updated              time.Time `xorm:"-"`
+	UpdatedUnix          int64
 }
 
 // BeforeInsert before protected branch insert create and update time
@@ -38,6 +56,232 @@ func (protectBranch *ProtectedBranch) BeforeUpdate() {
 	protectBranch.UpdatedUnix = time.Now().Unix()
 }
 
+// CanUserPush returns if some user could push to this protected branch
+func (protectBranch *ProtectedBranch) CanUserPush(userID int64) bool {
+	if !protectBranch.CanPush {
+		return false
+	}
+	if !protectBranch.EnableWhitelist {
+		return true
+	}
+	return protectBranch.isWhitelisted(x, userID, protectBranch.WhitelistUserIDs, protectBranch.WhitelistTeamIDs)
+}
+
+// CanUserCommit returns if some user could commit to this protected branch without
+// a pull request, e.g. with the web editor or the contents API. Such commits can not
+// be approved or get successful status checks before they land on the branch, and
+// they need to be signed by Gitea if the branch requires signed commits.
+func (protectBranch *ProtectedBranch) CanUserCommit(repo *Repository, u *User) bool {
+	if !protectBranch.EnableWhitelist || !protectBranch.CanUserPush(u.ID) {
+		return false
+	}
+	if protectBranch.RequiredApprovals > 0 ||
+		(protectBranch.EnableStatusCheck && len(protectBranch.StatusCheckContexts) > 0) {
+		return false
+	}
+	return !protectBranch.RequireSignedCommits || len(repo.signCRUDAction(u, protectBranch.BranchName)) > 0
+}
+
+// IsMergeStyleAllowed returns if pull requests could be merged into this protected
+// branch with given style. Rebased commits are not signed by Gitea, so the rebase
+// styles are not allowed if the branch requires signed commits.
+func (protectBranch *ProtectedBranch) IsMergeStyleAllowed(mergeStyle MergeStyle) bool {
+	return !protectBranch.RequireSignedCommits ||
+		mergeStyle != MergeStyleRebase && mergeStyle != MergeStyleRebaseMerge
+}
+
+// CanUserMerge returns if some user could merge a pull request to this protected branch
+func (protectBranch *ProtectedBranch) CanUserMerge(userID int64) bool {
+	if !protectBranch.EnableMergeWhitelist {
+		return true
+	}
+	return protectBranch.isWhitelisted(x, userID, protectBranch.MergeWhitelistUserIDs, protectBranch.MergeWhitelistTeamIDs)
+}
+
+func (protectBranch *ProtectedBranch) isWhitelisted(e Engine, userID int64, userIDs, teamIDs []int64) bool {
+	if userID <= 0 {
+		return false
+	}
+	if base.Int64sContains(userIDs, userID) {
+		return true
+	}
+	if len(teamIDs) == 0 {
+		return false
+	}
+
+	in, err := isUserInTeams(e, userID, teamIDs)
+	if err != nil {
+		log.Error(2, "isUserInTeams: %v", err)
+		return false
+	}
+	return in
+}
+
+// GetGrantedApprovalsCount returns the number of reviewers with write access
+// whose latest review of the pull request approves it.
+func (protectBranch *ProtectedBranch) GetGrantedApprovalsCount(pr *PullRequest) (int64, error) {
+	if err := pr.GetBaseRepo(); err != nil {
+		return 0, err
+	}
+	reviews, err := GetReviewsByIssueID(pr.IssueID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Reviews are sorted by creation, so later reviews overwrite earlier ones.
+	latest := make(map[int64]ReviewType, len(reviews))
+	for _, review := range reviews {
+		if review.Type == ReviewTypeApprove || review.Type == ReviewTypeReject {
+			latest[review.ReviewerID] = review.Type
+		}
+	}
+
+	var approvals int64
+	for reviewerID, reviewType := range latest {
+		if reviewType != ReviewTypeApprove {
+			continue
+		}
+		has, err := HasAccess(reviewerID, pr.BaseRepo, AccessModeWrite)
+		if err != nil {
+			return 0, err
+		} else if has {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
+
+// HasEnoughApprovals returns true if the pull request has the required number of approvals
+func (protectBranch *ProtectedBranch) HasEnoughApprovals(pr *PullRequest) (bool, error) {
+	if protectBranch.RequiredApprovals <= 0 {
+		return true, nil
+	}
+	approvals, err := protectBranch.GetGrantedApprovalsCount(pr)
+	if err != nil {
+		return false, err
+	}
+	return approvals >= protectBranch.RequiredApprovals, nil
+}
+
+// GetMissingStatusContexts returns the required status contexts which are not
+// successful for the given commit of the repository.
+func (protectBranch *ProtectedBranch) GetMissingStatusContexts(repo *Repository, sha string) ([]string, error) {
+	if !protectBranch.EnableStatusCheck || len(protectBranch.StatusCheckContexts) == 0 {
+		return nil, nil
+	}
+
+	succeeded := make(map[string]bool)
+	for page := 0; ; page++ {
+		statuses, err := GetLatestCommitStatus(repo, sha, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			if status.State == CommitStatusSuccess {
+				succeeded[status.Context] = true
+			}
+		}
+		if len(statuses) < 10 {
+			break
+		}
+	}
+
+	var missing []string
+	for _, context := range protectBranch.StatusCheckContexts {
+		if !succeeded[context] {
+			missing = append(missing, context)
+		}
+	}
+	return missing, nil
+}
+
+// GetProtectedBranchByID getting protected branch by ID
+func GetProtectedBranchByID(id int64) (*ProtectedBranch, error) {
+	rel := &ProtectedBranch{ID: id}
+	has, err := x.Get(rel)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	return rel, nil
+}
+
+// UpdateProtectBranchOptions represents the whitelists of a protected branch given by user and team IDs
+type UpdateProtectBranchOptions struct {
+	WhitelistUserIDs      []int64
+	WhitelistTeamIDs      []int64
+	MergeWhitelistUserIDs []int64
+	MergeWhitelistTeamIDs []int64
+}
+
+// UpdateProtectBranch saves the branch protection of a repository. Whitelisted users
+// need write access to the repository and teams have to belong to its owner organization,
+// other IDs are dropped.
+func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, opts UpdateProtectBranchOptions) (err error) {
+	if err = repo.GetOwner(); err != nil {
+		return fmt.Errorf("GetOwner: %v", err)
+	}
+
+	if protectBranch.WhitelistUserIDs, err = updateUserWhitelist(repo, opts.WhitelistUserIDs); err != nil {
+		return err
+	}
+	if protectBranch.MergeWhitelistUserIDs, err = updateUserWhitelist(repo, opts.MergeWhitelistUserIDs); err != nil {
+		return err
+	}
+	if protectBranch.WhitelistTeamIDs, err = updateTeamWhitelist(repo, opts.WhitelistTeamIDs); err != nil {
+		return err
+	}
+	if protectBranch.MergeWhitelistTeamIDs, err = updateTeamWhitelist(repo, opts.MergeWhitelistTeamIDs); err != nil {
+		return err
+	}
+
+	protectBranch.RepoID = repo.ID
+	protectBranch.BranchName = strings.ToLower(protectBranch.BranchName)
+	if protectBranch.ID == 0 {
+		_, err = x.InsertOne(protectBranch)
+		return err
+	}
+	_, err = x.Id(protectBranch.ID).AllCols().Update(protectBranch)
+	return err
+}
+
+// updateUserWhitelist checks whether the users are allowed to be whitelisted
+func updateUserWhitelist(repo *Repository, userIDs []int64) ([]int64, error) {
+	whitelist := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		has, err := HasAccess(userID, repo, AccessModeWrite)
+		if err != nil {
+			return nil, fmt.Errorf("HasAccess [user_id: %d, repo_id: %d]: %v", userID, repo.ID, err)
+		} else if has && !base.Int64sContains(whitelist, userID) {
+			whitelist = append(whitelist, userID)
+		}
+	}
+	return whitelist, nil
+}
+
+// updateTeamWhitelist checks whether the teams are allowed to be whitelisted
+func updateTeamWhitelist(repo *Repository, teamIDs []int64) ([]int64, error) {
+	whitelist := make([]int64, 0, len(teamIDs))
+	if !repo.Owner.IsOrganization() {
+		return whitelist, nil
+	}
+	for _, teamID := range teamIDs {
+		team, err := GetTeamByID(teamID)
+		if err != nil {
+			if err == ErrTeamNotExist {
+				continue
+			}
+			return nil, fmt.Errorf("GetTeamByID [team_id: %d]: %v", teamID, err)
+		}
+		if team.OrgID == repo.OwnerID && !base.Int64sContains(whitelist, teamID) {
+			whitelist = append(whitelist, teamID)
+		}
+	}
+	return whitelist, nil
+}
+
 // GetProtectedBranchByRepoID getting protected branch by repo ID
 func GetProtectedBranchByRepoID(RepoID int64) ([]*ProtectedBranch, error) {
 	protectedBranches := make([]*ProtectedBranch, 0)
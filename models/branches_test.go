@@ -0,0 +1,179 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedBranch_CanUserPush(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	protectBranch := &ProtectedBranch{RepoID: 3, BranchName: "master"}
+	assert.False(t, protectBranch.CanUserPush(2))
+
+	protectBranch.CanPush = true
+	assert.True(t, protectBranch.CanUserPush(2))
+	assert.True(t, protectBranch.CanUserPush(4))
+
+	protectBranch.EnableWhitelist = true
+	protectBranch.WhitelistUserIDs = []int64{4}
+	assert.False(t, protectBranch.CanUserPush(2))
+	assert.True(t, protectBranch.CanUserPush(4))
+	assert.False(t, protectBranch.CanUserPush(0))
+
+	// user 2 is member of team 1
+	protectBranch.WhitelistTeamIDs = []int64{1}
+	assert.True(t, protectBranch.CanUserPush(2))
+	assert.False(t, protectBranch.CanUserPush(5))
+}
+
+func TestProtectedBranch_CanUserCommit(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	protectBranch := &ProtectedBranch{RepoID: repo.ID, BranchName: "master", CanPush: true}
+	assert.False(t, protectBranch.CanUserCommit(repo, user))
+
+	protectBranch.EnableWhitelist = true
+	protectBranch.WhitelistUserIDs = []int64{user.ID}
+	assert.True(t, protectBranch.CanUserCommit(repo, user))
+
+	// direct commits can not be approved
+	protectBranch.RequiredApprovals = 1
+	assert.False(t, protectBranch.CanUserCommit(repo, user))
+	protectBranch.RequiredApprovals = 0
+
+	// nor get successful status checks
+	protectBranch.EnableStatusCheck = true
+	protectBranch.StatusCheckContexts = []string{"ci"}
+	assert.False(t, protectBranch.CanUserCommit(repo, user))
+	protectBranch.EnableStatusCheck = false
+
+	oldSigning := setting.Repository.Signing
+	defer func() { setting.Repository.Signing = oldSigning }()
+	protectBranch.RequireSignedCommits = true
+	setting.Repository.Signing.SigningKey = "none"
+	assert.False(t, protectBranch.CanUserCommit(repo, user))
+	setting.Repository.Signing.SigningKey = "gitea@example.com"
+	setting.Repository.Signing.CRUDActions = []string{SigningRuleAlways}
+	assert.True(t, protectBranch.CanUserCommit(repo, user))
+}
+
+func TestProtectedBranch_IsMergeStyleAllowed(t *testing.T) {
+	protectBranch := &ProtectedBranch{RepoID: 1, BranchName: "master"}
+	assert.True(t, protectBranch.IsMergeStyleAllowed(MergeStyleRebase))
+
+	protectBranch.RequireSignedCommits = true
+	assert.True(t, protectBranch.IsMergeStyleAllowed(MergeStyleMerge))
+	assert.False(t, protectBranch.IsMergeStyleAllowed(MergeStyleRebase))
+	assert.False(t, protectBranch.IsMergeStyleAllowed(MergeStyleRebaseMerge))
+	assert.True(t, protectBranch.IsMergeStyleAllowed(MergeStyleSquash))
+}
+
+func TestProtectedBranch_CanUserMerge(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	protectBranch := &ProtectedBranch{RepoID: 3, BranchName: "master"}
+	assert.True(t, protectBranch.CanUserMerge(2))
+
+	protectBranch.EnableMergeWhitelist = true
+	assert.False(t, protectBranch.CanUserMerge(2))
+
+	protectBranch.MergeWhitelistTeamIDs = []int64{2}
+	assert.True(t, protectBranch.CanUserMerge(2))
+	assert.True(t, protectBranch.CanUserMerge(4))
+	assert.False(t, protectBranch.CanUserMerge(5))
+}
+
+func TestProtectedBranch_HasEnoughApprovals(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	protectBranch := &ProtectedBranch{RepoID: pr.BaseRepoID, BranchName: pr.BaseBranch}
+
+	enough, err := protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.True(t, enough)
+
+	// the approval of user 4 does not count as user 4 has no write access
+	protectBranch.RequiredApprovals = 1
+	count, err := protectBranch.GetGrantedApprovalsCount(pr)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	enough, err = protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.False(t, enough)
+
+	// user 2 replaces the rejecting review by an approval
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: pr.IssueID}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err = CreateReview(CreateReviewOptions{
+		Type:     ReviewTypeApprove,
+		Issue:    issue,
+		Reviewer: user,
+	})
+	assert.NoError(t, err)
+	enough, err = protectBranch.HasEnoughApprovals(pr)
+	assert.NoError(t, err)
+	assert.True(t, enough)
+}
+
+func TestProtectedBranch_GetMissingStatusContexts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	sha := "1234123412341234123412341234123412341234"
+	protectBranch := &ProtectedBranch{
+		RepoID:              repo.ID,
+		BranchName:          "master",
+		StatusCheckContexts: []string{"ci/awesomeness", "cov/awesomeness"},
+	}
+
+	missing, err := protectBranch.GetMissingStatusContexts(repo, sha)
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+
+	protectBranch.EnableStatusCheck = true
+	missing, err = protectBranch.GetMissingStatusContexts(repo, sha)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ci/awesomeness"}, missing)
+}
+
+func TestUpdateProtectBranch(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// repository 3 is owned by organization 3
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	protectBranch := &ProtectedBranch{
+		BranchName:        "Master",
+		CanPush:           true,
+		EnableWhitelist:   true,
+		RequiredApprovals: 2,
+	}
+	assert.NoError(t, UpdateProtectBranch(repo, protectBranch, UpdateProtectBranchOptions{
+		// user 5 has no write access
+		WhitelistUserIDs: []int64{2, 4, 5, 4},
+		// team 100 does not exist
+		WhitelistTeamIDs:      []int64{1, 100},
+		MergeWhitelistUserIDs: []int64{4},
+	}))
+
+	protectBranch, err := GetProtectedBranchBy(repo.ID, "master")
+	assert.NoError(t, err)
+	if assert.NotNil(t, protectBranch) {
+		assert.Equal(t, []int64{2, 4}, protectBranch.WhitelistUserIDs)
+		assert.Equal(t, []int64{1}, protectBranch.WhitelistTeamIDs)
+		assert.Equal(t, []int64{4}, protectBranch.MergeWhitelistUserIDs)
+		assert.EqualValues(t, 2, protectBranch.RequiredApprovals)
+		assert.False(t, protectBranch.CanUserPush(5))
+		assert.True(t, protectBranch.CanUserPush(4))
+	}
+}
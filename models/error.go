@@ -338,6 +338,22 @@ func (err ErrGPGKeyAccessDenied) Error() string {
 		err.UserID, err.KeyID)
 }
 
+// ErrUnverifiedCommit represents a "UnverifiedCommit" kind of error.
+type ErrUnverifiedCommit struct {
+	ID     string
+	Reason string
+}
+
+// IsErrUnverifiedCommit checks if an error is a ErrUnverifiedCommit.
+func IsErrUnverifiedCommit(err error) bool {
+	_, ok := err.(ErrUnverifiedCommit)
+	return ok
+}
+
+func (err ErrUnverifiedCommit) Error() string {
+	return fmt.Sprintf("commit signature can not be verified [id: %s, reason: %s]", err.ID, err.Reason)
+}
+
 // ErrKeyAccessDenied represents a "KeyAccessDenied" kind of error.
 type ErrKeyAccessDenied struct {
 	UserID int64
@@ -441,6 +457,21 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return fmt.Sprintf("access token is empty")
 }
 
+// ErrInvalidAccessTokenScope represents a "InvalidAccessTokenScope" kind of error.
+type ErrInvalidAccessTokenScope struct {
+	Scope string
+}
+
+// IsErrInvalidAccessTokenScope checks if an error is a ErrInvalidAccessTokenScope.
+func IsErrInvalidAccessTokenScope(err error) bool {
+	_, ok := err.(ErrInvalidAccessTokenScope)
+	return ok
+}
+
+func (err ErrInvalidAccessTokenScope) Error() string {
+	return fmt.Sprintf("invalid access token scope [scope: %s]", err.Scope)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -553,6 +584,22 @@ func (err ErrInvalidCloneAddr) Error() string {
 		err.IsURLError, err.IsInvalidPath, err.IsPermissionDenied)
 }
 
+// ErrPushMirrorNotExist represents a "PushMirrorNotExist" kind of error.
+type ErrPushMirrorNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrPushMirrorNotExist checks if an error is a ErrPushMirrorNotExist.
+func IsErrPushMirrorNotExist(err error) bool {
+	_, ok := err.(ErrPushMirrorNotExist)
+	return ok
+}
+
+func (err ErrPushMirrorNotExist) Error() string {
+	return fmt.Sprintf("push mirror does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
 // ErrUpdateTaskNotExist represents a "UpdateTaskNotExist" kind of error.
 type ErrUpdateTaskNotExist struct {
 	UUID string
@@ -614,6 +661,36 @@ func (err ErrInvalidTagName) Error() string {
 	return fmt.Sprintf("release tag name is not valid [tag_name: %s]", err.TagName)
 }
 
+// ErrTagAlreadyExists represents a "TagAlreadyExists" kind of error.
+type ErrTagAlreadyExists struct {
+	TagName string
+}
+
+// IsErrTagAlreadyExists checks if an error is a ErrTagAlreadyExists.
+func IsErrTagAlreadyExists(err error) bool {
+	_, ok := err.(ErrTagAlreadyExists)
+	return ok
+}
+
+func (err ErrTagAlreadyExists) Error() string {
+	return fmt.Sprintf("tag already exists [tag_name: %s]", err.TagName)
+}
+
+// ErrTagNotExist represents a "TagNotExist" kind of error.
+type ErrTagNotExist struct {
+	TagName string
+}
+
+// IsErrTagNotExist checks if an error is a ErrTagNotExist.
+func IsErrTagNotExist(err error) bool {
+	_, ok := err.(ErrTagNotExist)
+	return ok
+}
+
+func (err ErrTagNotExist) Error() string {
+	return fmt.Sprintf("tag does not exist [tag_name: %s]", err.TagName)
+}
+
 // ErrRepoFileAlreadyExist represents a "RepoFileAlreadyExist" kind of error.
 type ErrRepoFileAlreadyExist struct {
 	FileName string
@@ -629,6 +706,38 @@ func (err ErrRepoFileAlreadyExist) Error() string {
 	return fmt.Sprintf("repository file already exists [file_name: %s]", err.FileName)
 }
 
+// ErrSHADoesNotMatch represents a "SHADoesNotMatch" kind of error.
+type ErrSHADoesNotMatch struct {
+	Path       string
+	GivenSHA   string
+	CurrentSHA string
+}
+
+// IsErrSHADoesNotMatch checks if an error is a ErrSHADoesNotMatch.
+func IsErrSHADoesNotMatch(err error) bool {
+	_, ok := err.(ErrSHADoesNotMatch)
+	return ok
+}
+
+func (err ErrSHADoesNotMatch) Error() string {
+	return fmt.Sprintf("sha does not match [path: %s, given: %s, current: %s]", err.Path, err.GivenSHA, err.CurrentSHA)
+}
+
+// ErrFilePathInvalid represents a "FilePathInvalid" kind of error.
+type ErrFilePathInvalid struct {
+	Path string
+}
+
+// IsErrFilePathInvalid checks if an error is a ErrFilePathInvalid.
+func IsErrFilePathInvalid(err error) bool {
+	_, ok := err.(ErrFilePathInvalid)
+	return ok
+}
+
+func (err ErrFilePathInvalid) Error() string {
+	return fmt.Sprintf("path is invalid [path: %s]", err.Path)
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -651,6 +760,51 @@ func (err ErrBranchNotExist) Error() string {
 	return fmt.Sprintf("branch does not exist [name: %s]", err.Name)
 }
 
+// ErrBranchAlreadyExists represents a "BranchAlreadyExists" kind of error.
+type ErrBranchAlreadyExists struct {
+	Name string
+}
+
+// IsErrBranchAlreadyExists checks if an error is a ErrBranchAlreadyExists.
+func IsErrBranchAlreadyExists(err error) bool {
+	_, ok := err.(ErrBranchAlreadyExists)
+	return ok
+}
+
+func (err ErrBranchAlreadyExists) Error() string {
+	return fmt.Sprintf("branch already exists [name: %s]", err.Name)
+}
+
+// ErrBranchIsDefault represents a "BranchIsDefault" kind of error.
+type ErrBranchIsDefault struct {
+	Name string
+}
+
+// IsErrBranchIsDefault checks if an error is a ErrBranchIsDefault.
+func IsErrBranchIsDefault(err error) bool {
+	_, ok := err.(ErrBranchIsDefault)
+	return ok
+}
+
+func (err ErrBranchIsDefault) Error() string {
+	return fmt.Sprintf("branch is the default branch [name: %s]", err.Name)
+}
+
+// ErrBranchIsProtected represents a "BranchIsProtected" kind of error.
+type ErrBranchIsProtected struct {
+	Name string
+}
+
+// IsErrBranchIsProtected checks if an error is a ErrBranchIsProtected.
+func IsErrBranchIsProtected(err error) bool {
+	_, ok := err.(ErrBranchIsProtected)
+	return ok
+}
+
+func (err ErrBranchIsProtected) Error() string {
+	return fmt.Sprintf("branch is protected [name: %s]", err.Name)
+}
+
 //  __      __      ___.   .__                   __
 // /  \    /  \ ____\_ |__ |  |__   ____   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \ /  _ \ /  _ \|  |/ /
@@ -673,6 +827,22 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrHookTaskNotExist represents a "HookTaskNotExist" kind of error.
+type ErrHookTaskNotExist struct {
+	ID     int64
+	HookID int64
+}
+
+// IsErrHookTaskNotExist checks if an error is a ErrHookTaskNotExist.
+func IsErrHookTaskNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskNotExist)
+	return ok
+}
+
+func (err ErrHookTaskNotExist) Error() string {
+	return fmt.Sprintf("hook task does not exist [id: %d, hook_id: %d]", err.ID, err.HookID)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -697,6 +867,86 @@ func (err ErrIssueNotExist) Error() string {
 	return fmt.Sprintf("issue does not exist [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
 }
 
+// ErrCircularDependency represents a "CircularDependency" kind of error.
+type ErrCircularDependency struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrCircularDependency checks if an error is a ErrCircularDependency.
+func IsErrCircularDependency(err error) bool {
+	_, ok := err.(ErrCircularDependency)
+	return ok
+}
+
+func (err ErrCircularDependency) Error() string {
+	return fmt.Sprintf("circular dependency [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependencyCrossRepository represents a "DependencyCrossRepository" kind of error.
+type ErrDependencyCrossRepository struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyCrossRepository checks if an error is a ErrDependencyCrossRepository.
+func IsErrDependencyCrossRepository(err error) bool {
+	_, ok := err.(ErrDependencyCrossRepository)
+	return ok
+}
+
+func (err ErrDependencyCrossRepository) Error() string {
+	return fmt.Sprintf("dependency on an issue of another repository [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependencyExists represents a "DependencyExists" kind of error.
+type ErrDependencyExists struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyExists checks if an error is a ErrDependencyExists.
+func IsErrDependencyExists(err error) bool {
+	_, ok := err.(ErrDependencyExists)
+	return ok
+}
+
+func (err ErrDependencyExists) Error() string {
+	return fmt.Sprintf("issue dependency already exists [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependencyNotExists represents a "DependencyNotExists" kind of error.
+type ErrDependencyNotExists struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyNotExists checks if an error is a ErrDependencyNotExists.
+func IsErrDependencyNotExists(err error) bool {
+	_, ok := err.(ErrDependencyNotExists)
+	return ok
+}
+
+func (err ErrDependencyNotExists) Error() string {
+	return fmt.Sprintf("issue dependency does not exist [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependenciesLeft represents an error that an issue cannot be closed
+// while the issues it depends on are still open.
+type ErrDependenciesLeft struct {
+	IssueID int64
+}
+
+// IsErrDependenciesLeft checks if an error is a ErrDependenciesLeft.
+func IsErrDependenciesLeft(err error) bool {
+	_, ok := err.(ErrDependenciesLeft)
+	return ok
+}
+
+func (err ErrDependenciesLeft) Error() string {
+	return fmt.Sprintf("issue has open dependencies [issue_id: %d]", err.IssueID)
+}
+
 // __________      .__  .__ __________                                     __
 // \______   \__ __|  | |  |\______   \ ____  ________ __   ____   _______/  |_
 //  |     ___/  |  \  | |  | |       _// __ \/ ____/  |  \_/ __ \ /  ___/\   __\
@@ -747,6 +997,38 @@ func (err ErrPullRequestAlreadyExists) Error() string {
 		err.ID, err.IssueID, err.HeadRepoID, err.BaseRepoID, err.HeadBranch, err.BaseBranch)
 }
 
+// ErrNotAllowedToMerge represents an error that a branch is protected and the current user is not allowed to modify it
+type ErrNotAllowedToMerge struct {
+	Reason string
+}
+
+// IsErrNotAllowedToMerge checks if an error is an ErrNotAllowedToMerge.
+func IsErrNotAllowedToMerge(err error) bool {
+	_, ok := err.(ErrNotAllowedToMerge)
+	return ok
+}
+
+func (err ErrNotAllowedToMerge) Error() string {
+	return fmt.Sprintf("not allowed to merge [reason: %s]", err.Reason)
+}
+
+// ErrInvalidMergeStyle represents an error if merging with disabled merge strategy
+type ErrInvalidMergeStyle struct {
+	ID    int64
+	Style MergeStyle
+}
+
+// IsErrInvalidMergeStyle checks if an error is a ErrInvalidMergeStyle.
+func IsErrInvalidMergeStyle(err error) bool {
+	_, ok := err.(ErrInvalidMergeStyle)
+	return ok
+}
+
+func (err ErrInvalidMergeStyle) Error() string {
+	return fmt.Sprintf("merge strategy is not allowed or is invalid [repo_id: %d, strategy: %s]",
+		err.ID, err.Style)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -770,6 +1052,81 @@ func (err ErrCommentNotExist) Error() string {
 	return fmt.Sprintf("comment does not exist [id: %d, issue_id: %d]", err.ID, err.IssueID)
 }
 
+// __________                     __  .__
+// \______   \ ____ _____    _____/  |_|__| ____   ____
+//  |       _// __ \\__  \ _/ ___\   __\  |/  _ \ /    \
+//  |    |   \  ___/ / __ \\  \___|  | |  (  <_> )   |  \
+//  |____|_  /\___  >____  /\___  >__| |__|\____/|___|  /
+//         \/     \/     \/     \/                    \/
+
+// ErrForbiddenReaction represents a "ForbiddenReaction" kind of error.
+type ErrForbiddenReaction struct {
+	Reaction string
+}
+
+// IsErrForbiddenReaction checks if an error is a ErrForbiddenReaction.
+func IsErrForbiddenReaction(err error) bool {
+	_, ok := err.(ErrForbiddenReaction)
+	return ok
+}
+
+func (err ErrForbiddenReaction) Error() string {
+	return fmt.Sprintf("reaction is not allowed [reaction: %s]", err.Reaction)
+}
+
+// ErrReactionAlreadyExist represents a "ReactionAlreadyExist" kind of error.
+type ErrReactionAlreadyExist struct {
+	Reaction string
+}
+
+// IsErrReactionAlreadyExist checks if an error is a ErrReactionAlreadyExist.
+func IsErrReactionAlreadyExist(err error) bool {
+	_, ok := err.(ErrReactionAlreadyExist)
+	return ok
+}
+
+func (err ErrReactionAlreadyExist) Error() string {
+	return fmt.Sprintf("reaction already exists [reaction: %s]", err.Reaction)
+}
+
+// __________            .__
+// \______   \ _______  _|__| ______  _  __
+//  |       _// __ \  \/ /  |/ __ \ \/ \/ /
+//  |    |   \  ___/\   /|  \  ___/\     /
+//  |____|_  /\___  >\_/ |__|\___  >\/\_/
+//         \/     \/             \/
+
+// ErrReviewNotExist represents a "ReviewNotExist" kind of error.
+type ErrReviewNotExist struct {
+	ID int64
+}
+
+// IsErrReviewNotExist checks if an error is a ErrReviewNotExist.
+func IsErrReviewNotExist(err error) bool {
+	_, ok := err.(ErrReviewNotExist)
+	return ok
+}
+
+func (err ErrReviewNotExist) Error() string {
+	return fmt.Sprintf("review does not exist [id: %d]", err.ID)
+}
+
+// ErrReviewOwnPullRequest represents a "ReviewOwnPullRequest" kind of error,
+// the poster of a pull request can neither approve nor reject it.
+type ErrReviewOwnPullRequest struct {
+	IssueID int64
+}
+
+// IsErrReviewOwnPullRequest checks if an error is a ErrReviewOwnPullRequest.
+func IsErrReviewOwnPullRequest(err error) bool {
+	_, ok := err.(ErrReviewOwnPullRequest)
+	return ok
+}
+
+func (err ErrReviewOwnPullRequest) Error() string {
+	return fmt.Sprintf("poster can not approve or reject own pull request [issue_id: %d]", err.IssueID)
+}
+
 //  _________ __                                __         .__
 //  /   _____//  |_  ____ ________  _  _______ _/  |_  ____ |  |__
 //  \_____  \\   __\/  _ \\____ \ \/ \/ /\__  \\   __\/ ___\|  |  \
@@ -860,6 +1217,61 @@ func (err ErrMilestoneNotExist) Error() string {
 	return fmt.Sprintf("milestone does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
 }
 
+// __________                   __               __
+// \______   \_______  ____    |__| ____   _____/  |_
+//  |     ___/\_  __ \/  _ \   |  |/ __ \_/ ___\   __\
+//  |    |     |  | \(  <_> )  |  \  ___/\  \___|  |
+//  |____|     |__|   \____/\__|  |\___  >\___  >__|
+//                         \______|    \/     \/
+
+// ErrProjectNotExist represents a "ProjectNotExist" kind of error.
+type ErrProjectNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrProjectNotExist checks if an error is a ErrProjectNotExist.
+func IsErrProjectNotExist(err error) bool {
+	_, ok := err.(ErrProjectNotExist)
+	return ok
+}
+
+func (err ErrProjectNotExist) Error() string {
+	return fmt.Sprintf("project does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrProjectBoardNotExist represents a "ProjectBoardNotExist" kind of error.
+type ErrProjectBoardNotExist struct {
+	ID        int64
+	ProjectID int64
+}
+
+// IsErrProjectBoardNotExist checks if an error is a ErrProjectBoardNotExist.
+func IsErrProjectBoardNotExist(err error) bool {
+	_, ok := err.(ErrProjectBoardNotExist)
+	return ok
+}
+
+func (err ErrProjectBoardNotExist) Error() string {
+	return fmt.Sprintf("project board does not exist [id: %d, project_id: %d]", err.ID, err.ProjectID)
+}
+
+// ErrProjectIssueNotExist represents a "ProjectIssueNotExist" kind of error.
+type ErrProjectIssueNotExist struct {
+	IssueID   int64
+	ProjectID int64
+}
+
+// IsErrProjectIssueNotExist checks if an error is a ErrProjectIssueNotExist.
+func IsErrProjectIssueNotExist(err error) bool {
+	_, ok := err.(ErrProjectIssueNotExist)
+	return ok
+}
+
+func (err ErrProjectIssueNotExist) Error() string {
+	return fmt.Sprintf("issue is not in the project [issue_id: %d, project_id: %d]", err.IssueID, err.ProjectID)
+}
+
 //    _____   __    __                .__                           __
 //   /  _  \_/  |__/  |______    ____ |  |__   _____   ____   _____/  |_
 //  /  /_\  \   __\   __\__  \ _/ ___\|  |  \ /     \_/ __ \ /    \   __\
@@ -1040,3 +1452,77 @@ func IsErrExternalLoginUserNotExist(err error) bool {
 func (err ErrExternalLoginUserNotExist) Error() string {
 	return fmt.Sprintf("external login user link does not exists [userID: %d, loginSourceID: %d]", err.UserID, err.LoginSourceID)
 }
+
+//  ________      _____          __  .__     ________
+//  \_____  \    /  _  \  __ ___/  |_|  |__  \_____  \
+//   /   |   \  /  /_\  \|  |  \   __\  |  \  /  ____/
+//  /    |    \/    |    \  |  /|  | |   Y  \/       \
+//  \_______  /\____|__  /____/ |__| |___|  /\_______ \
+//          \/         \/                 \/         \/
+
+// ErrOAuth2ApplicationNotExist represents a "OAuth2ApplicationNotExist" kind of error.
+type ErrOAuth2ApplicationNotExist struct {
+	ID       int64
+	ClientID string
+}
+
+// IsErrOAuth2ApplicationNotExist checks if an error is a ErrOAuth2ApplicationNotExist.
+func IsErrOAuth2ApplicationNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2ApplicationNotExist)
+	return ok
+}
+
+func (err ErrOAuth2ApplicationNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 application does not exist [id: %d, client_id: %s]", err.ID, err.ClientID)
+}
+
+// ErrOAuth2AuthorizationCodeNotExist represents a "OAuth2AuthorizationCodeNotExist" kind of error.
+type ErrOAuth2AuthorizationCodeNotExist struct {
+}
+
+// IsErrOAuth2AuthorizationCodeNotExist checks if an error is a ErrOAuth2AuthorizationCodeNotExist.
+func IsErrOAuth2AuthorizationCodeNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2AuthorizationCodeNotExist)
+	return ok
+}
+
+func (err ErrOAuth2AuthorizationCodeNotExist) Error() string {
+	return "OAuth2 authorization code does not exist"
+}
+
+// ErrOAuth2GrantNotExist represents a "OAuth2GrantNotExist" kind of error.
+type ErrOAuth2GrantNotExist struct {
+	ID int64
+}
+
+// IsErrOAuth2GrantNotExist checks if an error is a ErrOAuth2GrantNotExist.
+func IsErrOAuth2GrantNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2GrantNotExist)
+	return ok
+}
+
+func (err ErrOAuth2GrantNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 grant does not exist [id: %d]", err.ID)
+}
+
+//  _______             __   .__   _____ .__                   __   .__
+//  \      \    ____  _/  |_ |__|_/ ____\|__|  ____  _____   _/  |_ |__|  ____    ____
+//  /   |   \  /  _ \ \   __\|  |\   __\ |  |_/ ___\ \__  \  \   __\|  | /  _ \  /    \
+// /    |    \(  <_> ) |  |  |  | |  |   |  |\  \___  / __ \_ |  |  |  |(  <_> )|   |  \
+// \____|__  / \____/  |__|  |__| |__|   |__| \___  >(____  / |__|  |__| \____/ |___|  /
+//         \/                                     \/      \/                         \/
+
+// ErrNotificationNotExist represents a "NotificationNotExist" kind of error.
+type ErrNotificationNotExist struct {
+	ID int64
+}
+
+// IsErrNotificationNotExist checks if an error is a ErrNotificationNotExist.
+func IsErrNotificationNotExist(err error) bool {
+	_, ok := err.(ErrNotificationNotExist)
+	return ok
+}
+
+func (err ErrNotificationNotExist) Error() string {
+	return fmt.Sprintf("notification does not exist [id: %d]", err.ID)
+}
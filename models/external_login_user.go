@@ -72,3 +72,30 @@ func removeAllAccountLinks(e Engine, user *User) error {
 	_, err := e.Delete(&ExternalLoginUser{UserID: user.ID})
 	return err
 }
+
+// GetUserIDByExternalUserID returns the ID of the local user linked to the
+// given user of an external OAuth2 provider such as "github", or 0 if no
+// active login source of this provider has such a link.
+func GetUserIDByExternalUserID(provider, externalUserID string) (int64, error) {
+	sources, err := GetActiveOAuth2ProviderLoginSources()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, source := range sources {
+		if source.OAuth2().Provider != provider {
+			continue
+		}
+		externalLoginUser := &ExternalLoginUser{
+			ExternalID:    externalUserID,
+			LoginSourceID: source.ID,
+		}
+		has, err := x.Get(externalLoginUser)
+		if err != nil {
+			return 0, err
+		} else if has {
+			return externalLoginUser.UserID, nil
+		}
+	}
+	return 0, nil
+}
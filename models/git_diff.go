@@ -56,6 +56,7 @@ type DiffLine struct {
 	RightIdx int
 	Type     DiffLineType
 	Content  string
+	Comments []*Comment
 }
 
 // GetType returns the type of a DiffLine.
@@ -224,6 +225,31 @@ type Diff struct {
 	IsIncomplete                 bool
 }
 
+// LoadComments attaches the code comments of the pull request to the matching lines of the diff
+func (diff *Diff) LoadComments(issue *Issue, currentUser *User) error {
+	allComments, err := FetchCodeComments(issue, currentUser)
+	if err != nil {
+		return err
+	}
+	for _, file := range diff.Files {
+		lineComments, ok := allComments[file.Name]
+		if !ok {
+			continue
+		}
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.LeftIdx > 0 {
+					line.Comments = append(line.Comments, lineComments[int64(-line.LeftIdx)]...)
+				}
+				if line.RightIdx > 0 {
+					line.Comments = append(line.Comments, lineComments[int64(line.RightIdx)]...)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // NumFiles returns number of files changes in a diff.
 func (diff *Diff) NumFiles() int {
 	return len(diff.Files)
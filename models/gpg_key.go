@@ -379,6 +379,11 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 			}
 		}
 
+		//Check if the commit was signed by Gitea
+		if verification := verifyInstanceSignature(c, sig); verification != nil {
+			return verification
+		}
+
 		//Find Committer account
 		committer, err := GetUserByEmail(c.Committer.Email)
 		if err != nil { //Skipping not user for commiter
@@ -467,3 +472,17 @@ func ParseCommitsWithSignature(oldCommits *list.List) *list.List {
 	}
 	return newCommits
 }
+
+// VerifyCommits returns an ErrUnverifiedCommit for the first of the commits
+// whose signature can not be verified against a known key.
+func VerifyCommits(commits []*git.Commit) error {
+	for _, c := range commits {
+		if verification := ParseCommitWithSignature(c); !verification.Verified {
+			return ErrUnverifiedCommit{
+				ID:     c.ID.String(),
+				Reason: verification.Reason,
+			}
+		}
+	}
+	return nil
+}
@@ -5,8 +5,14 @@
 package models
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/gitutil"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -222,3 +228,47 @@ Q0KHb+QcycSgbDx0ZAvdIacuKvBBcbxrsmFUI4LR+oIup0G9gUc0roPvr014jYQL
 	assert.Len(t, key.Emails, 1)
 	assert.Equal(t, "user1@example.com", key.Emails[0].Email)
 }
+
+func TestVerifyCommitsInRange(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	repoPath, err := ioutil.TempDir("", "gitea-verify-commits")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	assert.NoError(t, git.InitRepository(repoPath, false))
+
+	commitFile := func(name string) string {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, name), []byte(name), 0644))
+		assert.NoError(t, git.AddChanges(repoPath, true))
+		assert.NoError(t, git.CommitChanges(repoPath, git.CommitChangesOptions{
+			Committer: user.NewGitSig(),
+			Message:   "Add " + name,
+		}))
+		stdout, err := git.NewCommand("rev-parse", "HEAD").RunInDir(repoPath)
+		assert.NoError(t, err)
+		return stdout[:40]
+	}
+	first := commitFile("first")
+	second := commitFile("second")
+	third := commitFile("third")
+
+	commits, err := gitutil.GetCommitsInRange(repoPath, nil, first, third)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, third, commits[0].ID.String())
+		assert.Equal(t, second, commits[1].ID.String())
+	}
+
+	err = VerifyCommits(commits)
+	if assert.True(t, IsErrUnverifiedCommit(err)) {
+		assert.Equal(t, third, err.(ErrUnverifiedCommit).ID)
+		assert.Equal(t, "gpg.error.not_signed_commit", err.(ErrUnverifiedCommit).Reason)
+	}
+	assert.NoError(t, VerifyCommits(nil))
+
+	// all the commits are reachable from master
+	commits, err = gitutil.GetCommitsInRange(repoPath, nil, git.EmptySHA, third)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 0)
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/go-xorm/xorm"
+)
+
+// IssueAssignees represents an issue-assignee relation.
+type IssueAssignees struct {
+	ID         int64 `xorm:"pk autoincr"`
+	AssigneeID int64 `xorm:"INDEX"`
+	IssueID    int64 `xorm:"INDEX"`
+}
+
+func (issue *Issue) loadAssignees(e Engine) (err error) {
+	issue.Assignees = make([]*User, 0, 5)
+	if err = e.
+		Join("INNER", "issue_assignees", "issue_assignees.assignee_id = `user`.id").
+		Where("issue_assignees.issue_id = ?", issue.ID).
+		Asc("issue_assignees.id").
+		Find(&issue.Assignees); err != nil {
+		return fmt.Errorf("find assignees [issue_id: %d]: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// LoadAssignees loads all assignees of the issue.
+func (issue *Issue) LoadAssignees() error {
+	return issue.loadAssignees(x)
+}
+
+// IsAssignedTo returns true if given user is one of the assignees of the issue.
+// Assignees must have been loaded before.
+func (issue *Issue) IsAssignedTo(uid int64) bool {
+	for _, assignee := range issue.Assignees {
+		if assignee.ID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func isUserAssignedToIssue(e Engine, issueID, uid int64) (bool, error) {
+	return e.Get(&IssueAssignees{IssueID: issueID, AssigneeID: uid})
+}
+
+// IsUserAssignedToIssue returns true if given user is assigned to the issue.
+func IsUserAssignedToIssue(issueID, uid int64) (bool, error) {
+	return isUserAssignedToIssue(x, issueID, uid)
+}
+
+// toggleAssignee assigns the user to the issue if not already assigned and
+// removes them otherwise, and creates the corresponding comment.
+// Assignees of the issue must have been loaded before.
+func (issue *Issue) toggleAssignee(e *xorm.Session, doer *User, assigneeID int64) (removed bool, err error) {
+	assignee, err := getUserByID(e, assigneeID)
+	if err != nil {
+		return false, err
+	}
+
+	removed = issue.IsAssignedTo(assigneeID)
+	if removed {
+		if _, err = e.Delete(&IssueAssignees{IssueID: issue.ID, AssigneeID: assigneeID}); err != nil {
+			return false, err
+		}
+		for i := range issue.Assignees {
+			if issue.Assignees[i].ID == assigneeID {
+				issue.Assignees = append(issue.Assignees[:i], issue.Assignees[i+1:]...)
+				break
+			}
+		}
+	} else {
+		if _, err = e.Insert(&IssueAssignees{IssueID: issue.ID, AssigneeID: assigneeID}); err != nil {
+			return false, err
+		}
+		issue.Assignees = append(issue.Assignees, assignee)
+	}
+
+	if err = issue.loadRepo(e); err != nil {
+		return false, fmt.Errorf("loadRepo: %v", err)
+	}
+	if _, err = createAssigneeComment(e, doer, issue.Repo, issue, assigneeID, removed); err != nil {
+		return false, fmt.Errorf("createAssigneeComment: %v", err)
+	}
+	return removed, nil
+}
+
+// ToggleAssignee assigns the user to the issue if not already assigned and
+// removes them otherwise. It returns true if the user has been removed.
+func (issue *Issue) ToggleAssignee(doer *User, assigneeID int64) (removed bool, err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return false, err
+	}
+
+	if err = issue.loadAssignees(sess); err != nil {
+		return false, err
+	}
+	if removed, err = issue.toggleAssignee(sess, doer, assigneeID); err != nil {
+		return false, err
+	}
+	if err = sess.Commit(); err != nil {
+		return false, err
+	}
+
+	issue.prepareAssigneeWebhooks(doer, removed)
+	return removed, nil
+}
+
+// UpdateAssignees replaces the assignees of the issue by the given users,
+// creating a comment for every user being assigned or removed.
+func (issue *Issue) UpdateAssignees(doer *User, assigneeIDs []int64) error {
+	if err := issue.LoadAssignees(); err != nil {
+		return err
+	}
+
+	toggleIDs := make([]int64, 0, len(assigneeIDs)+len(issue.Assignees))
+	for _, assignee := range issue.Assignees {
+		if !base.Int64sContains(assigneeIDs, assignee.ID) {
+			toggleIDs = append(toggleIDs, assignee.ID)
+		}
+	}
+	for _, assigneeID := range assigneeIDs {
+		if !issue.IsAssignedTo(assigneeID) && !base.Int64sContains(toggleIDs, assigneeID) {
+			toggleIDs = append(toggleIDs, assigneeID)
+		}
+	}
+
+	for _, assigneeID := range toggleIDs {
+		if _, err := issue.ToggleAssignee(doer, assigneeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearAssignees removes all assignees of the issue.
+func (issue *Issue) ClearAssignees(doer *User) error {
+	return issue.UpdateAssignees(doer, nil)
+}
+
+func (issue *Issue) prepareAssigneeWebhooks(doer *User, removed bool) {
+	err := issue.loadAttributes(x)
+	if err != nil {
+		log.Error(4, "loadAttributes: %v", err)
+		return
+	}
+
+	if issue.IsPull {
+		issue.PullRequest.Issue = issue
+		apiPullRequest := &api.PullRequestPayload{
+			Index:       issue.Index,
+			PullRequest: issue.PullRequest.APIFormat(),
+			Repository:  issue.Repo.APIFormat(AccessModeNone),
+			Sender:      doer.APIFormat(),
+		}
+		if removed {
+			apiPullRequest.Action = api.HookIssueUnassigned
+		} else {
+			apiPullRequest.Action = api.HookIssueAssigned
+		}
+		err = PrepareWebhooks(issue.Repo, HookEventPullRequest, apiPullRequest)
+	} else {
+		apiIssue := &api.IssuePayload{
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		}
+		if removed {
+			apiIssue.Action = api.HookIssueUnassigned
+		} else {
+			apiIssue.Action = api.HookIssueAssigned
+		}
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, apiIssue)
+	}
+	if err != nil {
+		log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, removed, err)
+		return
+	}
+	go HookQueue.Add(issue.RepoID)
+}
+
+// GetAssigneeIDsByNames returns the IDs of the users with given names, the
+// single assignee of the deprecated API field included.
+func GetAssigneeIDsByNames(assignee string, assignees []string) ([]int64, error) {
+	names := make([]string, 0, len(assignees)+1)
+	if len(assignee) > 0 {
+		names = append(names, assignee)
+	}
+	names = append(names, assignees...)
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		if len(name) == 0 {
+			continue
+		}
+		u, err := GetUserByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !base.Int64sContains(ids, u.ID) {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_ToggleAssignee(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	removed, err := issue.ToggleAssignee(doer, 2)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	AssertExistsAndLoadBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 2})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAssignees, IssueID: issue.ID, PosterID: doer.ID, AssigneeID: 2},
+		Cond("removed_assignee = ?", false))
+
+	removed, err = issue.ToggleAssignee(doer, 1)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAssignees, IssueID: issue.ID, PosterID: doer.ID, AssigneeID: 1},
+		Cond("removed_assignee = ?", true))
+
+	isAssigned, err := IsUserAssignedToIssue(issue.ID, 2)
+	assert.NoError(t, err)
+	assert.True(t, isAssigned)
+	isAssigned, err = IsUserAssignedToIssue(issue.ID, 1)
+	assert.NoError(t, err)
+	assert.False(t, isAssigned)
+
+	_, err = issue.ToggleAssignee(doer, NonexistentID)
+	assert.True(t, IsErrUserNotExist(err))
+}
+
+func TestIssue_UpdateAssignees(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	assert.NoError(t, issue.UpdateAssignees(doer, []int64{2, 4, 2}))
+	assert.NoError(t, issue.LoadAssignees())
+	if assert.Len(t, issue.Assignees, 2) {
+		assert.EqualValues(t, 2, issue.Assignees[0].ID)
+		assert.EqualValues(t, 4, issue.Assignees[1].ID)
+	}
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 1})
+	assert.EqualValues(t, 3, GetCount(t, &Comment{Type: CommentTypeAssignees, IssueID: issue.ID}))
+
+	assert.NoError(t, issue.ClearAssignees(doer))
+	assert.NoError(t, issue.LoadAssignees())
+	assert.Len(t, issue.Assignees, 0)
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID})
+	assert.EqualValues(t, 5, GetCount(t, &Comment{Type: CommentTypeAssignees, IssueID: issue.ID}))
+}
+
+func TestNewIssue_Assignees(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	poster := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	issue := &Issue{
+		RepoID:   repo.ID,
+		PosterID: poster.ID,
+		Poster:   poster,
+		Title:    "issue with assignees",
+	}
+	// user 5 has no write access to the repository and is dropped
+	assert.NoError(t, NewIssue(repo, issue, nil, []int64{2, 5}, nil))
+	AssertExistsAndLoadBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 2})
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 5})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAssignees, IssueID: issue.ID, AssigneeID: 2})
+	if assert.Len(t, issue.Assignees, 1) {
+		assert.EqualValues(t, 2, issue.Assignees[0].ID)
+	}
+}
+
+func TestIssues_Assignee(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err := issue.ToggleAssignee(doer, 2)
+	assert.NoError(t, err)
+
+	for _, assigneeID := range []int64{1, 2} {
+		issues, err := Issues(&IssuesOptions{RepoID: issue.RepoID, AssigneeID: assigneeID, Page: 1})
+		assert.NoError(t, err)
+		if assert.Len(t, issues, 1) {
+			assert.EqualValues(t, issue.ID, issues[0].ID)
+			assert.Len(t, issues[0].Assignees, 2)
+		}
+
+		stats, err := GetIssueStats(&IssueStatsOptions{RepoID: issue.RepoID, AssigneeID: assigneeID})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, stats.OpenCount)
+	}
+
+	issues, err := Issues(&IssuesOptions{RepoID: issue.RepoID, AssigneeID: 4, Page: 1})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+}
+
+func TestGetAssigneeIDsByNames(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	ids, err := GetAssigneeIDsByNames("user2", []string{"user4", "user2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, ids)
+
+	ids, err = GetAssigneeIDsByNames("", nil)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 0)
+
+	_, err = GetAssigneeIDsByNames("", []string{"user2", "nonexistent"})
+	assert.True(t, IsErrUserNotExist(err))
+}
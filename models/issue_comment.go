@@ -13,10 +13,9 @@ import (
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markdown"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // CommentType defines whether a comment is just a simple comment, an action (like close) or a reference.
@@ -60,6 +59,14 @@ const (
 	CommentTypeAddTimeManual
 	// Cancel a stopwatch for time tracking
 	CommentTypeCancelTracking
+	// Submitted review of a pull request
+	CommentTypeReview
+	// Comment on a line of the pull request diff, belongs to a review
+	CommentTypeCode
+	// Add a dependency the issue is blocked by
+	CommentTypeAddDependency
+	// Remove a dependency the issue was blocked by
+	CommentTypeRemoveDependency
 )
 
 // CommentTag defines comment tag type
@@ -75,29 +82,37 @@ const (
 
 // Comment represents a comment in commit and issue page.
 type Comment struct {
-	ID             int64 `xorm:"pk autoincr"`
-	Type           CommentType
-	PosterID       int64 `xorm:"INDEX"`
-	Poster         *User `xorm:"-"`
-	IssueID        int64 `xorm:"INDEX"`
-	LabelID        int64
-	Label          *Label `xorm:"-"`
-	OldMilestoneID int64
-	MilestoneID    int64
-	OldMilestone   *Milestone `xorm:"-"`
-	Milestone      *Milestone `xorm:"-"`
-	OldAssigneeID  int64
-	AssigneeID     int64
-	Assignee       *User `xorm:"-"`
-	OldAssignee    *User `xorm:"-"`
-	OldTitle       string
-	NewTitle       string
+	ID               int64 `xorm:"pk autoincr"`
+	Type             CommentType
+	PosterID         int64  `xorm:"INDEX"`
+	Poster           *User  `xorm:"-"`
+	IssueID          int64  `xorm:"INDEX"`
+	Issue            *Issue `xorm:"-"`
+	LabelID          int64
+	Label            *Label `xorm:"-"`
+	OldMilestoneID   int64
+	MilestoneID      int64
+	OldMilestone     *Milestone `xorm:"-"`
+	Milestone        *Milestone `xorm:"-"`
+	AssigneeID       int64
+	RemovedAssignee  bool
+	Assignee         *User `xorm:"-"`
+	OldTitle         string
+	NewTitle         string
+	DependentIssueID int64
+	DependentIssue   *Issue `xorm:"-"`
 
 	CommitID        int64
-	Line            int64
+	Line            int64 // - previous line / + proposed line
+	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
 
+	// Review the code comment or review summary belongs to
+	ReviewID    int64   `xorm:"INDEX"`
+	Review      *Review `xorm:"-"`
+	Invalidated bool
+
 	Created     time.Time `xorm:"-"`
 	CreatedUnix int64     `xorm:"INDEX"`
 	Updated     time.Time `xorm:"-"`
@@ -107,16 +122,21 @@ type Comment struct {
 	CommitSHA string `xorm:"VARCHAR(40)"`
 
 	Attachments []*Attachment `xorm:"-"`
+	Reactions   ReactionList  `xorm:"-"`
 
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
 }
 
 // BeforeInsert will be invoked by XORM before inserting a record
-// representing this object.
+// representing this object. Migrated comments keep their original timestamps.
 func (c *Comment) BeforeInsert() {
-	c.CreatedUnix = time.Now().Unix()
-	c.UpdatedUnix = c.CreatedUnix
+	if c.CreatedUnix == 0 {
+		c.CreatedUnix = time.Now().Unix()
+	}
+	if c.UpdatedUnix == 0 {
+		c.UpdatedUnix = c.CreatedUnix
+	}
 }
 
 // BeforeUpdate is invoked from XORM before updating this object.
@@ -222,6 +242,67 @@ func (c *Comment) EventTag() string {
 	return "event-" + com.ToStr(c.ID)
 }
 
+// LoadIssue loads the issue the comment belongs to, including its attributes
+func (c *Comment) LoadIssue() (err error) {
+	if c.Issue != nil {
+		return nil
+	}
+
+	c.Issue, err = GetIssueByID(c.IssueID)
+	if err != nil {
+		return err
+	}
+	return c.Issue.LoadAttributes()
+}
+
+func (c *Comment) prepareWebhooks(doer *User, action api.HookIssueCommentAction, changes *api.ChangesPayload) error {
+	if err := c.LoadIssue(); err != nil {
+		return err
+	}
+
+	if err := PrepareWebhooks(c.Issue.Repo, HookEventIssueComment, &api.IssueCommentPayload{
+		Action:     action,
+		Issue:      c.Issue.APIFormat(),
+		Comment:    c.APIFormat(),
+		Changes:    changes,
+		Repository: c.Issue.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		return err
+	}
+	go HookQueue.Add(c.Issue.RepoID)
+	return nil
+}
+
+// LoadReview loads the review the comment belongs to
+func (c *Comment) LoadReview() (err error) {
+	return c.loadReview(x)
+}
+
+func (c *Comment) loadReview(e Engine) (err error) {
+	if c.Review == nil && c.ReviewID > 0 {
+		c.Review, err = getReviewByID(e, c.ReviewID)
+	}
+	return err
+}
+
+// UnsignedLine returns the line number of a code comment without its side
+func (c *Comment) UnsignedLine() uint64 {
+	if c.Line < 0 {
+		return uint64(-c.Line)
+	}
+	return uint64(c.Line)
+}
+
+// DiffSide returns "previous" if the code comment refers to the merge base
+// version of the file and "proposed" if it refers to the head version.
+func (c *Comment) DiffSide() string {
+	if c.Line < 0 {
+		return "previous"
+	}
+	return "proposed"
+}
+
 // LoadLabel if comment.Type is CommentTypeLabel, then load Label
 func (c *Comment) LoadLabel() error {
 	var label Label
@@ -262,28 +343,42 @@ func (c *Comment) LoadMilestone() error {
 	return nil
 }
 
-// LoadAssignees if comment.Type is CommentTypeAssignees, then load assignees
-func (c *Comment) LoadAssignees() error {
+// LoadAssigneeUser if comment.Type is CommentTypeAssignees, then load the
+// user who has been assigned or removed
+func (c *Comment) LoadAssigneeUser() error {
 	var err error
-	if c.OldAssigneeID > 0 {
-		c.OldAssignee, err = getUserByID(x, c.OldAssigneeID)
-		if err != nil {
-			return err
-		}
-	}
-
 	if c.AssigneeID > 0 {
 		c.Assignee, err = getUserByID(x, c.AssigneeID)
 		if err != nil {
-			return err
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			c.Assignee = NewGhostUser()
 		}
 	}
 	return nil
 }
 
+// LoadDepIssueDetails if comment.Type is CommentTypeAddDependency or
+// CommentTypeRemoveDependency, then load the issue the dependency refers to
+func (c *Comment) LoadDepIssueDetails() (err error) {
+	if c.DependentIssueID <= 0 || c.DependentIssue != nil {
+		return nil
+	}
+	c.DependentIssue, err = getIssueByID(x, c.DependentIssueID)
+	if err != nil {
+		return err
+	}
+	return c.DependentIssue.loadRepo(x)
+}
+
 // MailParticipants sends new comment emails to repository watchers
 // and mentioned people.
-func (c *Comment) MailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
+func (c *Comment) MailParticipants(opType ActionType, issue *Issue) error {
+	return c.mailParticipants(x, opType, issue)
+}
+
+func (c *Comment) mailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
 	mentions := markdown.FindAllMentions(c.Content)
 	if err = UpdateIssueMentions(e, c.IssueID, mentions); err != nil {
 		return fmt.Errorf("UpdateIssueMentions [%d]: %v", c.IssueID, err)
@@ -310,21 +405,24 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		LabelID = opts.Label.ID
 	}
 	comment := &Comment{
-		Type:           opts.Type,
-		PosterID:       opts.Doer.ID,
-		Poster:         opts.Doer,
-		IssueID:        opts.Issue.ID,
-		LabelID:        LabelID,
-		OldMilestoneID: opts.OldMilestoneID,
-		MilestoneID:    opts.MilestoneID,
-		OldAssigneeID:  opts.OldAssigneeID,
-		AssigneeID:     opts.AssigneeID,
-		CommitID:       opts.CommitID,
-		CommitSHA:      opts.CommitSHA,
-		Line:           opts.LineNum,
-		Content:        opts.Content,
-		OldTitle:       opts.OldTitle,
-		NewTitle:       opts.NewTitle,
+		Type:             opts.Type,
+		PosterID:         opts.Doer.ID,
+		Poster:           opts.Doer,
+		IssueID:          opts.Issue.ID,
+		LabelID:          LabelID,
+		OldMilestoneID:   opts.OldMilestoneID,
+		MilestoneID:      opts.MilestoneID,
+		AssigneeID:       opts.AssigneeID,
+		RemovedAssignee:  opts.RemovedAssignee,
+		CommitID:         opts.CommitID,
+		CommitSHA:        opts.CommitSHA,
+		Line:             opts.LineNum,
+		TreePath:         opts.TreePath,
+		ReviewID:         opts.ReviewID,
+		Content:          opts.Content,
+		OldTitle:         opts.OldTitle,
+		NewTitle:         opts.NewTitle,
+		DependentIssueID: opts.DependentIssueID,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -350,8 +448,8 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 	// Check comment type.
 	switch opts.Type {
 	case CommentTypeComment:
-		act.OpType = ActionCommentIssue
-
+		// Plain comments are announced by the notifiers once the
+		// comment has been created, see CreateIssueComment.
 		if _, err = e.Exec("UPDATE `issue` SET num_comments=num_comments+1 WHERE id=?", opts.Issue.ID); err != nil {
 			return nil, err
 		}
@@ -378,6 +476,9 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 			}
 		}
 
+	case CommentTypeReview:
+		act.OpType = ActionCommentIssue
+
 	case CommentTypeReopen:
 		act.OpType = ActionReopenIssue
 		if opts.Issue.IsPull {
@@ -415,8 +516,8 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		if err = notifyWatchers(e, act); err != nil {
 			log.Error(4, "notifyWatchers: %v", err)
 		}
-		if err = comment.MailParticipants(e, act.OpType, opts.Issue); err != nil {
-			log.Error(4, "MailParticipants: %v", err)
+		if err = comment.mailParticipants(e, act.OpType, opts.Issue); err != nil {
+			log.Error(4, "mailParticipants: %v", err)
 		}
 	}
 
@@ -462,14 +563,14 @@ func createMilestoneComment(e *xorm.Session, doer *User, repo *Repository, issue
 	})
 }
 
-func createAssigneeComment(e *xorm.Session, doer *User, repo *Repository, issue *Issue, oldAssigneeID, assigneeID int64) (*Comment, error) {
+func createAssigneeComment(e *xorm.Session, doer *User, repo *Repository, issue *Issue, assigneeID int64, removedAssignee bool) (*Comment, error) {
 	return createComment(e, &CreateCommentOptions{
-		Type:          CommentTypeAssignees,
-		Doer:          doer,
-		Repo:          repo,
-		Issue:         issue,
-		OldAssigneeID: oldAssigneeID,
-		AssigneeID:    assigneeID,
+		Type:            CommentTypeAssignees,
+		Doer:            doer,
+		Repo:            repo,
+		Issue:           issue,
+		AssigneeID:      assigneeID,
+		RemovedAssignee: removedAssignee,
 	})
 }
 
@@ -502,17 +603,20 @@ type CreateCommentOptions struct {
 	Issue *Issue
 	Label *Label
 
-	OldMilestoneID int64
-	MilestoneID    int64
-	OldAssigneeID  int64
-	AssigneeID     int64
-	OldTitle       string
-	NewTitle       string
-	CommitID       int64
-	CommitSHA      string
-	LineNum        int64
-	Content        string
-	Attachments    []string // UUIDs of attachments
+	OldMilestoneID   int64
+	MilestoneID      int64
+	AssigneeID       int64
+	RemovedAssignee  bool
+	OldTitle         string
+	NewTitle         string
+	DependentIssueID int64
+	CommitID         int64
+	CommitSHA        string
+	LineNum          int64
+	TreePath         string
+	ReviewID         int64
+	Content          string
+	Attachments      []string // UUIDs of attachments
 }
 
 // CreateComment creates comment of issue or commit.
@@ -533,7 +637,7 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content string, attachments []string) (*Comment, error) {
-	return CreateComment(&CreateCommentOptions{
+	comment, err := CreateComment(&CreateCommentOptions{
 		Type:        CommentTypeComment,
 		Doer:        doer,
 		Repo:        repo,
@@ -541,6 +645,14 @@ func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content stri
 		Content:     content,
 		Attachments: attachments,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = issue.loadAttributes(x); err != nil {
+		log.Error(4, "loadAttributes: %v", err)
+	}
+	return comment, nil
 }
 
 // CreateRefComment creates a commit reference comment to issue.
@@ -586,10 +698,11 @@ func GetCommentByID(id int64) (*Comment, error) {
 
 // FindCommentsOptions describes the conditions to Find comments
 type FindCommentsOptions struct {
-	RepoID  int64
-	IssueID int64
-	Since   int64
-	Type    CommentType
+	RepoID   int64
+	IssueID  int64
+	ReviewID int64
+	Since    int64
+	Type     CommentType
 }
 
 func (opts *FindCommentsOptions) toConds() builder.Cond {
@@ -600,6 +713,9 @@ func (opts *FindCommentsOptions) toConds() builder.Cond {
 	if opts.IssueID > 0 {
 		cond = cond.And(builder.Eq{"comment.issue_id": opts.IssueID})
 	}
+	if opts.ReviewID > 0 {
+		cond = cond.And(builder.Eq{"comment.review_id": opts.ReviewID})
+	}
 	if opts.Since > 0 {
 		cond = cond.And(builder.Gte{"comment.updated_unix": opts.Since})
 	}
@@ -620,6 +736,42 @@ func findComments(e Engine, opts FindCommentsOptions) ([]*Comment, error) {
 		Find(&comments)
 }
 
+// FetchCodeComments returns the code comments of a pull request which are not outdated,
+// grouped by tree path and line. Pending reviews are only included for currentUser.
+func FetchCodeComments(issue *Issue, currentUser *User) (map[string]map[int64][]*Comment, error) {
+	return fetchCodeComments(x, issue, currentUser)
+}
+
+func fetchCodeComments(e Engine, issue *Issue, currentUser *User) (map[string]map[int64][]*Comment, error) {
+	pathToLineToComment := make(map[string]map[int64][]*Comment)
+
+	var reviewCond builder.Cond = builder.Neq{"review.type": ReviewTypePending}
+	if currentUser != nil {
+		reviewCond = reviewCond.Or(builder.Eq{"review.reviewer_id": currentUser.ID})
+	}
+
+	var comments []*Comment
+	if err := e.
+		Join("INNER", "review", "review.id = comment.review_id").
+		Where(builder.Eq{
+			"comment.issue_id":    issue.ID,
+			"comment.type":        CommentTypeCode,
+			"comment.invalidated": false,
+		}.And(reviewCond)).
+		Asc("comment.created_unix").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		if pathToLineToComment[comment.TreePath] == nil {
+			pathToLineToComment[comment.TreePath] = make(map[int64][]*Comment)
+		}
+		pathToLineToComment[comment.TreePath][comment.Line] = append(pathToLineToComment[comment.TreePath][comment.Line], comment)
+	}
+	return pathToLineToComment, nil
+}
+
 // FindComments returns all comments according options
 func FindComments(opts FindCommentsOptions) ([]*Comment, error) {
 	return findComments(x, opts)
@@ -652,13 +804,23 @@ func GetCommentsByRepoIDSince(repoID, since int64) ([]*Comment, error) {
 }
 
 // UpdateComment updates information of comment.
-func UpdateComment(c *Comment) error {
-	_, err := x.Id(c.ID).AllCols().Update(c)
-	return err
+func UpdateComment(doer *User, c *Comment, oldContent string) error {
+	if _, err := x.Id(c.ID).AllCols().Update(c); err != nil {
+		return err
+	}
+
+	if err := c.prepareWebhooks(doer, api.HookIssueCommentEdited, &api.ChangesPayload{
+		Body: &api.ChangesFromPayload{
+			From: oldContent,
+		},
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [comment_id: %d]: %v", c.ID, err)
+	}
+	return nil
 }
 
 // DeleteComment deletes the comment
-func DeleteComment(comment *Comment) error {
+func DeleteComment(doer *User, comment *Comment) error {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -678,9 +840,22 @@ func DeleteComment(comment *Comment) error {
 	}
 	sess.Where("comment_id = ?", comment.ID).Cols("is_deleted").Update(&Action{IsDeleted: true})
 
+	if _, err := sess.Delete(&Reaction{CommentID: comment.ID}); err != nil {
+		return err
+	}
+
 	if _, err := sess.Where("comment_id = ?", comment.ID).Cols("is_deleted").Update(&Action{IsDeleted: true}); err != nil {
 		return err
 	}
 
-	return sess.Commit()
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+
+	if comment.Type == CommentTypeComment {
+		if err := comment.prepareWebhooks(doer, api.HookIssueCommentDeleted, nil); err != nil {
+			log.Error(4, "PrepareWebhooks [comment_id: %d]: %v", comment.ID, err)
+		}
+	}
+	return nil
 }
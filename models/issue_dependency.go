@@ -0,0 +1,202 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"github.com/go-xorm/xorm"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IssueDependency represents an issue which depends on another issue, it
+// cannot be closed while the issue it depends on is still open.
+type IssueDependency struct {
+	ID           int64     `xorm:"pk autoincr"`
+	UserID       int64     `xorm:"NOT NULL"`
+	IssueID      int64     `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+	DependencyID int64     `xorm:"UNIQUE(issue_dependency) NOT NULL INDEX"`
+	Created      time.Time `xorm:"-"`
+	CreatedUnix  int64
+}
+
+// BeforeInsert is invoked from XORM before inserting an object of this type.
+func (d *IssueDependency) BeforeInsert() {
+	d.CreatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (d *IssueDependency) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		d.Created = time.Unix(d.CreatedUnix, 0).Local()
+	}
+}
+
+// isIssueDependencyReachable returns true if the issue depends on the
+// dependency, directly or through other dependencies.
+func isIssueDependencyReachable(e Engine, issueID, dependencyID int64) (bool, error) {
+	visited := map[int64]bool{issueID: true}
+	queue := []int64{issueID}
+	for len(queue) > 0 {
+		ids := make([]int64, 0, 10)
+		if err := e.Table("issue_dependency").Cols("dependency_id").In("issue_id", queue).Find(&ids); err != nil {
+			return false, err
+		}
+
+		queue = queue[:0]
+		for _, id := range ids {
+			if id == dependencyID {
+				return true, nil
+			} else if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+// CreateIssueDependency makes the issue depend on the dependency. Both must
+// belong to the same repository unless cross repository dependencies are
+// allowed.
+func CreateIssueDependency(doer *User, issue, dependency *Issue) error {
+	if issue.ID == dependency.ID {
+		return ErrCircularDependency{issue.ID, dependency.ID}
+	}
+	if issue.RepoID != dependency.RepoID && !setting.Service.AllowCrossRepositoryDependencies {
+		return ErrDependencyCrossRepository{issue.ID, dependency.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exists, err := sess.Get(&IssueDependency{IssueID: issue.ID, DependencyID: dependency.ID}); err != nil {
+		return err
+	} else if exists {
+		return ErrDependencyExists{issue.ID, dependency.ID}
+	}
+	if circular, err := isIssueDependencyReachable(sess, dependency.ID, issue.ID); err != nil {
+		return err
+	} else if circular {
+		return ErrCircularDependency{issue.ID, dependency.ID}
+	}
+
+	if _, err := sess.Insert(&IssueDependency{
+		UserID:       doer.ID,
+		IssueID:      issue.ID,
+		DependencyID: dependency.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err := createDependencyComment(sess, doer, issue, dependency, true); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// RemoveIssueDependency removes the dependency of the issue.
+func RemoveIssueDependency(doer *User, issue, dependency *Issue) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if affected, err := sess.Delete(&IssueDependency{IssueID: issue.ID, DependencyID: dependency.ID}); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrDependencyNotExists{issue.ID, dependency.ID}
+	}
+
+	if err := createDependencyComment(sess, doer, issue, dependency, false); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func createDependencyComment(e *xorm.Session, doer *User, issue, dependency *Issue, add bool) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+
+	cmtType := CommentTypeAddDependency
+	if !add {
+		cmtType = CommentTypeRemoveDependency
+	}
+	_, err := createComment(e, &CreateCommentOptions{
+		Type:             cmtType,
+		Doer:             doer,
+		Repo:             issue.Repo,
+		Issue:            issue,
+		DependentIssueID: dependency.ID,
+	})
+	return err
+}
+
+func (issue *Issue) getBlockedByDependencies(e Engine) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 5)
+	return issues, e.
+		Join("INNER", "issue_dependency", "issue_dependency.dependency_id = issue.id").
+		Where("issue_dependency.issue_id = ?", issue.ID).
+		Asc("issue.repo_id", "issue.index").
+		Find(&issues)
+}
+
+// BlockedByDependencies returns the issues this issue depends on.
+func (issue *Issue) BlockedByDependencies() ([]*Issue, error) {
+	return issue.getBlockedByDependencies(x)
+}
+
+// BlockingDependencies returns the issues which depend on this issue.
+func (issue *Issue) BlockingDependencies() ([]*Issue, error) {
+	issues := make([]*Issue, 0, 5)
+	return issues, x.
+		Join("INNER", "issue_dependency", "issue_dependency.issue_id = issue.id").
+		Where("issue_dependency.dependency_id = ?", issue.ID).
+		Asc("issue.repo_id", "issue.index").
+		Find(&issues)
+}
+
+func issueNoDependenciesLeft(e Engine, issue *Issue) (bool, error) {
+	count, err := e.
+		Table("issue_dependency").
+		Join("INNER", "issue", "issue.id = issue_dependency.dependency_id").
+		Where("issue_dependency.issue_id = ?", issue.ID).
+		And("issue.is_closed = ?", false).
+		Count(new(IssueDependency))
+	return count == 0, err
+}
+
+// IssueNoDependenciesLeft returns true if all issues the issue depends on are
+// closed.
+func IssueNoDependenciesLeft(issue *Issue) (bool, error) {
+	return issueNoDependenciesLeft(x, issue)
+}
+
+// IsDependenciesEnabled returns true if issue dependencies are enabled for
+// the repository.
+func (repo *Repository) IsDependenciesEnabled() bool {
+	return repo.isDependenciesEnabled(x)
+}
+
+func (repo *Repository) isDependenciesEnabled(e Engine) bool {
+	if err := repo.getUnits(e); err != nil {
+		log.Trace("getUnits: %v", err)
+		return false
+	}
+	for _, unit := range repo.Units {
+		if unit.Type == UnitTypeIssues {
+			return unit.IssuesConfig().EnableDependencies
+		}
+	}
+	return false
+}
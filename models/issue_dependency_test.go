@@ -0,0 +1,107 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	otherRepoIssue := AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(doer, issue1, issue2))
+	AssertExistsAndLoadBean(t, &IssueDependency{IssueID: issue1.ID, DependencyID: issue2.ID, UserID: doer.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAddDependency, IssueID: issue1.ID, DependentIssueID: issue2.ID})
+
+	err := CreateIssueDependency(doer, issue1, issue2)
+	assert.True(t, IsErrDependencyExists(err))
+
+	// issues must not block each other, directly or through other issues
+	err = CreateIssueDependency(doer, issue1, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+	err = CreateIssueDependency(doer, issue2, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+	assert.NoError(t, CreateIssueDependency(doer, issue2, issue3))
+	err = CreateIssueDependency(doer, issue3, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+
+	defer func(allowed bool) {
+		setting.Service.AllowCrossRepositoryDependencies = allowed
+	}(setting.Service.AllowCrossRepositoryDependencies)
+	setting.Service.AllowCrossRepositoryDependencies = false
+	err = CreateIssueDependency(doer, issue1, otherRepoIssue)
+	assert.True(t, IsErrDependencyCrossRepository(err))
+	setting.Service.AllowCrossRepositoryDependencies = true
+	assert.NoError(t, CreateIssueDependency(doer, issue1, otherRepoIssue))
+
+	blockedBy, err := issue1.BlockedByDependencies()
+	assert.NoError(t, err)
+	if assert.Len(t, blockedBy, 2) {
+		assert.EqualValues(t, issue2.ID, blockedBy[0].ID)
+		assert.EqualValues(t, otherRepoIssue.ID, blockedBy[1].ID)
+	}
+	blocking, err := issue2.BlockingDependencies()
+	assert.NoError(t, err)
+	if assert.Len(t, blocking, 1) {
+		assert.EqualValues(t, issue1.ID, blocking[0].ID)
+	}
+}
+
+func TestRemoveIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(doer, issue1, issue2))
+	assert.NoError(t, RemoveIssueDependency(doer, issue1, issue2))
+	AssertNotExistsBean(t, &IssueDependency{IssueID: issue1.ID, DependencyID: issue2.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeRemoveDependency, IssueID: issue1.ID, DependentIssueID: issue2.ID})
+
+	err := RemoveIssueDependency(doer, issue1, issue2)
+	assert.True(t, IsErrDependencyNotExists(err))
+}
+
+func TestIssue_ChangeStatusWithDependencies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	closedIssue := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	assert.NoError(t, issue1.LoadAttributes())
+	assert.NoError(t, issue2.LoadAttributes())
+
+	assert.NoError(t, CreateIssueDependency(doer, issue1, issue2))
+	assert.NoError(t, CreateIssueDependency(doer, issue1, closedIssue))
+	noDeps, err := IssueNoDependenciesLeft(issue1)
+	assert.NoError(t, err)
+	assert.False(t, noDeps)
+
+	// dependencies are ignored unless they are enabled for the repository
+	assert.False(t, repo.IsDependenciesEnabled())
+	repo.MustGetUnit(UnitTypeIssues).IssuesConfig().EnableDependencies = true
+	assert.True(t, repo.IsDependenciesEnabled())
+
+	err = issue1.ChangeStatus(doer, repo, true)
+	assert.True(t, IsErrDependenciesLeft(err))
+	AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID, IsClosed: false})
+
+	assert.NoError(t, issue2.ChangeStatus(doer, repo, true))
+	noDeps, err = IssueNoDependenciesLeft(issue1)
+	assert.NoError(t, err)
+	assert.True(t, noDeps)
+	assert.NoError(t, issue1.ChangeStatus(doer, repo, true))
+	AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID, IsClosed: true})
+}
@@ -8,9 +8,9 @@ import (
 	"html/template"
 	"testing"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"github.com/stretchr/testify/assert"
+
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // TODO TestGetLabelTemplateFile
@@ -42,9 +42,14 @@ func mailIssueCommentToParticipants(issue *Issue, doer *User, comment *Comment,
 		participants = append(participants, issue.Poster)
 	}
 
-	// Assignee must receive any communications
-	if issue.Assignee != nil && issue.AssigneeID > 0 && issue.AssigneeID != doer.ID {
-		participants = append(participants, issue.Assignee)
+	// Assignees must receive any communications
+	if err = issue.loadAssignees(x); err != nil {
+		return err
+	}
+	for _, assignee := range issue.Assignees {
+		if assignee.ID != doer.ID {
+			participants = append(participants, assignee)
+		}
 	}
 
 	tos := make([]string, 0, len(watchers)) // List of email addresses.
@@ -9,9 +9,9 @@ import (
 	"testing"
 	"time"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"github.com/stretchr/testify/assert"
+
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 func TestMilestone_State(t *testing.T) {
@@ -0,0 +1,301 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-xorm/builder"
+	"github.com/go-xorm/xorm"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Reaction represents a reaction of a user to an issue or a comment.
+type Reaction struct {
+	ID          int64     `xorm:"pk autoincr"`
+	Type        string    `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	IssueID     int64     `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	CommentID   int64     `xorm:"INDEX UNIQUE(s)"`
+	UserID      int64     `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	User        *User     `xorm:"-"`
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX"`
+}
+
+// BeforeInsert is invoked from XORM before inserting an object of this type.
+func (r *Reaction) BeforeInsert() {
+	r.CreatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (r *Reaction) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		r.Created = time.Unix(r.CreatedUnix, 0).Local()
+	}
+}
+
+// APIFormat converts a reaction to its API representation
+func (r *Reaction) APIFormat() *api.Reaction {
+	return &api.Reaction{
+		User:     r.User.APIFormat(),
+		Reaction: r.Type,
+		Created:  r.Created,
+	}
+}
+
+// IsValidReaction returns true if the reaction is one of the configured ones.
+func IsValidReaction(content string) bool {
+	for _, reaction := range setting.UI.Reactions {
+		if reaction == content {
+			return true
+		}
+	}
+	return false
+}
+
+// FindReactionsOptions describes the conditions to find reactions
+type FindReactionsOptions struct {
+	IssueID int64
+	// CommentID 0 finds the reactions to the issue itself, -1 those to the
+	// issue and all of its comments
+	CommentID int64
+	UserID    int64
+}
+
+func (opts *FindReactionsOptions) toConds() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"reaction.issue_id": opts.IssueID})
+	}
+	if opts.CommentID >= 0 {
+		cond = cond.And(builder.Eq{"reaction.comment_id": opts.CommentID})
+	}
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"reaction.user_id": opts.UserID})
+	}
+	return cond
+}
+
+func findReactions(e Engine, opts FindReactionsOptions) (ReactionList, error) {
+	reactions := make([]*Reaction, 0, 10)
+	return reactions, e.Where(opts.toConds()).Asc("reaction.created_unix", "reaction.id").Find(&reactions)
+}
+
+func createReaction(e *xorm.Session, doer *User, issue *Issue, comment *Comment, content string) (*Reaction, error) {
+	if !IsValidReaction(content) {
+		return nil, ErrForbiddenReaction{content}
+	}
+
+	reaction := &Reaction{
+		Type:    content,
+		IssueID: issue.ID,
+		UserID:  doer.ID,
+		User:    doer,
+	}
+	if comment != nil {
+		reaction.CommentID = comment.ID
+	}
+	if has, err := e.Get(&Reaction{
+		Type:      reaction.Type,
+		IssueID:   reaction.IssueID,
+		CommentID: reaction.CommentID,
+		UserID:    reaction.UserID,
+	}); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrReactionAlreadyExist{content}
+	}
+
+	if _, err := e.Insert(reaction); err != nil {
+		return nil, err
+	}
+	return reaction, nil
+}
+
+func createReactionWithSession(doer *User, issue *Issue, comment *Comment, content string) (*Reaction, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	reaction, err := createReaction(sess, doer, issue, comment, content)
+	if err != nil {
+		return nil, err
+	}
+	return reaction, sess.Commit()
+}
+
+// CreateIssueReaction adds a reaction of the user to the issue.
+func CreateIssueReaction(doer *User, issue *Issue, content string) (*Reaction, error) {
+	return createReactionWithSession(doer, issue, nil, content)
+}
+
+// CreateCommentReaction adds a reaction of the user to the comment.
+func CreateCommentReaction(doer *User, issue *Issue, comment *Comment, content string) (*Reaction, error) {
+	return createReactionWithSession(doer, issue, comment, content)
+}
+
+func deleteReaction(e Engine, doer *User, issue *Issue, comment *Comment, content string) error {
+	reaction := &Reaction{
+		Type:    content,
+		IssueID: issue.ID,
+		UserID:  doer.ID,
+	}
+	if comment != nil {
+		reaction.CommentID = comment.ID
+	}
+	// the comment ID must be part of the conditions even when it is zero
+	_, err := e.Where("comment_id = ?", reaction.CommentID).Delete(reaction)
+	return err
+}
+
+// DeleteIssueReaction removes a reaction of the user to the issue.
+func DeleteIssueReaction(doer *User, issue *Issue, content string) error {
+	return deleteReaction(x, doer, issue, nil, content)
+}
+
+// DeleteCommentReaction removes a reaction of the user to the comment.
+func DeleteCommentReaction(doer *User, issue *Issue, comment *Comment, content string) error {
+	return deleteReaction(x, doer, issue, comment, content)
+}
+
+// ReactionList represents a list of reactions
+type ReactionList []*Reaction
+
+// HasUser returns true if the user reacted with one of the reactions.
+func (list ReactionList) HasUser(userID int64) bool {
+	if userID == 0 {
+		return false
+	}
+	for _, reaction := range list {
+		if reaction.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupByType returns the reactions grouped by their type.
+func (list ReactionList) GroupByType() map[string]ReactionList {
+	var reactions = make(map[string]ReactionList)
+	for _, reaction := range list {
+		reactions[reaction.Type] = append(reactions[reaction.Type], reaction)
+	}
+	return reactions
+}
+
+func (list ReactionList) getUserIDs() []int64 {
+	userIDs := make(map[int64]struct{}, len(list))
+	for _, reaction := range list {
+		userIDs[reaction.UserID] = struct{}{}
+	}
+	return keysInt64(userIDs)
+}
+
+func (list ReactionList) loadUsers(e Engine) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	userMaps := make(map[int64]*User, len(list))
+	if err := e.In("id", list.getUserIDs()).Find(&userMaps); err != nil {
+		return fmt.Errorf("find users: %v", err)
+	}
+	for _, reaction := range list {
+		if user, ok := userMaps[reaction.UserID]; ok {
+			reaction.User = user
+		} else {
+			reaction.User = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// LoadUsers loads the users who reacted.
+func (list ReactionList) LoadUsers() error {
+	return list.loadUsers(x)
+}
+
+// reactionUsersShown is the number of users named in the description of a
+// reaction, the others are only counted
+const reactionUsersShown = 10
+
+// GetFirstUsers returns the names of the first users who reacted.
+func (list ReactionList) GetFirstUsers() string {
+	var buffer bytes.Buffer
+	for i, reaction := range list {
+		if i == reactionUsersShown {
+			break
+		}
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(reaction.User.DisplayName())
+	}
+	return buffer.String()
+}
+
+// GetMoreUserCount returns the number of users who reacted but are not
+// named by GetFirstUsers.
+func (list ReactionList) GetMoreUserCount() int {
+	if len(list) <= reactionUsersShown {
+		return 0
+	}
+	return len(list) - reactionUsersShown
+}
+
+func (issue *Issue) loadReactions(e Engine) (err error) {
+	if issue.Reactions != nil {
+		return nil
+	}
+
+	reactions, err := findReactions(e, FindReactionsOptions{
+		IssueID:   issue.ID,
+		CommentID: -1,
+	})
+	if err != nil {
+		return err
+	}
+	if err = reactions.loadUsers(e); err != nil {
+		return err
+	}
+
+	// reactions to the comments are assigned to them if they are loaded
+	comments := make(map[int64]*Comment, len(issue.Comments))
+	for _, comment := range issue.Comments {
+		comments[comment.ID] = comment
+	}
+	issue.Reactions = make(ReactionList, 0, len(reactions))
+	for _, reaction := range reactions {
+		if reaction.CommentID == 0 {
+			issue.Reactions = append(issue.Reactions, reaction)
+		} else if comment, ok := comments[reaction.CommentID]; ok {
+			comment.Reactions = append(comment.Reactions, reaction)
+		}
+	}
+	return nil
+}
+
+// LoadReactions loads the reactions to the comment.
+func (c *Comment) LoadReactions() (err error) {
+	if c.Reactions != nil {
+		return nil
+	}
+
+	c.Reactions, err = findReactions(x, FindReactionsOptions{
+		IssueID:   c.IssueID,
+		CommentID: c.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.Reactions.loadUsers(x)
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIssueReaction(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	reaction, err := CreateIssueReaction(doer, issue, "heart")
+	assert.NoError(t, err)
+	assert.EqualValues(t, doer.ID, reaction.UserID)
+	AssertExistsAndLoadBean(t, &Reaction{Type: "heart", IssueID: issue.ID, UserID: doer.ID})
+
+	_, err = CreateIssueReaction(doer, issue, "heart")
+	assert.True(t, IsErrReactionAlreadyExist(err))
+
+	_, err = CreateIssueReaction(doer, issue, "not-an-emoji")
+	assert.True(t, IsErrForbiddenReaction(err))
+
+	assert.NoError(t, DeleteIssueReaction(doer, issue, "heart"))
+	AssertNotExistsBean(t, &Reaction{Type: "heart", IssueID: issue.ID, UserID: doer.ID})
+}
+
+func TestCreateCommentReaction(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: comment.IssueID}).(*Issue)
+
+	_, err := CreateIssueReaction(doer, issue, "+1")
+	assert.NoError(t, err)
+	_, err = CreateCommentReaction(doer, issue, comment, "+1")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Reaction{Type: "+1", IssueID: issue.ID, CommentID: comment.ID, UserID: doer.ID})
+
+	// removing the reaction to the comment keeps the one to the issue
+	assert.NoError(t, DeleteCommentReaction(doer, issue, comment, "+1"))
+	AssertNotExistsBean(t, &Reaction{Type: "+1", IssueID: issue.ID, CommentID: comment.ID})
+	AssertExistsAndLoadBean(t, &Reaction{Type: "+1", IssueID: issue.ID, UserID: doer.ID})
+}
+
+func TestIssue_LoadReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: comment.IssueID}).(*Issue)
+
+	for _, user := range []*User{user2, user4} {
+		_, err := CreateIssueReaction(user, issue, "tada")
+		assert.NoError(t, err)
+	}
+	_, err := CreateIssueReaction(user4, issue, "eyes")
+	assert.True(t, IsErrForbiddenReaction(err))
+	_, err = CreateCommentReaction(user4, issue, comment, "confused")
+	assert.NoError(t, err)
+
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: comment.IssueID}).(*Issue)
+	assert.NoError(t, issue.LoadAttributes())
+	assert.Len(t, issue.Reactions, 2)
+	assert.True(t, issue.Reactions.HasUser(user4.ID))
+	assert.False(t, issue.Reactions.HasUser(0))
+
+	groups := issue.Reactions.GroupByType()
+	assert.Len(t, groups, 1)
+	assert.Len(t, groups["tada"], 2)
+	assert.Equal(t, user2.DisplayName()+", "+user4.DisplayName(), groups["tada"].GetFirstUsers())
+	assert.EqualValues(t, 0, groups["tada"].GetMoreUserCount())
+
+	for _, c := range issue.Comments {
+		if c.ID == comment.ID {
+			if assert.Len(t, c.Reactions, 1) {
+				assert.EqualValues(t, "confused", c.Reactions[0].Type)
+				assert.EqualValues(t, user4.ID, c.Reactions[0].User.ID)
+			}
+		} else {
+			assert.Empty(t, c.Reactions)
+		}
+	}
+}
@@ -5,8 +5,10 @@
 package models
 
 import (
-	"github.com/go-xorm/xorm"
 	"time"
+
+	"github.com/go-xorm/builder"
+	"github.com/go-xorm/xorm"
 )
 
 // TrackedTime represents a time that was spent for a specific issue.
@@ -51,6 +53,138 @@ func GetTrackedTimesByUser(userID int64) (trackedTimes []*TrackedTime, err error
 	return
 }
 
+// FindTrackedTimesOptions represents the conditions to find tracked times
+type FindTrackedTimesOptions struct {
+	IssueID     int64
+	UserID      int64
+	RepoID      int64
+	OwnerID     int64
+	MilestoneID int64
+	// CreatedAfterUnix and CreatedBeforeUnix restrict the tracked times to a date range, if not zero
+	CreatedAfterUnix  int64
+	CreatedBeforeUnix int64
+}
+
+func (opts *FindTrackedTimesOptions) toCond() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"tracked_time.issue_id": opts.IssueID})
+	}
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"tracked_time.user_id": opts.UserID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"issue.repo_id": opts.RepoID})
+	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.In("issue.repo_id",
+			builder.Select("id").From("repository").Where(builder.Eq{"owner_id": opts.OwnerID})))
+	}
+	if opts.MilestoneID > 0 {
+		cond = cond.And(builder.Eq{"issue.milestone_id": opts.MilestoneID})
+	}
+	if opts.CreatedAfterUnix > 0 {
+		cond = cond.And(builder.Gte{"tracked_time.created_unix": opts.CreatedAfterUnix})
+	}
+	if opts.CreatedBeforeUnix > 0 {
+		cond = cond.And(builder.Lt{"tracked_time.created_unix": opts.CreatedBeforeUnix})
+	}
+	return cond
+}
+
+// GetTrackedTimes returns the tracked times matching the options, oldest first
+func GetTrackedTimes(opts FindTrackedTimesOptions) ([]*TrackedTime, error) {
+	trackedTimes := make([]*TrackedTime, 0, 10)
+	return trackedTimes, x.
+		Join("INNER", "issue", "issue.id = tracked_time.issue_id").
+		Where(opts.toCond()).
+		Asc("tracked_time.created_unix", "tracked_time.id").
+		Find(&trackedTimes)
+}
+
+// TrackedTimeSum represents the total time a user spent on the issues of a
+// milestone of a repository
+type TrackedTimeSum struct {
+	RepoID      int64
+	MilestoneID int64
+	UserID      int64
+	Time        int64
+
+	Repo      *Repository `xorm:"-"`
+	Milestone *Milestone  `xorm:"-"`
+	User      *User       `xorm:"-"`
+}
+
+// TimeString returns the total time in a human readable format
+func (sum *TrackedTimeSum) TimeString() string {
+	return secToTime(sum.Time)
+}
+
+// TrackedTimeSums represents a report of tracked times
+type TrackedTimeSums []*TrackedTimeSum
+
+// Total returns the total time of the report in seconds
+func (sums TrackedTimeSums) Total() int64 {
+	var total int64
+	for _, sum := range sums {
+		total += sum.Time
+	}
+	return total
+}
+
+// TotalString returns the total time of the report in a human readable format
+func (sums TrackedTimeSums) TotalString() string {
+	return secToTime(sums.Total())
+}
+
+// GetTrackedTimeSums returns the tracked times matching the options summed up
+// per repository, milestone and user.
+func GetTrackedTimeSums(opts FindTrackedTimesOptions) (TrackedTimeSums, error) {
+	sums := make(TrackedTimeSums, 0, 10)
+	if err := x.Table("tracked_time").
+		Join("INNER", "issue", "issue.id = tracked_time.issue_id").
+		Where(opts.toCond()).
+		Select("issue.repo_id, issue.milestone_id, tracked_time.user_id, SUM(tracked_time.time) AS time").
+		GroupBy("issue.repo_id, issue.milestone_id, tracked_time.user_id").
+		OrderBy("issue.repo_id, issue.milestone_id, tracked_time.user_id").
+		Find(&sums); err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]int64, 0, len(sums))
+	milestoneIDs := make([]int64, 0, len(sums))
+	userIDs := make([]int64, 0, len(sums))
+	for _, sum := range sums {
+		repoIDs = append(repoIDs, sum.RepoID)
+		if sum.MilestoneID > 0 {
+			milestoneIDs = append(milestoneIDs, sum.MilestoneID)
+		}
+		userIDs = append(userIDs, sum.UserID)
+	}
+
+	repos := make(map[int64]*Repository, len(repoIDs))
+	if err := x.In("id", repoIDs).Find(&repos); err != nil {
+		return nil, err
+	}
+	milestones := make(map[int64]*Milestone, len(milestoneIDs))
+	if err := x.In("id", milestoneIDs).Find(&milestones); err != nil {
+		return nil, err
+	}
+	users := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return nil, err
+	}
+
+	for _, sum := range sums {
+		sum.Repo = repos[sum.RepoID]
+		sum.Milestone = milestones[sum.MilestoneID]
+		if sum.User = users[sum.UserID]; sum.User == nil {
+			sum.User = NewGhostUser()
+		}
+	}
+	return sums, nil
+}
+
 // BeforeInsert will be invoked by XORM before inserting a record
 // representing this object.
 func (t *TrackedTime) BeforeInsert() {
@@ -85,3 +85,68 @@ func TestTotalTimes(t *testing.T) {
 	assert.Len(t, total, 0)
 	assert.NoError(t, err)
 }
+
+func TestGetTrackedTimes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	times, err := GetTrackedTimes(FindTrackedTimesOptions{RepoID: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, times, 4) {
+		assert.EqualValues(t, 1, times[0].ID)
+		assert.EqualValues(t, 2, times[1].ID)
+		assert.EqualValues(t, 3, times[2].ID)
+		assert.EqualValues(t, 5, times[3].ID)
+	}
+
+	times, err = GetTrackedTimes(FindTrackedTimesOptions{RepoID: 1, CreatedBeforeUnix: 946684801})
+	assert.NoError(t, err)
+	if assert.Len(t, times, 1) {
+		assert.EqualValues(t, 1, times[0].ID)
+	}
+
+	times, err = GetTrackedTimes(FindTrackedTimesOptions{UserID: 2, MilestoneID: 1, CreatedAfterUnix: 946684802})
+	assert.NoError(t, err)
+	if assert.Len(t, times, 1) {
+		assert.EqualValues(t, 3, times[0].ID)
+	}
+
+	times, err = GetTrackedTimes(FindTrackedTimesOptions{OwnerID: 3})
+	assert.NoError(t, err)
+	assert.Len(t, times, 0)
+}
+
+func TestGetTrackedTimeSums(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	sums, err := GetTrackedTimeSums(FindTrackedTimesOptions{OwnerID: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, sums, 4) {
+		assert.EqualValues(t, 1, sums[0].Repo.ID)
+		assert.Nil(t, sums[0].Milestone)
+		assert.EqualValues(t, 1, sums[0].User.ID)
+		assert.EqualValues(t, 400, sums[0].Time)
+
+		assert.EqualValues(t, 1, sums[1].Repo.ID)
+		assert.Nil(t, sums[1].Milestone)
+		assert.EqualValues(t, 2, sums[1].User.ID)
+		assert.EqualValues(t, 1, sums[1].Time)
+
+		assert.EqualValues(t, 1, sums[2].Repo.ID)
+		assert.EqualValues(t, 1, sums[2].Milestone.ID)
+		assert.EqualValues(t, 2, sums[2].User.ID)
+		assert.EqualValues(t, 3662, sums[2].Time)
+		assert.Equal(t, "1h 1min 2s", sums[2].TimeString())
+
+		// times of deleted users are kept
+		assert.EqualValues(t, 2, sums[3].Repo.ID)
+		assert.EqualValues(t, -1, sums[3].User.ID)
+	}
+	assert.EqualValues(t, 4064, sums.Total())
+	assert.Equal(t, "1h 7min 44s", sums.TotalString())
+
+	sums, err = GetTrackedTimeSums(FindTrackedTimesOptions{RepoID: 1, UserID: 2, CreatedBeforeUnix: 946684802})
+	assert.NoError(t, err)
+	if assert.Len(t, sums, 1) {
+		assert.EqualValues(t, 3661, sums[0].Time)
+	}
+}
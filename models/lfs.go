@@ -2,8 +2,10 @@ package models
 
 import (
 	"errors"
-	"github.com/go-xorm/xorm"
+	"path"
 	"time"
+
+	"github.com/go-xorm/xorm"
 )
 
 // LFSMetaObject stores metadata for LFS tracked files.
@@ -17,6 +19,15 @@ type LFSMetaObject struct {
 	CreatedUnix  int64
 }
 
+// RelativePath returns the relative path of the lfs object inside the lfs storage
+func (m *LFSMetaObject) RelativePath() string {
+	if len(m.Oid) < 5 {
+		return m.Oid
+	}
+
+	return path.Join(m.Oid[0:2], m.Oid[2:4], m.Oid[4:])
+}
+
 // LFSTokenResponse defines the JSON structure in which the JWT token is stored.
 // This structure is fetched via SSH and passed by the Git LFS client to the server
 // endpoint for authorization.
@@ -120,3 +131,10 @@ func (m *LFSMetaObject) AfterSet(colName string, _ xorm.Cell) {
 		m.Created = time.Unix(m.CreatedUnix, 0).Local()
 	}
 }
+
+// IterateLFS iterates over all LFS meta objects.
+func IterateLFS(f func(mo *LFSMetaObject) error) error {
+	return x.Iterate(new(LFSMetaObject), func(idx int, bean interface{}) error {
+		return f(bean.(*LFSMetaObject))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +30,13 @@ func TestMain(m *testing.M) {
 	setting.SSH.Domain = "try.gitea.io"
 	setting.RepoRootPath = filepath.Join(os.TempDir(), "repos")
 	setting.AppDataPath = filepath.Join(os.TempDir(), "appdata")
+	setting.AttachmentStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "attachments")}
+	setting.LFSStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "lfs")}
+	setting.AvatarStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "avatars")}
+	if err := storage.Init(); err != nil {
+		fmt.Printf("Error initializing storages: %v\n", err)
+		os.Exit(1)
+	}
 
 	os.Exit(m.Run())
 }
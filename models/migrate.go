@@ -0,0 +1,205 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+// The functions in this file insert data imported from other project
+// hostings. Unlike their regular counterparts they keep the indexes and
+// timestamps of the given objects, and neither create comments for the
+// changes they imply nor notify anyone about them.
+
+// InsertMilestones inserts migrated milestones into their repository.
+func InsertMilestones(ms ...*Milestone) (err error) {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if _, err = sess.Insert(m); err != nil {
+			return err
+		}
+		if _, err = sess.Exec("UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
+			return err
+		}
+		if m.IsClosed {
+			if _, err = sess.Exec("UPDATE `repository` SET num_closed_milestones = num_closed_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.Commit()
+}
+
+func insertIssue(sess *xorm.Session, issue *Issue) error {
+	if err := issue.loadRepo(sess); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(issue); err != nil {
+		return fmt.Errorf("insert issue [index: %d]: %v", issue.Index, err)
+	}
+
+	for _, label := range issue.Labels {
+		if label.RepoID != issue.RepoID {
+			continue
+		}
+		if _, err := sess.Insert(&IssueLabel{IssueID: issue.ID, LabelID: label.ID}); err != nil {
+			return err
+		}
+		label.NumIssues++
+		if issue.IsClosed {
+			label.NumClosedIssues++
+		}
+		if err := updateLabel(sess, label); err != nil {
+			return err
+		}
+	}
+
+	if issue.MilestoneID > 0 {
+		m, err := getMilestoneByRepoID(sess, issue.RepoID, issue.MilestoneID)
+		if err != nil {
+			return err
+		}
+		m.NumIssues++
+		if issue.IsClosed {
+			m.NumClosedIssues++
+		}
+		if err = updateMilestone(sess, m); err != nil {
+			return err
+		}
+	}
+
+	numCol, numClosedCol := "num_issues", "num_closed_issues"
+	if issue.IsPull {
+		numCol, numClosedCol = "num_pulls", "num_closed_pulls"
+	}
+	if _, err := sess.Exec(fmt.Sprintf("UPDATE `repository` SET %[1]s = %[1]s + 1 WHERE id = ?", numCol), issue.RepoID); err != nil {
+		return err
+	}
+	if issue.IsClosed {
+		if _, err := sess.Exec(fmt.Sprintf("UPDATE `repository` SET %[1]s = %[1]s + 1 WHERE id = ?", numClosedCol), issue.RepoID); err != nil {
+			return err
+		}
+	}
+
+	return newIssueUsers(sess, issue.Repo, issue)
+}
+
+// InsertIssues inserts migrated issues with their labels into their
+// repository. The labels of the issues must already exist.
+func InsertIssues(issues ...*Issue) (err error) {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		if err = insertIssue(sess, issue); err != nil {
+			return err
+		}
+	}
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		UpdateIssueIndexer(issue)
+	}
+	return nil
+}
+
+// InsertIssueComments inserts migrated comments of issues and pull requests.
+func InsertIssueComments(comments []*Comment) (err error) {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		if _, err = sess.Insert(comment); err != nil {
+			return err
+		}
+		if comment.Type == CommentTypeComment {
+			if _, err = sess.Exec("UPDATE `issue` SET num_comments = num_comments + 1 WHERE id = ?", comment.IssueID); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.Commit()
+}
+
+// InsertPullRequests inserts migrated pull requests together with their
+// issues into their repository.
+func InsertPullRequests(prs ...*PullRequest) (err error) {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		pr.Issue.IsPull = true
+		if err = insertIssue(sess, pr.Issue); err != nil {
+			return err
+		}
+		pr.IssueID = pr.Issue.ID
+		pr.Index = pr.Issue.Index
+		if _, err = sess.Insert(pr); err != nil {
+			return fmt.Errorf("insert pull request [index: %d]: %v", pr.Index, err)
+		}
+	}
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		UpdateIssueIndexer(pr.Issue)
+	}
+	return nil
+}
+
+// InsertReleases inserts migrated releases into their repository.
+func InsertReleases(rels ...*Release) (err error) {
+	if len(rels) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	for _, rel := range rels {
+		if _, err = sess.Insert(rel); err != nil {
+			return fmt.Errorf("insert release [tag: %s]: %v", rel.TagName, err)
+		}
+	}
+	return sess.Commit()
+}
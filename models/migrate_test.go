@@ -0,0 +1,165 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertMilestones(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	reponame := "repo1"
+	repo := AssertExistsAndLoadBean(t, &Repository{Name: reponame}).(*Repository)
+	name := "milestonetest1"
+	m := &Milestone{
+		RepoID:         repo.ID,
+		Name:           name,
+		IsClosed:       true,
+		ClosedDateUnix: 1511862000,
+	}
+	assert.NoError(t, InsertMilestones(m))
+	AssertExistsAndLoadBean(t, &Milestone{ID: m.ID, ClosedDateUnix: 1511862000})
+	repoModified := AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository)
+	assert.EqualValues(t, repo.NumMilestones+1, repoModified.NumMilestones)
+	assert.EqualValues(t, repo.NumClosedMilestones+1, repoModified.NumClosedMilestones)
+	CheckConsistencyFor(t, &Repository{ID: repo.ID}, &Milestone{RepoID: repo.ID})
+}
+
+func TestInsertIssues(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	label := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
+	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+
+	issue := &Issue{
+		RepoID:      repo.ID,
+		Index:       10,
+		PosterID:    2,
+		Title:       "migrated issue",
+		Content:     "content",
+		MilestoneID: milestone.ID,
+		IsClosed:    true,
+		CreatedUnix: 1511862000,
+		UpdatedUnix: 1511865600,
+		Labels:      []*Label{label},
+	}
+	assert.NoError(t, InsertIssues(issue))
+
+	// the index and timestamps are kept
+	issue = AssertExistsAndLoadBean(t, &Issue{RepoID: repo.ID, Index: 10}).(*Issue)
+	assert.EqualValues(t, 1511862000, issue.CreatedUnix)
+	assert.EqualValues(t, 1511865600, issue.UpdatedUnix)
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: label.ID})
+	AssertNotExistsBean(t, &Comment{IssueID: issue.ID})
+
+	repoModified := AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository)
+	assert.EqualValues(t, repo.NumIssues+1, repoModified.NumIssues)
+	assert.EqualValues(t, repo.NumClosedIssues+1, repoModified.NumClosedIssues)
+	CheckConsistencyFor(t, &Repository{ID: repo.ID}, &Issue{ID: issue.ID}, &Label{ID: label.ID}, &Milestone{ID: milestone.ID})
+
+	// new issues are numbered after the migrated ones
+	next, err := repo.nextIssueIndex(x)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, next)
+
+	// indexes are unique within a repository
+	assert.Error(t, InsertIssues(&Issue{RepoID: repo.ID, Index: 10, PosterID: 2, Title: "duplicate"}))
+}
+
+func TestInsertIssueComments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	comments := []*Comment{
+		{Type: CommentTypeComment, IssueID: issue.ID, PosterID: 2, Content: "first", CreatedUnix: 1511862000},
+		{Type: CommentTypeComment, IssueID: issue.ID, PosterID: 2, Content: "second", CreatedUnix: 1511865600},
+	}
+	assert.NoError(t, InsertIssueComments(comments))
+
+	comment := AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Content: "second"}).(*Comment)
+	assert.EqualValues(t, 1511865600, comment.CreatedUnix)
+	assert.EqualValues(t, 1511865600, comment.UpdatedUnix)
+	issueModified := AssertExistsAndLoadBean(t, &Issue{ID: issue.ID}).(*Issue)
+	assert.EqualValues(t, issue.NumComments+2, issueModified.NumComments)
+	CheckConsistencyFor(t, &Issue{ID: issue.ID})
+}
+
+func TestInsertPullRequests(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	pr := &PullRequest{
+		Status:         PullRequestStatusMergeable,
+		BaseRepoID:     repo.ID,
+		HeadUserName:   "someone",
+		HeadBranch:     "feature",
+		BaseBranch:     "master",
+		HasMerged:      true,
+		MergedCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		MergedUnix:     1511865600,
+		Issue: &Issue{
+			RepoID:      repo.ID,
+			Index:       12,
+			PosterID:    2,
+			Title:       "migrated pull request",
+			IsClosed:    true,
+			CreatedUnix: 1511862000,
+		},
+	}
+	assert.NoError(t, InsertPullRequests(pr))
+
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: pr.ID}).(*PullRequest)
+	assert.EqualValues(t, 12, pr.Index)
+	assert.EqualValues(t, 1511865600, pr.MergedUnix)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: pr.IssueID}).(*Issue)
+	assert.True(t, issue.IsPull)
+
+	repoModified := AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository)
+	assert.EqualValues(t, repo.NumPulls+1, repoModified.NumPulls)
+	assert.EqualValues(t, repo.NumClosedPulls+1, repoModified.NumClosedPulls)
+	CheckConsistencyFor(t, &Repository{ID: repo.ID}, &Issue{ID: issue.ID}, &PullRequest{ID: pr.ID})
+}
+
+func TestInsertReleases(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	rel := &Release{
+		RepoID:       1,
+		PublisherID:  2,
+		TagName:      "v0.9.99",
+		LowerTagName: "v0.9.99",
+		Title:        "migrated release",
+		CreatedUnix:  1511862000,
+	}
+	assert.NoError(t, InsertReleases(rel))
+	AssertExistsAndLoadBean(t, &Release{ID: rel.ID, CreatedUnix: 1511862000})
+}
+
+func TestGetUserIDByExternalUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	source := &LoginSource{
+		Type:      LoginOAuth2,
+		Name:      "github-oauth",
+		IsActived: true,
+		Cfg:       &OAuth2Config{Provider: "github"},
+	}
+	assert.NoError(t, CreateLoginSource(source))
+	AssertSuccessfulInsert(t, &ExternalLoginUser{ExternalID: "1669571", UserID: 4, LoginSourceID: source.ID})
+
+	userID, err := GetUserIDByExternalUserID("github", "1669571")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, userID)
+
+	userID, err = GetUserIDByExternalUserID("github", "18600385")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, userID)
+
+	userID, err = GetUserIDByExternalUserID("gitlab", "1669571")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, userID)
+}
@@ -126,6 +126,34 @@ var migrations = []Migration{
 	NewMigration("unescape user full names", unescapeUserFullNames),
 	// v38 -> v39
 	NewMigration("remove commits and settings unit types", removeCommitsUnitType),
+	// v39 -> v40
+	NewMigration("add review table and code comment columns", addReviews),
+	// v40 -> v41
+	NewMigration("add whitelists, required approvals and status checks to protected branches", addProtectedBranchWhitelists),
+	// v41 -> v42
+	NewMigration("add merge styles to pull requests unit and pull requests", addPullRequestMergeStyles),
+	// v42 -> v43
+	NewMigration("add retry attempts to hook tasks", addHookTaskRetryColumns),
+	// v43 -> v44
+	NewMigration("add scopes, repositories and expiry to access tokens", addAccessTokenScopes),
+	// v44 -> v45
+	NewMigration("add OAuth2 applications, grants and authorization codes", addOAuth2Provider),
+	// v45 -> v46
+	NewMigration("add repository indexer status", addRepoIndexerStatus),
+	// v46 -> v47
+	NewMigration("add multiple assignees", addMultipleAssignees),
+	// v47 -> v48
+	NewMigration("add push mirrors", addPushMirrors),
+	// v48 -> v49
+	NewMigration("add issue dependencies", addIssueDependencies),
+	// v49 -> v50
+	NewMigration("add reactions", addReactions),
+	// v50 -> v51
+	NewMigration("add projects", addProjects),
+	// v51 -> v52
+	NewMigration("add require signed commits to protected branches", addProtectedBranchRequireSignedCommits),
+	// v52 -> v53
+	NewMigration("add visibility to users and organizations", addUserVisibility),
 }
 
 // Migrate database to current version
@@ -0,0 +1,40 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addReviews(x *xorm.Engine) error {
+	// Review see models/review.go
+	type Review struct {
+		ID          int64 `xorm:"pk autoincr"`
+		Type        int
+		ReviewerID  int64  `xorm:"INDEX"`
+		IssueID     int64  `xorm:"INDEX"`
+		Content     string `xorm:"TEXT"`
+		CommitID    string `xorm:"VARCHAR(40)"`
+		CreatedUnix int64  `xorm:"INDEX"`
+		UpdatedUnix int64  `xorm:"INDEX"`
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		TreePath    string
+		ReviewID    int64 `xorm:"INDEX"`
+		Invalidated bool
+	}
+
+	if err := x.Sync2(new(Review)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
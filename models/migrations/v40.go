@@ -0,0 +1,37 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addProtectedBranchWhitelists(x *xorm.Engine) error {
+	// ProtectedBranch see models/branches.go
+	type ProtectedBranch struct {
+		ID                    int64  `xorm:"pk autoincr"`
+		RepoID                int64  `xorm:"UNIQUE(s)"`
+		BranchName            string `xorm:"UNIQUE(s)"`
+		CanPush               bool
+		EnableWhitelist       bool     `xorm:"NOT NULL DEFAULT false"`
+		WhitelistUserIDs      []int64  `xorm:"JSON TEXT"`
+		WhitelistTeamIDs      []int64  `xorm:"JSON TEXT"`
+		EnableMergeWhitelist  bool     `xorm:"NOT NULL DEFAULT false"`
+		MergeWhitelistUserIDs []int64  `xorm:"JSON TEXT"`
+		MergeWhitelistTeamIDs []int64  `xorm:"JSON TEXT"`
+		RequiredApprovals     int64    `xorm:"NOT NULL DEFAULT 0"`
+		EnableStatusCheck     bool     `xorm:"NOT NULL DEFAULT false"`
+		StatusCheckContexts   []string `xorm:"JSON TEXT"`
+		CreatedUnix           int64
+		UpdatedUnix           int64
+	}
+
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
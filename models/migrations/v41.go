@@ -0,0 +1,31 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addPullRequestMergeStyles(x *xorm.Engine) error {
+	// PullRequest see models/pull.go
+	type PullRequest struct {
+		ID         int64  `xorm:"pk autoincr"`
+		MergeStyle string `xorm:"VARCHAR(20)"`
+	}
+
+	if err := x.Sync2(new(PullRequest)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Keep merge commits working and allow the new styles for existing repositories,
+	// the pull requests unit type did not change since v16.
+	if _, err := x.Exec("UPDATE repo_unit SET config = ? WHERE `type` = ?",
+		`{"AllowMerge":true,"AllowRebase":true,"AllowRebaseMerge":true,"AllowSquash":true}`, V16UnitTypePRs); err != nil {
+		return fmt.Errorf("update pull requests unit config: %v", err)
+	}
+	return nil
+}
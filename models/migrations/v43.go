@@ -0,0 +1,31 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addAccessTokenScopes(x *xorm.Engine) error {
+	// AccessToken see models/token.go
+	type AccessToken struct {
+		ID          int64    `xorm:"pk autoincr"`
+		Scopes      []string `xorm:"JSON TEXT"`
+		RepoIDs     []int64  `xorm:"JSON TEXT"`
+		ExpiresUnix int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Existing tokens keep granting full access to the account.
+	if _, err := x.Exec("UPDATE access_token SET scopes = ?", `["all"]`); err != nil {
+		return fmt.Errorf("update access token scopes: %v", err)
+	}
+	return nil
+}
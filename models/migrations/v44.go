@@ -0,0 +1,65 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+// oauth2Application see models/oauth2_application.go
+type oauth2Application struct {
+	ID           int64 `xorm:"pk autoincr"`
+	UID          int64 `xorm:"INDEX"`
+	Name         string
+	ClientID     string `xorm:"UNIQUE"`
+	ClientSecret string
+	RedirectURIs []string `xorm:"'redirect_uris' JSON TEXT"`
+	CreatedUnix  int64    `xorm:"INDEX"`
+	UpdatedUnix  int64    `xorm:"INDEX"`
+}
+
+func (*oauth2Application) TableName() string {
+	return "oauth2_application"
+}
+
+// oauth2AuthorizationCode see models/oauth2_application.go
+type oauth2AuthorizationCode struct {
+	ID                  int64 `xorm:"pk autoincr"`
+	GrantID             int64
+	Code                string `xorm:"INDEX UNIQUE"`
+	CodeChallenge       string
+	CodeChallengeMethod string
+	RedirectURI         string
+	ValidUntil          int64 `xorm:"INDEX"`
+}
+
+func (*oauth2AuthorizationCode) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// oauth2Grant see models/oauth2_application.go
+type oauth2Grant struct {
+	ID            int64  `xorm:"pk autoincr"`
+	UserID        int64  `xorm:"INDEX unique(user_application)"`
+	ApplicationID int64  `xorm:"INDEX unique(user_application)"`
+	Counter       int64  `xorm:"NOT NULL DEFAULT 1"`
+	Scope         string `xorm:"TEXT"`
+	Nonce         string `xorm:"TEXT"`
+	CreatedUnix   int64  `xorm:"INDEX"`
+	UpdatedUnix   int64  `xorm:"INDEX"`
+}
+
+func (*oauth2Grant) TableName() string {
+	return "oauth2_grant"
+}
+
+func addOAuth2Provider(x *xorm.Engine) error {
+	if err := x.Sync2(new(oauth2Application), new(oauth2AuthorizationCode), new(oauth2Grant)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
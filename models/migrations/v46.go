@@ -0,0 +1,60 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addMultipleAssignees(x *xorm.Engine) error {
+	// IssueAssignees see models/issue_assignees.go
+	type IssueAssignees struct {
+		ID         int64 `xorm:"pk autoincr"`
+		AssigneeID int64 `xorm:"INDEX"`
+		IssueID    int64 `xorm:"INDEX"`
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		ID              int64 `xorm:"pk autoincr"`
+		AssigneeID      int64
+		RemovedAssignee bool
+	}
+
+	// commentTypeAssignees see models/issue_comment.go
+	const commentTypeAssignees = 9
+
+	if err := x.Sync2(new(IssueAssignees), new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	// Move the single assignee of every issue to the new table.
+	if _, err := sess.Exec("INSERT INTO `issue_assignees` (assignee_id, issue_id) SELECT assignee_id, id FROM `issue` WHERE assignee_id > 0"); err != nil {
+		return fmt.Errorf("move assignees: %v", err)
+	}
+
+	// A comment used to record both the old and the new assignee, but is
+	// now about a single user being assigned or removed. Split comments
+	// replacing an assignee in a removal and an assignment.
+	if _, err := sess.Exec("INSERT INTO `comment` (type, poster_id, issue_id, assignee_id, removed_assignee, created_unix, updated_unix) "+
+		"SELECT type, poster_id, issue_id, old_assignee_id, ?, created_unix, updated_unix FROM `comment` WHERE type = ? AND old_assignee_id > 0 AND assignee_id > 0",
+		true, commentTypeAssignees); err != nil {
+		return fmt.Errorf("split assignee comments: %v", err)
+	}
+	if _, err := sess.Exec("UPDATE `comment` SET assignee_id = old_assignee_id, removed_assignee = ? WHERE type = ? AND old_assignee_id > 0 AND assignee_id = 0",
+		true, commentTypeAssignees); err != nil {
+		return fmt.Errorf("update removed assignee comments: %v", err)
+	}
+
+	return sess.Commit()
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addIssueDependencies(x *xorm.Engine) error {
+	// IssueDependency see models/issue_dependency.go
+	type IssueDependency struct {
+		ID           int64 `xorm:"pk autoincr"`
+		UserID       int64 `xorm:"NOT NULL"`
+		IssueID      int64 `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+		DependencyID int64 `xorm:"UNIQUE(issue_dependency) NOT NULL INDEX"`
+		CreatedUnix  int64
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		ID               int64 `xorm:"pk autoincr"`
+		DependentIssueID int64
+	}
+
+	if err := x.Sync2(new(IssueDependency), new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// RepoUnit see models/repo_unit.go
+	type RepoUnit struct {
+		ID     int64
+		Type   int
+		Config string `xorm:"TEXT"`
+	}
+
+	// Existing repositories get the default of the dependencies setting
+	units := make([]*RepoUnit, 0, 100)
+	if err := x.Find(&units, &RepoUnit{Type: V16UnitTypeIssues}); err != nil {
+		return fmt.Errorf("find issues units: %v", err)
+	}
+	for _, unit := range units {
+		config := make(map[string]interface{})
+		if len(unit.Config) > 0 {
+			if err := json.Unmarshal([]byte(unit.Config), &config); err != nil {
+				return fmt.Errorf("unmarshal config of repo unit %d: %v", unit.ID, err)
+			}
+		}
+		config["EnableDependencies"] = setting.Service.DefaultEnableDependencies
+
+		bs, err := json.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("marshal config of repo unit %d: %v", unit.ID, err)
+		}
+		if _, err := x.Exec("UPDATE repo_unit SET config = ? WHERE id = ?", string(bs), unit.ID); err != nil {
+			return fmt.Errorf("update config of repo unit %d: %v", unit.ID, err)
+		}
+	}
+	return nil
+}
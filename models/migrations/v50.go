@@ -0,0 +1,95 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addProjects(x *xorm.Engine) error {
+	// Project see models/project.go
+	type Project struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX"`
+		CreatorID      int64  `xorm:"NOT NULL"`
+		Title          string `xorm:"NOT NULL"`
+		Description    string `xorm:"TEXT"`
+		IsClosed       bool   `xorm:"INDEX"`
+		CreatedUnix    int64  `xorm:"INDEX"`
+		UpdatedUnix    int64
+		ClosedDateUnix int64
+	}
+
+	// ProjectBoard see models/project.go
+	type ProjectBoard struct {
+		ID          int64  `xorm:"pk autoincr"`
+		ProjectID   int64  `xorm:"INDEX NOT NULL"`
+		Title       string `xorm:"NOT NULL"`
+		Sorting     int    `xorm:"NOT NULL DEFAULT 0"`
+		IsClosed    bool   `xorm:"NOT NULL DEFAULT false"`
+		CreatorID   int64  `xorm:"NOT NULL"`
+		CreatedUnix int64  `xorm:"INDEX"`
+	}
+
+	// ProjectIssue see models/project.go
+	type ProjectIssue struct {
+		ID             int64 `xorm:"pk autoincr"`
+		IssueID        int64 `xorm:"UNIQUE NOT NULL"`
+		ProjectID      int64 `xorm:"INDEX NOT NULL"`
+		ProjectBoardID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Sorting        int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(Project), new(ProjectBoard), new(ProjectIssue)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Team see models/org_team.go
+	type Team struct {
+		ID        int64
+		UnitTypes string `xorm:"TEXT"`
+	}
+
+	const (
+		unitTypeIssues   = 2
+		unitTypeProjects = 8
+	)
+
+	// Teams limited to some units keep access to the boards of the issues
+	// they can see
+	teams := make([]*Team, 0, 50)
+	if err := x.Where("unit_types IS NOT NULL").Find(&teams); err != nil {
+		return fmt.Errorf("find teams: %v", err)
+	}
+	for _, team := range teams {
+		var unitTypes []int
+		if len(team.UnitTypes) == 0 {
+			continue
+		} else if err := json.Unmarshal([]byte(team.UnitTypes), &unitTypes); err != nil {
+			return fmt.Errorf("unmarshal unit types of team %d: %v", team.ID, err)
+		}
+
+		var hasIssues, hasProjects bool
+		for _, tp := range unitTypes {
+			hasIssues = hasIssues || tp == unitTypeIssues
+			hasProjects = hasProjects || tp == unitTypeProjects
+		}
+		if !hasIssues || hasProjects {
+			continue
+		}
+
+		bs, err := json.Marshal(append(unitTypes, unitTypeProjects))
+		if err != nil {
+			return fmt.Errorf("marshal unit types of team %d: %v", team.ID, err)
+		}
+		if _, err := x.Exec("UPDATE `team` SET unit_types = ? WHERE id = ?", string(bs), team.ID); err != nil {
+			return fmt.Errorf("update unit types of team %d: %v", team.ID, err)
+		}
+	}
+	return nil
+}
@@ -90,6 +90,7 @@ func init() {
 		new(IssueLabel),
 		new(Milestone),
 		new(Mirror),
+		new(PushMirror),
 		new(Release),
 		new(LoginSource),
 		new(Webhook),
@@ -114,6 +115,17 @@ func init() {
 		new(CommitStatus),
 		new(Stopwatch),
 		new(TrackedTime),
+		new(Review),
+		new(OAuth2Application),
+		new(OAuth2AuthorizationCode),
+		new(OAuth2Grant),
+		new(RepoIndexerStatus),
+		new(IssueAssignees),
+		new(IssueDependency),
+		new(Reaction),
+		new(Project),
+		new(ProjectBoard),
+		new(ProjectIssue),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -150,7 +162,10 @@ func LoadConfigs() {
 
 	sec = setting.Cfg.Section("indexer")
 	setting.Indexer.IssuePath = sec.Key("ISSUE_INDEXER_PATH").MustString("indexers/issues.bleve")
+	setting.Indexer.RepoIndexerEnabled = sec.Key("REPO_INDEXER_ENABLED").MustBool(false)
+	setting.Indexer.RepoPath = sec.Key("REPO_INDEXER_PATH").MustString("indexers/repos.bleve")
 	setting.Indexer.UpdateQueueLength = sec.Key("UPDATE_BUFFER_LEN").MustInt(20)
+	setting.Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(1024 * 1024)
 }
 
 // parsePostgreSQLHostPort parses given input in various forms defined in
@@ -7,6 +7,11 @@ package models
 import (
 	"fmt"
 	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/go-xorm/builder"
 )
 
 type (
@@ -280,7 +285,7 @@ func setNotificationStatusReadIfUnread(e Engine, userID, issueID int64) error {
 
 // SetNotificationStatus change the notification status
 func SetNotificationStatus(notificationID int64, user *User, status NotificationStatus) error {
-	notification, err := getNotificationByID(notificationID)
+	notification, err := GetNotificationByID(notificationID)
 	if err != nil {
 		return err
 	}
@@ -295,7 +300,8 @@ func SetNotificationStatus(notificationID int64, user *User, status Notification
 	return err
 }
 
-func getNotificationByID(notificationID int64) (*Notification, error) {
+// GetNotificationByID returns the notification with given ID
+func GetNotificationByID(notificationID int64) (*Notification, error) {
 	notification := new(Notification)
 	ok, err := x.
 		Where("id = ?", notificationID).
@@ -306,8 +312,150 @@ func getNotificationByID(notificationID int64) (*Notification, error) {
 	}
 
 	if !ok {
-		return nil, fmt.Errorf("Notification %d does not exists", notificationID)
+		return nil, ErrNotificationNotExist{notificationID}
 	}
 
 	return notification, nil
 }
+
+// FindNotificationOptions represents the conditions to find notifications
+type FindNotificationOptions struct {
+	UserID int64
+	RepoID int64
+	// RepoIDs restricts the notifications to the given repositories, if not empty
+	RepoIDs  []int64
+	Statuses []NotificationStatus
+	// UpdatedAfterUnix and UpdatedBeforeUnix restrict the notifications to a date range, if not zero
+	UpdatedAfterUnix  int64
+	UpdatedBeforeUnix int64
+	Page              int
+	PageSize          int
+}
+
+func (opts *FindNotificationOptions) toCond() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"notification.user_id": opts.UserID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"notification.repo_id": opts.RepoID})
+	}
+	if len(opts.RepoIDs) > 0 {
+		cond = cond.And(builder.In("notification.repo_id", opts.RepoIDs))
+	}
+	if len(opts.Statuses) > 0 {
+		statuses := make([]interface{}, len(opts.Statuses))
+		for i, status := range opts.Statuses {
+			statuses[i] = status
+		}
+		cond = cond.And(builder.In("notification.status", statuses...))
+	}
+	if opts.UpdatedAfterUnix > 0 {
+		cond = cond.And(builder.Gte{"notification.updated_unix": opts.UpdatedAfterUnix})
+	}
+	if opts.UpdatedBeforeUnix > 0 {
+		cond = cond.And(builder.Lt{"notification.updated_unix": opts.UpdatedBeforeUnix})
+	}
+	return cond
+}
+
+// GetNotifications returns the notifications matching the options, most recently updated first
+func GetNotifications(opts FindNotificationOptions) (NotificationList, error) {
+	sess := x.
+		Where(opts.toCond()).
+		Desc("notification.updated_unix", "notification.id")
+	if opts.Page > 0 && opts.PageSize > 0 {
+		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+	}
+
+	notifications := make(NotificationList, 0, opts.PageSize)
+	return notifications, sess.Find(&notifications)
+}
+
+// CountNotifications returns the number of notifications matching the options
+func CountNotifications(opts FindNotificationOptions) (int64, error) {
+	return x.Where(opts.toCond()).Count(new(Notification))
+}
+
+// UpdateNotificationStatuses changes the status of the notifications matching
+// the options which have the status from to the status to.
+func UpdateNotificationStatuses(opts FindNotificationOptions, from, to NotificationStatus) error {
+	opts.Statuses = []NotificationStatus{from}
+	_, err := x.
+		Where(opts.toCond()).
+		Cols("status", "updated_unix").
+		Update(&Notification{Status: to})
+	return err
+}
+
+// APIFormat converts a Notification to its API format, its repository and
+// issue must have been loaded.
+func (n *Notification) APIFormat() *api.NotificationThread {
+	subject := &api.NotificationSubject{
+		Title:   n.Issue.Title,
+		HTMLURL: n.Issue.HTMLURL(),
+		Type:    "Issue",
+		State:   "open",
+	}
+	if n.Issue.IsPull {
+		subject.Type = "Pull"
+		subject.URL = fmt.Sprintf("%s/pulls/%d", n.Repository.APIURL(), n.Issue.Index)
+	} else {
+		subject.URL = fmt.Sprintf("%s/issues/%d", n.Repository.APIURL(), n.Issue.Index)
+	}
+	if n.Issue.IsClosed {
+		subject.State = "closed"
+	}
+
+	return &api.NotificationThread{
+		ID:         n.ID,
+		Repository: n.Repository.APIFormat(AccessModeNone),
+		Subject:    subject,
+		Unread:     n.Status == NotificationStatusUnread,
+		Pinned:     n.Status == NotificationStatusPinned,
+		UpdatedAt:  time.Unix(n.UpdatedUnix, 0).Local(),
+		URL:        fmt.Sprintf("%sapi/v1/notifications/threads/%d", setting.AppURL, n.ID),
+	}
+}
+
+// NotificationList defines a list of notifications
+type NotificationList []*Notification
+
+func (notifications NotificationList) loadAttributes(e Engine) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	repoIDs := make(map[int64]struct{}, len(notifications))
+	issueIDs := make(map[int64]struct{}, len(notifications))
+	for _, notification := range notifications {
+		repoIDs[notification.RepoID] = struct{}{}
+		issueIDs[notification.IssueID] = struct{}{}
+	}
+
+	repoMaps := make(map[int64]*Repository, len(repoIDs))
+	if err := e.In("id", keysInt64(repoIDs)).Find(&repoMaps); err != nil {
+		return fmt.Errorf("find repository: %v", err)
+	}
+	issueMaps := make(map[int64]*Issue, len(issueIDs))
+	if err := e.In("id", keysInt64(issueIDs)).Find(&issueMaps); err != nil {
+		return fmt.Errorf("find issue: %v", err)
+	}
+
+	for _, notification := range notifications {
+		var ok bool
+		if notification.Repository, ok = repoMaps[notification.RepoID]; !ok {
+			return ErrRepoNotExist{ID: notification.RepoID}
+		}
+		if notification.Issue, ok = issueMaps[notification.IssueID]; !ok {
+			return ErrIssueNotExist{ID: notification.IssueID}
+		}
+		notification.Issue.Repo = notification.Repository
+	}
+	return nil
+}
+
+// LoadAttributes loads the repositories and issues of the notifications
+func (notifications NotificationList) LoadAttributes() error {
+	return notifications.loadAttributes(x)
+}
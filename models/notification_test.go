@@ -76,3 +76,71 @@ func TestSetNotificationStatus(t *testing.T) {
 	assert.Error(t, SetNotificationStatus(1, user, NotificationStatusRead))
 	assert.Error(t, SetNotificationStatus(NonexistentID, user, NotificationStatusRead))
 }
+
+func TestGetNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notfs, err := GetNotifications(FindNotificationOptions{
+		RepoID:   1,
+		Statuses: []NotificationStatus{NotificationStatusUnread, NotificationStatusRead},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 2)
+
+	opts := FindNotificationOptions{UserID: 1, Statuses: []NotificationStatus{NotificationStatusUnread}}
+	notfs, err = GetNotifications(opts)
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 1, notfs[0].ID)
+	}
+	cnt, err := CountNotifications(opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cnt)
+
+	opts.UpdatedBeforeUnix = 946684800
+	cnt, err = CountNotifications(opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, cnt)
+	opts.UpdatedBeforeUnix = 0
+	opts.UpdatedAfterUnix = 946684800
+	cnt, err = CountNotifications(opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cnt)
+}
+
+func TestNotificationList_LoadAttributes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notfs, err := GetNotifications(FindNotificationOptions{UserID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, notfs.LoadAttributes())
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 1, notfs[0].Repository.ID)
+		assert.EqualValues(t, 1, notfs[0].Issue.ID)
+
+		thread := notfs[0].APIFormat()
+		assert.True(t, thread.Unread)
+		assert.Equal(t, "Issue", thread.Subject.Type)
+		assert.Equal(t, notfs[0].Issue.Title, thread.Subject.Title)
+	}
+}
+
+func TestUpdateNotificationStatuses(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.NoError(t, UpdateNotificationStatuses(FindNotificationOptions{UserID: 1, UpdatedBeforeUnix: 946684800},
+		NotificationStatusUnread, NotificationStatusRead))
+	AssertExistsAndLoadBean(t, &Notification{ID: 1, Status: NotificationStatusUnread})
+
+	assert.NoError(t, UpdateNotificationStatuses(FindNotificationOptions{UserID: 1},
+		NotificationStatusUnread, NotificationStatusRead))
+	AssertExistsAndLoadBean(t, &Notification{ID: 1, Status: NotificationStatusRead})
+	AssertExistsAndLoadBean(t, &Notification{ID: 2, Status: NotificationStatusRead})
+}
+
+func TestGetNotificationByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notf, err := GetNotificationByID(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, notf.UserID)
+
+	_, err = GetNotificationByID(NonexistentID)
+	assert.True(t, IsErrNotificationNotExist(err))
+}
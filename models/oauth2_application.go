@@ -0,0 +1,481 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-xorm/xorm"
+	gouuid "github.com/satori/go.uuid"
+
+	"code.gitea.io/gitea/modules/base"
+)
+
+// oauth2AuthorizationCodeLifetime is the time an authorization code can be exchanged for tokens
+const oauth2AuthorizationCodeLifetime = 10 * time.Minute
+
+// OAuth2Application represents an OAuth2 client registered by a user or an organization.
+type OAuth2Application struct {
+	ID   int64 `xorm:"pk autoincr"`
+	UID  int64 `xorm:"INDEX"`
+	User *User `xorm:"-"`
+	Name string
+
+	ClientID string `xorm:"UNIQUE"`
+	// ClientSecret is the SHA256 hash of the secret, which is only shown once.
+	ClientSecret string
+	RedirectURIs []string `xorm:"'redirect_uris' JSON TEXT"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX"`
+}
+
+// TableName sets the table name to `oauth2_application`
+func (app *OAuth2Application) TableName() string {
+	return "oauth2_application"
+}
+
+// BeforeInsert will be invoked by XORM before inserting a record representing this object.
+func (app *OAuth2Application) BeforeInsert() {
+	app.CreatedUnix = time.Now().Unix()
+	app.UpdatedUnix = app.CreatedUnix
+}
+
+// BeforeUpdate is invoked from XORM before updating this object.
+func (app *OAuth2Application) BeforeUpdate() {
+	app.UpdatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (app *OAuth2Application) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		app.Created = time.Unix(app.CreatedUnix, 0).Local()
+	case "updated_unix":
+		app.Updated = time.Unix(app.UpdatedUnix, 0).Local()
+	}
+}
+
+// LoadUser loads the owner of the application.
+func (app *OAuth2Application) LoadUser() (err error) {
+	if app.User == nil {
+		app.User, err = GetUserByID(app.UID)
+	}
+	return err
+}
+
+// PrimaryRedirectURI returns the first redirect URI or an empty string if none is set.
+func (app *OAuth2Application) PrimaryRedirectURI() string {
+	if len(app.RedirectURIs) == 0 {
+		return ""
+	}
+	return app.RedirectURIs[0]
+}
+
+// ContainsRedirectURI checks if the redirect URI is registered for the application.
+func (app *OAuth2Application) ContainsRedirectURI(redirectURI string) bool {
+	for _, uri := range app.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func hashOAuth2ClientSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateClientSecret replaces the client secret of the application by a new
+// random one and returns it in clear text.
+func (app *OAuth2Application) GenerateClientSecret() (string, error) {
+	secret, err := base.GetRandomString(44)
+	if err != nil {
+		return "", err
+	}
+	app.ClientSecret = hashOAuth2ClientSecret(secret)
+	if _, err = x.Id(app.ID).Cols("client_secret").Update(app); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ValidateClientSecret checks the given secret against the stored hash.
+func (app *OAuth2Application) ValidateClientSecret(secret string) bool {
+	return len(app.ClientSecret) > 0 &&
+		subtle.ConstantTimeCompare([]byte(app.ClientSecret), []byte(hashOAuth2ClientSecret(secret))) == 1
+}
+
+// GetGrantByUserID returns the grant of the user for the application,
+// or nil if the user has not authorized the application yet.
+func (app *OAuth2Application) GetGrantByUserID(userID int64) (*OAuth2Grant, error) {
+	grant := &OAuth2Grant{
+		ApplicationID: app.ID,
+		UserID:        userID,
+	}
+	has, err := x.Get(grant)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return grant, nil
+}
+
+// CreateGrant creates a grant of the application for the user.
+func (app *OAuth2Application) CreateGrant(userID int64, scope string) (*OAuth2Grant, error) {
+	grant := &OAuth2Grant{
+		ApplicationID: app.ID,
+		UserID:        userID,
+		Counter:       1,
+		Scope:         scope,
+	}
+	if _, err := x.Insert(grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// GetOAuth2ApplicationByClientID returns the OAuth2 application with the given client ID.
+func GetOAuth2ApplicationByClientID(clientID string) (*OAuth2Application, error) {
+	app := &OAuth2Application{ClientID: clientID}
+	has, err := x.Get(app)
+	if err != nil {
+		return nil, err
+	} else if len(clientID) == 0 || !has {
+		return nil, ErrOAuth2ApplicationNotExist{ClientID: clientID}
+	}
+	return app, nil
+}
+
+// GetOAuth2ApplicationByID returns the OAuth2 application with the given ID.
+func GetOAuth2ApplicationByID(id int64) (*OAuth2Application, error) {
+	app := new(OAuth2Application)
+	has, err := x.Id(id).Get(app)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuth2ApplicationNotExist{ID: id}
+	}
+	return app, nil
+}
+
+// GetOAuth2ApplicationsByUserID returns all OAuth2 applications owned by the user or organization.
+func GetOAuth2ApplicationsByUserID(userID int64) ([]*OAuth2Application, error) {
+	apps := make([]*OAuth2Application, 0, 5)
+	return apps, x.
+		Where("uid=?", userID).
+		Asc("id").
+		Find(&apps)
+}
+
+// CreateOAuth2ApplicationOptions holds options to create an OAuth2 application
+type CreateOAuth2ApplicationOptions struct {
+	Name         string
+	UserID       int64
+	RedirectURIs []string
+}
+
+// CreateOAuth2Application creates a new OAuth2 application without client secret.
+func CreateOAuth2Application(opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error) {
+	app := &OAuth2Application{
+		UID:          opts.UserID,
+		Name:         opts.Name,
+		ClientID:     gouuid.NewV4().String(),
+		RedirectURIs: opts.RedirectURIs,
+	}
+	if _, err := x.Insert(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// UpdateOAuth2Application updates the name and the redirect URIs of an OAuth2 application.
+func UpdateOAuth2Application(app *OAuth2Application) error {
+	_, err := x.Id(app.ID).Cols("name", "redirect_uris").Update(app)
+	return err
+}
+
+// DeleteOAuth2Application deletes the OAuth2 application of the user
+// including all grants and authorization codes issued for it.
+func DeleteOAuth2Application(id, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if deleted, err := sess.Delete(&OAuth2Application{ID: id, UID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuth2ApplicationNotExist{ID: id}
+	}
+
+	grantIDs := make([]int64, 0, 10)
+	if err := sess.Table("oauth2_grant").Where("application_id=?", id).Cols("id").Find(&grantIDs); err != nil {
+		return err
+	}
+	if len(grantIDs) > 0 {
+		if _, err := sess.In("grant_id", grantIDs).Delete(new(OAuth2AuthorizationCode)); err != nil {
+			return err
+		}
+		if _, err := sess.In("id", grantIDs).Delete(new(OAuth2Grant)); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// OAuth2AuthorizationCode is a short living code which can be exchanged for tokens of a grant.
+type OAuth2AuthorizationCode struct {
+	ID                  int64        `xorm:"pk autoincr"`
+	Grant               *OAuth2Grant `xorm:"-"`
+	GrantID             int64
+	Code                string `xorm:"INDEX UNIQUE"`
+	CodeChallenge       string
+	CodeChallengeMethod string
+	RedirectURI         string
+	ValidUntil          int64 `xorm:"INDEX"`
+}
+
+// TableName sets the table name to `oauth2_authorization_code`
+func (code *OAuth2AuthorizationCode) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// GenerateRedirectURI returns the redirect URI passing the code and the state to the client.
+func (code *OAuth2AuthorizationCode) GenerateRedirectURI(state string) (*url.URL, error) {
+	redirect, err := url.Parse(code.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	q := redirect.Query()
+	if state != "" {
+		q.Set("state", state)
+	}
+	q.Set("code", code.Code)
+	redirect.RawQuery = q.Encode()
+	return redirect, nil
+}
+
+// IsExpired returns true if the code cannot be exchanged anymore.
+func (code *OAuth2AuthorizationCode) IsExpired() bool {
+	return code.ValidUntil <= time.Now().Unix()
+}
+
+// Invalidate deletes the code so it cannot be used again. It returns an
+// ErrOAuth2AuthorizationCodeNotExist if the code has already been invalidated.
+func (code *OAuth2AuthorizationCode) Invalidate() error {
+	affected, err := x.Delete(code)
+	if err != nil {
+		return err
+	} else if affected != 1 {
+		return ErrOAuth2AuthorizationCodeNotExist{}
+	}
+	return nil
+}
+
+// ValidateCodeChallenge checks the code verifier sent by the client against
+// the code challenge of the authorization request (RFC 7636).
+func (code *OAuth2AuthorizationCode) ValidateCodeChallenge(verifier string) bool {
+	switch code.CodeChallengeMethod {
+	case "S256":
+		hash := sha256.Sum256([]byte(verifier))
+		return len(verifier) > 0 &&
+			subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(hash[:])), []byte(code.CodeChallenge)) == 1
+	case "plain":
+		return len(verifier) > 0 && subtle.ConstantTimeCompare([]byte(verifier), []byte(code.CodeChallenge)) == 1
+	case "":
+		return true
+	}
+	return false
+}
+
+// GetOAuth2AuthorizationByCode returns the authorization code including its grant.
+func GetOAuth2AuthorizationByCode(code string) (*OAuth2AuthorizationCode, error) {
+	authCode := &OAuth2AuthorizationCode{Code: code}
+	has, err := x.Get(authCode)
+	if err != nil {
+		return nil, err
+	} else if len(code) == 0 || !has {
+		return nil, ErrOAuth2AuthorizationCodeNotExist{}
+	}
+
+	if authCode.Grant, err = GetOAuth2GrantByID(authCode.GrantID); err != nil {
+		return nil, err
+	}
+	return authCode, nil
+}
+
+// OAuth2Grant represents the authorization of an OAuth2 application by a user.
+type OAuth2Grant struct {
+	ID            int64              `xorm:"pk autoincr"`
+	UserID        int64              `xorm:"INDEX unique(user_application)"`
+	Application   *OAuth2Application `xorm:"-"`
+	ApplicationID int64              `xorm:"INDEX unique(user_application)"`
+	// Counter is increased to invalidate refresh tokens which have been used.
+	Counter int64 `xorm:"NOT NULL DEFAULT 1"`
+	// Scope is a space separated list of the granted scopes.
+	Scope string `xorm:"TEXT"`
+	Nonce string `xorm:"TEXT"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX"`
+}
+
+// TableName sets the table name to `oauth2_grant`
+func (grant *OAuth2Grant) TableName() string {
+	return "oauth2_grant"
+}
+
+// BeforeInsert will be invoked by XORM before inserting a record representing this object.
+func (grant *OAuth2Grant) BeforeInsert() {
+	grant.CreatedUnix = time.Now().Unix()
+	grant.UpdatedUnix = grant.CreatedUnix
+}
+
+// BeforeUpdate is invoked from XORM before updating this object.
+func (grant *OAuth2Grant) BeforeUpdate() {
+	grant.UpdatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (grant *OAuth2Grant) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		grant.Created = time.Unix(grant.CreatedUnix, 0).Local()
+	case "updated_unix":
+		grant.Updated = time.Unix(grant.UpdatedUnix, 0).Local()
+	}
+}
+
+// LoadApplication loads the application the grant belongs to.
+func (grant *OAuth2Grant) LoadApplication() (err error) {
+	if grant.Application == nil {
+		grant.Application, err = GetOAuth2ApplicationByID(grant.ApplicationID)
+	}
+	return err
+}
+
+// GenerateNewAuthorizationCode creates an authorization code for the grant.
+func (grant *OAuth2Grant) GenerateNewAuthorizationCode(redirectURI, codeChallenge, codeChallengeMethod string) (*OAuth2AuthorizationCode, error) {
+	code := &OAuth2AuthorizationCode{
+		Grant:               grant,
+		GrantID:             grant.ID,
+		Code:                base.GetRandomBytesAsBase64(32),
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+		RedirectURI:         redirectURI,
+		ValidUntil:          time.Now().Add(oauth2AuthorizationCodeLifetime).Unix(),
+	}
+	if _, err := x.Insert(code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// IncreaseCounter increases the counter of the grant, invalidating issued refresh tokens.
+func (grant *OAuth2Grant) IncreaseCounter() error {
+	if _, err := x.Id(grant.ID).Incr("counter").Update(new(OAuth2Grant)); err != nil {
+		return err
+	}
+	updatedGrant, err := GetOAuth2GrantByID(grant.ID)
+	if err != nil {
+		return err
+	}
+	grant.Counter = updatedGrant.Counter
+	return nil
+}
+
+// ScopeContains checks if the grant includes the given scope.
+func (grant *OAuth2Grant) ScopeContains(scope string) bool {
+	for _, currentScope := range strings.Fields(grant.Scope) {
+		if scope == currentScope {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateScope updates the scope and the OpenID Connect nonce of the grant.
+func (grant *OAuth2Grant) UpdateScope(scope, nonce string) error {
+	grant.Scope = scope
+	grant.Nonce = nonce
+	_, err := x.Id(grant.ID).Cols("scope", "nonce").Update(grant)
+	return err
+}
+
+// AccessTokenScopes returns the access token scopes granted to the application,
+// the tokens of the grant have no API access if none has been granted.
+func (grant *OAuth2Grant) AccessTokenScopes() []string {
+	scopes := make([]string, 0, 5)
+	for _, scope := range strings.Fields(grant.Scope) {
+		if _, err := NormalizeAccessTokenScopes([]string{scope}); err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return scopes
+	}
+	scopes, _ = NormalizeAccessTokenScopes(scopes)
+	return scopes
+}
+
+// GetOAuth2GrantByID returns the grant with the given ID.
+func GetOAuth2GrantByID(id int64) (*OAuth2Grant, error) {
+	grant := new(OAuth2Grant)
+	has, err := x.Id(id).Get(grant)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuth2GrantNotExist{id}
+	}
+	return grant, nil
+}
+
+// GetOAuth2GrantsByUserID returns all grants of the user including their applications.
+func GetOAuth2GrantsByUserID(userID int64) ([]*OAuth2Grant, error) {
+	grants := make([]*OAuth2Grant, 0, 5)
+	if err := x.Where("user_id=?", userID).Asc("id").Find(&grants); err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		if err := grant.LoadApplication(); err != nil {
+			return nil, err
+		}
+	}
+	return grants, nil
+}
+
+// RevokeOAuth2Grant deletes the grant of the user including its authorization codes,
+// which invalidates all tokens issued for it.
+func RevokeOAuth2Grant(grantID, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if deleted, err := sess.Delete(&OAuth2Grant{ID: grantID, UserID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuth2GrantNotExist{grantID}
+	}
+	if _, err := sess.Where("grant_id=?", grantID).Delete(new(OAuth2AuthorizationCode)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
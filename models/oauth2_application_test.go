@@ -0,0 +1,200 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2Application_GenerateClientSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	assert.True(t, app.ValidateClientSecret("4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA="))
+
+	secret, err := app.GenerateClientSecret()
+	assert.NoError(t, err)
+	assert.True(t, len(secret) > 0)
+	assert.True(t, app.ValidateClientSecret(secret))
+	assert.False(t, app.ValidateClientSecret("4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA="))
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1, ClientSecret: app.ClientSecret})
+}
+
+func TestOAuth2Application_ContainsRedirectURI(t *testing.T) {
+	app := &OAuth2Application{RedirectURIs: []string{"a", "b", "c"}}
+	assert.True(t, app.ContainsRedirectURI("a"))
+	assert.True(t, app.ContainsRedirectURI("b"))
+	assert.False(t, app.ContainsRedirectURI("d"))
+	assert.Equal(t, "a", app.PrimaryRedirectURI())
+}
+
+func TestGetOAuth2ApplicationByClientID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := GetOAuth2ApplicationByClientID("da7da3ba-9a13-4167-856f-3899de0b0138")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, app.ID)
+	assert.Equal(t, []string{"a", "https://example.com/xyzzy"}, app.RedirectURIs)
+
+	_, err = GetOAuth2ApplicationByClientID("invalid")
+	assert.True(t, IsErrOAuth2ApplicationNotExist(err))
+	_, err = GetOAuth2ApplicationByClientID("")
+	assert.True(t, IsErrOAuth2ApplicationNotExist(err))
+}
+
+func TestCreateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := CreateOAuth2Application(CreateOAuth2ApplicationOptions{
+		Name:         "newapp",
+		UserID:       2,
+		RedirectURIs: []string{"https://example.com/callback"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, app.ClientID, 36)
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: app.ID, Name: "newapp", ClientID: app.ClientID})
+
+	apps, err := GetOAuth2ApplicationsByUserID(2)
+	assert.NoError(t, err)
+	if assert.Len(t, apps, 1) {
+		assert.Equal(t, []string{"https://example.com/callback"}, apps[0].RedirectURIs)
+	}
+}
+
+func TestDeleteOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuth2ApplicationNotExist(DeleteOAuth2Application(1, 2)))
+
+	assert.NoError(t, DeleteOAuth2Application(1, 1))
+	AssertNotExistsBean(t, &OAuth2Application{ID: 1})
+	AssertNotExistsBean(t, &OAuth2Grant{ID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{ID: 1})
+}
+
+func TestOAuth2Application_GetGrantByUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	grant, err := app.GetGrantByUserID(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, grant.ID)
+
+	grant, err = app.GetGrantByUserID(2)
+	assert.NoError(t, err)
+	assert.Nil(t, grant)
+
+	grant, err = app.CreateGrant(2, "openid read:repo")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: grant.ID, UserID: 2, ApplicationID: 1, Counter: 1})
+}
+
+func TestOAuth2AuthorizationCode_ValidateCodeChallenge(t *testing.T) {
+	code := &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "S256",
+		CodeChallenge:       "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg",
+	}
+	assert.True(t, code.ValidateCodeChallenge("N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"))
+	assert.False(t, code.ValidateCodeChallenge("wiogjerogorewngoenrgoiuenorg"))
+	assert.False(t, code.ValidateCodeChallenge(""))
+
+	code = &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "plain",
+		CodeChallenge:       "N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt",
+	}
+	assert.True(t, code.ValidateCodeChallenge("N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"))
+	assert.False(t, code.ValidateCodeChallenge("wiogjerogorewngoenrgoiuenorg"))
+
+	code = &OAuth2AuthorizationCode{CodeChallengeMethod: "invalid"}
+	assert.False(t, code.ValidateCodeChallenge(""))
+
+	code = &OAuth2AuthorizationCode{}
+	assert.True(t, code.ValidateCodeChallenge(""))
+}
+
+func TestOAuth2AuthorizationCode_GenerateRedirectURI(t *testing.T) {
+	code := &OAuth2AuthorizationCode{
+		RedirectURI: "https://example.com/callback?foo=bar",
+		Code:        "thecode",
+	}
+	redirect, err := code.GenerateRedirectURI("thestate")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/callback?code=thecode&foo=bar&state=thestate", redirect.String())
+
+	redirect, err = code.GenerateRedirectURI("")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/callback?code=thecode&foo=bar", redirect.String())
+}
+
+func TestGetOAuth2AuthorizationByCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	code, err := GetOAuth2AuthorizationByCode("authcode")
+	assert.NoError(t, err)
+	if assert.NotNil(t, code.Grant) {
+		assert.EqualValues(t, 1, code.Grant.ID)
+	}
+	assert.False(t, code.IsExpired())
+
+	assert.NoError(t, code.Invalidate())
+	// a code can only be invalidated once
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(code.Invalidate()))
+	_, err = GetOAuth2AuthorizationByCode("authcode")
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(err))
+	_, err = GetOAuth2AuthorizationByCode("")
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(err))
+}
+
+func TestOAuth2Grant_GenerateNewAuthorizationCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
+	code, err := grant.GenerateNewAuthorizationCode("https://example.com/xyzzy", "thechallenge", "plain")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code.Code)
+	AssertExistsAndLoadBean(t, &OAuth2AuthorizationCode{ID: code.ID, Code: code.Code, GrantID: 1})
+}
+
+func TestOAuth2Grant_IncreaseCounter(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 1}).(*OAuth2Grant)
+	assert.NoError(t, grant.IncreaseCounter())
+	assert.EqualValues(t, 2, grant.Counter)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 2})
+}
+
+func TestOAuth2Grant_ScopeContains(t *testing.T) {
+	grant := &OAuth2Grant{Scope: "openid profile"}
+	assert.True(t, grant.ScopeContains("openid"))
+	assert.True(t, grant.ScopeContains("profile"))
+	assert.False(t, grant.ScopeContains("email"))
+	assert.False(t, grant.ScopeContains("openid profile"))
+}
+
+func TestOAuth2Grant_AccessTokenScopes(t *testing.T) {
+	grant := &OAuth2Grant{Scope: "openid profile"}
+	assert.Empty(t, grant.AccessTokenScopes())
+	assert.False(t, (&AccessToken{Scopes: grant.AccessTokenScopes()}).HasScope(AccessTokenScopeAreaUser, AccessTokenScopeLevelRead))
+
+	grant.Scope = "openid read:repo write:issue"
+	assert.Equal(t, []string{"read:repo", "write:issue"}, grant.AccessTokenScopes())
+}
+
+func TestGetOAuth2GrantsByUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grants, err := GetOAuth2GrantsByUserID(1)
+	assert.NoError(t, err)
+	if assert.Len(t, grants, 1) && assert.NotNil(t, grants[0].Application) {
+		assert.EqualValues(t, 1, grants[0].Application.ID)
+	}
+
+	grants, err = GetOAuth2GrantsByUserID(34134)
+	assert.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func TestRevokeOAuth2Grant(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuth2GrantNotExist(RevokeOAuth2Grant(1, 2)))
+
+	assert.NoError(t, RevokeOAuth2Grant(1, 1))
+	AssertNotExistsBean(t, &OAuth2Grant{ID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{GrantID: 1})
+}
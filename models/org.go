@@ -10,7 +10,8 @@ import (
 	"os"
 	"strings"
 
-	"github.com/Unknwon/com"
+	"code.gitea.io/gitea/modules/storage"
+
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
 )
@@ -201,19 +202,23 @@ func CountOrganizations() int64 {
 	return count
 }
 
-// Organizations returns number of organizations in given page.
-func Organizations(opts *SearchUserOptions) ([]*User, error) {
-	orgs := make([]*User, 0, opts.PageSize)
-
+// Organizations returns the organizations in given page which can be seen by
+// the searcher, and the total number of them.
+func Organizations(opts *SearchUserOptions) ([]*User, int64, error) {
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = "name ASC"
 	}
 
-	sess := x.
-		Limit(opts.PageSize, (opts.Page-1)*opts.PageSize).
-		Where("type=1")
+	cond := opts.visibilityCond().And(builder.Eq{"type": UserTypeOrganization})
+	count, err := x.Where(cond).Count(new(User))
+	if err != nil {
+		return nil, 0, fmt.Errorf("Count: %v", err)
+	}
 
-	return orgs, sess.
+	orgs := make([]*User, 0, opts.PageSize)
+	return orgs, count, x.
+		Where(cond).
+		Limit(opts.PageSize, (opts.Page-1)*opts.PageSize).
 		OrderBy(opts.OrderBy).
 		Find(&orgs)
 }
@@ -277,11 +282,9 @@ func deleteOrg(e *xorm.Session, u *User) error {
 	}
 
 	if len(u.Avatar) > 0 {
-		avatarPath := u.CustomAvatarPath()
-		if com.IsExist(avatarPath) {
-			if err := os.Remove(avatarPath); err != nil {
-				return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
-			}
+		avatarPath := u.CustomAvatarRelativePath()
+		if err := storage.Avatars.Delete(avatarPath); err != nil && !storage.IsErrObjectNotExist(err) {
+			return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
 		}
 	}
 
@@ -315,15 +318,19 @@ func IsOrganizationOwner(orgID, uid int64) bool {
 	return has
 }
 
-// IsOrganizationMember returns true if given user is member of organization.
-func IsOrganizationMember(orgID, uid int64) bool {
-	has, _ := x.
+func isOrganizationMember(e Engine, orgID, uid int64) bool {
+	has, _ := e.
 		Where("uid=?", uid).
 		And("org_id=?", orgID).
 		Get(new(OrgUser))
 	return has
 }
 
+// IsOrganizationMember returns true if given user is member of organization.
+func IsOrganizationMember(orgID, uid int64) bool {
+	return isOrganizationMember(x, orgID, uid)
+}
+
 // IsPublicMembership returns true if given user public his/her membership.
 func IsPublicMembership(orgID, uid int64) bool {
 	has, _ := x.
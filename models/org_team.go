@@ -422,6 +422,15 @@ func IsTeamMember(orgID, teamID, userID int64) bool {
 	return isTeamMember(x, orgID, teamID, userID)
 }
 
+func isUserInTeams(e Engine, userID int64, teamIDs []int64) (bool, error) {
+	return e.Where("uid=?", userID).In("team_id", teamIDs).Get(new(TeamUser))
+}
+
+// IsUserInTeams returns true if given user is a member of one of the teams.
+func IsUserInTeams(userID int64, teamIDs []int64) (bool, error) {
+	return isUserInTeams(x, userID, teamIDs)
+}
+
 func getTeamUsersByTeamID(e Engine, teamID int64) ([]*TeamUser, error) {
 	teamUsers := make([]*TeamUser, 0, 10)
 	return teamUsers, e.
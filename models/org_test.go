@@ -237,22 +237,33 @@ func TestCountOrganizations(t *testing.T) {
 
 func TestOrganizations(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
-	testSuccess := func(opts *SearchUserOptions, expectedOrgIDs []int64) {
-		orgs, err := Organizations(opts)
+	testSuccess := func(opts *SearchUserOptions, expectedOrgIDs []int64, expectedCount int64) {
+		orgs, count, err := Organizations(opts)
 		assert.NoError(t, err)
+		assert.Equal(t, expectedCount, count)
 		assert.Len(t, orgs, len(expectedOrgIDs))
 		for i, expectedOrgID := range expectedOrgIDs {
 			assert.EqualValues(t, expectedOrgID, orgs[i].ID)
 		}
 	}
 	testSuccess(&SearchUserOptions{OrderBy: "id ASC", Page: 1, PageSize: 2},
-		[]int64{3, 6})
+		[]int64{3, 6}, 3)
 
 	testSuccess(&SearchUserOptions{OrderBy: "id ASC", Page: 2, PageSize: 2},
-		[]int64{7})
+		[]int64{7}, 3)
 
 	testSuccess(&SearchUserOptions{Page: 3, PageSize: 2},
-		[]int64{})
+		[]int64{}, 3)
+
+	// hidden organizations are only listed for their members
+	_, err := x.ID(3).Cols("visibility").Update(&User{Visibility: VisibleTypePrivate})
+	assert.NoError(t, err)
+	testSuccess(&SearchUserOptions{OrderBy: "id ASC", Page: 1, PageSize: 10},
+		[]int64{6, 7}, 2)
+	testSuccess(&SearchUserOptions{OrderBy: "id ASC", Searcher: &User{ID: 4}, Page: 1, PageSize: 10},
+		[]int64{3, 6, 7}, 3)
+	testSuccess(&SearchUserOptions{OrderBy: "id ASC", Private: true, Page: 1, PageSize: 10},
+		[]int64{3, 6, 7}, 3)
 }
 
 func TestDeleteOrganization(t *testing.T) {
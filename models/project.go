@@ -0,0 +1,588 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/go-xorm/xorm"
+)
+
+// Project represents a project board of a repository, its issues and pull
+// requests are arranged in ordered columns.
+type Project struct {
+	ID              int64  `xorm:"pk autoincr"`
+	RepoID          int64  `xorm:"INDEX"`
+	CreatorID       int64  `xorm:"NOT NULL"`
+	Creator         *User  `xorm:"-"`
+	Title           string `xorm:"NOT NULL"`
+	Description     string `xorm:"TEXT"`
+	RenderedContent string `xorm:"-"`
+	IsClosed        bool   `xorm:"INDEX"`
+	NumIssues       int    `xorm:"-"`
+
+	Created        time.Time `xorm:"-"`
+	CreatedUnix    int64     `xorm:"INDEX"`
+	Updated        time.Time `xorm:"-"`
+	UpdatedUnix    int64
+	ClosedDate     time.Time `xorm:"-"`
+	ClosedDateUnix int64
+}
+
+// BeforeInsert is invoked from XORM before inserting an object of this type.
+func (p *Project) BeforeInsert() {
+	p.CreatedUnix = time.Now().Unix()
+	p.UpdatedUnix = p.CreatedUnix
+}
+
+// BeforeUpdate is invoked from XORM before updating this object.
+func (p *Project) BeforeUpdate() {
+	p.UpdatedUnix = time.Now().Unix()
+	p.ClosedDateUnix = p.ClosedDate.Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of
+// this object.
+func (p *Project) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		p.Created = time.Unix(p.CreatedUnix, 0).Local()
+	case "updated_unix":
+		p.Updated = time.Unix(p.UpdatedUnix, 0).Local()
+	case "closed_date_unix":
+		p.ClosedDate = time.Unix(p.ClosedDateUnix, 0).Local()
+	}
+}
+
+// State returns string representation of project status.
+func (p *Project) State() api.StateType {
+	if p.IsClosed {
+		return api.StateClosed
+	}
+	return api.StateOpen
+}
+
+func (p *Project) loadCreator(e Engine) (err error) {
+	if p.Creator != nil {
+		return nil
+	}
+	p.Creator, err = getUserByID(e, p.CreatorID)
+	if IsErrUserNotExist(err) {
+		p.CreatorID = -1
+		p.Creator = NewGhostUser()
+		return nil
+	}
+	return err
+}
+
+// LoadCreator loads the user who created the project.
+func (p *Project) LoadCreator() error {
+	return p.loadCreator(x)
+}
+
+// APIFormat returns this Project in API format.
+func (p *Project) APIFormat() *api.Project {
+	apiProject := &api.Project{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		State:       p.State(),
+		Created:     p.Created,
+		Updated:     p.Updated,
+	}
+	if p.Creator != nil {
+		apiProject.Creator = p.Creator.APIFormat()
+	}
+	if p.IsClosed {
+		apiProject.Closed = &p.ClosedDate
+	}
+	return apiProject
+}
+
+// NewProject creates a new project of a repository.
+func NewProject(p *Project) error {
+	_, err := x.Insert(p)
+	return err
+}
+
+func getProjectByRepoID(e Engine, repoID, id int64) (*Project, error) {
+	p := &Project{
+		ID:     id,
+		RepoID: repoID,
+	}
+	has, err := e.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectNotExist{id, repoID}
+	}
+	return p, nil
+}
+
+// GetProjectByRepoID returns the project in a repository.
+func GetProjectByRepoID(repoID, id int64) (*Project, error) {
+	return getProjectByRepoID(x, repoID, id)
+}
+
+// GetProjects returns a page of the projects of given repository and status,
+// all of them are returned if page is zero.
+func GetProjects(repoID int64, page int, isClosed bool) ([]*Project, error) {
+	projects := make([]*Project, 0, setting.UI.IssuePagingNum)
+	sess := x.Where("repo_id = ? AND is_closed = ?", repoID, isClosed)
+	if page > 0 {
+		sess = sess.Limit(setting.UI.IssuePagingNum, (page-1)*setting.UI.IssuePagingNum)
+	}
+	return projects, sess.Desc("created_unix").Find(&projects)
+}
+
+// ProjectStats returns number of open and closed projects of given repository.
+func ProjectStats(repoID int64) (open int64, closed int64) {
+	open, _ = x.
+		Where("repo_id=? AND is_closed=?", repoID, false).
+		Count(new(Project))
+	closed, _ = x.
+		Where("repo_id=? AND is_closed=?", repoID, true).
+		Count(new(Project))
+	return open, closed
+}
+
+// LoadNumIssues counts the issues and pull requests in the project.
+func (p *Project) LoadNumIssues() error {
+	count, err := x.Where("project_id = ?", p.ID).Count(new(ProjectIssue))
+	if err != nil {
+		return err
+	}
+	p.NumIssues = int(count)
+	return nil
+}
+
+// UpdateProject updates information of given project.
+func UpdateProject(p *Project) error {
+	_, err := x.Id(p.ID).Cols("title", "description").Update(p)
+	return err
+}
+
+// ChangeProjectStatus changes the project open/closed status.
+func ChangeProjectStatus(p *Project, isClosed bool) error {
+	p.IsClosed = isClosed
+	if isClosed {
+		p.ClosedDate = time.Now()
+	}
+	_, err := x.Id(p.ID).Cols("is_closed", "closed_date_unix").Update(p)
+	return err
+}
+
+// DeleteProjectByRepoID deletes a project from a repository along with its
+// boards, the issues are only removed from it.
+func DeleteProjectByRepoID(repoID, id int64) error {
+	p, err := GetProjectByRepoID(repoID, id)
+	if err != nil {
+		if IsErrProjectNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err = sess.Delete(&ProjectIssue{ProjectID: p.ID}); err != nil {
+		return err
+	}
+	if _, err = sess.Delete(&ProjectBoard{ProjectID: p.ID}); err != nil {
+		return err
+	}
+	if _, err = sess.Id(p.ID).Delete(new(Project)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// ProjectBoard is a column of a project. Issues which are in the project but
+// not in any of its boards are shown in a default board with ID 0.
+type ProjectBoard struct {
+	ID        int64  `xorm:"pk autoincr"`
+	ProjectID int64  `xorm:"INDEX NOT NULL"`
+	Title     string `xorm:"NOT NULL"`
+	Sorting   int    `xorm:"NOT NULL DEFAULT 0"`
+	// IsClosed marks the board which collects the closed issues, an issue
+	// is closed when moved to it and reopened when moved out of it
+	IsClosed  bool  `xorm:"NOT NULL DEFAULT false"`
+	CreatorID int64 `xorm:"NOT NULL"`
+
+	Issues []*Issue `xorm:"-"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX"`
+}
+
+// BeforeInsert is invoked from XORM before inserting an object of this type.
+func (b *ProjectBoard) BeforeInsert() {
+	b.CreatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of
+// this object.
+func (b *ProjectBoard) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		b.Created = time.Unix(b.CreatedUnix, 0).Local()
+	}
+}
+
+// IsDefault returns true if the board holds the issues which are in no other
+// board of the project.
+func (b *ProjectBoard) IsDefault() bool {
+	return b.ID == 0
+}
+
+// APIFormat returns this ProjectBoard in API format.
+func (b *ProjectBoard) APIFormat() *api.ProjectBoard {
+	return &api.ProjectBoard{
+		ID:           b.ID,
+		Title:        b.Title,
+		Sorting:      b.Sorting,
+		ClosedColumn: b.IsClosed,
+	}
+}
+
+// unsetClosedBoard makes sure only the given board of the project collects
+// the closed issues
+func unsetClosedBoard(e Engine, b *ProjectBoard) error {
+	_, err := e.Where("project_id = ? AND id != ?", b.ProjectID, b.ID).
+		Cols("is_closed").
+		Update(&ProjectBoard{IsClosed: false})
+	return err
+}
+
+// NewProjectBoard adds a board to a project.
+func NewProjectBoard(b *ProjectBoard) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Insert(b); err != nil {
+		return err
+	}
+	if b.IsClosed {
+		if err := unsetClosedBoard(sess, b); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+func getProjectBoard(e Engine, projectID, id int64) (*ProjectBoard, error) {
+	b := &ProjectBoard{
+		ID:        id,
+		ProjectID: projectID,
+	}
+	has, err := e.Get(b)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectBoardNotExist{id, projectID}
+	}
+	return b, nil
+}
+
+// GetProjectBoard returns the board of a project, the default board is
+// returned for ID 0.
+func GetProjectBoard(projectID, id int64) (*ProjectBoard, error) {
+	if id == 0 {
+		return &ProjectBoard{ProjectID: projectID}, nil
+	}
+	return getProjectBoard(x, projectID, id)
+}
+
+func (p *Project) getBoards(e Engine) ([]*ProjectBoard, error) {
+	boards := make([]*ProjectBoard, 0, 5)
+	return boards, e.Where("project_id = ?", p.ID).Asc("sorting", "id").Find(&boards)
+}
+
+// GetBoards returns the boards of the project in their order.
+func (p *Project) GetBoards() ([]*ProjectBoard, error) {
+	return p.getBoards(x)
+}
+
+// UpdateProjectBoard updates the title, position and the closed flag of
+// the board.
+func UpdateProjectBoard(b *ProjectBoard) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Id(b.ID).Cols("title", "sorting", "is_closed").Update(b); err != nil {
+		return err
+	}
+	if b.IsClosed {
+		if err := unsetClosedBoard(sess, b); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// DeleteProjectBoard deletes a board, its issues are moved to the default
+// board of the project.
+func DeleteProjectBoard(b *ProjectBoard) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("UPDATE `project_issue` SET project_board_id = 0 WHERE project_board_id = ?", b.ID); err != nil {
+		return err
+	}
+	if _, err := sess.Id(b.ID).Delete(new(ProjectBoard)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// LoadIssues loads the issues and pull requests in the board in their order.
+func (b *ProjectBoard) LoadIssues() error {
+	b.Issues = make([]*Issue, 0, 10)
+	if err := x.
+		Join("INNER", "project_issue", "issue.id = project_issue.issue_id").
+		Where("project_issue.project_id = ? AND project_issue.project_board_id = ?", b.ProjectID, b.ID).
+		Asc("project_issue.sorting", "project_issue.id").
+		Find(&b.Issues); err != nil {
+		return err
+	}
+	return IssueList(b.Issues).LoadAttributes()
+}
+
+// ProjectIssue places an issue or a pull request on a board of a project,
+// an issue can be in at most one project.
+type ProjectIssue struct {
+	ID             int64 `xorm:"pk autoincr"`
+	IssueID        int64 `xorm:"UNIQUE NOT NULL"`
+	ProjectID      int64 `xorm:"INDEX NOT NULL"`
+	ProjectBoardID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Sorting        int   `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func getProjectIssue(e Engine, issueID int64) (*ProjectIssue, error) {
+	pi := &ProjectIssue{IssueID: issueID}
+	if has, err := e.Get(pi); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return pi, nil
+}
+
+// LoadProject loads the project the issue is in, if any.
+func (issue *Issue) LoadProject() error {
+	if issue.Project != nil {
+		return nil
+	}
+
+	pi, err := getProjectIssue(x, issue.ID)
+	if err != nil || pi == nil {
+		return err
+	}
+	issue.Project, err = getProjectByRepoID(x, issue.RepoID, pi.ProjectID)
+	return err
+}
+
+// getClosedBoard returns the board collecting the closed issues of the
+// project, if it has one
+func getClosedBoard(e Engine, projectID int64) (*ProjectBoard, error) {
+	b := new(ProjectBoard)
+	if has, err := e.Where("project_id = ? AND is_closed = ?", projectID, true).Get(b); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// placeProjectIssue moves the issue to the given position of the board,
+// the position is clamped to the issues already in it
+func placeProjectIssue(e Engine, pi *ProjectIssue, boardID int64, position int) error {
+	others := make([]*ProjectIssue, 0, 10)
+	if err := e.Where("project_id = ? AND project_board_id = ? AND id != ?", pi.ProjectID, boardID, pi.ID).
+		Asc("sorting", "id").
+		Find(&others); err != nil {
+		return err
+	}
+	if position < 0 || position > len(others) {
+		position = len(others)
+	}
+
+	pi.ProjectBoardID = boardID
+	pi.Sorting = position
+	if _, err := e.Id(pi.ID).Cols("project_board_id", "sorting").Update(pi); err != nil {
+		return err
+	}
+	for i, other := range others {
+		sorting := i
+		if i >= position {
+			sorting++
+		}
+		if other.Sorting == sorting {
+			continue
+		}
+		other.Sorting = sorting
+		if _, err := e.Id(other.ID).Cols("sorting").Update(other); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateProjectIssueStatus moves the issue to the closed board of its
+// project when it is closed, and out of it when it is reopened
+func updateProjectIssueStatus(e Engine, issue *Issue) error {
+	pi, err := getProjectIssue(e, issue.ID)
+	if err != nil || pi == nil {
+		return err
+	}
+
+	closedBoard, err := getClosedBoard(e, pi.ProjectID)
+	if err != nil || closedBoard == nil {
+		return err
+	}
+	if issue.IsClosed && pi.ProjectBoardID != closedBoard.ID {
+		return placeProjectIssue(e, pi, closedBoard.ID, -1)
+	} else if !issue.IsClosed && pi.ProjectBoardID == closedBoard.ID {
+		return placeProjectIssue(e, pi, 0, -1)
+	}
+	return nil
+}
+
+// ChangeProjectAssign puts the issue in the project, or removes it from its
+// project if the project is nil.
+func ChangeProjectAssign(issue *Issue, p *Project) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	pi, err := getProjectIssue(sess, issue.ID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		if pi != nil {
+			if _, err = sess.Id(pi.ID).Delete(new(ProjectIssue)); err != nil {
+				return err
+			}
+		}
+		issue.Project = nil
+		return sess.Commit()
+	} else if p.RepoID != issue.RepoID {
+		return ErrProjectNotExist{p.ID, issue.RepoID}
+	} else if pi != nil && pi.ProjectID == p.ID {
+		issue.Project = p
+		return nil
+	}
+
+	if pi == nil {
+		pi = &ProjectIssue{IssueID: issue.ID, ProjectID: p.ID}
+		if _, err = sess.Insert(pi); err != nil {
+			return err
+		}
+	} else {
+		pi.ProjectID = p.ID
+		if _, err = sess.Id(pi.ID).Cols("project_id").Update(pi); err != nil {
+			return err
+		}
+	}
+
+	var boardID int64
+	if issue.IsClosed {
+		closedBoard, err := getClosedBoard(sess, p.ID)
+		if err != nil {
+			return err
+		} else if closedBoard != nil {
+			boardID = closedBoard.ID
+		}
+	}
+	if err = placeProjectIssue(sess, pi, boardID, -1); err != nil {
+		return err
+	}
+
+	issue.Project = p
+	return sess.Commit()
+}
+
+// MoveProjectIssue moves the issue to the given position of a board of its
+// project. The issue is closed when it is moved to the board collecting the
+// closed issues, and reopened when it is moved out of it.
+func MoveProjectIssue(doer *User, issue *Issue, b *ProjectBoard, position int) error {
+	pi, err := getProjectIssue(x, issue.ID)
+	if err != nil {
+		return err
+	} else if pi == nil || pi.ProjectID != b.ProjectID {
+		return ErrProjectIssueNotExist{issue.ID, b.ProjectID}
+	}
+
+	// closed issues moved between the other boards stay closed
+	changeStatus := b.IsClosed && !issue.IsClosed
+	if !b.IsClosed && issue.IsClosed && pi.ProjectBoardID > 0 {
+		current, err := getProjectBoard(x, pi.ProjectID, pi.ProjectBoardID)
+		if err != nil {
+			return err
+		}
+		changeStatus = current.IsClosed
+	}
+	if changeStatus {
+		if err = issue.loadRepo(x); err != nil {
+			return err
+		}
+		if err = issue.ChangeStatus(doer, issue.Repo, b.IsClosed); err != nil {
+			return err
+		}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	// changing the status may have moved the issue already
+	if pi, err = getProjectIssue(sess, issue.ID); err != nil {
+		return err
+	}
+	if err = placeProjectIssue(sess, pi, b.ID, position); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func deleteProjectsByRepoID(e Engine, repoID int64) error {
+	projectIDs := make([]int64, 0, 10)
+	if err := e.Table("project").Cols("id").Where("repo_id = ?", repoID).Find(&projectIDs); err != nil {
+		return err
+	}
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	if _, err := e.In("project_id", projectIDs).Delete(new(ProjectIssue)); err != nil {
+		return err
+	}
+	if _, err := e.In("project_id", projectIDs).Delete(new(ProjectBoard)); err != nil {
+		return err
+	}
+	_, err := e.In("id", projectIDs).Delete(new(Project))
+	return err
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProjects(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	projects, err := GetProjects(1, 1, false)
+	assert.NoError(t, err)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, 1, projects[0].ID)
+	}
+
+	projects, err = GetProjects(1, 0, true)
+	assert.NoError(t, err)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, 2, projects[0].ID)
+	}
+
+	open, closed := ProjectStats(1)
+	assert.EqualValues(t, 1, open)
+	assert.EqualValues(t, 1, closed)
+
+	_, err = GetProjectByRepoID(2, 1)
+	assert.True(t, IsErrProjectNotExist(err))
+}
+
+func TestDeleteProjectByRepoID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, DeleteProjectByRepoID(1, 1))
+	AssertNotExistsBean(t, &Project{ID: 1})
+	AssertNotExistsBean(t, &ProjectBoard{ProjectID: 1})
+	AssertNotExistsBean(t, &ProjectIssue{ProjectID: 1})
+	AssertExistsAndLoadBean(t, &Issue{ID: 1})
+
+	assert.NoError(t, DeleteProjectByRepoID(1, NonexistentID))
+}
+
+func TestProject_GetBoards(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	project := AssertExistsAndLoadBean(t, &Project{ID: 1}).(*Project)
+
+	board := &ProjectBoard{ProjectID: project.ID, Title: "Review", Sorting: 1, IsClosed: true}
+	assert.NoError(t, NewProjectBoard(board))
+
+	// only one board collects the closed issues
+	assert.True(t, AssertExistsAndLoadBean(t, &ProjectBoard{ID: board.ID}).(*ProjectBoard).IsClosed)
+	assert.False(t, AssertExistsAndLoadBean(t, &ProjectBoard{ID: 3}).(*ProjectBoard).IsClosed)
+
+	boards, err := project.GetBoards()
+	assert.NoError(t, err)
+	if assert.Len(t, boards, 4) {
+		assert.EqualValues(t, 1, boards[0].ID)
+		assert.EqualValues(t, 2, boards[1].ID)
+		assert.EqualValues(t, board.ID, boards[2].ID)
+		assert.EqualValues(t, 3, boards[3].ID)
+	}
+
+	// the issues of a deleted board are moved to the default board
+	oldBoard := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 1}).(*ProjectBoard)
+	assert.NoError(t, DeleteProjectBoard(oldBoard))
+	defaultBoard, err := GetProjectBoard(project.ID, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, defaultBoard.LoadIssues())
+	if assert.Len(t, defaultBoard.Issues, 2) {
+		assert.EqualValues(t, 1, defaultBoard.Issues[0].ID)
+		assert.EqualValues(t, 2, defaultBoard.Issues[1].ID)
+	}
+}
+
+func TestChangeProjectAssign(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	project := AssertExistsAndLoadBean(t, &Project{ID: 1}).(*Project)
+	otherProject := AssertExistsAndLoadBean(t, &Project{ID: 2}).(*Project)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	otherRepoIssue := AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+
+	assert.NoError(t, ChangeProjectAssign(issue, project))
+	pi := AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue.ID, ProjectID: project.ID}).(*ProjectIssue)
+	assert.EqualValues(t, 0, pi.ProjectBoardID)
+
+	// an issue is in at most one project
+	assert.NoError(t, ChangeProjectAssign(issue, otherProject))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue.ID, ProjectID: otherProject.ID})
+	AssertNotExistsBean(t, &ProjectIssue{IssueID: issue.ID, ProjectID: project.ID})
+
+	assert.NoError(t, ChangeProjectAssign(issue, nil))
+	AssertNotExistsBean(t, &ProjectIssue{IssueID: issue.ID})
+
+	err := ChangeProjectAssign(otherRepoIssue, project)
+	assert.True(t, IsErrProjectNotExist(err))
+
+	// closed issues are put on the board collecting them
+	closedIssue := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	assert.NoError(t, ChangeProjectAssign(closedIssue, nil))
+	assert.NoError(t, ChangeProjectAssign(closedIssue, project))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: closedIssue.ID, ProjectBoardID: 3})
+}
+
+func TestMoveProjectIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	todo := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 1}).(*ProjectBoard)
+	inProgress := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 2}).(*ProjectBoard)
+	done := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 3}).(*ProjectBoard)
+	assert.NoError(t, issue1.LoadAttributes())
+
+	// reorder the issues of a board
+	assert.NoError(t, MoveProjectIssue(doer, issue2, todo, 0))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue2.ID, ProjectBoardID: todo.ID, Sorting: 0})
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectBoardID: todo.ID, Sorting: 1})
+
+	assert.NoError(t, MoveProjectIssue(doer, issue1, inProgress, 5))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectBoardID: inProgress.ID, Sorting: 0})
+
+	// moving an issue to the closed board closes it, and out of it reopens it
+	assert.NoError(t, MoveProjectIssue(doer, issue1, done, 0))
+	assert.True(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectBoardID: done.ID, Sorting: 0})
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 5, ProjectBoardID: done.ID, Sorting: 1})
+
+	assert.NoError(t, MoveProjectIssue(doer, issue1, todo, -1))
+	assert.False(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectBoardID: todo.ID, Sorting: 1})
+
+	// without a closed board, closed issues stay closed
+	done.IsClosed = false
+	assert.NoError(t, UpdateProjectBoard(done))
+	assert.NoError(t, issue1.ChangeStatus(doer, issue1.Repo, true))
+	assert.NoError(t, MoveProjectIssue(doer, issue1, inProgress, 0))
+	assert.True(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectBoardID: inProgress.ID})
+	assert.NoError(t, MoveProjectIssue(doer, issue1, todo, 0))
+	assert.True(t, AssertExistsAndLoadBean(t, &Issue{ID: issue1.ID}).(*Issue).IsClosed)
+
+	otherBoard := &ProjectBoard{ID: 4, ProjectID: 2}
+	err := MoveProjectIssue(doer, issue1, otherBoard, 0)
+	assert.True(t, IsErrProjectIssueNotExist(err))
+}
+
+func TestIssue_ChangeStatusInProject(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	closedIssue := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	assert.NoError(t, issue.LoadAttributes())
+	assert.NoError(t, closedIssue.LoadAttributes())
+
+	assert.NoError(t, issue.ChangeStatus(doer, repo, true))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue.ID, ProjectBoardID: 3})
+
+	assert.NoError(t, closedIssue.ChangeStatus(doer, repo, false))
+	pi := AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: closedIssue.ID}).(*ProjectIssue)
+	assert.EqualValues(t, 0, pi.ProjectBoardID)
+}
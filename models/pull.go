@@ -5,6 +5,7 @@
 package models
 
 import (
+	"container/list"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -16,11 +17,12 @@ import (
 
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/gitutil"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/sync"
-	api "code.gitea.io/sdk/gitea"
 
 	"github.com/Unknwon/com"
 	"github.com/go-xorm/xorm"
@@ -48,6 +50,20 @@ const (
 	PullRequestStatusManuallyMerged
 )
 
+// MergeStyle represents the approach to merge commits into base branch.
+type MergeStyle string
+
+const (
+	// MergeStyleMerge create merge commit
+	MergeStyleMerge MergeStyle = "merge"
+	// MergeStyleRebase rebase before merging
+	MergeStyleRebase MergeStyle = "rebase"
+	// MergeStyleRebaseMerge rebase before merging with merge commit (--no-ff)
+	MergeStyleRebaseMerge MergeStyle = "rebase-merge"
+	// MergeStyleSquash squash commits into single commit before merging
+	MergeStyleSquash MergeStyle = "squash"
+)
+
 // PullRequest represents relation between pull request and repositories.
 type PullRequest struct {
 	ID     int64 `xorm:"pk autoincr"`
@@ -67,12 +83,13 @@ type PullRequest struct {
 	BaseBranch   string
 	MergeBase    string `xorm:"VARCHAR(40)"`
 
-	HasMerged      bool      `xorm:"INDEX"`
-	MergedCommitID string    `xorm:"VARCHAR(40)"`
-	MergerID       int64     `xorm:"INDEX"`
-	Merger         *User     `xorm:"-"`
-	Merged         time.Time `xorm:"-"`
-	MergedUnix     int64     `xorm:"INDEX"`
+	HasMerged      bool       `xorm:"INDEX"`
+	MergedCommitID string     `xorm:"VARCHAR(40)"`
+	MergeStyle     MergeStyle `xorm:"VARCHAR(20)"`
+	MergerID       int64      `xorm:"INDEX"`
+	Merger         *User      `xorm:"-"`
+	Merged         time.Time  `xorm:"-"`
+	MergedUnix     int64      `xorm:"INDEX"`
 }
 
 // BeforeUpdate is invoked from XORM before updating an object of this type.
@@ -188,6 +205,7 @@ func (pr *PullRequest) APIFormat() *api.PullRequest {
 		Labels:    apiIssue.Labels,
 		Milestone: apiIssue.Milestone,
 		Assignee:  apiIssue.Assignee,
+		Assignees: apiIssue.Assignees,
 		State:     apiIssue.State,
 		Comments:  apiIssue.Comments,
 		HTMLURL:   pr.Issue.HTMLURL(),
@@ -250,15 +268,135 @@ func (pr *PullRequest) CanAutoMerge() bool {
 	return pr.Status == PullRequestStatusMergeable
 }
 
-// Merge merges pull request to base repository.
+// CheckUserAllowedToMerge checks whether the branch protection of the base branch
+// and the dependencies of the pull request allow doer to merge it, an
+// ErrNotAllowedToMerge is returned otherwise.
+func (pr *PullRequest) CheckUserAllowedToMerge(doer *User) error {
+	if err := pr.GetBaseRepo(); err != nil {
+		return fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	if pr.BaseRepo.IsDependenciesEnabled() {
+		if err := pr.LoadIssue(); err != nil {
+			return fmt.Errorf("LoadIssue: %v", err)
+		}
+		noDeps, err := IssueNoDependenciesLeft(pr.Issue)
+		if err != nil {
+			return fmt.Errorf("IssueNoDependenciesLeft: %v", err)
+		} else if !noDeps {
+			return ErrNotAllowedToMerge{"pull request has open dependencies"}
+		}
+	}
+
+	protectBranch, err := GetProtectedBranchBy(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("GetProtectedBranchBy: %v", err)
+	} else if protectBranch == nil {
+		return nil
+	}
+
+	if !protectBranch.CanUserMerge(doer.ID) {
+		return ErrNotAllowedToMerge{"user is not whitelisted to merge into the protected branch"}
+	}
+
+	enough, err := protectBranch.HasEnoughApprovals(pr)
+	if err != nil {
+		return fmt.Errorf("HasEnoughApprovals: %v", err)
+	} else if !enough {
+		return ErrNotAllowedToMerge{fmt.Sprintf("pull request needs %d approvals", protectBranch.RequiredApprovals)}
+	}
+
+	if protectBranch.RequireSignedCommits && len(pr.signMerge(doer)) == 0 {
+		return ErrNotAllowedToMerge{"the merge commit can not be signed, but the protected branch requires signed commits"}
+	}
+
+	if !protectBranch.EnableStatusCheck && !protectBranch.RequireSignedCommits {
+		return nil
+	}
+	headCommitID, err := pr.GetHeadCommitID()
+	if err != nil {
+		return fmt.Errorf("GetHeadCommitID: %v", err)
+	}
+
+	if protectBranch.EnableStatusCheck {
+		missing, err := protectBranch.GetMissingStatusContexts(pr.BaseRepo, headCommitID)
+		if err != nil {
+			return fmt.Errorf("GetMissingStatusContexts: %v", err)
+		} else if len(missing) > 0 {
+			return ErrNotAllowedToMerge{fmt.Sprintf("required status checks are not successful: %s", strings.Join(missing, ", "))}
+		}
+	}
+
+	if protectBranch.RequireSignedCommits {
+		commits, err := gitutil.GetCommitsInRange(pr.BaseRepo.RepoPath(), nil, git.BranchPrefix+pr.BaseBranch, headCommitID)
+		if err != nil {
+			return fmt.Errorf("GetCommitsInRange: %v", err)
+		}
+		if err = VerifyCommits(commits); IsErrUnverifiedCommit(err) {
+			return ErrNotAllowedToMerge{fmt.Sprintf("commit %s is not signed by a known key", err.(ErrUnverifiedCommit).ID)}
+		} else if err != nil {
+			return fmt.Errorf("VerifyCommits: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetDefaultMergeMessage returns default message used when merging pull request
+func (pr *PullRequest) GetDefaultMergeMessage() string {
+	if err := pr.GetHeadRepo(); err != nil {
+		log.Error(4, "GetHeadRepo[%d]: %v", pr.HeadRepoID, err)
+		return ""
+	} else if pr.HeadRepo == nil {
+		return ""
+	}
+	return fmt.Sprintf("Merge branch '%s' of %s/%s into %s", pr.HeadBranch, pr.HeadUserName, pr.HeadRepo.Name, pr.BaseBranch)
+}
+
+// GetDefaultSquashMessage returns default message used when squash and merging pull request
+func (pr *PullRequest) GetDefaultSquashMessage() string {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error(4, "LoadIssue[%d]: %v", pr.IssueID, err)
+		return ""
+	}
+	return fmt.Sprintf("%s (#%d)", pr.Issue.Title, pr.Issue.Index)
+}
+
+// Merge merges pull request to base repository with the given merge style.
+// An empty message falls back to the default message of the style.
 // FIXME: add repoWorkingPull make sure two merges does not happen at same time.
-func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error) {
+func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle MergeStyle, message string) (err error) {
 	if err = pr.GetHeadRepo(); err != nil {
 		return fmt.Errorf("GetHeadRepo: %v", err)
 	} else if err = pr.GetBaseRepo(); err != nil {
 		return fmt.Errorf("GetBaseRepo: %v", err)
 	}
 
+	prUnit, err := pr.BaseRepo.GetUnit(UnitTypePullRequests)
+	if err != nil {
+		return err
+	}
+	if !prUnit.PullRequestsConfig().IsMergeStyleAllowed(mergeStyle) {
+		return ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
+	protectBranch, err := GetProtectedBranchBy(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("GetProtectedBranchBy: %v", err)
+	} else if protectBranch != nil && !protectBranch.IsMergeStyleAllowed(mergeStyle) {
+		return ErrNotAllowedToMerge{"rebased commits are not signed, but the protected branch requires signed commits"}
+	}
+
+	if err = pr.CheckUserAllowedToMerge(doer); err != nil {
+		return err
+	}
+
+	if len(message) == 0 {
+		if mergeStyle == MergeStyleSquash {
+			message = pr.GetDefaultSquashMessage()
+		} else {
+			message = pr.GetDefaultMergeMessage()
+		}
+	}
+
 	defer func() {
 		go HookQueue.Add(pr.BaseRepo.ID)
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false)
@@ -307,18 +445,70 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error
 		return fmt.Errorf("git fetch [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
 	}
 
-	if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
-		fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
-		"git", "merge", "--no-ff", "--no-commit", "head_repo/"+pr.HeadBranch); err != nil {
-		return fmt.Errorf("git merge --no-ff --no-commit [%s]: %v - %s", tmpBasePath, err, stderr)
+	headBranch := "head_repo/" + pr.HeadBranch
+	switch mergeStyle {
+	case MergeStyleRebase, MergeStyleRebaseMerge:
+		// Rebase the head branch on top of the base branch in a local staging branch.
+		stagingBranch := "head_repo_" + pr.HeadBranch
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git checkout -b): %s", tmpBasePath),
+			"git", "checkout", "-b", stagingBranch, headBranch); err != nil {
+			return fmt.Errorf("git checkout -b [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git rebase): %s", tmpBasePath),
+			"git", "rebase", "-q", pr.BaseBranch); err != nil {
+			return fmt.Errorf("git rebase [%s -> %s]: %v - %s", headRepoPath, tmpBasePath, err, stderr)
+		}
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git checkout): %s", tmpBasePath),
+			"git", "checkout", pr.BaseBranch); err != nil {
+			return fmt.Errorf("git checkout: %s", stderr)
+		}
+		headBranch = stagingBranch
 	}
 
-	sig := doer.NewGitSig()
-	if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
-		fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
-		"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
-		"-m", fmt.Sprintf("Merge branch '%s' of %s/%s into %s", pr.HeadBranch, pr.HeadUserName, pr.HeadRepo.Name, pr.BaseBranch)); err != nil {
-		return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+	switch mergeStyle {
+	case MergeStyleMerge, MergeStyleRebaseMerge:
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
+			"git", "merge", "--no-ff", "--no-commit", headBranch); err != nil {
+			return fmt.Errorf("git merge --no-ff --no-commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+		sig := doer.NewGitSig()
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
+			"git", commitArgs(sig, message, pr.signMerge(doer))...); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	case MergeStyleRebase:
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --ff-only): %s", tmpBasePath),
+			"git", "merge", "--ff-only", "-q", headBranch); err != nil {
+			return fmt.Errorf("git merge --ff-only [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	case MergeStyleSquash:
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --squash): %s", tmpBasePath),
+			"git", "merge", "-q", "--squash", headBranch); err != nil {
+			return fmt.Errorf("git merge --squash [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+		// The squashed commit is authored by the poster of the pull request.
+		if err = pr.LoadIssue(); err != nil {
+			return fmt.Errorf("LoadIssue: %v", err)
+		} else if err = pr.Issue.loadPoster(x); err != nil {
+			return fmt.Errorf("loadPoster: %v", err)
+		}
+		sig := pr.Issue.Poster.NewGitSig()
+		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git squash): %s", tmpBasePath),
+			"git", commitArgs(sig, message, pr.signMerge(doer))...); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+	default:
+		return ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
 	}
 
 	// Push back to upstream.
@@ -336,47 +526,43 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository) (err error
 	pr.Merged = time.Now()
 	pr.Merger = doer
 	pr.MergerID = doer.ID
+	pr.MergeStyle = mergeStyle
 
 	if err = pr.setMerged(); err != nil {
 		log.Error(4, "setMerged [%d]: %v", pr.ID, err)
 	}
 
-	if err = MergePullRequestAction(doer, pr.Issue.Repo, pr.Issue); err != nil {
-		log.Error(4, "MergePullRequestAction [%d]: %v", pr.ID, err)
-	}
-
 	// Reload pull request information.
 	if err = pr.LoadAttributes(); err != nil {
 		log.Error(4, "LoadAttributes: %v", err)
 		return nil
 	}
-	if err = PrepareWebhooks(pr.Issue.Repo, HookEventPullRequest, &api.PullRequestPayload{
-		Action:      api.HookIssueClosed,
-		Index:       pr.Index,
-		PullRequest: pr.APIFormat(),
-		Repository:  pr.Issue.Repo.APIFormat(AccessModeNone),
-		Sender:      doer.APIFormat(),
-	}); err != nil {
-		log.Error(4, "PrepareWebhooks: %v", err)
-		return nil
-	}
 
-	l, err := headGitRepo.CommitsBetweenIDs(pr.MergedCommitID, pr.MergeBase)
-	if err != nil {
-		log.Error(4, "CommitsBetweenIDs: %v", err)
-		return nil
-	}
+	var l *list.List
+	if mergeStyle == MergeStyleMerge {
+		l, err = headGitRepo.CommitsBetweenIDs(pr.MergedCommitID, pr.MergeBase)
+		if err != nil {
+			log.Error(4, "CommitsBetweenIDs: %v", err)
+			return nil
+		}
 
-	// TODO: when squash commits, no need to append merge commit.
-	// It is possible that head branch is not fully sync with base branch for merge commits,
-	// so we need to get latest head commit and append merge commit manually
-	// to avoid strange diff commits produced.
-	mergeCommit, err := baseGitRepo.GetBranchCommit(pr.BaseBranch)
-	if err != nil {
-		log.Error(4, "GetBranchCommit: %v", err)
-		return nil
+		// It is possible that head branch is not fully sync with base branch for merge commits,
+		// so we need to get latest head commit and append merge commit manually
+		// to avoid strange diff commits produced.
+		mergeCommit, err := baseGitRepo.GetBranchCommit(pr.BaseBranch)
+		if err != nil {
+			log.Error(4, "GetBranchCommit: %v", err)
+			return nil
+		}
+		l.PushFront(mergeCommit)
+	} else {
+		// Rebased and squashed commits only exist in the base repository.
+		l, err = baseGitRepo.CommitsBetweenIDs(pr.MergedCommitID, pr.MergeBase)
+		if err != nil {
+			log.Error(4, "CommitsBetweenIDs: %v", err)
+			return nil
+		}
 	}
-	l.PushFront(mergeCommit)
 
 	p := &api.PushPayload{
 		Ref:        git.BranchPrefix + pr.BaseBranch,
@@ -600,7 +786,7 @@ func (pr *PullRequest) testPatch() (err error) {
 }
 
 // NewPullRequest creates new pull request with labels for repository.
-func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []string, pr *PullRequest, patch []byte) (err error) {
+func NewPullRequest(repo *Repository, pull *Issue, labelIDs, assigneeIDs []int64, uuids []string, pr *PullRequest, patch []byte) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -611,6 +797,7 @@ func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []str
 		Repo:        repo,
 		Issue:       pull,
 		LabelIDs:    labelIDs,
+		AssigneeIDs: assigneeIDs,
 		Attachments: uuids,
 		IsPull:      true,
 	}); err != nil {
@@ -640,33 +827,8 @@ func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []str
 		return fmt.Errorf("Commit: %v", err)
 	}
 
-	if err = NotifyWatchers(&Action{
-		ActUserID: pull.Poster.ID,
-		ActUser:   pull.Poster,
-		OpType:    ActionCreatePullRequest,
-		Content:   fmt.Sprintf("%d|%s", pull.Index, pull.Title),
-		RepoID:    repo.ID,
-		Repo:      repo,
-		IsPrivate: repo.IsPrivate,
-	}); err != nil {
-		log.Error(4, "NotifyWatchers: %v", err)
-	} else if err = pull.MailParticipants(); err != nil {
-		log.Error(4, "MailParticipants: %v", err)
-	}
-
 	pr.Issue = pull
 	pull.PullRequest = pr
-	if err = PrepareWebhooks(repo, HookEventPullRequest, &api.PullRequestPayload{
-		Action:      api.HookIssueOpened,
-		Index:       pull.Index,
-		PullRequest: pr.APIFormat(),
-		Repository:  repo.APIFormat(AccessModeNone),
-		Sender:      pull.Poster.APIFormat(),
-	}); err != nil {
-		log.Error(4, "PrepareWebhooks: %v", err)
-	}
-	go HookQueue.Add(repo.ID)
-
 	return nil
 }
 
@@ -922,6 +1084,23 @@ func (pr *PullRequest) PushToBaseRepo() (err error) {
 	return nil
 }
 
+// GetGitRefName returns the reference of the pull request head in the base repository
+func (pr *PullRequest) GetGitRefName() string {
+	return fmt.Sprintf("refs/pull/%d/head", pr.Index)
+}
+
+// GetHeadCommitID returns the commit the pull request head points to in the base repository
+func (pr *PullRequest) GetHeadCommitID() (string, error) {
+	if err := pr.GetBaseRepo(); err != nil {
+		return "", fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	stdout, err := git.NewCommand("rev-parse", pr.GetGitRefName()).RunInDir(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse %s: %v", pr.GetGitRefName(), err)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
 // AddToTaskQueue adds itself to pull request test task queue.
 func (pr *PullRequest) AddToTaskQueue() {
 	go pullRequestQueue.AddFunc(pr.ID, func() {
@@ -977,6 +1156,8 @@ func addHeadRepoTasks(prs []*PullRequest) {
 		} else if err := pr.PushToBaseRepo(); err != nil {
 			log.Error(4, "PushToBaseRepo: %v", err)
 			continue
+		} else if err := pr.InvalidateCodeComments(); err != nil {
+			log.Error(4, "InvalidateCodeComments: %v", err)
 		}
 
 		pr.AddToTaskQueue()
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/modules/setting"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -230,3 +232,66 @@ func TestChangeUsernameInPullRequests(t *testing.T) {
 	}
 	CheckConsistencyFor(t, &PullRequest{})
 }
+
+func TestPullRequest_GetDefaultMergeMessage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.Equal(t, "Merge branch 'branch2' of user1/repo1 into master", pr.GetDefaultMergeMessage())
+	assert.Equal(t, "issue3 (#3)", pr.GetDefaultSquashMessage())
+}
+
+func TestPullRequestsConfig_IsMergeStyleAllowed(t *testing.T) {
+	cfg := &PullRequestsConfig{AllowMerge: true, AllowSquash: true}
+	assert.True(t, cfg.IsMergeStyleAllowed(MergeStyleMerge))
+	assert.False(t, cfg.IsMergeStyleAllowed(MergeStyleRebase))
+	assert.False(t, cfg.IsMergeStyleAllowed(MergeStyleRebaseMerge))
+	assert.True(t, cfg.IsMergeStyleAllowed(MergeStyleSquash))
+	assert.False(t, cfg.IsMergeStyleAllowed("invalid"))
+	assert.Equal(t, []MergeStyle{MergeStyleMerge, MergeStyleSquash}, cfg.AllowedMergeStyles())
+}
+
+func TestPullRequest_Merge_InvalidStyle(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	_, err := x.Id(3).Cols("config").Update(&RepoUnit{Config: &PullRequestsConfig{AllowMerge: true}})
+	assert.NoError(t, err)
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	err = pr.Merge(doer, nil, MergeStyleSquash, "")
+	assert.True(t, IsErrInvalidMergeStyle(err))
+	err = pr.Merge(doer, nil, "invalid", "")
+	assert.True(t, IsErrInvalidMergeStyle(err))
+}
+
+func TestPullRequest_Merge_RebaseIntoSignedBranch(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err := x.Insert(&ProtectedBranch{RepoID: pr.BaseRepoID, BranchName: pr.BaseBranch, RequireSignedCommits: true})
+	assert.NoError(t, err)
+
+	err = pr.Merge(doer, nil, MergeStyleRebase, "")
+	assert.True(t, IsErrNotAllowedToMerge(err))
+	err = pr.Merge(doer, nil, MergeStyleRebaseMerge, "")
+	assert.True(t, IsErrNotAllowedToMerge(err))
+}
+
+func TestPullRequest_Merge_UnsignedIntoSignedBranch(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err := x.Insert(&ProtectedBranch{RepoID: pr.BaseRepoID, BranchName: pr.BaseBranch, RequireSignedCommits: true})
+	assert.NoError(t, err)
+
+	oldSigning := setting.Repository.Signing
+	defer func() { setting.Repository.Signing = oldSigning }()
+	setting.Repository.Signing.SigningKey = "none"
+	setting.Repository.Signing.Merges = []string{SigningRuleAlways}
+
+	err = pr.CheckUserAllowedToMerge(doer)
+	assert.True(t, IsErrNotAllowedToMerge(err))
+	err = pr.Merge(doer, nil, MergeStyleMerge, "")
+	assert.True(t, IsErrNotAllowedToMerge(err))
+	err = pr.Merge(doer, nil, MergeStyleSquash, "")
+	assert.True(t, IsErrNotAllowedToMerge(err))
+}
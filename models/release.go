@@ -11,9 +11,10 @@ import (
 	"time"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
 )
@@ -100,6 +101,7 @@ func (r *Release) APIFormat() *api.Release {
 	return &api.Release{
 		ID:           r.ID,
 		TagName:      r.TagName,
+		Title:        r.Title,
 		Target:       r.Target,
 		Note:         r.Note,
 		URL:          r.APIURL(),
@@ -199,9 +201,27 @@ func CreateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	return addReleaseAttachments(rel.ID, attachmentUUIDs)
+}
+
+// prepareReleaseWebhooks notifies the webhooks of the release's repository
+// about the given release action, errors are only logged.
+func prepareReleaseWebhooks(rel *Release, doer *User, action api.HookReleaseAction) {
+	if err := rel.LoadAttributes(); err != nil {
+		log.Error(4, "LoadAttributes: %v", err)
+		return
+	}
 
-	return err
+	if err := PrepareWebhooks(rel.Repo, HookEventRelease, &api.ReleasePayload{
+		Action:     action,
+		Release:    rel.APIFormat(),
+		Repository: rel.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [release_id: %d]: %v", rel.ID, err)
+	} else {
+		go HookQueue.Add(rel.RepoID)
+	}
 }
 
 // GetRelease returns release by given ID.
@@ -357,7 +377,7 @@ func SortReleases(rels []*Release) {
 }
 
 // UpdateRelease updates information of a release.
-func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
+func UpdateRelease(doer *User, gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
 	if err = createTag(gitRepo, rel); err != nil {
 		return err
 	}
@@ -366,9 +386,12 @@ func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	if err = addReleaseAttachments(rel.ID, attachmentUUIDs); err != nil {
+		return err
+	}
 
-	return err
+	prepareReleaseWebhooks(rel, doer, api.HookReleaseUpdated)
+	return nil
 }
 
 // DeleteReleaseByID deletes a release and corresponding Git tag by given ID.
@@ -403,5 +426,7 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		return fmt.Errorf("Delete: %v", err)
 	}
 
+	rel.Repo = repo
+	prepareReleaseWebhooks(rel, u, api.HookReleaseDeleted)
 	return nil
 }
@@ -25,8 +25,9 @@ import (
 	"code.gitea.io/gitea/modules/options"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/sync"
-	api "code.gitea.io/sdk/gitea"
 
 	"github.com/Unknwon/cae/zip"
 	"github.com/Unknwon/com"
@@ -431,6 +432,11 @@ func (repo *Repository) MustGetUnit(tp UnitType) *RepoUnit {
 			Type:   tp,
 			Config: new(ExternalTrackerConfig),
 		}
+	} else if tp == UnitTypePullRequests {
+		return &RepoUnit{
+			Type:   tp,
+			Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true},
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -621,8 +627,11 @@ func (repo *Repository) HasAccess(u *User) bool {
 
 // UpdateDefaultBranch updates the default branch
 func (repo *Repository) UpdateDefaultBranch() error {
-	_, err := x.ID(repo.ID).Cols("default_branch").Update(repo)
-	return err
+	if _, err := x.ID(repo.ID).Cols("default_branch").Update(repo); err != nil {
+		return err
+	}
+	UpdateRepoIndexer(repo)
+	return nil
 }
 
 // IsOwnedBy returns true when user owns this repository
@@ -666,11 +675,20 @@ func (repo *Repository) CanEnableEditor() bool {
 	return !repo.IsMirror
 }
 
-// NextIssueIndex returns the next issue index
+// nextIssueIndex returns the next issue index, which follows the highest one
+// in use since migrated repositories may have gaps in their numbering.
 // FIXME: should have a mutex to prevent producing same index for two issues that are created
 // closely enough.
-func (repo *Repository) NextIssueIndex() int64 {
-	return int64(repo.NumIssues+repo.NumPulls) + 1
+func (repo *Repository) nextIssueIndex(e Engine) (int64, error) {
+	issue := new(Issue)
+	if _, err := e.
+		Where("repo_id = ?", repo.ID).
+		Desc("index").
+		Cols("index").
+		Get(issue); err != nil {
+		return 0, err
+	}
+	return issue.Index + 1, nil
 }
 
 var (
@@ -832,8 +850,8 @@ func wikiRemoteURL(remote string) string {
 }
 
 // MigrateRepository migrates a existing repository from other project hosting.
-func MigrateRepository(u *User, opts MigrateRepoOptions) (*Repository, error) {
-	repo, err := CreateRepository(u, CreateRepoOptions{
+func MigrateRepository(doer, u *User, opts MigrateRepoOptions) (*Repository, error) {
+	repo, err := CreateRepository(doer, u, CreateRepoOptions{
 		Name:        opts.Name,
 		Description: opts.Description,
 		IsPrivate:   opts.IsPrivate,
@@ -1013,7 +1031,7 @@ func CleanUpMigrateInfo(repo *Repository) (*Repository, error) {
 }
 
 // initRepoCommit temporarily changes with work directory.
-func initRepoCommit(tmpPath string, sig *git.Signature) (err error) {
+func initRepoCommit(tmpPath string, u *User) (err error) {
 	var stderr string
 	if _, stderr, err = process.GetManager().ExecDir(-1,
 		tmpPath, fmt.Sprintf("initRepoCommit (git add): %s", tmpPath),
@@ -1023,8 +1041,7 @@ func initRepoCommit(tmpPath string, sig *git.Signature) (err error) {
 
 	if _, stderr, err = process.GetManager().ExecDir(-1,
 		tmpPath, fmt.Sprintf("initRepoCommit (git commit): %s", tmpPath),
-		"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
-		"-m", "Initial commit"); err != nil {
+		"git", commitArgs(u.NewGitSig(), "Initial commit", signInitialCommit(u))...); err != nil {
 		return fmt.Errorf("git commit: %s", stderr)
 	}
 
@@ -1166,7 +1183,7 @@ func initRepository(e Engine, repoPath string, u *User, repo *Repository, opts C
 		}
 
 		// Apply changes and commit.
-		if err = initRepoCommit(tmpDir, u.NewGitSig()); err != nil {
+		if err = initRepoCommit(tmpDir, u); err != nil {
 			return fmt.Errorf("initRepoCommit: %v", err)
 		}
 	}
@@ -1226,7 +1243,17 @@ func createRepository(e *xorm.Session, u *User, repo *Repository) (err error) {
 				RepoID: repo.ID,
 				Type:   tp,
 				Index:  i,
-				Config: &IssuesConfig{EnableTimetracker: setting.Service.DefaultEnableTimetracking},
+				Config: &IssuesConfig{
+					EnableTimetracker:  setting.Service.DefaultEnableTimetracking,
+					EnableDependencies: setting.Service.DefaultEnableDependencies,
+				},
+			})
+		} else if tp == UnitTypePullRequests {
+			units = append(units, RepoUnit{
+				RepoID: repo.ID,
+				Type:   tp,
+				Index:  i,
+				Config: &PullRequestsConfig{AllowMerge: true, AllowRebase: true, AllowRebaseMerge: true, AllowSquash: true},
 			})
 		} else {
 			units = append(units, RepoUnit{
@@ -1274,7 +1301,7 @@ func createRepository(e *xorm.Session, u *User, repo *Repository) (err error) {
 }
 
 // CreateRepository creates a repository for given user or organization.
-func CreateRepository(u *User, opts CreateRepoOptions) (_ *Repository, err error) {
+func CreateRepository(doer, u *User, opts CreateRepoOptions) (_ *Repository, err error) {
 	if !u.CanCreateRepo() {
 		return nil, ErrReachLimitOfRepo{u.MaxRepoCreation}
 	}
@@ -1318,7 +1345,29 @@ func CreateRepository(u *User, opts CreateRepoOptions) (_ *Repository, err error
 		}
 	}
 
-	return repo, sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return nil, err
+	}
+
+	if u.IsOrganization() {
+		prepareRepositoryWebhooks(doer, repo, api.HookRepoCreated)
+	}
+	return repo, nil
+}
+
+// prepareRepositoryWebhooks notifies the organization webhooks that a repository
+// has been created or deleted, errors are only logged.
+func prepareRepositoryWebhooks(doer *User, repo *Repository, action api.HookRepoAction) {
+	if err := PrepareWebhooks(repo, HookEventRepository, &api.RepositoryPayload{
+		Action:       action,
+		Repository:   repo.APIFormat(AccessModeOwner),
+		Organization: repo.MustOwner().APIFormat(),
+		Sender:       doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	} else {
+		go HookQueue.Add(repo.ID)
+	}
 }
 
 func countRepositories(userID int64, private bool) int64 {
@@ -1451,8 +1500,13 @@ func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error
 
 	if err = watchRepo(sess, newOwner.ID, repo.ID, true); err != nil {
 		return fmt.Errorf("watchRepo: %v", err)
-	} else if err = transferRepoAction(sess, doer, owner, repo); err != nil {
-		return fmt.Errorf("transferRepoAction: %v", err)
+	}
+
+	// Remove watch for organization.
+	if owner.IsOrganization() {
+		if err = watchRepo(sess, owner.ID, repo.ID, false); err != nil {
+			return fmt.Errorf("watchRepo [false]: %v", err)
+		}
 	}
 
 	// Rename remote repository to new path and delete local copy.
@@ -1630,7 +1684,7 @@ func UpdateRepositoryUnits(repo *Repository, units []RepoUnit) (err error) {
 }
 
 // DeleteRepository deletes a repository for a user or organization.
-func DeleteRepository(uid, repoID int64) error {
+func DeleteRepository(doer *User, uid, repoID int64) error {
 	// In case is a organization.
 	org, err := GetUserByID(uid)
 	if err != nil {
@@ -1678,16 +1732,22 @@ func DeleteRepository(uid, repoID int64) error {
 		&Watch{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Mirror{RepoID: repoID},
+		&PushMirror{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Release{RepoID: repoID},
 		&Collaboration{RepoID: repoID},
 		&PullRequest{BaseRepoID: repoID},
 		&RepoUnit{RepoID: repoID},
 		&RepoRedirect{RedirectRepoID: repoID},
+		&RepoIndexerStatus{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deleteProjectsByRepoID(sess, repoID); err != nil {
+		return fmt.Errorf("deleteProjectsByRepoID: %v", err)
+	}
+
 	// Delete comments and attachments.
 	issueIDs := make([]int64, 0, 25)
 	attachmentPaths := make([]string, 0, len(issueIDs))
@@ -1706,6 +1766,18 @@ func DeleteRepository(uid, repoID int64) error {
 		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueUser{}); err != nil {
 			return err
 		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueAssignees{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueDependency{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("dependency_id", issueIDs).Delete(&IssueDependency{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&Reaction{}); err != nil {
+			return err
+		}
 
 		attachments := make([]*Attachment, 0, 5)
 		if err = sess.
@@ -1714,7 +1786,7 @@ func DeleteRepository(uid, repoID int64) error {
 			return err
 		}
 		for j := range attachments {
-			attachmentPaths = append(attachmentPaths, attachments[j].LocalPath())
+			attachmentPaths = append(attachmentPaths, attachments[j].RelativePath())
 		}
 
 		if _, err = sess.In("issue_id", issueIDs).Delete(&Attachment{}); err != nil {
@@ -1748,7 +1820,7 @@ func DeleteRepository(uid, repoID int64) error {
 
 	// Remove attachment files.
 	for i := range attachmentPaths {
-		removeAllWithNotice(sess, "Delete attachment", attachmentPaths[i])
+		removeStorageWithNotice(sess, storage.Attachments, "Delete attachment", attachmentPaths[i])
 	}
 
 	// Remove LFS objects
@@ -1767,11 +1839,7 @@ func DeleteRepository(uid, repoID int64) error {
 			continue
 		}
 
-		oidPath := filepath.Join(v.Oid[0:2], v.Oid[2:4], v.Oid[4:len(v.Oid)])
-		err = os.Remove(filepath.Join(setting.LFS.ContentPath, oidPath))
-		if err != nil {
-			return err
-		}
+		removeStorageWithNotice(sess, storage.LFS, "Delete orphaned LFS file", v.RelativePath())
 	}
 
 	if _, err := sess.Delete(&LFSMetaObject{RepositoryID: repoID}); err != nil {
@@ -1788,6 +1856,12 @@ func DeleteRepository(uid, repoID int64) error {
 		return fmt.Errorf("Commit: %v", err)
 	}
 
+	DeleteRepoFromIndexer(repo)
+
+	if org.IsOrganization() {
+		repo.Owner = org
+		prepareRepositoryWebhooks(doer, repo, api.HookRepoDeleted)
+	}
 	return nil
 }
 
@@ -1808,6 +1882,30 @@ func GetRepositoryByRef(ref string) (*Repository, error) {
 	return GetRepositoryByName(user.ID, repoName)
 }
 
+// GetReadableRepoIDsByFullNames returns the IDs of the repositories given by
+// their full names, which the user must be allowed to read.
+func GetReadableRepoIDsByFullNames(u *User, fullNames []string) ([]int64, error) {
+	ids := make([]int64, 0, len(fullNames))
+	for _, fullName := range fullNames {
+		repo, err := GetRepositoryByRef(fullName)
+		if err != nil {
+			if IsErrUserNotExist(err) || IsErrRepoNotExist(err) || err == ErrInvalidReference {
+				return nil, ErrRepoNotExist{0, 0, fullName}
+			}
+			return nil, err
+		}
+
+		has, err := HasAccess(u.ID, repo, AccessModeRead)
+		if err != nil {
+			return nil, err
+		} else if !has {
+			return nil, ErrRepoNotExist{repo.ID, repo.OwnerID, fullName}
+		}
+		ids = append(ids, repo.ID)
+	}
+	return ids, nil
+}
+
 // GetRepositoryByName returns the repository by given name under user if exists.
 func GetRepositoryByName(ownerID int64, name string) (*Repository, error) {
 	repo := &Repository{
@@ -1981,7 +2079,7 @@ func gatherMissingRepoRecords() ([]*Repository, error) {
 }
 
 // DeleteMissingRepositories deletes all repository records that lost Git files.
-func DeleteMissingRepositories() error {
+func DeleteMissingRepositories(doer *User) error {
 	repos, err := gatherMissingRepoRecords()
 	if err != nil {
 		return fmt.Errorf("gatherMissingRepoRecords: %v", err)
@@ -1993,7 +2091,7 @@ func DeleteMissingRepositories() error {
 
 	for _, repo := range repos {
 		log.Trace("Deleting %d/%d...", repo.OwnerID, repo.ID)
-		if err := DeleteRepository(repo.OwnerID, repo.ID); err != nil {
+		if err := DeleteRepository(doer, repo.OwnerID, repo.ID); err != nil {
 			if err2 := CreateRepositoryNotice(fmt.Sprintf("DeleteRepository [%d]: %v", repo.ID, err)); err2 != nil {
 				return fmt.Errorf("CreateRepositoryNotice: %v", err)
 			}
@@ -2045,10 +2143,11 @@ func SyncRepositoryHooks() error {
 var taskStatusTable = sync.NewStatusTable()
 
 const (
-	mirrorUpdate   = "mirror_update"
-	gitFsck        = "git_fsck"
-	checkRepos     = "check_repos"
-	archiveCleanup = "archive_cleanup"
+	mirrorUpdate     = "mirror_update"
+	pushMirrorUpdate = "push_mirror_update"
+	gitFsck          = "git_fsck"
+	checkRepos       = "check_repos"
+	archiveCleanup   = "archive_cleanup"
 )
 
 // GitFsck calls 'git fsck' to check repository health.
@@ -2233,7 +2332,7 @@ func HasForkedRepo(ownerID, repoID int64) (*Repository, bool) {
 }
 
 // ForkRepository forks a repository
-func ForkRepository(u *User, oldRepo *Repository, name, desc string) (_ *Repository, err error) {
+func ForkRepository(doer, u *User, oldRepo *Repository, name, desc string) (_ *Repository, err error) {
 	forkedRepo, err := oldRepo.GetUserFork(u.ID)
 	if err != nil {
 		return nil, err
@@ -2321,7 +2420,20 @@ func ForkRepository(u *User, oldRepo *Repository, name, desc string) (_ *Reposit
 		}
 	}
 
-	return repo, sess2.Commit()
+	if err = sess2.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = PrepareWebhooks(oldRepo, HookEventFork, &api.ForkPayload{
+		Forkee: repo.APIFormat(AccessModeNone),
+		Repo:   oldRepo.APIFormat(AccessModeNone),
+		Sender: doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [repo_id: %d]: %v", oldRepo.ID, err)
+	} else {
+		go HookQueue.Add(oldRepo.ID)
+	}
+	return repo, nil
 }
 
 // GetForks returns all the forks of the repository
@@ -2353,6 +2465,12 @@ func (repo *Repository) GetUserFork(userID int64) (*Repository, error) {
 
 // CreateNewBranch creates a new repository branch
 func (repo *Repository) CreateNewBranch(doer *User, oldBranchName, branchName string) (err error) {
+	if !git.IsBranchExist(repo.RepoPath(), oldBranchName) {
+		return ErrBranchNotExist{oldBranchName}
+	} else if git.IsBranchExist(repo.RepoPath(), branchName) {
+		return ErrBranchAlreadyExists{branchName}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -5,7 +5,11 @@
 package models
 
 import (
+	"fmt"
+
 	"code.gitea.io/git"
+
+	"github.com/Unknwon/com"
 )
 
 // Branch holds the branch information
@@ -60,3 +64,38 @@ func (branch *Branch) GetCommit() (*git.Commit, error) {
 	}
 	return gitRepo.GetBranchCommit(branch.Name)
 }
+
+// DeleteBranch deletes a branch of the repository, the default branch and
+// protected branches can not be deleted.
+func (repo *Repository) DeleteBranch(doer *User, branchName string) error {
+	if branchName == repo.DefaultBranch {
+		return ErrBranchIsDefault{branchName}
+	}
+	if protectBranch, err := GetProtectedBranchBy(repo.ID, branchName); err != nil {
+		return fmt.Errorf("GetProtectedBranchBy: %v", err)
+	} else if protectBranch != nil {
+		return ErrBranchIsProtected{branchName}
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !gitRepo.IsBranchExist(branchName) {
+		return ErrBranchNotExist{branchName}
+	}
+
+	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
+	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
+
+	if err = gitRepo.DeleteBranch(branchName, git.DeleteBranchOptions{
+		Force: true,
+	}); err != nil {
+		return fmt.Errorf("DeleteBranch: %v", err)
+	}
+
+	// The local copy still has the branch, which would keep it from being created again.
+	RemoveAllWithNotice("Delete repository local copy", repo.LocalCopyPath())
+
+	return PrepareDeleteRefWebhooks(doer, repo, git.BranchPrefix+branchName)
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_DeleteBranch(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo.DefaultBranch = "master"
+
+	assert.True(t, IsErrBranchIsDefault(repo.DeleteBranch(doer, "master")))
+
+	assert.NoError(t, repo.AddProtectedBranch("develop", true))
+	assert.True(t, IsErrBranchIsProtected(repo.DeleteBranch(doer, "develop")))
+	assert.True(t, IsErrBranchIsProtected(repo.DeleteBranch(doer, "Develop")))
+}
@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Unknwon/com"
@@ -20,6 +21,7 @@ import (
 
 	"code.gitea.io/git"
 
+	"code.gitea.io/gitea/modules/gitutil"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
@@ -72,6 +74,38 @@ func (repo *Repository) CheckoutNewBranch(oldBranch, newBranch string) error {
 	return checkoutNewBranch(repo.RepoPath(), repo.LocalCopyPath(), oldBranch, newBranch)
 }
 
+// IsValidRepoFilePath returns false if given path of a file, once cleaned,
+// is absolute, leaves the work tree of the repository or enters a .git directory.
+func IsValidRepoFilePath(treePath string) bool {
+	cleaned := path.Clean(treePath)
+	if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return false
+	}
+	for _, part := range strings.Split(cleaned, "/") {
+		if strings.EqualFold(part, ".git") {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRepoFileSHA makes sure the blob of given file at HEAD of the local copy
+// has given SHA, so that changes made meanwhile are not overwritten.
+func checkRepoFileSHA(localPath, treePath, sha string) error {
+	stdout, err := git.NewCommand("rev-parse", "HEAD:"+treePath).RunInDir(localPath)
+	if err != nil {
+		return git.ErrNotExist{ID: "HEAD", RelPath: treePath}
+	}
+	if currentSHA := strings.TrimSpace(stdout); currentSHA != sha {
+		return ErrSHADoesNotMatch{
+			Path:       treePath,
+			GivenSHA:   sha,
+			CurrentSHA: currentSHA,
+		}
+	}
+	return nil
+}
+
 // UpdateRepoFileOptions holds the repository file update options
 type UpdateRepoFileOptions struct {
 	LastCommitID string
@@ -82,22 +116,38 @@ type UpdateRepoFileOptions struct {
 	Message      string
 	Content      string
 	IsNewFile    bool
+	// SHA of the blob being updated, required unless it is a new file.
+	SHA       string
+	Author    *git.Signature
+	Committer *git.Signature
 }
 
 // UpdateRepoFile adds or updates a file in repository.
-func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (err error) {
+func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (push *Push, err error) {
+	if !IsValidRepoFilePath(opts.NewTreeName) {
+		return nil, ErrFilePathInvalid{opts.NewTreeName}
+	} else if !opts.IsNewFile && !IsValidRepoFilePath(opts.OldTreeName) {
+		return nil, ErrFilePathInvalid{opts.OldTreeName}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
 	if err = repo.DiscardLocalRepoBranchChanges(opts.OldBranch); err != nil {
-		return fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
 	} else if err = repo.UpdateLocalCopyBranch(opts.OldBranch); err != nil {
-		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
+	}
+
+	if !opts.IsNewFile {
+		if err = checkRepoFileSHA(repo.LocalCopyPath(), opts.OldTreeName, opts.SHA); err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
-			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
+			return nil, fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
 		}
 	}
 
@@ -107,13 +157,13 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 	dir := path.Dir(filePath)
 
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %v", dir, err)
+		return nil, fmt.Errorf("Failed to create dir %s: %v", dir, err)
 	}
 
-	// If it's meant to be a new file, make sure it doesn't exist.
-	if opts.IsNewFile {
+	// If it's meant to be a new file or the file is moved, make sure it doesn't exist.
+	if opts.IsNewFile || opts.OldTreeName != opts.NewTreeName {
 		if com.IsExist(filePath) {
-			return ErrRepoFileAlreadyExist{filePath}
+			return nil, ErrRepoFileAlreadyExist{filePath}
 		}
 	}
 
@@ -121,37 +171,34 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 	// Otherwise, move the file when name changed.
 	if com.IsFile(oldFilePath) && opts.OldTreeName != opts.NewTreeName {
 		if err = git.MoveFile(localPath, opts.OldTreeName, opts.NewTreeName); err != nil {
-			return fmt.Errorf("git mv %s %s: %v", opts.OldTreeName, opts.NewTreeName, err)
+			return nil, fmt.Errorf("git mv %s %s: %v", opts.OldTreeName, opts.NewTreeName, err)
 		}
 	}
 
 	if err = ioutil.WriteFile(filePath, []byte(opts.Content), 0666); err != nil {
-		return fmt.Errorf("WriteFile: %v", err)
+		return nil, fmt.Errorf("WriteFile: %v", err)
 	}
 
 	if err = git.AddChanges(localPath, true); err != nil {
-		return fmt.Errorf("git add --all: %v", err)
-	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
-		Committer: doer.NewGitSig(),
-		Message:   opts.Message,
-	}); err != nil {
-		return fmt.Errorf("CommitChanges: %v", err)
+		return nil, fmt.Errorf("git add --all: %v", err)
+	} else if err = gitutil.CommitChanges(localPath, repo.crudCommitChangesOptions(doer, opts.Author, opts.Committer, opts.Message, opts.NewBranch)); err != nil {
+		return nil, fmt.Errorf("CommitChanges: %v", err)
 	} else if err = git.Push(localPath, git.PushOptions{
 		Remote: "origin",
 		Branch: opts.NewBranch,
 	}); err != nil {
-		return fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
+		return nil, fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
 	}
 
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
 	if err != nil {
 		log.Error(4, "OpenRepository: %v", err)
-		return nil
+		return nil, nil
 	}
 	commit, err := gitRepo.GetBranchCommit(opts.NewBranch)
 	if err != nil {
 		log.Error(4, "GetBranchCommit [branch: %s]: %v", opts.NewBranch, err)
-		return nil
+		return nil, nil
 	}
 
 	// Simulate push event.
@@ -163,7 +210,7 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 	if opts.NewBranch != opts.OldBranch {
 		oldCommitID = git.EmptySHA
 	}
-	if err := CommitRepoAction(CommitRepoActionOptions{
+	if push, err = CommitRepoAction(CommitRepoActionOptions{
 		PusherName:  doer.Name,
 		RepoOwnerID: repo.MustOwner().ID,
 		RepoName:    repo.Name,
@@ -173,14 +220,18 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 		Commits:     pushCommits,
 	}); err != nil {
 		log.Error(4, "CommitRepoAction: %v", err)
-		return nil
+		return nil, nil
 	}
 
-	return nil
+	return push, nil
 }
 
 // GetDiffPreview produces and returns diff result of a file which is not yet committed.
 func (repo *Repository) GetDiffPreview(branch, treePath, content string) (diff *Diff, err error) {
+	if !IsValidRepoFilePath(treePath) {
+		return nil, ErrFilePathInvalid{treePath}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -245,53 +296,62 @@ type DeleteRepoFileOptions struct {
 	NewBranch    string
 	TreePath     string
 	Message      string
+	// SHA of the blob being deleted, it is required.
+	SHA       string
+	Author    *git.Signature
+	Committer *git.Signature
 }
 
 // DeleteRepoFile deletes a repository file
-func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (err error) {
+func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (push *Push, err error) {
+	if !IsValidRepoFilePath(opts.TreePath) {
+		return nil, ErrFilePathInvalid{opts.TreePath}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
 	if err = repo.DiscardLocalRepoBranchChanges(opts.OldBranch); err != nil {
-		return fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
 	} else if err = repo.UpdateLocalCopyBranch(opts.OldBranch); err != nil {
-		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
+	}
+
+	if err = checkRepoFileSHA(repo.LocalCopyPath(), opts.TreePath, opts.SHA); err != nil {
+		return nil, err
 	}
 
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
-			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
+			return nil, fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
 		}
 	}
 
 	localPath := repo.LocalCopyPath()
 	if err = os.Remove(path.Join(localPath, opts.TreePath)); err != nil {
-		return fmt.Errorf("Remove: %v", err)
+		return nil, fmt.Errorf("Remove: %v", err)
 	}
 
 	if err = git.AddChanges(localPath, true); err != nil {
-		return fmt.Errorf("git add --all: %v", err)
-	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
-		Committer: doer.NewGitSig(),
-		Message:   opts.Message,
-	}); err != nil {
-		return fmt.Errorf("CommitChanges: %v", err)
+		return nil, fmt.Errorf("git add --all: %v", err)
+	} else if err = gitutil.CommitChanges(localPath, repo.crudCommitChangesOptions(doer, opts.Author, opts.Committer, opts.Message, opts.NewBranch)); err != nil {
+		return nil, fmt.Errorf("CommitChanges: %v", err)
 	} else if err = git.Push(localPath, git.PushOptions{
 		Remote: "origin",
 		Branch: opts.NewBranch,
 	}); err != nil {
-		return fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
+		return nil, fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
 	}
 
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
 	if err != nil {
 		log.Error(4, "OpenRepository: %v", err)
-		return nil
+		return nil, nil
 	}
 	commit, err := gitRepo.GetBranchCommit(opts.NewBranch)
 	if err != nil {
 		log.Error(4, "GetBranchCommit [branch: %s]: %v", opts.NewBranch, err)
-		return nil
+		return nil, nil
 	}
 
 	// Simulate push event.
@@ -299,7 +359,7 @@ func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (
 		Len:     1,
 		Commits: []*PushCommit{CommitToPushCommit(commit)},
 	}
-	if err := CommitRepoAction(CommitRepoActionOptions{
+	if push, err = CommitRepoAction(CommitRepoActionOptions{
 		PusherName:  doer.Name,
 		RepoOwnerID: repo.MustOwner().ID,
 		RepoName:    repo.Name,
@@ -309,10 +369,10 @@ func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (
 		Commits:     pushCommits,
 	}); err != nil {
 		log.Error(4, "CommitRepoAction: %v", err)
-		return nil
+		return nil, nil
 	}
 
-	return nil
+	return push, nil
 }
 
 //  ____ ___        .__                    .___ ___________.___.__
@@ -463,28 +523,28 @@ type UploadRepoFileOptions struct {
 }
 
 // UploadRepoFiles uploads files to a repository
-func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions) (err error) {
+func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions) (push *Push, err error) {
 	if len(opts.Files) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	uploads, err := GetUploadsByUUIDs(opts.Files)
 	if err != nil {
-		return fmt.Errorf("GetUploadsByUUIDs [uuids: %v]: %v", opts.Files, err)
+		return nil, fmt.Errorf("GetUploadsByUUIDs [uuids: %v]: %v", opts.Files, err)
 	}
 
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
 	if err = repo.DiscardLocalRepoBranchChanges(opts.OldBranch); err != nil {
-		return fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("DiscardLocalRepoBranchChanges [branch: %s]: %v", opts.OldBranch, err)
 	} else if err = repo.UpdateLocalCopyBranch(opts.OldBranch); err != nil {
-		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
+		return nil, fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
 	}
 
 	if opts.OldBranch != opts.NewBranch {
 		if err = repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
-			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
+			return nil, fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
 		}
 	}
 
@@ -492,7 +552,7 @@ func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions)
 	dirPath := path.Join(localPath, opts.TreePath)
 
 	if err := os.MkdirAll(dirPath, os.ModePerm); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %v", dirPath, err)
+		return nil, fmt.Errorf("Failed to create dir %s: %v", dirPath, err)
 	}
 
 	// Copy uploaded files into repository.
@@ -504,33 +564,30 @@ func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions)
 		}
 
 		if err = com.Copy(tmpPath, targetPath); err != nil {
-			return fmt.Errorf("Copy: %v", err)
+			return nil, fmt.Errorf("Copy: %v", err)
 		}
 	}
 
 	if err = git.AddChanges(localPath, true); err != nil {
-		return fmt.Errorf("git add --all: %v", err)
-	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
-		Committer: doer.NewGitSig(),
-		Message:   opts.Message,
-	}); err != nil {
-		return fmt.Errorf("CommitChanges: %v", err)
+		return nil, fmt.Errorf("git add --all: %v", err)
+	} else if err = gitutil.CommitChanges(localPath, signedCommitChangesOptions(doer, opts.Message, repo.signCRUDAction(doer, opts.NewBranch))); err != nil {
+		return nil, fmt.Errorf("CommitChanges: %v", err)
 	} else if err = git.Push(localPath, git.PushOptions{
 		Remote: "origin",
 		Branch: opts.NewBranch,
 	}); err != nil {
-		return fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
+		return nil, fmt.Errorf("git push origin %s: %v", opts.NewBranch, err)
 	}
 
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
 	if err != nil {
 		log.Error(4, "OpenRepository: %v", err)
-		return nil
+		return nil, nil
 	}
 	commit, err := gitRepo.GetBranchCommit(opts.NewBranch)
 	if err != nil {
 		log.Error(4, "GetBranchCommit [branch: %s]: %v", opts.NewBranch, err)
-		return nil
+		return nil, nil
 	}
 
 	// Simulate push event.
@@ -538,7 +595,7 @@ func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions)
 		Len:     1,
 		Commits: []*PushCommit{CommitToPushCommit(commit)},
 	}
-	if err := CommitRepoAction(CommitRepoActionOptions{
+	if push, err = CommitRepoAction(CommitRepoActionOptions{
 		PusherName:  doer.Name,
 		RepoOwnerID: repo.MustOwner().ID,
 		RepoName:    repo.Name,
@@ -548,8 +605,8 @@ func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions)
 		Commits:     pushCommits,
 	}); err != nil {
 		log.Error(4, "CommitRepoAction: %v", err)
-		return nil
+		return nil, nil
 	}
 
-	return DeleteUploads(uploads...)
+	return push, DeleteUploads(uploads...)
 }
@@ -0,0 +1,43 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidRepoFilePath(t *testing.T) {
+	for _, treePath := range []string{"README.md", "docs/new.txt", "a/../b.txt", "docs/.gitignore", ".github/file"} {
+		assert.True(t, IsValidRepoFilePath(treePath), treePath)
+	}
+	for _, treePath := range []string{"", ".", "..", "../outside", "a/../../outside", "/etc/passwd",
+		".git/config", "a/../.git/hooks/update", "sub/.git/config", ".GIT/config"} {
+		assert.False(t, IsValidRepoFilePath(treePath), treePath)
+	}
+}
+
+func TestRepository_UpdateRepoFileInvalidPath(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	_, err := repo.UpdateRepoFile(user, UpdateRepoFileOptions{
+		OldBranch:   "master",
+		NewBranch:   "master",
+		OldTreeName: "../outside",
+		NewTreeName: "../outside",
+		IsNewFile:   true,
+	})
+	assert.True(t, IsErrFilePathInvalid(err))
+
+	_, err = repo.DeleteRepoFile(user, DeleteRepoFileOptions{
+		OldBranch: "master",
+		NewBranch: "master",
+		TreePath:  ".git/config",
+	})
+	assert.True(t, IsErrFilePathInvalid(err))
+}
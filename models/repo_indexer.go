@@ -0,0 +1,477 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/simple"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/registry"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+// repoIndexerAnalyzer analyzer used for the content of the indexed files,
+// which splits on unicode word boundaries so identifiers like foo_bar are
+// kept as a single token.
+const repoIndexerAnalyzer = "repoIndexerAnalyzer"
+
+func init() {
+	registry.RegisterAnalyzer(repoIndexerAnalyzer, func(config map[string]interface{}, cache *registry.Cache) (*analysis.Analyzer, error) {
+		tokenizer, err := cache.TokenizerNamed(unicode.Name)
+		if err != nil {
+			return nil, err
+		}
+		toLowerFilter, err := cache.TokenFilterNamed(lowercase.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &analysis.Analyzer{
+			Tokenizer:    tokenizer,
+			TokenFilters: []analysis.TokenFilter{toLowerFilter},
+		}, nil
+	})
+}
+
+// RepoIndexerStatus status of a repository's entry in the repository indexer
+type RepoIndexerStatus struct {
+	ID        int64  `xorm:"pk autoincr"`
+	RepoID    int64  `xorm:"INDEX"`
+	CommitSha string `xorm:"VARCHAR(40)"`
+}
+
+// getRepoIndexerStatus returns the commit of the repository that is indexed,
+// or an empty status if the repository has never been indexed.
+func getRepoIndexerStatus(repoID int64) (*RepoIndexerStatus, error) {
+	status := &RepoIndexerStatus{RepoID: repoID}
+	has, err := x.Get(status)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		status.CommitSha = ""
+	}
+	return status, nil
+}
+
+// updateRepoIndexerStatus records the commit of the repository that is indexed
+func updateRepoIndexerStatus(repoID int64, sha string) error {
+	status, err := getRepoIndexerStatus(repoID)
+	if err != nil {
+		return err
+	}
+	status.CommitSha = sha
+	if status.ID == 0 {
+		_, err = x.Insert(status)
+	} else {
+		_, err = x.Id(status.ID).Cols("commit_sha").Update(status)
+	}
+	return err
+}
+
+// repoIndexerUpdateQueue queue of repositories that need to be updated in the
+// repository indexer
+var repoIndexerUpdateQueue chan repoIndexerOperation
+
+// repoIndexer (thread-safe) index for searching the code of repositories
+var repoIndexer bleve.Index
+
+// repoIndexerOperation an update or deletion of a repository in the indexer
+type repoIndexerOperation struct {
+	repo    *Repository
+	deleted bool
+}
+
+// repoIndexerData data stored in the repository indexer
+type repoIndexerData struct {
+	RepoID   int64
+	Filename string
+	Content  string
+}
+
+// repoIndexerUID a unique identifier for a file of a repository
+func repoIndexerUID(repoID int64, filename string) string {
+	return strconv.FormatInt(repoID, 36) + "_" + filename
+}
+
+// InitRepoIndexer initialize the repository indexer
+func InitRepoIndexer() {
+	_, err := os.Stat(setting.Indexer.RepoPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if err = createRepoIndexer(); err != nil {
+				log.Fatal(4, "CreateRepoIndexer: %v", err)
+			}
+		} else {
+			log.Fatal(4, "InitRepoIndexer: %v", err)
+		}
+	} else {
+		repoIndexer, err = bleve.Open(setting.Indexer.RepoPath)
+		if err != nil {
+			log.Fatal(4, "InitRepoIndexer, open index: %v", err)
+		}
+	}
+	repoIndexerUpdateQueue = make(chan repoIndexerOperation, setting.Indexer.UpdateQueueLength)
+	go func() {
+		if err := populateRepoIndexer(); err != nil {
+			log.Error(4, "PopulateRepoIndexer: %v", err)
+		}
+	}()
+	go processRepoIndexerUpdateQueue()
+}
+
+// createRepoIndexer create a repository indexer if one does not already
+// exist. The status of all repositories is reset since none of them is
+// indexed yet.
+func createRepoIndexer() error {
+	mapping := bleve.NewIndexMapping()
+	docMapping := bleve.NewDocumentMapping()
+
+	docMapping.AddFieldMappingsAt("RepoID", bleve.NewNumericFieldMapping())
+
+	filenameFieldMapping := bleve.NewTextFieldMapping()
+	filenameFieldMapping.Analyzer = simple.Name
+	docMapping.AddFieldMappingsAt("Filename", filenameFieldMapping)
+
+	contentFieldMapping := bleve.NewTextFieldMapping()
+	contentFieldMapping.Analyzer = repoIndexerAnalyzer
+	docMapping.AddFieldMappingsAt("Content", contentFieldMapping)
+
+	mapping.DefaultMapping = docMapping
+
+	if _, err := x.Where("1=1").Delete(new(RepoIndexerStatus)); err != nil {
+		return fmt.Errorf("reset indexer status: %v", err)
+	}
+
+	var err error
+	repoIndexer, err = bleve.New(setting.Indexer.RepoPath, mapping)
+	return err
+}
+
+// populateRepoIndexer indexes all repositories which have not been indexed yet
+func populateRepoIndexer() error {
+	for page := 1; ; page++ {
+		repos := make([]*Repository, 0, 10)
+		if err := x.
+			Join("LEFT", "repo_indexer_status", "repository.id = repo_indexer_status.repo_id").
+			Where("repo_indexer_status.id IS NULL").
+			And("repository.is_bare = ?", false).
+			Asc("repository.id").
+			Limit(10, (page-1)*10).
+			Find(&repos); err != nil {
+			return fmt.Errorf("find unindexed repositories: %v", err)
+		}
+		if len(repos) == 0 {
+			return nil
+		}
+		for _, repo := range repos {
+			if err := updateRepoIndexer(repo); err != nil {
+				log.Error(4, "updateRepoIndexer [%d]: %v", repo.ID, err)
+			}
+		}
+	}
+}
+
+// fileUpdate a file of a repository which is added to or changed in the index
+type fileUpdate struct {
+	Filename string
+	BlobSha  string
+}
+
+// parseGitLsTreeOutput parses the output of `git ls-tree -l -z`, skipping
+// submodules and files too large to be indexed.
+func parseGitLsTreeOutput(stdout string) []fileUpdate {
+	lines := strings.Split(stdout, "\x00")
+	updates := make([]fileUpdate, 0, len(lines))
+	for _, line := range lines {
+		// <mode> SP <type> SP <object> SP <object size> TAB <file>
+		tab := strings.IndexByte(line, '\t')
+		if tab < 0 {
+			continue
+		}
+		fields := strings.Fields(line[:tab])
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil || size > setting.Indexer.MaxIndexerFileSize {
+			continue
+		}
+		updates = append(updates, fileUpdate{
+			Filename: line[tab+1:],
+			BlobSha:  fields[2],
+		})
+	}
+	return updates
+}
+
+// repoChanges returns the files to update and the files to remove from the
+// index to move it from the indexed commit to the given revision.
+func repoChanges(repoPath, oldSha, newSha string) (updates []fileUpdate, removals []string, err error) {
+	if len(oldSha) == 0 {
+		stdout, err := git.NewCommand("ls-tree", "--full-tree", "-r", "-l", "-z", newSha).RunInDir(repoPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return parseGitLsTreeOutput(stdout), nil, nil
+	}
+
+	stdout, err := git.NewCommand("diff", "--name-status", "--no-renames", "-z", oldSha, newSha).RunInDir(repoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	// -z output is a sequence of NUL separated <status> NUL <path> pairs
+	fields := strings.Split(strings.TrimSuffix(stdout, "\x00"), "\x00")
+	changed := make([]string, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "D" {
+			removals = append(removals, fields[i+1])
+		} else {
+			changed = append(changed, fields[i+1])
+		}
+	}
+	if len(changed) == 0 {
+		return nil, removals, nil
+	}
+
+	cmd := git.NewCommand("ls-tree", "--full-tree", "-l", "-z", newSha, "--")
+	cmd.AddArguments(changed...)
+	if stdout, err = cmd.RunInDir(repoPath); err != nil {
+		return nil, nil, err
+	}
+	updates = parseGitLsTreeOutput(stdout)
+
+	// files which have become too large or a submodule must not linger in
+	// the index either
+	kept := make(map[string]bool, len(updates))
+	for _, update := range updates {
+		kept[update.Filename] = true
+	}
+	for _, filename := range changed {
+		if !kept[filename] {
+			removals = append(removals, filename)
+		}
+	}
+	return updates, removals, nil
+}
+
+// isCommitExist returns true if the commit is present in the repository
+func isCommitExist(repoPath, sha string) bool {
+	_, err := git.NewCommand("cat-file", "-e", sha+"^{commit}").RunInDir(repoPath)
+	return err == nil
+}
+
+// updateRepoIndexer indexes the default branch of the repository
+func updateRepoIndexer(repo *Repository) error {
+	repoPath := repo.RepoPath()
+	gitRepo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	sha, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			// nothing has been pushed to the default branch yet
+			return nil
+		}
+		return fmt.Errorf("GetBranchCommitID: %v", err)
+	}
+
+	status, err := getRepoIndexerStatus(repo.ID)
+	if err != nil {
+		return fmt.Errorf("getRepoIndexerStatus: %v", err)
+	} else if status.CommitSha == sha {
+		return nil
+	}
+
+	if len(status.CommitSha) > 0 && !isCommitExist(repoPath, status.CommitSha) {
+		// the indexed commit has been garbage collected, rebuild the index
+		if err = deleteRepoFromIndexer(repo.ID); err != nil {
+			return err
+		}
+		status.CommitSha = ""
+	}
+
+	updates, removals, err := repoChanges(repoPath, status.CommitSha, sha)
+	if err != nil {
+		return fmt.Errorf("repoChanges: %v", err)
+	}
+
+	batch := repoIndexer.NewBatch()
+	for _, update := range updates {
+		content, err := git.NewCommand("cat-file", "blob", update.BlobSha).RunInDirBytes(repoPath)
+		if err != nil {
+			return fmt.Errorf("cat-file %s: %v", update.BlobSha, err)
+		}
+		uid := repoIndexerUID(repo.ID, update.Filename)
+		if !base.IsTextFile(content) {
+			// the file may have been text in the previous revision
+			batch.Delete(uid)
+			continue
+		}
+		if err = batch.Index(uid, &repoIndexerData{
+			RepoID:   repo.ID,
+			Filename: update.Filename,
+			Content:  string(content),
+		}); err != nil {
+			return fmt.Errorf("batch.Index: %v", err)
+		}
+	}
+	for _, filename := range removals {
+		batch.Delete(repoIndexerUID(repo.ID, filename))
+	}
+	if err = repoIndexer.Batch(batch); err != nil {
+		return fmt.Errorf("index.Batch: %v", err)
+	}
+	return updateRepoIndexerStatus(repo.ID, sha)
+}
+
+// deleteRepoFromIndexer removes all files of the repository from the index
+func deleteRepoFromIndexer(repoID int64) error {
+	for {
+		search := bleve.NewSearchRequestOptions(numericQuery(repoID, "RepoID"), 1000, 0, false)
+		result, err := repoIndexer.Search(search)
+		if err != nil {
+			return fmt.Errorf("Search: %v", err)
+		}
+		if len(result.Hits) == 0 {
+			return nil
+		}
+		batch := repoIndexer.NewBatch()
+		for _, hit := range result.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err = repoIndexer.Batch(batch); err != nil {
+			return fmt.Errorf("index.Batch: %v", err)
+		}
+	}
+}
+
+func processRepoIndexerUpdateQueue() {
+	for {
+		select {
+		case op := <-repoIndexerUpdateQueue:
+			if op.deleted {
+				if err := deleteRepoFromIndexer(op.repo.ID); err != nil {
+					log.Error(4, "deleteRepoFromIndexer [%d]: %v", op.repo.ID, err)
+				}
+			} else if err := updateRepoIndexer(op.repo); err != nil {
+				log.Error(4, "updateRepoIndexer [%d]: %v", op.repo.ID, err)
+			}
+		}
+	}
+}
+
+// UpdateRepoIndexer add/update the default branch of a repository to the
+// repository indexer
+func UpdateRepoIndexer(repo *Repository) {
+	if !setting.Indexer.RepoIndexerEnabled {
+		return
+	}
+	go func() {
+		repoIndexerUpdateQueue <- repoIndexerOperation{repo: repo}
+	}()
+}
+
+// DeleteRepoFromIndexer remove all files of a repository from the repository
+// indexer
+func DeleteRepoFromIndexer(repo *Repository) {
+	if !setting.Indexer.RepoIndexerEnabled {
+		return
+	}
+	go func() {
+		repoIndexerUpdateQueue <- repoIndexerOperation{repo: repo, deleted: true}
+	}()
+}
+
+// RepoSearchMatch the byte offsets of a match in the content of a file
+type RepoSearchMatch struct {
+	Start int
+	End   int
+}
+
+// RepoSearchResult a file of a repository matching a search
+type RepoSearchResult struct {
+	RepoID   int64
+	Filename string
+	Content  string
+	// Matches of the keyword in Content, sorted by offset
+	Matches []RepoSearchMatch
+}
+
+// SearchRepoByKeyword searches the files of the given repositories, or of all
+// repositories if repoIDs is nil, for a keyword. When isFilename is true the
+// keyword is matched against the path of the files instead of their content.
+// Returns the total number of matching files and the results of the page.
+func SearchRepoByKeyword(repoIDs []int64, keyword string, isFilename bool, page, pageSize int) (int64, []*RepoSearchResult, error) {
+	if repoIDs != nil && len(repoIDs) == 0 {
+		return 0, nil, nil
+	}
+
+	field := "Content"
+	if isFilename {
+		field = "Filename"
+	}
+	keywordQuery := bleve.NewMatchPhraseQuery(keyword)
+	keywordQuery.SetField(field)
+
+	var indexerQuery query.Query = keywordQuery
+	if repoIDs != nil {
+		repoQueries := make([]query.Query, len(repoIDs))
+		for i, repoID := range repoIDs {
+			repoQueries[i] = numericQuery(repoID, "RepoID")
+		}
+		indexerQuery = bleve.NewConjunctionQuery(
+			bleve.NewDisjunctionQuery(repoQueries...),
+			keywordQuery,
+		)
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	search := bleve.NewSearchRequestOptions(indexerQuery, pageSize, (page-1)*pageSize, false)
+	search.Fields = []string{"RepoID", "Filename", "Content"}
+
+	result, err := repoIndexer.Search(search)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	results := make([]*RepoSearchResult, len(result.Hits))
+	for i, hit := range result.Hits {
+		res := &RepoSearchResult{
+			RepoID:   int64(hit.Fields["RepoID"].(float64)),
+			Filename: hit.Fields["Filename"].(string),
+			Content:  hit.Fields["Content"].(string),
+		}
+		if !isFilename {
+			for _, locations := range hit.Locations["Content"] {
+				for _, location := range locations {
+					res.Matches = append(res.Matches, RepoSearchMatch{
+						Start: int(location.Start),
+						End:   int(location.End),
+					})
+				}
+			}
+			sort.Slice(res.Matches, func(i, j int) bool {
+				return res.Matches[i].Start < res.Matches[j].Start
+			})
+		}
+		results[i] = res
+	}
+	return int64(result.Total), results, nil
+}
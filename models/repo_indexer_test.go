@@ -0,0 +1,95 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitLsTreeOutput(t *testing.T) {
+	setting.Indexer.MaxIndexerFileSize = 100
+	stdout := "100644 blob 2a3c39b8a0bfa48e6d93cd0fbd0b3bdb2ebb5d4c 25\tREADME.md\x00" +
+		"160000 commit 9b21c5a3b2b1ee28ce1bcbb1c1d3b6d3cf9c5e8e -\tvendor/lib\x00" +
+		"100644 blob 0d6ab1ee37e4fcc9b0e8fd2a6c6c63bee0e1d9a2 4096\tbig.bin\x00" +
+		"100644 blob 80e6ec4d67c4b7a1b48fdb03fd0bfe98c1a6d1b8 12\tdir/with space.go\x00"
+
+	assert.Equal(t, []fileUpdate{
+		{Filename: "README.md", BlobSha: "2a3c39b8a0bfa48e6d93cd0fbd0b3bdb2ebb5d4c"},
+		{Filename: "dir/with space.go", BlobSha: "80e6ec4d67c4b7a1b48fdb03fd0bfe98c1a6d1b8"},
+	}, parseGitLsTreeOutput(stdout))
+}
+
+func TestSearchRepoByKeyword(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	dir, err := ioutil.TempDir("", "repo-indexer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	setting.Indexer.RepoPath = filepath.Join(dir, "repos.bleve")
+	assert.NoError(t, createRepoIndexer())
+	defer repoIndexer.Close()
+
+	for _, data := range []*repoIndexerData{
+		{RepoID: 1, Filename: "main.go", Content: "package main\n\nfunc main() {\n\tprintln(\"Hello_World\")\n}\n"},
+		{RepoID: 1, Filename: "docs/README.md", Content: "# Hello\n\nThis is the readme\n"},
+		{RepoID: 2, Filename: "hello.go", Content: "package hello\n\n// hello_world says hello\nfunc hello_world() {}\n"},
+	} {
+		assert.NoError(t, repoIndexer.Index(repoIndexerUID(data.RepoID, data.Filename), data))
+	}
+
+	total, results, err := SearchRepoByKeyword([]int64{1}, "hello_world", false, 1, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	if assert.Len(t, results, 1) {
+		assert.EqualValues(t, 1, results[0].RepoID)
+		assert.Equal(t, "main.go", results[0].Filename)
+		if assert.Len(t, results[0].Matches, 1) {
+			match := results[0].Matches[0]
+			assert.Equal(t, "Hello_World", results[0].Content[match.Start:match.End])
+		}
+	}
+
+	total, _, err = SearchRepoByKeyword(nil, "hello_world", false, 1, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+
+	total, results, err = SearchRepoByKeyword(nil, "readme", true, 1, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "docs/README.md", results[0].Filename)
+		assert.Empty(t, results[0].Matches)
+	}
+
+	total, results, err = SearchRepoByKeyword([]int64{}, "hello", false, 1, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, total)
+	assert.Empty(t, results)
+
+	assert.NoError(t, deleteRepoFromIndexer(1))
+	total, _, err = SearchRepoByKeyword(nil, "hello", false, 1, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+}
+
+func TestUpdateRepoIndexerStatus(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	status, err := getRepoIndexerStatus(1)
+	assert.NoError(t, err)
+	assert.Empty(t, status.CommitSha)
+
+	assert.NoError(t, updateRepoIndexerStatus(1, "65f1bf27bc3bf70f64657658635e66094edbcb4d"))
+	assert.NoError(t, updateRepoIndexerStatus(1, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6"))
+	AssertExistsAndLoadBean(t, &RepoIndexerStatus{RepoID: 1, CommitSha: "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6"})
+	AssertCount(t, &RepoIndexerStatus{RepoID: 1}, 1)
+}
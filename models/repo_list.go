@@ -100,6 +100,8 @@ type SearchRepoOptions struct {
 	Starred   bool   `json:"-"`
 	Page      int    `json:"-"`
 	IsProfile bool   `json:"-"`
+	// RepoIDs restricts the results to these repositories if not empty
+	RepoIDs []int64 `json:"-"`
 	// Limit of result
 	//
 	// maximum: setting.ExplorePagingNum
@@ -135,7 +137,7 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (repos RepositoryList, coun
 		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
 	}
 	if !opts.Private {
-		cond = cond.And(builder.Eq{"is_private": false})
+		cond = cond.And(builder.Eq{"is_private": false}, visibleOwnerCond(opts.Searcher))
 	}
 
 	if opts.Searcher != nil {
@@ -155,6 +157,10 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (repos RepositoryList, coun
 		cond = cond.Or(builder.And(builder.Like{"lower_name", opts.Keyword}, builder.In("owner_id", ownerIds)))
 	}
 
+	if len(opts.RepoIDs) > 0 {
+		cond = cond.And(builder.In("repository.id", opts.RepoIDs))
+	}
+
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = "name ASC"
 	}
@@ -229,9 +235,7 @@ func GetRecentUpdatedRepositories(opts *SearchRepoOptions) (repos RepositoryList
 	}
 
 	if !opts.Private {
-		cond = builder.Eq{
-			"is_private": false,
-		}
+		cond = builder.And(builder.Eq{"is_private": false}, visibleOwnerCond(opts.Searcher))
 	}
 
 	if opts.Searcher != nil && !opts.Searcher.IsAdmin {
@@ -270,3 +274,34 @@ func GetRecentUpdatedRepositories(opts *SearchRepoOptions) (repos RepositoryList
 
 	return repos, count, nil
 }
+
+// visibleOwnerCond returns the condition of the repositories whose owner can be
+// seen by given viewer, viewer is nil for anonymous visitors.
+func visibleOwnerCond(viewer *User) builder.Cond {
+	if viewer != nil && viewer.IsAdmin {
+		return builder.NewCond()
+	}
+	return builder.In("owner_id", builder.Select("id").From("`user`").Where(visibleUserCond(viewer)))
+}
+
+// FindUserAccessibleRepoIDs returns the IDs of the repositories the user can
+// read: the public repositories of the owners the user can see and the private
+// repositories the user owns or has access to. Anonymous users can only read
+// the public repositories of public owners.
+func FindUserAccessibleRepoIDs(user *User) ([]int64, error) {
+	cond := builder.NewCond().Or(builder.And(builder.Eq{"is_private": false}, visibleOwnerCond(user)))
+	if user != nil {
+		cond = cond.Or(builder.Eq{"owner_id": user.ID}).
+			Or(builder.In("id", builder.Select("repo_id").From("access").
+				Where(builder.Eq{"user_id": user.ID}.And(builder.Gte{"mode": AccessModeRead}))))
+	}
+
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, x.Table("repository").Cols("id").Where(cond).Find(&repoIDs)
+}
+
+// GetRepositoriesMapByIDs returns the repositories by given id slice.
+func GetRepositoriesMapByIDs(ids []int64) (map[int64]*Repository, error) {
+	var repos = make(map[int64]*Repository, len(ids))
+	return repos, x.In("id", ids).Find(&repos)
+}
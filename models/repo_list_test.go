@@ -7,6 +7,8 @@ package models
 import (
 	"testing"
 
+	"code.gitea.io/gitea/modules/base"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +63,49 @@ func TestSearchRepositoryByName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(3), count)
 }
+
+func TestSearchRepositoryByName_OwnerVisibility(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	_, err := x.ID(14).Cols("visibility").Update(&User{Visibility: VisibleTypeLimited})
+	assert.NoError(t, err)
+
+	test := func(searcher *User, expectedCount int64) {
+		_, count, err := SearchRepositoryByName(&SearchRepoOptions{
+			Keyword:  "test_repo",
+			Page:     1,
+			PageSize: 10,
+			Searcher: searcher,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCount, count)
+	}
+	test(nil, 0)
+	test(&User{ID: 5}, 2)
+	test(&User{ID: 14}, 3)
+
+	repos, count, err := GetRecentUpdatedRepositories(&SearchRepoOptions{Page: 1, PageSize: 20})
+	assert.NoError(t, err)
+	for _, repo := range repos {
+		assert.NotEqual(t, int64(14), repo.OwnerID)
+	}
+	assert.EqualValues(t, len(repos), count)
+}
+
+func TestFindUserAccessibleRepoIDs_OwnerVisibility(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	_, err := x.ID(14).Cols("visibility").Update(&User{Visibility: VisibleTypePrivate})
+	assert.NoError(t, err)
+
+	// the public repositories 12 and 14 are owned by user 14
+	test := func(user *User, expected bool) {
+		repoIDs, err := FindUserAccessibleRepoIDs(user)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, base.Int64sContains(repoIDs, 12))
+		assert.Equal(t, expected, base.Int64sContains(repoIDs, 14))
+		assert.True(t, base.Int64sContains(repoIDs, 1))
+	}
+	test(nil, false)
+	test(&User{ID: 5}, false)
+	test(&User{ID: 14}, true)
+	test(&User{ID: 1, IsAdmin: true}, true)
+}
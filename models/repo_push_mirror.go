@@ -0,0 +1,264 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+
+	"github.com/Unknwon/com"
+	"github.com/go-xorm/xorm"
+	"gopkg.in/ini.v1"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+)
+
+// PushMirrorQueue holds an UniqueQueue object of the push mirrors to sync
+var PushMirrorQueue = sync.NewUniqueQueue(setting.Repository.MirrorQueueLength)
+
+// PushMirror represents a remote the git data of a repository is pushed to,
+// on every push and on schedule. The address of the remote, credentials
+// included, is kept in the git config of the repository.
+type PushMirror struct {
+	ID         int64       `xorm:"pk autoincr"`
+	RepoID     int64       `xorm:"INDEX"`
+	Repo       *Repository `xorm:"-"`
+	RemoteName string
+
+	// Interval between scheduled pushes, 0 if they are disabled
+	Interval       time.Duration
+	Created        time.Time `xorm:"-"`
+	CreatedUnix    int64
+	LastUpdate     time.Time `xorm:"-"`
+	LastUpdateUnix int64     `xorm:"INDEX"`
+	LastError      string    `xorm:"TEXT"`
+
+	address string `xorm:"-"`
+}
+
+// BeforeInsert will be invoked by XORM before inserting a record
+func (m *PushMirror) BeforeInsert() {
+	m.CreatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (m *PushMirror) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		m.Created = time.Unix(m.CreatedUnix, 0).Local()
+	case "last_update_unix":
+		m.LastUpdate = time.Unix(m.LastUpdateUnix, 0).Local()
+	}
+}
+
+func (m *PushMirror) loadRepo(e Engine) (err error) {
+	if m.Repo == nil {
+		m.Repo, err = getRepositoryByID(e, m.RepoID)
+	}
+	return err
+}
+
+func (m *PushMirror) readAddress() {
+	if len(m.address) > 0 {
+		return
+	}
+
+	cfg, err := ini.Load(m.Repo.GitConfigPath())
+	if err != nil {
+		log.Error(4, "Load: %v", err)
+		return
+	}
+	m.address = cfg.Section(fmt.Sprintf("remote \"%s\"", m.RemoteName)).Key("url").Value()
+}
+
+// Address returns the address of the remote without credentials.
+func (m *PushMirror) Address() string {
+	m.readAddress()
+	return HandleCloneUserCredentials(m.address, false)
+}
+
+// IsScheduled returns true if the git data is pushed to the remote on schedule.
+func (m *PushMirror) IsScheduled() bool {
+	return m.Interval > 0
+}
+
+// isDue returns true if the scheduled push to the remote is due.
+func (m *PushMirror) isDue() bool {
+	return m.IsScheduled() && time.Unix(m.LastUpdateUnix, 0).Add(m.Interval).Before(time.Now())
+}
+
+// APIFormat converts a push mirror to its API representation
+func (m *PushMirror) APIFormat() *api.PushMirror {
+	apiMirror := &api.PushMirror{
+		ID:            m.ID,
+		RemoteName:    m.RemoteName,
+		RemoteAddress: m.Address(),
+		Created:       m.Created,
+		LastError:     m.LastError,
+	}
+	if m.IsScheduled() {
+		apiMirror.Interval = m.Interval.String()
+	}
+	if m.LastUpdateUnix > 0 {
+		apiMirror.LastUpdate = &m.LastUpdate
+	}
+	return apiMirror
+}
+
+// runSync pushes the git data to the remote and records the outcome.
+func (m *PushMirror) runSync() error {
+	repoPath := m.Repo.RepoPath()
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+
+	m.LastError = ""
+	if _, stderr, err := process.GetManager().ExecDir(
+		timeout, repoPath, fmt.Sprintf("PushMirror.runSync: %s", repoPath),
+		"git", "push", "--mirror", m.RemoteName); err != nil {
+		m.readAddress()
+		m.LastError = strings.Replace(stderr, m.address, m.Address(), -1)
+		log.Error(4, "Failed to push repository '%s' to mirror '%s': %s", repoPath, m.Address(), m.LastError)
+	}
+
+	m.LastUpdateUnix = time.Now().Unix()
+	_, err := x.Id(m.ID).Cols("last_update_unix", "last_error").Update(m)
+	return err
+}
+
+// AddPushMirror adds a remote the git data of the repository is pushed to.
+func AddPushMirror(repo *Repository, address string, interval time.Duration) (*PushMirror, error) {
+	remoteName, err := base.GetRandomString(10)
+	if err != nil {
+		return nil, fmt.Errorf("GetRandomString: %v", err)
+	}
+
+	m := &PushMirror{
+		RepoID:     repo.ID,
+		Repo:       repo,
+		RemoteName: "remote_mirror_" + strings.ToLower(remoteName),
+		Interval:   interval,
+	}
+	if _, err = git.NewCommand("remote", "add", "--mirror=push", m.RemoteName, address).RunInDir(repo.RepoPath()); err != nil {
+		return nil, fmt.Errorf("add remote: %v", err)
+	}
+
+	if _, err = x.Insert(m); err != nil {
+		if _, err := git.NewCommand("remote", "remove", m.RemoteName).RunInDir(repo.RepoPath()); err != nil {
+			log.Error(4, "Failed to remove remote %s of %s: %v", m.RemoteName, repo.RepoPath(), err)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetPushMirrorByID returns the push mirror of the repository by given ID.
+func GetPushMirrorByID(repoID, id int64) (*PushMirror, error) {
+	m := &PushMirror{ID: id, RepoID: repoID}
+	has, err := x.Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushMirrorNotExist{id, repoID}
+	}
+	return m, m.loadRepo(x)
+}
+
+// GetPushMirrorsByRepoID returns all push mirrors of the repository.
+func GetPushMirrorsByRepoID(repoID int64) ([]*PushMirror, error) {
+	mirrors := make([]*PushMirror, 0, 5)
+	if err := x.Where("repo_id = ?", repoID).Asc("id").Find(&mirrors); err != nil {
+		return nil, err
+	}
+	for _, m := range mirrors {
+		if err := m.loadRepo(x); err != nil {
+			return nil, err
+		}
+	}
+	return mirrors, nil
+}
+
+// UpdatePushMirrorInterval changes the interval between scheduled pushes.
+func UpdatePushMirrorInterval(m *PushMirror, interval time.Duration) error {
+	m.Interval = interval
+	_, err := x.Id(m.ID).Cols("interval").Update(m)
+	return err
+}
+
+// DeletePushMirror removes the push mirror and its remote.
+func DeletePushMirror(m *PushMirror) error {
+	if _, err := x.Delete(&PushMirror{ID: m.ID}); err != nil {
+		return err
+	}
+
+	if _, err := git.NewCommand("remote", "remove", m.RemoteName).RunInDir(m.Repo.RepoPath()); err != nil {
+		return fmt.Errorf("remove remote: %v", err)
+	}
+	return nil
+}
+
+// AddPushMirrorsToQueue queues all push mirrors of the repository, which is
+// done on every push to it.
+func AddPushMirrorsToQueue(repoID int64) {
+	ids := make([]int64, 0, 5)
+	if err := x.Table("push_mirror").Cols("id").Where("repo_id = ?", repoID).Find(&ids); err != nil {
+		log.Error(4, "Find push mirrors of repository %d: %v", repoID, err)
+		return
+	}
+	for _, id := range ids {
+		PushMirrorQueue.Add(id)
+	}
+}
+
+// PushMirrorUpdate checks and queues the push mirrors due to be synced.
+func PushMirrorUpdate() {
+	if !taskStatusTable.StartIfNotRunning(pushMirrorUpdate) {
+		return
+	}
+	defer taskStatusTable.Stop(pushMirrorUpdate)
+
+	log.Trace("Doing: PushMirrorUpdate")
+
+	if err := x.Iterate(new(PushMirror), func(idx int, bean interface{}) error {
+		m := bean.(*PushMirror)
+		if m.isDue() {
+			PushMirrorQueue.Add(m.ID)
+		}
+		return nil
+	}); err != nil {
+		log.Error(4, "PushMirrorUpdate: %v", err)
+	}
+}
+
+// SyncPushMirrors pushes the git data of repositories to the queued push mirrors.
+func SyncPushMirrors() {
+	for id := range PushMirrorQueue.Queue() {
+		log.Trace("SyncPushMirrors [id: %v]", id)
+		PushMirrorQueue.Remove(id)
+
+		m := &PushMirror{ID: com.StrTo(id).MustInt64()}
+		if has, err := x.Get(m); err != nil {
+			log.Error(4, "GetPushMirrorByID [%s]: %v", id, err)
+			continue
+		} else if !has {
+			// the push mirror has been removed in the meantime
+			continue
+		}
+		if err := m.loadRepo(x); err != nil {
+			log.Error(4, "GetRepositoryByID [%d]: %v", m.RepoID, err)
+			continue
+		}
+
+		if err := m.runSync(); err != nil {
+			log.Error(4, "Update push mirror [%s]: %v", id, err)
+		}
+	}
+}
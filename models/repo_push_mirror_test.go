@@ -0,0 +1,51 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushMirror_isDue(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		interval   time.Duration
+		lastUpdate time.Time
+		expected   bool
+	}{
+		{0, time.Unix(0, 0), false},
+		{time.Hour, time.Unix(0, 0), true},
+		{time.Hour, now.Add(-2 * time.Hour), true},
+		{time.Hour, now.Add(-30 * time.Minute), false},
+	} {
+		m := &PushMirror{Interval: test.interval, LastUpdateUnix: test.lastUpdate.Unix()}
+		assert.Equal(t, test.expected, m.isDue())
+	}
+}
+
+func TestGetPushMirrorByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	m := &PushMirror{RepoID: 1, RemoteName: "remote_mirror_test", Interval: time.Hour}
+	AssertSuccessfulInsert(t, m)
+
+	m, err := GetPushMirrorByID(1, m.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, m.Repo.ID)
+	assert.Equal(t, "remote_mirror_test", m.RemoteName)
+
+	// push mirrors of other repositories are not found
+	_, err = GetPushMirrorByID(2, m.ID)
+	assert.True(t, IsErrPushMirrorNotExist(err))
+
+	mirrors, err := GetPushMirrorsByRepoID(1)
+	assert.NoError(t, err)
+	assert.Len(t, mirrors, 1)
+
+	assert.NoError(t, UpdatePushMirrorInterval(m, 0))
+	AssertExistsAndLoadBean(t, &PushMirror{ID: m.ID}, Cond("`interval` = ?", 0))
+}
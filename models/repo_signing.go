@@ -0,0 +1,271 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+// Signing rules of commits created by Gitea
+const (
+	SigningRuleNever     = "never"
+	SigningRuleAlways    = "always"
+	SigningRulePubkey    = "pubkey"
+	SigningRuleProtected = "protected"
+)
+
+func gitConfigValue(name string) string {
+	stdout, err := git.NewCommand("config", "--get", name).Run()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(stdout)
+}
+
+// signingKey returns the GPG key commits created by Gitea are signed with,
+// or an empty string if signing is disabled.
+func signingKey() string {
+	switch key := setting.Repository.Signing.SigningKey; key {
+	case "", "none":
+		return ""
+	case "default":
+		return gitConfigValue("user.signingkey")
+	default:
+		return key
+	}
+}
+
+// signingSig returns the committer of commits signed by Gitea.
+func signingSig() *git.Signature {
+	sig := &git.Signature{
+		Name:  setting.Repository.Signing.SigningName,
+		Email: setting.Repository.Signing.SigningEmail,
+		When:  time.Now(),
+	}
+	if len(sig.Name) == 0 {
+		sig.Name = gitConfigValue("user.name")
+	}
+	if len(sig.Name) == 0 {
+		sig.Name = setting.AppName
+	}
+	if len(sig.Email) == 0 {
+		sig.Email = gitConfigValue("user.email")
+	}
+	if len(sig.Email) == 0 {
+		sig.Email = "gitea@" + setting.Service.NoReplyAddress
+	}
+	return sig
+}
+
+// signingRulesSatisfied returns true if a commit of given user to given branch
+// meets all the signing rules. Branches are never protected when repo is nil.
+func signingRulesSatisfied(rules []string, u *User, repo *Repository, branch string) bool {
+	for _, rule := range rules {
+		switch rule {
+		case SigningRuleNever:
+			return false
+		case SigningRuleAlways:
+			return true
+		case SigningRulePubkey:
+			if u == nil {
+				return false
+			}
+			keys, err := ListGPGKeys(u.ID)
+			if err != nil {
+				log.Error(4, "ListGPGKeys: %v", err)
+				return false
+			} else if len(keys) == 0 {
+				return false
+			}
+		case SigningRuleProtected:
+			if repo == nil {
+				return false
+			}
+			protected, err := repo.IsProtectedBranch(branch)
+			if err != nil {
+				log.Error(4, "IsProtectedBranch: %v", err)
+				return false
+			} else if !protected {
+				return false
+			}
+		default:
+			log.Warn("Unknown signing rule: %s", rule)
+		}
+	}
+	return true
+}
+
+// signInitialCommit returns the key to sign the initial commit of a repository
+// owned by given user with, or an empty string if it is not signed.
+func signInitialCommit(u *User) string {
+	if !signingRulesSatisfied(setting.Repository.Signing.InitialCommit, u, nil, "") {
+		return ""
+	}
+	return signingKey()
+}
+
+// signWikiCommit returns the key to sign a wiki commit of given user with,
+// or an empty string if it is not signed.
+func (repo *Repository) signWikiCommit(u *User) string {
+	if !signingRulesSatisfied(setting.Repository.Signing.Wiki, u, nil, "") {
+		return ""
+	}
+	return signingKey()
+}
+
+// signCRUDAction returns the key to sign a web editor commit of given user to
+// given branch with, or an empty string if it is not signed.
+func (repo *Repository) signCRUDAction(u *User, branch string) string {
+	if !signingRulesSatisfied(setting.Repository.Signing.CRUDActions, u, repo, branch) {
+		return ""
+	}
+	return signingKey()
+}
+
+// signMerge returns the key to sign the merge of the pull request by given user
+// with, or an empty string if it is not signed.
+func (pr *PullRequest) signMerge(u *User) string {
+	if !signingRulesSatisfied(setting.Repository.Signing.Merges, u, pr.BaseRepo, pr.BaseBranch) {
+		return ""
+	}
+	return signingKey()
+}
+
+// signedCommitChangesOptions returns the options to commit changes of given user,
+// with Gitea as committer if the commit is signed with given key.
+func signedCommitChangesOptions(u *User, message, key string) gitutil.CommitChangesOptions {
+	if len(key) == 0 {
+		return gitutil.CommitChangesOptions{
+			Committer: u.NewGitSig(),
+			Message:   message,
+		}
+	}
+	return gitutil.CommitChangesOptions{
+		Committer:  signingSig(),
+		Author:     u.NewGitSig(),
+		Message:    message,
+		SigningKey: key,
+	}
+}
+
+// crudCommitChangesOptions returns the options to commit changes of given user
+// to given branch, authored and committed by given signatures if not nil. The
+// committer is ignored when the commit is signed by Gitea.
+func (repo *Repository) crudCommitChangesOptions(u *User, author, committer *git.Signature, message, branch string) gitutil.CommitChangesOptions {
+	opts := signedCommitChangesOptions(u, message, repo.signCRUDAction(u, branch))
+	if author != nil {
+		opts.Author = author
+	}
+	if committer != nil && len(opts.SigningKey) == 0 {
+		opts.Committer = committer
+	}
+	return opts
+}
+
+// commitArgs returns the arguments of git to commit the changes authored by
+// given signature, signed with given key and with Gitea as committer if not empty.
+func commitArgs(author *git.Signature, message, key string) []string {
+	args := make([]string, 0, 10)
+	if len(key) > 0 {
+		sig := signingSig()
+		args = append(args, "-c", "user.name="+sig.Name, "-c", "user.email="+sig.Email)
+	}
+	args = append(args, "commit", fmt.Sprintf("--author='%s <%s>'", author.Name, author.Email))
+	if len(key) > 0 {
+		args = append(args, "-S"+key)
+	} else {
+		args = append(args, "--no-gpg-sign")
+	}
+	return append(args, "-m", message)
+}
+
+var instancePublicKey = struct {
+	sync.Mutex
+	id  string
+	key *GPGKey
+}{}
+
+// getInstancePublicKey returns the public part of the key commits created by
+// Gitea are signed with, exported from the gpg keyring.
+func getInstancePublicKey(id string) (*GPGKey, error) {
+	instancePublicKey.Lock()
+	defer instancePublicKey.Unlock()
+	if instancePublicKey.id == id {
+		return instancePublicKey.key, nil
+	}
+
+	stdout, stderr, err := process.GetManager().Exec(fmt.Sprintf("getInstancePublicKey: %s", id),
+		"gpg", "--armor", "--export", id)
+	if err != nil {
+		return nil, fmt.Errorf("gpg --export: %v - %s", err, stderr)
+	}
+	ekeys, err := openpgp.ReadArmoredKeyRing(strings.NewReader(stdout))
+	if err != nil {
+		return nil, err
+	} else if len(ekeys) == 0 {
+		return nil, fmt.Errorf("no key found for %s", id)
+	}
+
+	pubkey := ekeys[0].PrimaryKey
+	key, err := parseSubGPGKey(0, "", pubkey, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range ekeys[0].Subkeys {
+		sub, err := parseSubGPGKey(0, pubkey.KeyIdString(), k.PublicKey, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		key.SubsKey = append(key.SubsKey, sub)
+	}
+
+	instancePublicKey.id = id
+	instancePublicKey.key = key
+	return key, nil
+}
+
+// verifyInstanceSignature checks if the commit is signed with the key of Gitea,
+// it returns nil if it is not.
+func verifyInstanceSignature(c *git.Commit, sig *packet.Signature) *CommitVerification {
+	id := signingKey()
+	if len(id) == 0 {
+		return nil
+	}
+	key, err := getInstancePublicKey(id)
+	if err != nil {
+		log.Error(4, "getInstancePublicKey: %v", err)
+		return nil
+	}
+
+	for _, k := range append([]*GPGKey{key}, key.SubsKey...) {
+		hash, err := populateHash(sig.Hash, []byte(c.Signature.Payload))
+		if err != nil {
+			log.Error(4, "PopulateHash: %v", err)
+			return nil
+		}
+		if err := verifySign(sig, hash, k); err == nil {
+			signer := signingSig()
+			return &CommitVerification{
+				Verified:    true,
+				Reason:      fmt.Sprintf("%s <%s> / %s", signer.Name, signer.Email, k.KeyID),
+				SigningUser: &User{Name: signer.Name, Email: signer.Email},
+				SigningKey:  k,
+			}
+		}
+	}
+	return nil
+}
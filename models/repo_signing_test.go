@@ -0,0 +1,98 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningRulesSatisfied(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	assert.True(t, signingRulesSatisfied([]string{}, user, repo, "master"))
+	assert.True(t, signingRulesSatisfied([]string{SigningRuleAlways}, nil, nil, ""))
+	assert.False(t, signingRulesSatisfied([]string{SigningRuleNever, SigningRuleAlways}, user, repo, "master"))
+	assert.False(t, signingRulesSatisfied([]string{SigningRulePubkey}, user, repo, "master"))
+	assert.False(t, signingRulesSatisfied([]string{SigningRuleProtected}, user, repo, "master"))
+
+	_, err := x.Insert(&GPGKey{OwnerID: user.ID, KeyID: "0123456789ABCDEF", Content: "key"})
+	assert.NoError(t, err)
+	assert.True(t, signingRulesSatisfied([]string{SigningRulePubkey}, user, repo, "master"))
+	assert.False(t, signingRulesSatisfied([]string{SigningRulePubkey, SigningRuleProtected}, user, repo, "master"))
+
+	_, err = x.Insert(&ProtectedBranch{RepoID: repo.ID, BranchName: "master"})
+	assert.NoError(t, err)
+	assert.True(t, signingRulesSatisfied([]string{SigningRulePubkey, SigningRuleProtected}, user, repo, "master"))
+	assert.False(t, signingRulesSatisfied([]string{SigningRuleProtected}, user, repo, "develop"))
+	assert.False(t, signingRulesSatisfied([]string{SigningRuleProtected}, user, nil, "master"))
+}
+
+func TestParseCommitWithSignature_Instance(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not found")
+	}
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	tmpDir, err := ioutil.TempDir("", "gitea-signing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	gnupgHome := filepath.Join(tmpDir, "gnupg")
+	assert.NoError(t, os.Mkdir(gnupgHome, 0700))
+	oldGnupgHome := os.Getenv("GNUPGHOME")
+	os.Setenv("GNUPGHOME", gnupgHome)
+	defer os.Setenv("GNUPGHOME", oldGnupgHome)
+
+	out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key",
+		"Gitea <gitea@example.com>", "default", "default", "never").CombinedOutput()
+	if !assert.NoError(t, err, string(out)) {
+		return
+	}
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+
+	oldSigning := setting.Repository.Signing
+	defer func() { setting.Repository.Signing = oldSigning }()
+	setting.Repository.Signing.SigningKey = "gitea@example.com"
+	setting.Repository.Signing.SigningName = "Gitea"
+	setting.Repository.Signing.SigningEmail = "gitea@example.com"
+
+	repoPath := filepath.Join(tmpDir, "repo")
+	assert.NoError(t, git.InitRepository(repoPath, false))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, "README.md"), []byte("signed"), 0644))
+	assert.NoError(t, git.AddChanges(repoPath, true))
+	assert.NoError(t, gitutil.CommitChanges(repoPath, signedCommitChangesOptions(user, "Signed commit", signingKey())))
+
+	gitRepo, err := git.OpenRepository(repoPath)
+	assert.NoError(t, err)
+	commit, err := gitRepo.GetBranchCommit("master")
+	assert.NoError(t, err)
+	assert.Equal(t, "gitea@example.com", commit.Committer.Email)
+	assert.Equal(t, user.Email, commit.Author.Email)
+
+	verification := ParseCommitWithSignature(commit)
+	assert.True(t, verification.Verified)
+	assert.EqualValues(t, 0, verification.SigningUser.ID)
+	assert.Equal(t, "Gitea", verification.SigningUser.Name)
+	assert.NoError(t, VerifyCommits([]*git.Commit{commit}))
+
+	// without a signing key, the commit can not be verified anymore
+	setting.Repository.Signing.SigningKey = "none"
+	verification = ParseCommitWithSignature(commit)
+	assert.False(t, verification.Verified)
+	assert.True(t, IsErrUnverifiedCommit(VerifyCommits([]*git.Commit{commit})))
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/gitutil"
+)
+
+// CreateTagOptions holds the options to create a tag in a repository
+type CreateTagOptions struct {
+	TagName string
+	// Target is the branch or commit to tag
+	Target string
+	// Message makes the tag an annotated tag if not empty, tagged by the doer
+	Message string
+}
+
+// CreateTag creates a tag in the repository, it returns the push of the tag
+// to be notified to the watchers and webhooks.
+func (repo *Repository) CreateTag(doer *User, opts CreateTagOptions) (*Push, error) {
+	// Names starting with '-' would be taken for options of the git commands.
+	if strings.HasPrefix(opts.TagName, "-") || !gitutil.IsValidRefName(git.TagPrefix+opts.TagName) {
+		return nil, ErrInvalidTagName{opts.TagName}
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	if gitRepo.IsTagExist(opts.TagName) {
+		return nil, ErrTagAlreadyExists{opts.TagName}
+	}
+
+	var commit *git.Commit
+	if strings.HasPrefix(opts.Target, "-") {
+		return nil, git.ErrNotExist{ID: opts.Target}
+	} else if gitRepo.IsBranchExist(opts.Target) {
+		commit, err = gitRepo.GetBranchCommit(opts.Target)
+	} else if tp, _ := gitutil.GetObjectType(gitRepo, opts.Target); tp == git.ObjectCommit {
+		commit, err = gitRepo.GetCommit(opts.Target)
+	} else {
+		return nil, git.ErrNotExist{ID: opts.Target}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetCommit: %v", err)
+	}
+
+	if len(opts.Message) > 0 {
+		err = gitutil.CreateAnnotatedTag(gitRepo, opts.TagName, opts.Message, commit.ID.String(), doer.NewGitSig())
+	} else {
+		err = gitRepo.CreateTag(opts.TagName, commit.ID.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CreateTag: %v", err)
+	}
+
+	return CommitRepoAction(CommitRepoActionOptions{
+		PusherName:  doer.Name,
+		RepoOwnerID: repo.OwnerID,
+		RepoName:    repo.Name,
+		RefFullName: git.TagPrefix + opts.TagName,
+		OldCommitID: git.EmptySHA,
+		NewCommitID: commit.ID.String(),
+		Commits:     &PushCommits{},
+	})
+}
+
+// DeleteTag deletes a tag of the repository, tags of releases have to be
+// deleted along with their release.
+func (repo *Repository) DeleteTag(doer *User, tagName string) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !gitRepo.IsTagExist(tagName) {
+		return ErrTagNotExist{tagName}
+	}
+
+	if has, err := IsReleaseExist(repo.ID, tagName); err != nil {
+		return fmt.Errorf("IsReleaseExist: %v", err)
+	} else if has {
+		return ErrReleaseAlreadyExist{tagName}
+	}
+
+	if err = gitutil.DeleteTag(gitRepo, tagName); err != nil {
+		return fmt.Errorf("DeleteTag: %v", err)
+	}
+	return PrepareDeleteRefWebhooks(doer, repo, git.TagPrefix+tagName)
+}
@@ -123,7 +123,7 @@ func TestForkRepository(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, repo)
 
-	repo, err = ForkRepository(&User{ID: 13}, repo, "test", "test")
+	repo, err = ForkRepository(&User{ID: 13}, &User{ID: 13}, repo, "test", "test")
 	assert.Nil(t, repo)
 	assert.Error(t, err)
 	assert.True(t, IsErrRepoAlreadyExist(err))
@@ -72,7 +72,8 @@ func (cfg *ExternalTrackerConfig) ToDB() ([]byte, error) {
 
 // IssuesConfig describes issues config
 type IssuesConfig struct {
-	EnableTimetracker bool
+	EnableTimetracker  bool
+	EnableDependencies bool
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -85,14 +86,52 @@ func (cfg *IssuesConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// PullRequestsConfig describes pull requests config
+type PullRequestsConfig struct {
+	AllowMerge       bool
+	AllowRebase      bool
+	AllowRebaseMerge bool
+	AllowSquash      bool
+}
+
+// FromDB fills up a PullRequestsConfig from serialized format.
+func (cfg *PullRequestsConfig) FromDB(bs []byte) error {
+	return json.Unmarshal(bs, &cfg)
+}
+
+// ToDB exports a PullRequestsConfig to a serialized format.
+func (cfg *PullRequestsConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
+// IsMergeStyleAllowed returns if merge style is allowed
+func (cfg *PullRequestsConfig) IsMergeStyleAllowed(mergeStyle MergeStyle) bool {
+	return mergeStyle == MergeStyleMerge && cfg.AllowMerge ||
+		mergeStyle == MergeStyleRebase && cfg.AllowRebase ||
+		mergeStyle == MergeStyleRebaseMerge && cfg.AllowRebaseMerge ||
+		mergeStyle == MergeStyleSquash && cfg.AllowSquash
+}
+
+// AllowedMergeStyles returns the merge styles allowed by the config
+func (cfg *PullRequestsConfig) AllowedMergeStyles() []MergeStyle {
+	styles := make([]MergeStyle, 0, 4)
+	for _, style := range []MergeStyle{MergeStyleMerge, MergeStyleRebase, MergeStyleRebaseMerge, MergeStyleSquash} {
+		if cfg.IsMergeStyleAllowed(style) {
+			styles = append(styles, style)
+		}
+	}
+	return styles
+}
+
 // BeforeSet is invoked from XORM before setting the value of a field of this object.
 func (r *RepoUnit) BeforeSet(colName string, val xorm.Cell) {
 	switch colName {
 	case "type":
 		switch UnitType(Cell2Int64(val)) {
-		case UnitTypeCode, UnitTypePullRequests, UnitTypeReleases,
-			UnitTypeWiki:
+		case UnitTypeCode, UnitTypeReleases, UnitTypeWiki, UnitTypeProjects:
 			r.Config = new(UnitConfig)
+		case UnitTypePullRequests:
+			r.Config = new(PullRequestsConfig)
 		case UnitTypeExternalWiki:
 			r.Config = new(ExternalWikiConfig)
 		case UnitTypeExternalTracker:
@@ -124,8 +163,8 @@ func (r *RepoUnit) CodeConfig() *UnitConfig {
 }
 
 // PullRequestsConfig returns config for UnitTypePullRequests
-func (r *RepoUnit) PullRequestsConfig() *UnitConfig {
-	return r.Config.(*UnitConfig)
+func (r *RepoUnit) PullRequestsConfig() *PullRequestsConfig {
+	return r.Config.(*PullRequestsConfig)
 }
 
 // ReleasesConfig returns config for UnitTypeReleases
@@ -0,0 +1,574 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+
+	"github.com/go-xorm/builder"
+	"github.com/go-xorm/xorm"
+
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ReviewType defines the sort of feedback a review gives
+type ReviewType int
+
+// ReviewTypeUnknown unknown review type
+const ReviewTypeUnknown ReviewType = -1
+
+const (
+	// ReviewTypePending is a review which was not published yet
+	ReviewTypePending ReviewType = iota
+	// ReviewTypeApprove approves changes
+	ReviewTypeApprove
+	// ReviewTypeComment gives general feedback
+	ReviewTypeComment
+	// ReviewTypeReject gives feedback blocking merge
+	ReviewTypeReject
+)
+
+// Icon returns the corresponding icon for the review type
+func (rt ReviewType) Icon() string {
+	switch rt {
+	case ReviewTypeApprove:
+		return "eye"
+	case ReviewTypeReject:
+		return "x"
+	case ReviewTypeComment:
+		return "comment"
+	default:
+		return "comment"
+	}
+}
+
+// APIFormat converts the review type to its API state
+func (rt ReviewType) APIFormat() api.ReviewStateType {
+	switch rt {
+	case ReviewTypePending:
+		return api.ReviewStatePending
+	case ReviewTypeApprove:
+		return api.ReviewStateApproved
+	case ReviewTypeComment:
+		return api.ReviewStateComment
+	case ReviewTypeReject:
+		return api.ReviewStateRequestChanges
+	default:
+		return api.ReviewStateUnknown
+	}
+}
+
+// ToReviewType converts an API review state into a review type,
+// ReviewTypeUnknown is returned for invalid states.
+func ToReviewType(state api.ReviewStateType) ReviewType {
+	switch api.ReviewStateType(strings.ToUpper(string(state))) {
+	case api.ReviewStatePending:
+		return ReviewTypePending
+	case api.ReviewStateApproved:
+		return ReviewTypeApprove
+	case api.ReviewStateComment:
+		return ReviewTypeComment
+	case api.ReviewStateRequestChanges:
+		return ReviewTypeReject
+	default:
+		return ReviewTypeUnknown
+	}
+}
+
+// Review represents collection of code comments giving feedback for a PR
+type Review struct {
+	ID         int64 `xorm:"pk autoincr"`
+	Type       ReviewType
+	Reviewer   *User  `xorm:"-"`
+	ReviewerID int64  `xorm:"INDEX"`
+	Issue      *Issue `xorm:"-"`
+	IssueID    int64  `xorm:"INDEX"`
+	Content    string `xorm:"TEXT"`
+	// CommitID is the head commit of the pull request the review was submitted for
+	CommitID string `xorm:"VARCHAR(40)"`
+
+	Created     time.Time `xorm:"-"`
+	CreatedUnix int64     `xorm:"INDEX"`
+	Updated     time.Time `xorm:"-"`
+	UpdatedUnix int64     `xorm:"INDEX"`
+
+	// CodeComments are the inline comments of the review
+	CodeComments []*Comment `xorm:"-"`
+}
+
+// BeforeInsert will be invoked by XORM before inserting a record
+// representing this object.
+func (r *Review) BeforeInsert() {
+	r.CreatedUnix = time.Now().Unix()
+	r.UpdatedUnix = r.CreatedUnix
+}
+
+// BeforeUpdate is invoked from XORM before updating this object.
+func (r *Review) BeforeUpdate() {
+	r.UpdatedUnix = time.Now().Unix()
+}
+
+// AfterSet is invoked from XORM after setting the value of a field of this object.
+func (r *Review) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		r.Created = time.Unix(r.CreatedUnix, 0).Local()
+	case "updated_unix":
+		r.Updated = time.Unix(r.UpdatedUnix, 0).Local()
+	}
+}
+
+func (r *Review) loadCodeComments(e Engine) (err error) {
+	if r.CodeComments == nil {
+		r.CodeComments, err = findComments(e, FindCommentsOptions{
+			ReviewID: r.ID,
+			Type:     CommentTypeCode,
+		})
+	}
+	return err
+}
+
+// LoadCodeComments loads the inline comments of the review
+func (r *Review) LoadCodeComments() error {
+	return r.loadCodeComments(x)
+}
+
+func (r *Review) loadIssue(e Engine) (err error) {
+	if r.Issue == nil {
+		r.Issue, err = getIssueByID(e, r.IssueID)
+	}
+	return err
+}
+
+func (r *Review) loadReviewer(e Engine) (err error) {
+	if r.Reviewer == nil && r.ReviewerID > 0 {
+		r.Reviewer, err = getUserByID(e, r.ReviewerID)
+		if IsErrUserNotExist(err) {
+			r.ReviewerID = -1
+			r.Reviewer = NewGhostUser()
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *Review) loadAttributes(e Engine) (err error) {
+	if err = r.loadReviewer(e); err != nil {
+		return err
+	}
+	if err = r.loadIssue(e); err != nil {
+		return err
+	}
+	return r.loadCodeComments(e)
+}
+
+// LoadAttributes loads all attributes except CodeComments
+func (r *Review) LoadAttributes() error {
+	return r.loadAttributes(x)
+}
+
+// HTMLURL returns the link to the review summary in the pull request timeline.
+// The issue has to be loaded.
+func (r *Review) HTMLURL() string {
+	return fmt.Sprintf("%s#pullrequestreview-%d", r.Issue.HTMLURL(), r.ID)
+}
+
+// APIFormat converts a review into its API representation,
+// attributes have to be loaded.
+func (r *Review) APIFormat() *api.PullReview {
+	return &api.PullReview{
+		ID:                r.ID,
+		Reviewer:          r.Reviewer.APIFormat(),
+		State:             r.Type.APIFormat(),
+		Body:              r.Content,
+		CommitID:          r.CommitID,
+		CodeCommentsCount: len(r.CodeComments),
+		Submitted:         r.Updated,
+		HTMLURL:           r.HTMLURL(),
+		HTMLPullURL:       r.Issue.HTMLURL(),
+	}
+}
+
+// APIFormatReviewComment converts a code comment of the review into its API
+// representation, attributes of the review have to be loaded.
+func (r *Review) APIFormatReviewComment(c *Comment) *api.PullReviewComment {
+	apiComment := &api.PullReviewComment{
+		ID:          c.ID,
+		Body:        c.Content,
+		Reviewer:    c.Poster.APIFormat(),
+		ReviewID:    r.ID,
+		Created:     c.Created,
+		Updated:     c.Updated,
+		Path:        c.TreePath,
+		CommitID:    c.CommitSHA,
+		IsOutdated:  c.Invalidated,
+		HTMLURL:     fmt.Sprintf("%s#%s", r.Issue.HTMLURL(), c.HashTag()),
+		HTMLPullURL: r.Issue.HTMLURL(),
+	}
+	if c.Line < 0 {
+		apiComment.OldLineNum = c.UnsignedLine()
+	} else {
+		apiComment.LineNum = c.UnsignedLine()
+	}
+	return apiComment
+}
+
+func getReviewByID(e Engine, id int64) (*Review, error) {
+	review := new(Review)
+	if has, err := e.Id(id).Get(review); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReviewNotExist{ID: id}
+	}
+	return review, nil
+}
+
+// GetReviewByID returns the review by the given ID
+func GetReviewByID(id int64) (*Review, error) {
+	return getReviewByID(x, id)
+}
+
+// FindReviewOptions represent possible filters to find reviews
+type FindReviewOptions struct {
+	Type       ReviewType
+	IssueID    int64
+	ReviewerID int64
+}
+
+func (opts *FindReviewOptions) toCond() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	if opts.ReviewerID > 0 {
+		cond = cond.And(builder.Eq{"reviewer_id": opts.ReviewerID})
+	}
+	if opts.Type != ReviewTypeUnknown {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	return cond
+}
+
+func findReviews(e Engine, opts FindReviewOptions) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	return reviews, e.Where(opts.toCond()).
+		Asc("created_unix").
+		Find(&reviews)
+}
+
+// FindReviews returns reviews passing FindReviewOptions
+func FindReviews(opts FindReviewOptions) ([]*Review, error) {
+	return findReviews(x, opts)
+}
+
+// GetReviewsByIssueID returns all submitted reviews of a pull request,
+// pending reviews are excluded.
+func GetReviewsByIssueID(issueID int64) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	return reviews, x.Where("issue_id = ? AND type != ?", issueID, ReviewTypePending).
+		Asc("created_unix").
+		Find(&reviews)
+}
+
+// CreateReviewOptions represent the options to create a review. Type, Issue and Reviewer are required.
+type CreateReviewOptions struct {
+	Content  string
+	Type     ReviewType
+	Issue    *Issue
+	Reviewer *User
+	CommitID string
+}
+
+func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
+	review := &Review{
+		Type:       opts.Type,
+		Issue:      opts.Issue,
+		IssueID:    opts.Issue.ID,
+		Reviewer:   opts.Reviewer,
+		ReviewerID: opts.Reviewer.ID,
+		Content:    opts.Content,
+		CommitID:   opts.CommitID,
+	}
+	if _, err := e.Insert(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// CreateReview creates a new review based on opts
+func CreateReview(opts CreateReviewOptions) (*Review, error) {
+	return createReview(x, opts)
+}
+
+func getCurrentReview(e Engine, reviewer *User, issue *Issue) (*Review, error) {
+	if reviewer == nil {
+		return nil, nil
+	}
+	reviews, err := findReviews(e, FindReviewOptions{
+		Type:       ReviewTypePending,
+		IssueID:    issue.ID,
+		ReviewerID: reviewer.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, ErrReviewNotExist{}
+	}
+	reviews[0].Reviewer = reviewer
+	reviews[0].Issue = issue
+	return reviews[0], nil
+}
+
+// GetCurrentReview returns the pending review of reviewer for given issue
+func GetCurrentReview(reviewer *User, issue *Issue) (*Review, error) {
+	return getCurrentReview(x, reviewer, issue)
+}
+
+// UpdateReview updates the type, content and commit of a review
+func UpdateReview(r *Review) error {
+	_, err := x.Id(r.ID).Cols("type", "content", "commit_id").Update(r)
+	return err
+}
+
+// SubmitReview publishes the pending review of doer for the pull request, or
+// creates a new one if doer has none. A review comment is added to the timeline.
+func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content string) (*Review, *Comment, error) {
+	if reviewType == ReviewTypePending || reviewType == ReviewTypeUnknown {
+		return nil, nil, fmt.Errorf("invalid review type: %d", reviewType)
+	}
+	if reviewType != ReviewTypeComment && issue.PosterID == doer.ID {
+		return nil, nil, ErrReviewOwnPullRequest{IssueID: issue.ID}
+	}
+
+	if err := issue.loadRepo(x); err != nil {
+		return nil, nil, err
+	}
+	if err := issue.loadPullRequest(x); err != nil {
+		return nil, nil, err
+	}
+	commitID, err := issue.PullRequest.GetHeadCommitID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return nil, nil, err
+	}
+
+	review, err := getCurrentReview(sess, doer, issue)
+	if err != nil {
+		if !IsErrReviewNotExist(err) {
+			return nil, nil, err
+		}
+		review, err = createReview(sess, CreateReviewOptions{
+			Type:     reviewType,
+			Issue:    issue,
+			Reviewer: doer,
+			Content:  content,
+			CommitID: commitID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		review.Type = reviewType
+		review.Content = content
+		review.CommitID = commitID
+		if _, err = sess.Id(review.ID).Cols("type", "content", "commit_id").Update(review); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	comm, err := createComment(sess, &CreateCommentOptions{
+		Type:     CommentTypeReview,
+		Doer:     doer,
+		Repo:     issue.Repo,
+		Issue:    issue,
+		Content:  content,
+		ReviewID: review.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return review, comm, nil
+}
+
+// DeleteReview deletes a pending review together with its code comments
+func DeleteReview(r *Review) error {
+	if r.Type != ReviewTypePending {
+		return fmt.Errorf("only pending reviews can be deleted [id: %d]", r.ID)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Where("review_id = ? AND type = ?", r.ID, CommentTypeCode).Delete(new(Comment)); err != nil {
+		return err
+	}
+	if _, err := sess.Id(r.ID).Delete(new(Review)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// CreateCodeComment creates a comment on a line of the pull request diff. Positive
+// lines refer to the head version of the file, negative lines to its merge base version.
+// Without a pending review of doer the comment is published right away as a review on its own.
+func CreateCodeComment(doer *User, issue *Issue, treePath string, line int64, content string, pendingReview bool) (*Comment, error) {
+	if line == 0 || len(treePath) == 0 {
+		return nil, fmt.Errorf("code comment needs a path and a line")
+	}
+	if err := issue.loadRepo(x); err != nil {
+		return nil, err
+	}
+	if err := issue.loadPullRequest(x); err != nil {
+		return nil, err
+	}
+	pr := issue.PullRequest
+
+	headCommitID, err := pr.GetHeadCommitID()
+	if err != nil {
+		return nil, err
+	}
+	commitID := headCommitID
+	if line < 0 {
+		commitID = pr.MergeBase
+	}
+
+	review, err := GetCurrentReview(doer, issue)
+	if err != nil {
+		if !IsErrReviewNotExist(err) {
+			return nil, err
+		}
+		reviewType := ReviewTypeComment
+		if pendingReview {
+			reviewType = ReviewTypePending
+		}
+		review, err = CreateReview(CreateReviewOptions{
+			Type:     reviewType,
+			Issue:    issue,
+			Reviewer: doer,
+			CommitID: headCommitID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := CreateComment(&CreateCommentOptions{
+		Type:      CommentTypeCode,
+		Doer:      doer,
+		Repo:      issue.Repo,
+		Issue:     issue,
+		Content:   content,
+		LineNum:   line,
+		TreePath:  treePath,
+		CommitSHA: commitID,
+		ReviewID:  review.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Type != ReviewTypePending {
+		// A single comment shows up in the timeline like a submitted review
+		if _, err = CreateComment(&CreateCommentOptions{
+			Type:     CommentTypeReview,
+			Doer:     doer,
+			Repo:     issue.Repo,
+			Issue:    issue,
+			ReviewID: review.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return comment, nil
+}
+
+// fileLine returns the content of the given line of a file at a commit
+func fileLine(repoPath, commitID, treePath string, line uint64) (string, bool, error) {
+	data, err := git.NewCommand("show", commitID+":"+treePath).RunInDirBytes(repoPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "exists on disk, but not in") {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	lines := strings.Split(string(data), "\n")
+	if line == 0 || line > uint64(len(lines)) {
+		return "", false, nil
+	}
+	return lines[line-1], true, nil
+}
+
+// InvalidateCodeComments marks the code comments of the pull request as outdated
+// whose commented line has changed since the comment was made.
+func (pr *PullRequest) InvalidateCodeComments() error {
+	if err := pr.GetBaseRepo(); err != nil {
+		return fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	comments, err := findComments(x, FindCommentsOptions{
+		IssueID: pr.IssueID,
+		Type:    CommentTypeCode,
+	})
+	if err != nil {
+		return fmt.Errorf("findComments: %v", err)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	headCommitID, err := pr.GetHeadCommitID()
+	if err != nil {
+		return err
+	}
+
+	repoPath := pr.BaseRepo.RepoPath()
+	for _, comment := range comments {
+		if comment.Invalidated {
+			continue
+		}
+		currentCommitID := headCommitID
+		if comment.Line < 0 {
+			currentCommitID = pr.MergeBase
+		}
+		if comment.CommitSHA == currentCommitID {
+			continue
+		}
+
+		oldLine, oldExists, err := fileLine(repoPath, comment.CommitSHA, comment.TreePath, comment.UnsignedLine())
+		if err != nil {
+			// The commented commit may be gone after a force push
+			oldExists = false
+		}
+		newLine, newExists, err := fileLine(repoPath, currentCommitID, comment.TreePath, comment.UnsignedLine())
+		if err != nil {
+			return fmt.Errorf("fileLine: %v", err)
+		}
+		if oldExists && newExists && oldLine == newLine {
+			continue
+		}
+
+		comment.Invalidated = true
+		if _, err = x.Id(comment.ID).Cols("invalidated").Update(comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func TestToReviewType(t *testing.T) {
+	assert.Equal(t, ReviewTypeApprove, ToReviewType(api.ReviewStateApproved))
+	assert.Equal(t, ReviewTypeReject, ToReviewType("request_changes"))
+	assert.Equal(t, ReviewTypeComment, ToReviewType(api.ReviewStateComment))
+	assert.Equal(t, ReviewTypePending, ToReviewType(api.ReviewStatePending))
+	assert.Equal(t, ReviewTypeUnknown, ToReviewType("invalid"))
+
+	for _, rt := range []ReviewType{ReviewTypePending, ReviewTypeApprove, ReviewTypeComment, ReviewTypeReject} {
+		assert.Equal(t, rt, ToReviewType(rt.APIFormat()))
+	}
+}
+
+func TestGetReviewByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review, err := GetReviewByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "LGTM", review.Content)
+	assert.Equal(t, ReviewTypeApprove, review.Type)
+
+	_, err = GetReviewByID(100)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestReview_LoadAttributes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review := AssertExistsAndLoadBean(t, &Review{ID: 1}).(*Review)
+	assert.NoError(t, review.LoadAttributes())
+	assert.EqualValues(t, 4, review.Reviewer.ID)
+	assert.EqualValues(t, 3, review.Issue.ID)
+	assert.Len(t, review.CodeComments, 1)
+	assert.EqualValues(t, 5, review.CodeComments[0].ID)
+
+	apiReview := review.APIFormat()
+	assert.Equal(t, api.ReviewStateApproved, apiReview.State)
+	assert.Equal(t, 1, apiReview.CodeCommentsCount)
+
+	apiComment := review.APIFormatReviewComment(review.CodeComments[0])
+	assert.EqualValues(t, 2, apiComment.OldLineNum)
+	assert.EqualValues(t, 0, apiComment.LineNum)
+	assert.Equal(t, "README.md", apiComment.Path)
+}
+
+func TestFindReviews(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviews, err := FindReviews(FindReviewOptions{
+		Type:    ReviewTypeUnknown,
+		IssueID: 3,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 3)
+
+	reviews, err = FindReviews(FindReviewOptions{
+		Type:       ReviewTypeApprove,
+		IssueID:    3,
+		ReviewerID: 4,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 1) {
+		assert.EqualValues(t, 1, reviews[0].ID)
+	}
+
+	reviews, err = GetReviewsByIssueID(3)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 2)
+	for _, review := range reviews {
+		assert.NotEqual(t, ReviewTypePending, review.Type)
+	}
+}
+
+func TestGetCurrentReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	review, err := GetCurrentReview(user5, issue)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, review.ID)
+	assert.Equal(t, ReviewTypePending, review.Type)
+
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	_, err = GetCurrentReview(user4, issue)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestCreateReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	review, err := CreateReview(CreateReviewOptions{
+		Content:  "New Review",
+		Type:     ReviewTypePending,
+		Issue:    issue,
+		Reviewer: user,
+	})
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, Content: "New Review", Type: ReviewTypePending})
+
+	review.Type = ReviewTypeComment
+	review.Content = "Updated Review"
+	assert.NoError(t, UpdateReview(review))
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, Content: "Updated Review", Type: ReviewTypeComment})
+}
+
+func TestSubmitReview_OwnPullRequest(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	poster := AssertExistsAndLoadBean(t, &User{ID: issue.PosterID}).(*User)
+
+	_, _, err := SubmitReview(poster, issue, ReviewTypeApprove, "")
+	assert.True(t, IsErrReviewOwnPullRequest(err))
+	_, _, err = SubmitReview(poster, issue, ReviewTypeReject, "")
+	assert.True(t, IsErrReviewOwnPullRequest(err))
+	_, _, err = SubmitReview(poster, issue, ReviewTypePending, "")
+	assert.Error(t, err)
+}
+
+func TestDeleteReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review := AssertExistsAndLoadBean(t, &Review{ID: 1}).(*Review)
+	assert.Error(t, DeleteReview(review))
+
+	review = AssertExistsAndLoadBean(t, &Review{ID: 2}).(*Review)
+	assert.NoError(t, DeleteReview(review))
+	AssertNotExistsBean(t, &Review{ID: 2})
+	AssertNotExistsBean(t, &Comment{ID: 4})
+	AssertExistsAndLoadBean(t, &Comment{ID: 5})
+}
+
+func TestFetchCodeComments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	res, err := FetchCodeComments(issue, user4)
+	assert.NoError(t, err)
+	assert.Len(t, res["README.md"], 1)
+	assert.Len(t, res["README.md"][-2], 1)
+
+	// the reviewer sees the comments of the own pending review
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	res, err = FetchCodeComments(issue, user5)
+	assert.NoError(t, err)
+	assert.Len(t, res["README.md"], 2)
+	if assert.Len(t, res["README.md"][4], 1) {
+		comment := res["README.md"][4][0]
+		assert.EqualValues(t, 4, comment.ID)
+		assert.EqualValues(t, 4, comment.UnsignedLine())
+		assert.Equal(t, "proposed", comment.DiffSide())
+	}
+}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/go-xorm/xorm"
@@ -13,6 +14,130 @@ import (
 	"code.gitea.io/gitea/modules/base"
 )
 
+// AccessTokenScopeArea represents a group of API endpoints an access token can be granted access to.
+type AccessTokenScopeArea string
+
+// Enumerate all the scope areas of access tokens
+const (
+	AccessTokenScopeAreaRepo  AccessTokenScopeArea = "repo"
+	AccessTokenScopeAreaIssue AccessTokenScopeArea = "issue"
+	AccessTokenScopeAreaOrg   AccessTokenScopeArea = "org"
+	AccessTokenScopeAreaUser  AccessTokenScopeArea = "user"
+	AccessTokenScopeAreaAdmin AccessTokenScopeArea = "admin"
+)
+
+// AccessTokenScopeAreas contains all the scope areas of access tokens in display order.
+var AccessTokenScopeAreas = []AccessTokenScopeArea{
+	AccessTokenScopeAreaRepo,
+	AccessTokenScopeAreaIssue,
+	AccessTokenScopeAreaOrg,
+	AccessTokenScopeAreaUser,
+	AccessTokenScopeAreaAdmin,
+}
+
+// AccessTokenScopeLevel represents the access level granted to a scope area.
+type AccessTokenScopeLevel int
+
+// Enumerate all the scope levels of access tokens, a higher level includes the lower ones
+const (
+	AccessTokenScopeLevelNone AccessTokenScopeLevel = iota
+	AccessTokenScopeLevelRead
+	AccessTokenScopeLevelWrite
+)
+
+// Name returns the name of the scope level used in scopes
+func (l AccessTokenScopeLevel) Name() string {
+	switch l {
+	case AccessTokenScopeLevelRead:
+		return "read"
+	case AccessTokenScopeLevelWrite:
+		return "write"
+	}
+	return ""
+}
+
+// Scope returns the scope granting the level to the given area, e.g. "write:repo".
+func (l AccessTokenScopeLevel) Scope(area AccessTokenScopeArea) string {
+	return l.Name() + ":" + string(area)
+}
+
+// Scopes which are not bound to a scope area
+const (
+	// AccessTokenScopeAll grants full access to the account
+	AccessTokenScopeAll = "all"
+	// AccessTokenScopeDeleteRepo grants to delete repositories
+	AccessTokenScopeDeleteRepo = "delete_repo"
+)
+
+// parseAccessTokenScope splits a scope like "read:repo" into its level and area.
+func parseAccessTokenScope(scope string) (AccessTokenScopeLevel, AccessTokenScopeArea, bool) {
+	fields := strings.SplitN(scope, ":", 2)
+	if len(fields) != 2 {
+		return AccessTokenScopeLevelNone, "", false
+	}
+
+	var level AccessTokenScopeLevel
+	switch fields[0] {
+	case AccessTokenScopeLevelRead.Name():
+		level = AccessTokenScopeLevelRead
+	case AccessTokenScopeLevelWrite.Name():
+		level = AccessTokenScopeLevelWrite
+	default:
+		return AccessTokenScopeLevelNone, "", false
+	}
+
+	area := AccessTokenScopeArea(fields[1])
+	for _, a := range AccessTokenScopeAreas {
+		if a == area {
+			return level, area, true
+		}
+	}
+	return AccessTokenScopeLevelNone, "", false
+}
+
+// NormalizeAccessTokenScopes validates the given scopes and returns them in
+// their canonical order without duplicated or redundant scopes.
+func NormalizeAccessTokenScopes(scopes []string) ([]string, error) {
+	var (
+		levels     = make(map[AccessTokenScopeArea]AccessTokenScopeLevel, len(AccessTokenScopeAreas))
+		deleteRepo bool
+	)
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		switch scope {
+		case "":
+			continue
+		case AccessTokenScopeAll:
+			return []string{AccessTokenScopeAll}, nil
+		case AccessTokenScopeDeleteRepo:
+			deleteRepo = true
+			continue
+		}
+
+		level, area, ok := parseAccessTokenScope(scope)
+		if !ok {
+			return nil, ErrInvalidAccessTokenScope{scope}
+		}
+		if level > levels[area] {
+			levels[area] = level
+		}
+	}
+
+	normalized := make([]string, 0, len(levels)+1)
+	for _, area := range AccessTokenScopeAreas {
+		if level := levels[area]; level > AccessTokenScopeLevelNone {
+			normalized = append(normalized, level.Scope(area))
+		}
+	}
+	if deleteRepo {
+		normalized = append(normalized, AccessTokenScopeDeleteRepo)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidAccessTokenScope{}
+	}
+	return normalized, nil
+}
+
 // AccessToken represents a personal access token.
 type AccessToken struct {
 	ID   int64 `xorm:"pk autoincr"`
@@ -20,6 +145,13 @@ type AccessToken struct {
 	Name string
 	Sha1 string `xorm:"UNIQUE VARCHAR(40)"`
 
+	// Scopes granted to the token, see NormalizeAccessTokenScopes.
+	Scopes []string `xorm:"JSON TEXT"`
+	// RepoIDs restricts the token to the given repositories if not empty.
+	RepoIDs []int64 `xorm:"JSON TEXT"`
+
+	Expires           time.Time `xorm:"-"`
+	ExpiresUnix       int64     `xorm:"INDEX NOT NULL DEFAULT 0"`
 	Created           time.Time `xorm:"-"`
 	CreatedUnix       int64     `xorm:"INDEX"`
 	Updated           time.Time `xorm:"-"` // Note: Updated must below Created for AfterSet.
@@ -41,6 +173,10 @@ func (t *AccessToken) BeforeUpdate() {
 // AfterSet is invoked from XORM after setting the value of a field of this object.
 func (t *AccessToken) AfterSet(colName string, _ xorm.Cell) {
 	switch colName {
+	case "expires_unix":
+		if t.ExpiresUnix > 0 {
+			t.Expires = time.Unix(t.ExpiresUnix, 0).Local()
+		}
 	case "created_unix":
 		t.Created = time.Unix(t.CreatedUnix, 0).Local()
 	case "updated_unix":
@@ -50,10 +186,77 @@ func (t *AccessToken) AfterSet(colName string, _ xorm.Cell) {
 	}
 }
 
+// IsExpired returns true if the token cannot be used anymore.
+func (t *AccessToken) IsExpired() bool {
+	return t.ExpiresUnix > 0 && t.ExpiresUnix <= time.Now().Unix()
+}
+
+// HasScope returns true if the token is granted the given level on the scope area.
+func (t *AccessToken) HasScope(area AccessTokenScopeArea, level AccessTokenScopeLevel) bool {
+	for _, scope := range t.Scopes {
+		if scope == AccessTokenScopeAll {
+			return true
+		}
+		l, a, ok := parseAccessTokenScope(scope)
+		if ok && a == area && l >= level {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllScopes returns true if the token grants full access to the account.
+func (t *AccessToken) HasAllScopes() bool {
+	for _, scope := range t.Scopes {
+		if scope == AccessTokenScopeAll {
+			return true
+		}
+	}
+	return false
+}
+
+// CanDeleteRepo returns true if the token is allowed to delete repositories.
+func (t *AccessToken) CanDeleteRepo() bool {
+	for _, scope := range t.Scopes {
+		if scope == AccessTokenScopeAll || scope == AccessTokenScopeDeleteRepo {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessRepo returns true if the token is not restricted to other repositories.
+func (t *AccessToken) CanAccessRepo(repoID int64) bool {
+	if len(t.RepoIDs) == 0 {
+		return true
+	}
+	for _, id := range t.RepoIDs {
+		if id == repoID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRepositories returns the repositories the token is restricted to.
+func (t *AccessToken) GetRepositories() ([]*Repository, error) {
+	repos := make([]*Repository, 0, len(t.RepoIDs))
+	if len(t.RepoIDs) == 0 {
+		return repos, nil
+	}
+	if err := x.In("id", t.RepoIDs).Asc("id").Find(&repos); err != nil {
+		return nil, err
+	}
+	return repos, RepositoryList(repos).LoadAttributes()
+}
+
 // NewAccessToken creates new access token.
-func NewAccessToken(t *AccessToken) error {
+func NewAccessToken(t *AccessToken) (err error) {
+	if t.Scopes, err = NormalizeAccessTokenScopes(t.Scopes); err != nil {
+		return err
+	}
 	t.Sha1 = base.EncodeSha1(gouuid.NewV4().String())
-	_, err := x.Insert(t)
+	_, err = x.Insert(t)
 	return err
 }
 
@@ -13,18 +13,88 @@ import (
 func TestNewAccessToken(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	token := &AccessToken{
-		UID:  3,
-		Name: "Token C",
+		UID:    3,
+		Name:   "Token C",
+		Scopes: []string{"read:repo", "write:repo", "delete_repo"},
 	}
 	assert.NoError(t, NewAccessToken(token))
-	AssertExistsAndLoadBean(t, token)
+	assert.Equal(t, []string{"write:repo", "delete_repo"}, token.Scopes)
+	AssertExistsAndLoadBean(t, &AccessToken{ID: token.ID, Sha1: token.Sha1})
 
 	invalidToken := &AccessToken{
-		ID:   token.ID, // duplicate
-		UID:  2,
-		Name: "Token F",
+		ID:     token.ID, // duplicate
+		UID:    2,
+		Name:   "Token F",
+		Scopes: []string{"all"},
 	}
 	assert.Error(t, NewAccessToken(invalidToken))
+
+	noScopeToken := &AccessToken{
+		UID:  3,
+		Name: "Token G",
+	}
+	assert.True(t, IsErrInvalidAccessTokenScope(NewAccessToken(noScopeToken)))
+}
+
+func TestNormalizeAccessTokenScopes(t *testing.T) {
+	scopes, err := NormalizeAccessTokenScopes([]string{"write:issue", "", "read:admin", "read:issue", " read:repo "})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read:repo", "write:issue", "read:admin"}, scopes)
+
+	scopes, err = NormalizeAccessTokenScopes([]string{"read:repo", "all"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"all"}, scopes)
+
+	for _, invalid := range [][]string{nil, {""}, {"read"}, {"admin:repo"}, {"write:package"}} {
+		_, err = NormalizeAccessTokenScopes(invalid)
+		assert.True(t, IsErrInvalidAccessTokenScope(err))
+	}
+}
+
+func TestAccessToken_HasScope(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	token := AssertExistsAndLoadBean(t, &AccessToken{ID: 1}).(*AccessToken)
+	assert.True(t, token.HasScope(AccessTokenScopeAreaAdmin, AccessTokenScopeLevelWrite))
+	assert.True(t, token.CanDeleteRepo())
+
+	token = AssertExistsAndLoadBean(t, &AccessToken{ID: 2}).(*AccessToken)
+	assert.True(t, token.HasScope(AccessTokenScopeAreaRepo, AccessTokenScopeLevelRead))
+	assert.False(t, token.HasScope(AccessTokenScopeAreaRepo, AccessTokenScopeLevelWrite))
+	assert.True(t, token.HasScope(AccessTokenScopeAreaIssue, AccessTokenScopeLevelRead))
+	assert.True(t, token.HasScope(AccessTokenScopeAreaIssue, AccessTokenScopeLevelWrite))
+	assert.False(t, token.HasScope(AccessTokenScopeAreaUser, AccessTokenScopeLevelRead))
+	assert.False(t, token.CanDeleteRepo())
+}
+
+func TestAccessToken_CanAccessRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	token := AssertExistsAndLoadBean(t, &AccessToken{ID: 1}).(*AccessToken)
+	assert.True(t, token.CanAccessRepo(1))
+	assert.True(t, token.CanAccessRepo(2))
+	repos, err := token.GetRepositories()
+	assert.NoError(t, err)
+	assert.Empty(t, repos)
+
+	token = AssertExistsAndLoadBean(t, &AccessToken{ID: 2}).(*AccessToken)
+	assert.True(t, token.CanAccessRepo(1))
+	assert.False(t, token.CanAccessRepo(2))
+	repos, err = token.GetRepositories()
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.Equal(t, "user2/repo1", repos[0].FullName())
+	}
+}
+
+func TestAccessToken_IsExpired(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	token := AssertExistsAndLoadBean(t, &AccessToken{ID: 1}).(*AccessToken)
+	assert.False(t, token.IsExpired())
+
+	token = AssertExistsAndLoadBean(t, &AccessToken{ID: 3}).(*AccessToken)
+	assert.True(t, token.IsExpired())
 }
 
 func TestGetAccessTokenBySHA(t *testing.T) {
@@ -72,7 +142,7 @@ func TestUpdateAccessToken(t *testing.T) {
 	token.Name = "Token Z"
 
 	assert.NoError(t, UpdateAccessToken(token))
-	AssertExistsAndLoadBean(t, token)
+	AssertExistsAndLoadBean(t, &AccessToken{ID: token.ID, Name: "Token Z"})
 }
 
 func TestDeleteAccessTokenByID(t *testing.T) {
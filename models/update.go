@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // env keys for git hooks need
@@ -62,13 +63,13 @@ type PushUpdateOptions struct {
 	NewCommitID  string
 }
 
-// PushUpdate must be called for any push actions in order to
-// generates necessary push action history feeds.
-func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
+// PushUpdate must be called for any push actions in order to update the
+// repository, it returns the push to be notified unless a reference was deleted.
+func PushUpdate(opts PushUpdateOptions) (repo *Repository, push *Push, err error) {
 	isNewRef := opts.OldCommitID == git.EmptySHA
 	isDelRef := opts.NewCommitID == git.EmptySHA
 	if isNewRef && isDelRef {
-		return nil, fmt.Errorf("Old and new revisions are both %s", git.EmptySHA)
+		return nil, nil, fmt.Errorf("Old and new revisions are both %s", git.EmptySHA)
 	}
 
 	repoPath := RepoPath(opts.RepoUserName, opts.RepoName)
@@ -76,28 +77,36 @@ func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 	gitUpdate := exec.Command("git", "update-server-info")
 	gitUpdate.Dir = repoPath
 	if err = gitUpdate.Run(); err != nil {
-		return nil, fmt.Errorf("Failed to call 'git update-server-info': %v", err)
+		return nil, nil, fmt.Errorf("Failed to call 'git update-server-info': %v", err)
 	}
 
 	owner, err := GetUserByName(opts.RepoUserName)
 	if err != nil {
-		return nil, fmt.Errorf("GetUserByName: %v", err)
+		return nil, nil, fmt.Errorf("GetUserByName: %v", err)
 	}
 
 	repo, err = GetRepositoryByName(owner.ID, opts.RepoName)
 	if err != nil {
-		return nil, fmt.Errorf("GetRepositoryByName: %v", err)
+		return nil, nil, fmt.Errorf("GetRepositoryByName: %v", err)
 	}
 
 	if isDelRef {
 		log.GitLogger.Info("Reference '%s' has been deleted from '%s/%s' by %s",
 			opts.RefFullName, opts.RepoUserName, opts.RepoName, opts.PusherName)
-		return repo, nil
+
+		pusher, err := GetUserByName(opts.PusherName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetUserByName [%s]: %v", opts.PusherName, err)
+		}
+		if err = PrepareDeleteRefWebhooks(pusher, repo, opts.RefFullName); err != nil {
+			return nil, nil, fmt.Errorf("PrepareDeleteRefWebhooks: %v", err)
+		}
+		return repo, nil, nil
 	}
 
 	gitRepo, err := git.OpenRepository(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("OpenRepository: %v", err)
+		return nil, nil, fmt.Errorf("OpenRepository: %v", err)
 	}
 
 	if err = repo.UpdateSize(); err != nil {
@@ -106,7 +115,7 @@ func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 
 	// Push tags.
 	if strings.HasPrefix(opts.RefFullName, git.TagPrefix) {
-		if err := CommitRepoAction(CommitRepoActionOptions{
+		push, err = CommitRepoAction(CommitRepoActionOptions{
 			PusherName:  opts.PusherName,
 			RepoOwnerID: owner.ID,
 			RepoName:    repo.Name,
@@ -114,15 +123,16 @@ func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 			OldCommitID: opts.OldCommitID,
 			NewCommitID: opts.NewCommitID,
 			Commits:     &PushCommits{},
-		}); err != nil {
-			return nil, fmt.Errorf("CommitRepoAction (tag): %v", err)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("CommitRepoAction (tag): %v", err)
 		}
-		return repo, nil
+		return repo, push, nil
 	}
 
 	newCommit, err := gitRepo.GetCommit(opts.NewCommitID)
 	if err != nil {
-		return nil, fmt.Errorf("gitRepo.GetCommit: %v", err)
+		return nil, nil, fmt.Errorf("gitRepo.GetCommit: %v", err)
 	}
 
 	// Push new branch.
@@ -130,16 +140,16 @@ func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 	if isNewRef {
 		l, err = newCommit.CommitsBeforeLimit(10)
 		if err != nil {
-			return nil, fmt.Errorf("newCommit.CommitsBeforeLimit: %v", err)
+			return nil, nil, fmt.Errorf("newCommit.CommitsBeforeLimit: %v", err)
 		}
 	} else {
 		l, err = newCommit.CommitsBeforeUntil(opts.OldCommitID)
 		if err != nil {
-			return nil, fmt.Errorf("newCommit.CommitsBeforeUntil: %v", err)
+			return nil, nil, fmt.Errorf("newCommit.CommitsBeforeUntil: %v", err)
 		}
 	}
 
-	if err := CommitRepoAction(CommitRepoActionOptions{
+	push, err = CommitRepoAction(CommitRepoActionOptions{
 		PusherName:  opts.PusherName,
 		RepoOwnerID: owner.ID,
 		RepoName:    repo.Name,
@@ -147,8 +157,33 @@ func PushUpdate(opts PushUpdateOptions) (repo *Repository, err error) {
 		OldCommitID: opts.OldCommitID,
 		NewCommitID: opts.NewCommitID,
 		Commits:     ListToPushCommits(l),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("CommitRepoAction (branch): %v", err)
+	}
+
+	if opts.RefFullName == git.BranchPrefix+repo.DefaultBranch {
+		UpdateRepoIndexer(repo)
+	}
+	return repo, push, nil
+}
+
+// PrepareDeleteRefWebhooks adds the delete webhooks of a removed branch or tag to the task queue.
+func PrepareDeleteRefWebhooks(doer *User, repo *Repository, refFullName string) error {
+	refType := "branch"
+	if strings.HasPrefix(refFullName, git.TagPrefix) {
+		refType = "tag"
+	}
+
+	if err := PrepareWebhooks(repo, HookEventDelete, &api.DeletePayload{
+		Ref:        git.RefEndName(refFullName),
+		RefType:    refType,
+		PusherType: api.PusherTypeUser,
+		Repo:       repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
 	}); err != nil {
-		return nil, fmt.Errorf("CommitRepoAction (branch): %v", err)
+		return err
 	}
-	return repo, nil
+	go HookQueue.Add(repo.ID)
+	return nil
 }
@@ -30,12 +30,13 @@ import (
 	"golang.org/x/crypto/pbkdf2"
 
 	"code.gitea.io/git"
-	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // UserType defines the user type
@@ -49,6 +50,49 @@ const (
 	UserTypeOrganization
 )
 
+// VisibleType defines who can see a user or an organization
+type VisibleType int
+
+const (
+	// VisibleTypePublic makes the user or organization visible to everyone
+	VisibleTypePublic VisibleType = iota
+
+	// VisibleTypeLimited makes the user or organization visible to signed in users only
+	VisibleTypeLimited
+
+	// VisibleTypePrivate makes the user visible to itself and the organization to its members only
+	VisibleTypePrivate
+)
+
+// visibleTypeNames maps the visibility types to their names
+var visibleTypeNames = map[VisibleType]string{
+	VisibleTypePublic:  "public",
+	VisibleTypeLimited: "limited",
+	VisibleTypePrivate: "private",
+}
+
+// String returns the name of the visibility type
+func (vt VisibleType) String() string {
+	return visibleTypeNames[vt]
+}
+
+// IsValid returns true if the visibility type is known
+func (vt VisibleType) IsValid() bool {
+	_, ok := visibleTypeNames[vt]
+	return ok
+}
+
+// ParseVisibleType returns the visibility type of given name,
+// false is returned if the name is unknown.
+func ParseVisibleType(name string) (VisibleType, bool) {
+	for vt, vtName := range visibleTypeNames {
+		if vtName == name {
+			return vt, true
+		}
+	}
+	return VisibleTypePublic, false
+}
+
 const syncExternalUsers = "sync_external_users"
 
 var (
@@ -85,6 +129,7 @@ type User struct {
 	LoginSource      int64 `xorm:"NOT NULL DEFAULT 0"`
 	LoginName        string
 	Type             UserType
+	Visibility       VisibleType   `xorm:"NOT NULL DEFAULT 0"`
 	OwnedOrgs        []*User       `xorm:"-"`
 	Orgs             []*User       `xorm:"-"`
 	Repos            []*Repository `xorm:"-"`
@@ -282,9 +327,30 @@ func (u *User) GenerateActivateCode() string {
 	return u.GenerateEmailActivateCode(u.Email)
 }
 
-// CustomAvatarPath returns user custom avatar file path.
-func (u *User) CustomAvatarPath() string {
-	return filepath.Join(setting.AvatarUploadPath, u.Avatar)
+// CustomAvatarRelativePath returns user custom avatar relative path inside the avatars storage.
+func (u *User) CustomAvatarRelativePath() string {
+	return u.Avatar
+}
+
+// HasAvatarFile returns true if the avatar of the user is present in the avatars storage.
+func (u *User) HasAvatarFile() bool {
+	if len(u.Avatar) == 0 {
+		return false
+	}
+	_, err := storage.Avatars.Stat(u.CustomAvatarRelativePath())
+	return err == nil
+}
+
+// saveAvatar encodes the image as PNG and stores it in the avatars storage.
+func saveAvatar(relPath string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("Encode: %v", err)
+	}
+	if _, err := storage.Avatars.Save(relPath, &buf); err != nil {
+		return fmt.Errorf("Save: %v", err)
+	}
+	return nil
 }
 
 // GenerateRandomAvatar generates a random avatar for user.
@@ -305,21 +371,12 @@ func (u *User) generateRandomAvatar(e Engine) error {
 	// NOTICE for random avatar, it still uses id as avatar name, but custom avatar use md5
 	// since random image is not a user's photo, there is no security for enumable
 	u.Avatar = fmt.Sprintf("%d", u.ID)
-	if err = os.MkdirAll(filepath.Dir(u.CustomAvatarPath()), os.ModePerm); err != nil {
-		return fmt.Errorf("MkdirAll: %v", err)
-	}
-	fw, err := os.Create(u.CustomAvatarPath())
-	if err != nil {
-		return fmt.Errorf("Create: %v", err)
-	}
-	defer fw.Close()
-
 	if _, err := e.Id(u.ID).Cols("avatar").Update(u); err != nil {
 		return err
 	}
 
-	if err = png.Encode(fw, img); err != nil {
-		return fmt.Errorf("Encode: %v", err)
+	if err = saveAvatar(u.CustomAvatarRelativePath(), img); err != nil {
+		return err
 	}
 
 	log.Info("New random avatar created: %d", u.ID)
@@ -336,12 +393,12 @@ func (u *User) RelAvatarLink() string {
 
 	switch {
 	case u.UseCustomAvatar:
-		if !com.IsFile(u.CustomAvatarPath()) {
+		if !u.HasAvatarFile() {
 			return base.DefaultAvatarLink()
 		}
 		return setting.AppSubURL + "/avatars/" + u.Avatar
 	case setting.DisableGravatar, setting.OfflineMode:
-		if !com.IsFile(u.CustomAvatarPath()) {
+		if !u.HasAvatarFile() {
 			if err := u.GenerateRandomAvatar(); err != nil {
 				log.Error(3, "GenerateRandomAvatar: %v", err)
 			}
@@ -443,18 +500,8 @@ func (u *User) UploadAvatar(data []byte) error {
 		return fmt.Errorf("updateUser: %v", err)
 	}
 
-	if err := os.MkdirAll(setting.AvatarUploadPath, os.ModePerm); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %v", setting.AvatarUploadPath, err)
-	}
-
-	fw, err := os.Create(u.CustomAvatarPath())
-	if err != nil {
-		return fmt.Errorf("Create: %v", err)
-	}
-	defer fw.Close()
-
-	if err = png.Encode(fw, m); err != nil {
-		return fmt.Errorf("Encode: %v", err)
+	if err = saveAvatar(u.CustomAvatarRelativePath(), m); err != nil {
+		return err
 	}
 
 	return sess.Commit()
@@ -462,10 +509,10 @@ func (u *User) UploadAvatar(data []byte) error {
 
 // DeleteAvatar deletes the user's custom avatar.
 func (u *User) DeleteAvatar() error {
-	log.Trace("DeleteAvatar[%d]: %s", u.ID, u.CustomAvatarPath())
+	log.Trace("DeleteAvatar[%d]: %s", u.ID, u.CustomAvatarRelativePath())
 	if len(u.Avatar) > 0 {
-		if err := os.Remove(u.CustomAvatarPath()); err != nil {
-			return fmt.Errorf("Failed to remove %s: %v", u.CustomAvatarPath(), err)
+		if err := storage.Avatars.Delete(u.CustomAvatarRelativePath()); err != nil {
+			return fmt.Errorf("Failed to remove %s: %v", u.CustomAvatarRelativePath(), err)
 		}
 	}
 
@@ -477,6 +524,13 @@ func (u *User) DeleteAvatar() error {
 	return nil
 }
 
+// IterateUserWithAvatar iterates over all users and organizations having an avatar file.
+func IterateUserWithAvatar(f func(u *User) error) error {
+	return x.Where("avatar != ''").Iterate(new(User), func(idx int, bean interface{}) error {
+		return f(bean.(*User))
+	})
+}
+
 // IsAdminOfRepo returns true if user has admin or higher access of repository.
 func (u *User) IsAdminOfRepo(repo *Repository) bool {
 	has, err := HasAccess(u.ID, repo, AccessModeAdmin)
@@ -500,6 +554,36 @@ func (u *User) IsOrganization() bool {
 	return u.Type == UserTypeOrganization
 }
 
+// IsVisibleTo returns true if the user or organization can be seen by given viewer,
+// viewer is nil for anonymous visitors.
+func (u *User) IsVisibleTo(viewer *User) bool {
+	switch u.Visibility {
+	case VisibleTypePublic:
+		return true
+	case VisibleTypeLimited:
+		return viewer != nil
+	}
+	if viewer == nil {
+		return false
+	}
+	return viewer.IsAdmin || viewer.ID == u.ID || u.IsOrgMember(viewer.ID)
+}
+
+// visibleUserCond returns the condition of the users and organizations that can be
+// seen by given viewer, viewer is nil for anonymous visitors.
+func visibleUserCond(viewer *User) builder.Cond {
+	if viewer == nil {
+		return builder.Eq{"visibility": VisibleTypePublic}
+	} else if viewer.IsAdmin {
+		return builder.NewCond()
+	}
+	return builder.Or(
+		builder.In("visibility", VisibleTypePublic, VisibleTypeLimited),
+		builder.Eq{"id": viewer.ID},
+		builder.In("id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": viewer.ID})),
+	)
+}
+
 // IsUserOrgOwner returns true if user is in the owner team of given organization.
 func (u *User) IsUserOrgOwner(orgID int64) bool {
 	return IsOrganizationOwner(orgID, u.ID)
@@ -737,18 +821,23 @@ func CountUsers() int64 {
 	return countUsers(x)
 }
 
-// Users returns number of users in given page.
-func Users(opts *SearchUserOptions) ([]*User, error) {
+// Users returns the users in given page which can be seen by the searcher,
+// and the total number of them.
+func Users(opts *SearchUserOptions) ([]*User, int64, error) {
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = "name ASC"
 	}
 
+	cond := opts.visibilityCond().And(builder.Eq{"type": UserTypeIndividual})
+	count, err := x.Where(cond).Count(new(User))
+	if err != nil {
+		return nil, 0, fmt.Errorf("Count: %v", err)
+	}
+
 	users := make([]*User, 0, opts.PageSize)
-	sess := x.
+	return users, count, x.
+		Where(cond).
 		Limit(opts.PageSize, (opts.Page-1)*opts.PageSize).
-		Where("type=0")
-
-	return users, sess.
 		OrderBy(opts.OrderBy).
 		Find(&users)
 }
@@ -969,6 +1058,8 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&IssueUser{UID: u.ID},
 		&EmailAddress{UID: u.ID},
 		&UserOpenID{UID: u.ID},
+		&OAuth2Grant{UserID: u.ID},
+		&OAuth2Application{UID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -989,7 +1080,7 @@ func deleteUser(e *xorm.Session, u *User) error {
 	// ***** END: PublicKey *****
 
 	// Clear assignee.
-	if _, err = e.Exec("UPDATE `issue` SET assignee_id=0 WHERE assignee_id=?", u.ID); err != nil {
+	if _, err = e.Delete(&IssueAssignees{AssigneeID: u.ID}); err != nil {
 		return fmt.Errorf("clear assignee: %v", err)
 	}
 
@@ -1013,11 +1104,9 @@ func deleteUser(e *xorm.Session, u *User) error {
 	}
 
 	if len(u.Avatar) > 0 {
-		avatarPath := u.CustomAvatarPath()
-		if com.IsExist(avatarPath) {
-			if err := os.Remove(avatarPath); err != nil {
-				return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
-			}
+		avatarPath := u.CustomAvatarRelativePath()
+		if err := storage.Avatars.Delete(avatarPath); err != nil && !storage.IsErrObjectNotExist(err) {
+			return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
 		}
 	}
 
@@ -1254,10 +1343,20 @@ type SearchUserOptions struct {
 	Keyword  string
 	Type     UserType
 	OrderBy  string
+	Searcher *User // Seeker of the users, nil for anonymous visitors
+	Private  bool  // Include the users the searcher can not see
 	Page     int
 	PageSize int // Can be smaller than or equal to setting.UI.ExplorePagingNum
 }
 
+// visibilityCond returns the condition of the users the searcher can see
+func (opts *SearchUserOptions) visibilityCond() builder.Cond {
+	if opts.Private {
+		return builder.NewCond()
+	}
+	return visibleUserCond(opts.Searcher)
+}
+
 // SearchUserByName takes keyword and part of user name to search,
 // it returns results in given range and number of total results.
 func SearchUserByName(opts *SearchUserOptions) (users []*User, _ int64, _ error) {
@@ -1282,6 +1381,7 @@ func SearchUserByName(opts *SearchUserOptions) (users []*User, _ int64, _ error)
 			builder.Like{"lower_name", opts.Keyword},
 			builder.Like{"LOWER(full_name)", opts.Keyword},
 		),
+		opts.visibilityCond(),
 	)
 
 	count, err := x.Where(cond).Count(new(User))
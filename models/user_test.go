@@ -69,3 +69,67 @@ func TestDeleteUser(t *testing.T) {
 	test(8)
 	test(11)
 }
+
+func TestUser_IsVisibleTo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	for _, u := range []*User{user2, org3} {
+		assert.True(t, u.IsVisibleTo(nil))
+		assert.True(t, u.IsVisibleTo(user5))
+
+		u.Visibility = VisibleTypeLimited
+		assert.False(t, u.IsVisibleTo(nil))
+		assert.True(t, u.IsVisibleTo(user5))
+
+		u.Visibility = VisibleTypePrivate
+		assert.False(t, u.IsVisibleTo(nil))
+		assert.False(t, u.IsVisibleTo(user5))
+		assert.True(t, u.IsVisibleTo(admin))
+	}
+	assert.True(t, user2.IsVisibleTo(user2))
+	assert.True(t, org3.IsVisibleTo(user2))
+}
+
+func TestParseVisibleType(t *testing.T) {
+	for _, vt := range []VisibleType{VisibleTypePublic, VisibleTypeLimited, VisibleTypePrivate} {
+		parsed, ok := ParseVisibleType(vt.String())
+		assert.True(t, ok)
+		assert.Equal(t, vt, parsed)
+	}
+	_, ok := ParseVisibleType("secret")
+	assert.False(t, ok)
+	assert.False(t, VisibleType(3).IsValid())
+}
+
+func TestSearchUserByName_Visibility(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	_, err := x.ID(3).Cols("visibility").Update(&User{Visibility: VisibleTypePrivate})
+	assert.NoError(t, err)
+	_, err = x.ID(6).Cols("visibility").Update(&User{Visibility: VisibleTypeLimited})
+	assert.NoError(t, err)
+
+	testSuccess := func(searcher *User, expectedOrgIDs []int64) {
+		orgs, count, err := SearchUserByName(&SearchUserOptions{
+			Keyword:  "user",
+			Type:     UserTypeOrganization,
+			OrderBy:  "id ASC",
+			Searcher: searcher,
+			PageSize: 10,
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(expectedOrgIDs), count)
+		if assert.Len(t, orgs, len(expectedOrgIDs)) {
+			for i, expectedOrgID := range expectedOrgIDs {
+				assert.EqualValues(t, expectedOrgID, orgs[i].ID)
+			}
+		}
+	}
+	testSuccess(nil, []int64{7})
+	testSuccess(&User{ID: 5}, []int64{6, 7})
+	testSuccess(&User{ID: 4}, []int64{3, 6, 7})
+	testSuccess(&User{ID: 1, IsAdmin: true}, []int64{3, 6, 7})
+}
@@ -16,11 +16,12 @@ import (
 	"github.com/go-xorm/xorm"
 	gouuid "github.com/satori/go.uuid"
 
-	api "code.gitea.io/sdk/gitea"
+	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/modules/httplib"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/sync"
 )
 
@@ -66,9 +67,15 @@ func IsValidHookContentType(name string) bool {
 
 // HookEvents is a set of web hook events
 type HookEvents struct {
-	Create      bool `json:"create"`
-	Push        bool `json:"push"`
-	PullRequest bool `json:"pull_request"`
+	Create       bool `json:"create"`
+	Delete       bool `json:"delete"`
+	Fork         bool `json:"fork"`
+	Issues       bool `json:"issues"`
+	IssueComment bool `json:"issue_comment"`
+	Push         bool `json:"push"`
+	PullRequest  bool `json:"pull_request"`
+	Repository   bool `json:"repository"`
+	Release      bool `json:"release"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -150,6 +157,15 @@ func (w *Webhook) GetSlackHook() *SlackMeta {
 	return s
 }
 
+// GetDiscordHook returns discord metadata
+func (w *Webhook) GetDiscordHook() *DiscordMeta {
+	s := &DiscordMeta{}
+	if err := json.Unmarshal([]byte(w.Meta), s); err != nil {
+		log.Error(4, "webhook.GetDiscordHook(%d): %v", w.ID, err)
+	}
+	return s
+}
+
 // History returns history of webhook by given conditions.
 func (w *Webhook) History(page int) ([]*HookTask, error) {
 	return HookTasks(w.ID, page)
@@ -168,6 +184,30 @@ func (w *Webhook) HasCreateEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Create)
 }
 
+// HasDeleteEvent returns true if hook enabled delete event.
+func (w *Webhook) HasDeleteEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Delete)
+}
+
+// HasForkEvent returns true if hook enabled fork event.
+func (w *Webhook) HasForkEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Fork)
+}
+
+// HasIssuesEvent returns true if hook enabled issues event.
+func (w *Webhook) HasIssuesEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Issues)
+}
+
+// HasIssueCommentEvent returns true if hook enabled issue_comment event.
+func (w *Webhook) HasIssueCommentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.IssueComment)
+}
+
 // HasPushEvent returns true if hook enabled push event.
 func (w *Webhook) HasPushEvent() bool {
 	return w.PushOnly || w.SendEverything ||
@@ -180,17 +220,55 @@ func (w *Webhook) HasPullRequestEvent() bool {
 		(w.ChooseEvents && w.HookEvents.PullRequest)
 }
 
-// EventsArray returns an array of hook events
-func (w *Webhook) EventsArray() []string {
-	events := make([]string, 0, 3)
-	if w.HasCreateEvent() {
-		events = append(events, "create")
+// HasReleaseEvent returns if hook enabled release event.
+func (w *Webhook) HasReleaseEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Release)
+}
+
+// HasRepositoryEvent returns if hook enabled repository event.
+func (w *Webhook) HasRepositoryEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Repository)
+}
+
+func (w *Webhook) eventCheckers() []struct {
+	has func() bool
+	typ HookEventType
+} {
+	return []struct {
+		has func() bool
+		typ HookEventType
+	}{
+		{w.HasCreateEvent, HookEventCreate},
+		{w.HasDeleteEvent, HookEventDelete},
+		{w.HasForkEvent, HookEventFork},
+		{w.HasPushEvent, HookEventPush},
+		{w.HasIssuesEvent, HookEventIssues},
+		{w.HasIssueCommentEvent, HookEventIssueComment},
+		{w.HasPullRequestEvent, HookEventPullRequest},
+		{w.HasRepositoryEvent, HookEventRepository},
+		{w.HasReleaseEvent, HookEventRelease},
 	}
-	if w.HasPushEvent() {
-		events = append(events, "push")
+}
+
+// checkEvent returns true if the hook is interested in the given event.
+func (w *Webhook) checkEvent(event HookEventType) bool {
+	for _, c := range w.eventCheckers() {
+		if c.typ == event {
+			return c.has()
+		}
 	}
-	if w.HasPullRequestEvent() {
-		events = append(events, "pull_request")
+	return false
+}
+
+// EventsArray returns an array of hook events
+func (w *Webhook) EventsArray() []string {
+	events := make([]string, 0, 9)
+	for _, c := range w.eventCheckers() {
+		if c.has() {
+			events = append(events, string(c.typ))
+		}
 	}
 	return events
 }
@@ -314,12 +392,16 @@ const (
 	GOGS HookTaskType = iota + 1
 	SLACK
 	GITEA
+	DISCORD
+	MSTEAMS
 )
 
 var hookTaskTypes = map[string]HookTaskType{
-	"gitea": GITEA,
-	"gogs":  GOGS,
-	"slack": SLACK,
+	"gitea":   GITEA,
+	"gogs":    GOGS,
+	"slack":   SLACK,
+	"discord": DISCORD,
+	"msteams": MSTEAMS,
 }
 
 // ToHookTaskType returns HookTaskType by given name.
@@ -336,6 +418,10 @@ func (t HookTaskType) Name() string {
 		return "gogs"
 	case SLACK:
 		return "slack"
+	case DISCORD:
+		return "discord"
+	case MSTEAMS:
+		return "msteams"
 	}
 	return ""
 }
@@ -351,9 +437,15 @@ type HookEventType string
 
 // Types of hook events
 const (
-	HookEventCreate      HookEventType = "create"
-	HookEventPush        HookEventType = "push"
-	HookEventPullRequest HookEventType = "pull_request"
+	HookEventCreate       HookEventType = "create"
+	HookEventDelete       HookEventType = "delete"
+	HookEventFork         HookEventType = "fork"
+	HookEventPush         HookEventType = "push"
+	HookEventIssues       HookEventType = "issues"
+	HookEventIssueComment HookEventType = "issue_comment"
+	HookEventPullRequest  HookEventType = "pull_request"
+	HookEventRepository   HookEventType = "repository"
+	HookEventRelease      HookEventType = "release"
 )
 
 // HookRequest represents hook task request information.
@@ -384,6 +476,8 @@ type HookTask struct {
 	IsDelivered     bool
 	Delivered       int64
 	DeliveredString string `xorm:"-"`
+	Attempts        int    `xorm:"NOT NULL DEFAULT 0"`
+	NextRetryUnix   int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	// History info.
 	IsSucceed       bool
@@ -451,6 +545,26 @@ func HookTasks(hookID int64, page int) ([]*HookTask, error) {
 		Find(&tasks)
 }
 
+// CountHookTasks returns the number of hook tasks of a webhook.
+func CountHookTasks(hookID int64) (int64, error) {
+	return x.Where("hook_id=?", hookID).Count(new(HookTask))
+}
+
+// GetHookTaskByID returns the hook task of a webhook by given ID.
+func GetHookTaskByID(hookID, id int64) (*HookTask, error) {
+	t := &HookTask{
+		ID:     id,
+		HookID: hookID,
+	}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskNotExist{ID: id, HookID: hookID}
+	}
+	return t, nil
+}
+
 // CreateHookTask creates a new hook task,
 // it handles conversion from Payload to PayloadContent.
 func CreateHookTask(t *HookTask) error {
@@ -464,6 +578,31 @@ func CreateHookTask(t *HookTask) error {
 	return err
 }
 
+// RedeliverHookTask creates a new hook task sending the payload of the given
+// hook task again.
+func RedeliverHookTask(hookID, id int64) (*HookTask, error) {
+	t, err := GetHookTaskByID(hookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery := &HookTask{
+		RepoID:         t.RepoID,
+		HookID:         t.HookID,
+		UUID:           gouuid.NewV4().String(),
+		Type:           t.Type,
+		URL:            t.URL,
+		PayloadContent: t.PayloadContent,
+		ContentType:    t.ContentType,
+		EventType:      t.EventType,
+		IsSSL:          t.IsSSL,
+	}
+	if _, err = x.Insert(redelivery); err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
 // UpdateHookTask updates information of hook task.
 func UpdateHookTask(t *HookTask) error {
 	_, err := x.Id(t.ID).AllCols().Update(t)
@@ -487,57 +626,109 @@ func PrepareWebhooks(repo *Repository, event HookEventType, p api.Payloader) err
 		ws = append(ws, orgHooks...)
 	}
 
-	if len(ws) == 0 {
-		return nil
+	for _, w := range ws {
+		if !w.checkEvent(event) {
+			continue
+		}
+		if err = PrepareWebhook(w, repo, event, p); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// PrepareWebhook adds a new hook task of a single webhook to task queue for given payload,
+// regardless of the events the webhook is subscribed to.
+func PrepareWebhook(w *Webhook, repo *Repository, event HookEventType, p api.Payloader) (err error) {
+	// Use separate objects so modifications won't be made on payload on non-Gogs/Gitea type hooks.
 	var payloader api.Payloader
-	for _, w := range ws {
-		switch event {
-		case HookEventCreate:
-			if !w.HasCreateEvent() {
-				continue
-			}
-		case HookEventPush:
-			if !w.HasPushEvent() {
-				continue
-			}
-		case HookEventPullRequest:
-			if !w.HasPullRequestEvent() {
-				continue
-			}
+	switch w.HookTaskType {
+	case SLACK:
+		payloader, err = GetSlackPayload(p, event, w.Meta)
+		if err != nil {
+			return fmt.Errorf("GetSlackPayload: %v", err)
 		}
-
-		// Use separate objects so modifications won't be made on payload on non-Gogs/Gitea type hooks.
-		switch w.HookTaskType {
-		case SLACK:
-			payloader, err = GetSlackPayload(p, event, w.Meta)
-			if err != nil {
-				return fmt.Errorf("GetSlackPayload: %v", err)
-			}
-		default:
-			p.SetSecret(w.Secret)
-			payloader = p
+	case DISCORD:
+		payloader, err = GetDiscordPayload(p, event, w.Meta)
+		if err != nil {
+			return fmt.Errorf("GetDiscordPayload: %v", err)
 		}
-
-		if err = CreateHookTask(&HookTask{
-			RepoID:      repo.ID,
-			HookID:      w.ID,
-			Type:        w.HookTaskType,
-			URL:         w.URL,
-			Payloader:   payloader,
-			ContentType: w.ContentType,
-			EventType:   event,
-			IsSSL:       w.IsSSL,
-		}); err != nil {
-			return fmt.Errorf("CreateHookTask: %v", err)
+	case MSTEAMS:
+		payloader, err = GetMSTeamsPayload(p, event)
+		if err != nil {
+			return fmt.Errorf("GetMSTeamsPayload: %v", err)
 		}
+	default:
+		p.SetSecret(w.Secret)
+		payloader = p
+	}
+
+	if err = CreateHookTask(&HookTask{
+		RepoID:      repo.ID,
+		HookID:      w.ID,
+		Type:        w.HookTaskType,
+		URL:         w.URL,
+		Payloader:   payloader,
+		ContentType: w.ContentType,
+		EventType:   event,
+		IsSSL:       w.IsSSL,
+	}); err != nil {
+		return fmt.Errorf("CreateHookTask: %v", err)
 	}
 	return nil
 }
 
+// PrepareTestWebhook adds a hook task to task queue sending a push payload of
+// the given commit, or of a fake commit if it is nil, to test the webhook.
+func PrepareTestWebhook(w *Webhook, repo *Repository, commit *git.Commit, doer *User) error {
+	if commit == nil {
+		ghost := NewGhostUser()
+		commit = &git.Commit{
+			ID:            git.MustIDFromString(git.EmptySHA),
+			Author:        ghost.NewGitSig(),
+			Committer:     ghost.NewGitSig(),
+			CommitMessage: "This is a fake commit",
+		}
+	}
+
+	apiUser := doer.APIFormat()
+	return PrepareWebhook(w, repo, HookEventPush, &api.PushPayload{
+		Ref:    git.BranchPrefix + repo.DefaultBranch,
+		Before: commit.ID.String(),
+		After:  commit.ID.String(),
+		Commits: []*api.PayloadCommit{
+			{
+				ID:      commit.ID.String(),
+				Message: commit.Message(),
+				URL:     repo.HTMLURL() + "/commit/" + commit.ID.String(),
+				Author: &api.PayloadUser{
+					Name:  commit.Author.Name,
+					Email: commit.Author.Email,
+				},
+				Committer: &api.PayloadUser{
+					Name:  commit.Committer.Name,
+					Email: commit.Committer.Email,
+				},
+			},
+		},
+		Repo:   repo.APIFormat(AccessModeNone),
+		Pusher: apiUser,
+		Sender: apiUser,
+	})
+}
+
+// hookRetryBackoff returns the delay before the given retry of a failed delivery,
+// which doubles with each attempt.
+func hookRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return time.Duration(setting.Webhook.RetryBackoff) * time.Second << uint(attempts-1)
+}
+
 func (t *HookTask) deliver() {
 	t.IsDelivered = true
+	t.Attempts++
 
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 	req := httplib.Post(t.URL).SetTimeout(timeout, timeout).
@@ -574,6 +765,12 @@ func (t *HookTask) deliver() {
 			log.Trace("Hook delivered: %s", t.UUID)
 		} else {
 			log.Trace("Hook delivery failed: %s", t.UUID)
+			if t.Attempts <= setting.Webhook.MaxRetries {
+				// Schedule another attempt of the failed delivery.
+				backoff := hookRetryBackoff(t.Attempts)
+				t.IsDelivered = false
+				t.NextRetryUnix = time.Now().Add(backoff).Unix()
+			}
 		}
 
 		// Update webhook last delivery status.
@@ -619,7 +816,7 @@ func (t *HookTask) deliver() {
 // TODO: shoot more hooks at same time.
 func DeliverHooks() {
 	tasks := make([]*HookTask, 0, 10)
-	err := x.Where("is_delivered=?", false).Find(&tasks)
+	err := x.Where("is_delivered=? AND next_retry_unix<=?", false, time.Now().Unix()).Find(&tasks)
 	if err != nil {
 		log.Error(4, "DeliverHooks: %v", err)
 		return
@@ -640,7 +837,8 @@ func DeliverHooks() {
 		HookQueue.Remove(repoID)
 
 		tasks = make([]*HookTask, 0, 5)
-		if err := x.Where("repo_id=? AND is_delivered=?", repoID, false).Find(&tasks); err != nil {
+		if err := x.Where("repo_id=? AND is_delivered=? AND next_retry_unix<=?", repoID, false, time.Now().Unix()).
+			Find(&tasks); err != nil {
 			log.Error(4, "Get repository [%s] hook tasks: %v", repoID, err)
 			continue
 		}
@@ -654,6 +852,22 @@ func DeliverHooks() {
 	}
 }
 
+// RetryHookTasks queues the repositories which have failed deliveries
+// whose backoff has elapsed.
+func RetryHookTasks() {
+	repoIDs := make([]int64, 0, 10)
+	if err := x.Table("hook_task").
+		Where("is_delivered=? AND next_retry_unix>0 AND next_retry_unix<=?", false, time.Now().Unix()).
+		Distinct("repo_id").
+		Find(&repoIDs); err != nil {
+		log.Error(4, "RetryHookTasks: %v", err)
+		return
+	}
+	for _, repoID := range repoIDs {
+		HookQueue.Add(repoID)
+	}
+}
+
 // InitDeliverHooks starts the hooks delivery thread
 func InitDeliverHooks() {
 	go DeliverHooks()
@@ -0,0 +1,256 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Colors of chat messages, shared by the chat payload formats
+const (
+	chatGreenColor  = 0x1ac600
+	chatGreyColor   = 0xc3c3c3
+	chatOrangeColor = 0xffa500
+	chatYellowColor = 0xffd930
+	chatRedColor    = 0xff3232
+)
+
+// chatMessage is the format independent content of a chat message
+// describing a webhook event. Texts use markdown links which are
+// understood by Discord and Microsoft Teams.
+type chatMessage struct {
+	Title  string
+	Text   string
+	URL    string
+	Color  int
+	Repo   *api.Repository
+	Sender *api.User
+}
+
+// chatLink formats a markdown link
+func chatLink(url, text string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func getCreateChatMessage(p *api.CreatePayload) *chatMessage {
+	refName := git.RefEndName(p.Ref)
+	return &chatMessage{
+		Title:  fmt.Sprintf("[%s] %s %s created", p.Repo.FullName, p.RefType, refName),
+		URL:    p.Repo.HTMLURL + "/src/" + refName,
+		Color:  chatGreenColor,
+		Repo:   p.Repo,
+		Sender: p.Sender,
+	}
+}
+
+func getDeleteChatMessage(p *api.DeletePayload) *chatMessage {
+	refName := git.RefEndName(p.Ref)
+	return &chatMessage{
+		Title:  fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName),
+		URL:    p.Repo.HTMLURL,
+		Color:  chatRedColor,
+		Repo:   p.Repo,
+		Sender: p.Sender,
+	}
+}
+
+func getForkChatMessage(p *api.ForkPayload) *chatMessage {
+	return &chatMessage{
+		Title:  fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName),
+		URL:    p.Forkee.HTMLURL,
+		Color:  chatGreenColor,
+		Repo:   p.Repo,
+		Sender: p.Sender,
+	}
+}
+
+func getPushChatMessage(p *api.PushPayload) *chatMessage {
+	var (
+		branchName = git.RefEndName(p.Ref)
+		commitDesc string
+	)
+	if len(p.Commits) == 1 {
+		commitDesc = "1 new commit"
+	} else {
+		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+	}
+
+	url := p.Repo.HTMLURL + "/src/" + branchName
+	if len(p.CompareURL) > 0 {
+		url = p.CompareURL
+	}
+
+	lines := make([]string, len(p.Commits))
+	for i, commit := range p.Commits {
+		lines[i] = fmt.Sprintf("%s %s - %s", chatLink(commit.URL, commit.ID[:7]),
+			strings.Split(commit.Message, "\n")[0], commit.Author.Name)
+	}
+
+	return &chatMessage{
+		Title:  fmt.Sprintf("[%s:%s] %s", p.Repo.FullName, branchName, commitDesc),
+		Text:   strings.Join(lines, "\n"),
+		URL:    url,
+		Color:  chatGreenColor,
+		Repo:   p.Repo,
+		Sender: p.Sender,
+	}
+}
+
+// getIssueActionChatTitle returns the title and the color of an issue or pull request action
+func getIssueActionChatTitle(kind string, action api.HookIssueAction, assignee *api.User) (string, int) {
+	switch action {
+	case api.HookIssueOpened:
+		return kind + " opened", chatOrangeColor
+	case api.HookIssueClosed:
+		return kind + " closed", chatRedColor
+	case api.HookIssueReOpened:
+		return kind + " re-opened", chatYellowColor
+	case api.HookIssueEdited:
+		return kind + " edited", chatYellowColor
+	case api.HookIssueAssigned:
+		if assignee != nil {
+			return fmt.Sprintf("%s assigned to %s", kind, assignee.UserName), chatGreenColor
+		}
+		return kind + " assigned", chatGreenColor
+	case api.HookIssueUnassigned:
+		return kind + " unassigned", chatGreyColor
+	case api.HookIssueLabelUpdated:
+		return kind + " labels updated", chatYellowColor
+	case api.HookIssueLabelCleared:
+		return kind + " labels cleared", chatGreyColor
+	case api.HookIssueSynchronized:
+		return kind + " synchronized", chatGreyColor
+	case api.HookIssueMilestoned:
+		return kind + " milestoned", chatYellowColor
+	case api.HookIssueDemilestoned:
+		return kind + " milestone cleared", chatGreyColor
+	}
+	return kind + " " + string(action), chatGreyColor
+}
+
+func getIssuesChatMessage(p *api.IssuePayload) *chatMessage {
+	title, color := getIssueActionChatTitle("Issue", p.Action, p.Issue.Assignee)
+	msg := &chatMessage{
+		Title:  fmt.Sprintf("[%s] %s: #%d %s", p.Repository.FullName, title, p.Index, p.Issue.Title),
+		URL:    fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+		Color:  color,
+		Repo:   p.Repository,
+		Sender: p.Sender,
+	}
+	if p.Action == api.HookIssueOpened || p.Action == api.HookIssueEdited {
+		msg.Text = p.Issue.Body
+	}
+	return msg
+}
+
+func getIssueCommentChatMessage(p *api.IssueCommentPayload) *chatMessage {
+	msg := &chatMessage{
+		Text:   p.Comment.Body,
+		URL:    p.Comment.HTMLURL,
+		Repo:   p.Repository,
+		Sender: p.Sender,
+	}
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		msg.Title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		msg.Color = chatOrangeColor
+	case api.HookIssueCommentEdited:
+		msg.Title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		msg.Color = chatYellowColor
+	case api.HookIssueCommentDeleted:
+		msg.Title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		msg.URL = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+		msg.Color = chatRedColor
+	}
+	return msg
+}
+
+func getPullRequestChatMessage(p *api.PullRequestPayload) *chatMessage {
+	title, color := getIssueActionChatTitle("Pull request", p.Action, p.PullRequest.Assignee)
+	if p.Action == api.HookIssueClosed && p.PullRequest.HasMerged {
+		title, color = "Pull request merged", chatGreenColor
+	}
+	msg := &chatMessage{
+		Title:  fmt.Sprintf("[%s] %s: #%d %s", p.Repository.FullName, title, p.Index, p.PullRequest.Title),
+		URL:    p.PullRequest.HTMLURL,
+		Color:  color,
+		Repo:   p.Repository,
+		Sender: p.Sender,
+	}
+	if p.Action == api.HookIssueOpened || p.Action == api.HookIssueEdited {
+		msg.Text = p.PullRequest.Body
+	}
+	return msg
+}
+
+func getReleaseChatMessage(p *api.ReleasePayload) *chatMessage {
+	msg := &chatMessage{
+		URL:    p.Repository.HTMLURL + "/src/" + p.Release.TagName,
+		Repo:   p.Repository,
+		Sender: p.Sender,
+	}
+	switch p.Action {
+	case api.HookReleasePublished:
+		msg.Title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+		msg.Text = p.Release.Note
+		msg.Color = chatGreenColor
+	case api.HookReleaseUpdated:
+		msg.Title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+		msg.Text = p.Release.Note
+		msg.Color = chatYellowColor
+	case api.HookReleaseDeleted:
+		msg.Title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+		msg.URL = p.Repository.HTMLURL + "/releases"
+		msg.Color = chatRedColor
+	}
+	return msg
+}
+
+func getRepositoryChatMessage(p *api.RepositoryPayload) *chatMessage {
+	msg := &chatMessage{
+		URL:    p.Repository.HTMLURL,
+		Repo:   p.Repository,
+		Sender: p.Sender,
+	}
+	switch p.Action {
+	case api.HookRepoCreated:
+		msg.Title = fmt.Sprintf("[%s] Repository created", p.Repository.FullName)
+		msg.Color = chatGreenColor
+	case api.HookRepoDeleted:
+		msg.Title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
+		msg.Color = chatRedColor
+	}
+	return msg
+}
+
+// getChatMessage converts a webhook payload into a chatMessage
+func getChatMessage(p api.Payloader, event HookEventType) (*chatMessage, error) {
+	switch event {
+	case HookEventCreate:
+		return getCreateChatMessage(p.(*api.CreatePayload)), nil
+	case HookEventDelete:
+		return getDeleteChatMessage(p.(*api.DeletePayload)), nil
+	case HookEventFork:
+		return getForkChatMessage(p.(*api.ForkPayload)), nil
+	case HookEventIssues:
+		return getIssuesChatMessage(p.(*api.IssuePayload)), nil
+	case HookEventIssueComment:
+		return getIssueCommentChatMessage(p.(*api.IssueCommentPayload)), nil
+	case HookEventPush:
+		return getPushChatMessage(p.(*api.PushPayload)), nil
+	case HookEventPullRequest:
+		return getPullRequestChatMessage(p.(*api.PullRequestPayload)), nil
+	case HookEventRelease:
+		return getReleaseChatMessage(p.(*api.ReleasePayload)), nil
+	case HookEventRepository:
+		return getRepositoryChatMessage(p.(*api.RepositoryPayload)), nil
+	}
+	return nil, fmt.Errorf("unsupported event: %s", event)
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// DiscordMeta contains the discord metadata
+type DiscordMeta struct {
+	Username string `json:"username"`
+	IconURL  string `json:"icon_url"`
+}
+
+// DiscordPayload represents the message posted to a discord webhook
+type DiscordPayload struct {
+	Wait      bool           `json:"wait"`
+	Content   string         `json:"content"`
+	Username  string         `json:"username"`
+	AvatarURL string         `json:"avatar_url"`
+	TTS       bool           `json:"tts"`
+	Embeds    []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordEmbed contains the discord message
+type DiscordEmbed struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	URL         string             `json:"url"`
+	Color       int                `json:"color"`
+	Author      DiscordEmbedAuthor `json:"author"`
+}
+
+// DiscordEmbedAuthor contains the author of a discord message
+type DiscordEmbedAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	IconURL string `json:"icon_url"`
+}
+
+// SetSecret sets the discord secret
+func (p *DiscordPayload) SetSecret(_ string) {}
+
+// JSONPayload Marshals the DiscordPayload to json
+func (p *DiscordPayload) JSONPayload() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// GetDiscordPayload converts a discord webhook into a DiscordPayload
+func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (*DiscordPayload, error) {
+	s := new(DiscordPayload)
+
+	discord := &DiscordMeta{}
+	if err := json.Unmarshal([]byte(meta), &discord); err != nil {
+		return s, errors.New("GetDiscordPayload meta json:" + err.Error())
+	}
+
+	msg, err := getChatMessage(p, event)
+	if err != nil {
+		return s, fmt.Errorf("getChatMessage: %v", err)
+	}
+
+	embed := DiscordEmbed{
+		Title:       msg.Title,
+		Description: msg.Text,
+		URL:         msg.URL,
+		Color:       msg.Color,
+	}
+	if msg.Sender != nil {
+		embed.Author = DiscordEmbedAuthor{
+			Name:    msg.Sender.UserName,
+			URL:     setting.AppURL + msg.Sender.UserName,
+			IconURL: msg.Sender.AvatarURL,
+		}
+	}
+
+	return &DiscordPayload{
+		Username:  discord.Username,
+		AvatarURL: discord.IconURL,
+		Embeds:    []DiscordEmbed{embed},
+	}, nil
+}
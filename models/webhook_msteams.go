@@ -0,0 +1,104 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// MSTeamsPayload represents the MessageCard posted to a Microsoft Teams webhook
+type MSTeamsPayload struct {
+	Type            string           `json:"@type"`
+	Context         string           `json:"@context"`
+	ThemeColor      string           `json:"themeColor"`
+	Title           string           `json:"title"`
+	Summary         string           `json:"summary"`
+	Sections        []MSTeamsSection `json:"sections"`
+	PotentialAction []MSTeamsAction  `json:"potentialAction"`
+}
+
+// MSTeamsSection is a section of a MessageCard
+type MSTeamsSection struct {
+	ActivityTitle    string        `json:"activityTitle"`
+	ActivitySubtitle string        `json:"activitySubtitle"`
+	ActivityImage    string        `json:"activityImage"`
+	Facts            []MSTeamsFact `json:"facts"`
+	Text             string        `json:"text"`
+}
+
+// MSTeamsFact is a key value pair shown in a section
+type MSTeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// MSTeamsAction is an action of a MessageCard
+type MSTeamsAction struct {
+	Type    string                `json:"@type"`
+	Name    string                `json:"name"`
+	Targets []MSTeamsActionTarget `json:"targets"`
+}
+
+// MSTeamsActionTarget is the target of an action
+type MSTeamsActionTarget struct {
+	Os  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// SetSecret sets the msteams secret
+func (p *MSTeamsPayload) SetSecret(_ string) {}
+
+// JSONPayload Marshals the MSTeamsPayload to json
+func (p *MSTeamsPayload) JSONPayload() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// GetMSTeamsPayload converts a msteams webhook into a MSTeamsPayload
+func GetMSTeamsPayload(p api.Payloader, event HookEventType) (*MSTeamsPayload, error) {
+	msg, err := getChatMessage(p, event)
+	if err != nil {
+		return new(MSTeamsPayload), fmt.Errorf("getChatMessage: %v", err)
+	}
+
+	section := MSTeamsSection{
+		Text: msg.Text,
+		Facts: []MSTeamsFact{{
+			Name:  "Repository:",
+			Value: msg.Repo.FullName,
+		}, {
+			Name:  "Event:",
+			Value: string(event),
+		}},
+	}
+	if msg.Sender != nil {
+		section.ActivityTitle = msg.Sender.FullName
+		section.ActivitySubtitle = msg.Sender.UserName
+		section.ActivityImage = msg.Sender.AvatarURL
+	}
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%06x", msg.Color),
+		Title:      msg.Title,
+		Summary:    msg.Title,
+		Sections:   []MSTeamsSection{section},
+		PotentialAction: []MSTeamsAction{{
+			Type: "OpenUri",
+			Name: "View in Gitea",
+			Targets: []MSTeamsActionTarget{{
+				Os:  "default",
+				URI: msg.URL,
+			}},
+		}},
+	}, nil
+}
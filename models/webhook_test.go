@@ -6,9 +6,13 @@ package models
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
-	api "code.gitea.io/sdk/gitea"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,6 +40,17 @@ func TestWebhook_GetSlackHook(t *testing.T) {
 	})
 }
 
+func TestWebhook_GetDiscordHook(t *testing.T) {
+	w := &Webhook{
+		Meta: `{"username": "username", "icon_url": "https://example.com/icon.png"}`,
+	}
+	discordHook := w.GetDiscordHook()
+	assert.Equal(t, *discordHook, DiscordMeta{
+		Username: "username",
+		IconURL:  "https://example.com/icon.png",
+	})
+}
+
 func TestWebhook_History(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	webhook := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
@@ -72,12 +87,21 @@ func TestWebhook_UpdateEvent(t *testing.T) {
 }
 
 func TestWebhook_EventsArray(t *testing.T) {
-	assert.Equal(t, []string{"create", "push", "pull_request"},
+	assert.Equal(t, []string{"create", "delete", "fork", "push", "issues", "issue_comment", "pull_request", "repository", "release"},
 		(&Webhook{
 			HookEvent: &HookEvent{SendEverything: true},
 		}).EventsArray(),
 	)
 
+	assert.Equal(t, []string{"issues", "release"},
+		(&Webhook{
+			HookEvent: &HookEvent{
+				ChooseEvents: true,
+				HookEvents:   HookEvents{Issues: true, Release: true},
+			},
+		}).EventsArray(),
+	)
+
 	assert.Equal(t, []string{"push"},
 		(&Webhook{
 			HookEvent: &HookEvent{PushOnly: true},
@@ -192,18 +216,24 @@ func TestToHookTaskType(t *testing.T) {
 	assert.Equal(t, GOGS, ToHookTaskType("gogs"))
 	assert.Equal(t, SLACK, ToHookTaskType("slack"))
 	assert.Equal(t, GITEA, ToHookTaskType("gitea"))
+	assert.Equal(t, DISCORD, ToHookTaskType("discord"))
+	assert.Equal(t, MSTEAMS, ToHookTaskType("msteams"))
 }
 
 func TestHookTaskType_Name(t *testing.T) {
 	assert.Equal(t, "gogs", GOGS.Name())
 	assert.Equal(t, "slack", SLACK.Name())
 	assert.Equal(t, "gitea", GITEA.Name())
+	assert.Equal(t, "discord", DISCORD.Name())
+	assert.Equal(t, "msteams", MSTEAMS.Name())
 }
 
 func TestIsValidHookTaskType(t *testing.T) {
 	assert.True(t, IsValidHookTaskType("gogs"))
 	assert.True(t, IsValidHookTaskType("slack"))
 	assert.True(t, IsValidHookTaskType("gitea"))
+	assert.True(t, IsValidHookTaskType("discord"))
+	assert.True(t, IsValidHookTaskType("msteams"))
 	assert.False(t, IsValidHookTaskType("invalid"))
 }
 
@@ -219,6 +249,27 @@ func TestHookTasks(t *testing.T) {
 	assert.Len(t, hookTasks, 0)
 }
 
+func TestCountHookTasks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	count, err := CountHookTasks(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	count, err = CountHookTasks(2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestGetHookTaskByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	hookTask, err := GetHookTaskByID(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid1", hookTask.UUID)
+
+	_, err = GetHookTaskByID(2, 1)
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
+
 func TestCreateHookTask(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	hookTask := &HookTask{
@@ -261,6 +312,158 @@ func TestPrepareWebhooks(t *testing.T) {
 	}
 }
 
-// TODO TestHookTask_deliver
+func TestPrepareWebhooks_SkipEvent(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	// webhook 1 is only interested in push events
+	hookTask := &HookTask{RepoID: repo.ID, HookID: 1, EventType: HookEventIssues}
+	AssertNotExistsBean(t, hookTask)
+	assert.NoError(t, PrepareWebhooks(repo, HookEventIssues, &api.IssuePayload{}))
+	AssertNotExistsBean(t, hookTask)
+}
+
+func TestRedeliverHookTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	hookTask := AssertExistsAndLoadBean(t, &HookTask{ID: 1}).(*HookTask)
+	redelivery, err := RedeliverHookTask(1, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hookTask.ID, redelivery.ID)
+	assert.NotEqual(t, hookTask.UUID, redelivery.UUID)
+	AssertExistsAndLoadBean(t, &HookTask{
+		ID:             redelivery.ID,
+		RepoID:         hookTask.RepoID,
+		HookID:         hookTask.HookID,
+		PayloadContent: hookTask.PayloadContent,
+	}, Cond("is_delivered = ?", false))
+
+	_, err = RedeliverHookTask(1, 100)
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
+
+func TestPrepareTestWebhook(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	// webhook 2 is inactive
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 2}).(*Webhook)
+	hookTask := &HookTask{RepoID: repo.ID, HookID: w.ID, EventType: HookEventPush}
+	AssertNotExistsBean(t, hookTask)
+	assert.NoError(t, PrepareTestWebhook(w, repo, nil, doer))
+	hookTask = AssertExistsAndLoadBean(t, hookTask).(*HookTask)
+	assert.Contains(t, hookTask.PayloadContent, "This is a fake commit")
+}
+
+func TestHookTask_deliverRetry(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hookTask := AssertExistsAndLoadBean(t, &HookTask{ID: 1}).(*HookTask)
+	hookTask.URL = server.URL
+	hookTask.deliver()
+	assert.False(t, hookTask.IsSucceed)
+	assert.False(t, hookTask.IsDelivered)
+	assert.EqualValues(t, 1, hookTask.Attempts)
+	assert.True(t, hookTask.NextRetryUnix > time.Now().Unix())
+
+	// no retries are left after the last attempt
+	hookTask.Attempts = setting.Webhook.MaxRetries
+	hookTask.deliver()
+	assert.False(t, hookTask.IsSucceed)
+	assert.True(t, hookTask.IsDelivered)
+	assert.EqualValues(t, setting.Webhook.MaxRetries+1, hookTask.Attempts)
+}
+
+func TestHookRetryBackoff(t *testing.T) {
+	backoff := time.Duration(setting.Webhook.RetryBackoff) * time.Second
+	assert.Equal(t, backoff, hookRetryBackoff(1))
+	assert.Equal(t, 2*backoff, hookRetryBackoff(2))
+	assert.Equal(t, 4*backoff, hookRetryBackoff(3))
+}
+
+func TestRetryHookTasks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	due := &HookTask{
+		RepoID:        2,
+		HookID:        1,
+		Payloader:     &api.PushPayload{},
+		NextRetryUnix: time.Now().Add(-time.Minute).Unix(),
+	}
+	pending := &HookTask{
+		RepoID:        3,
+		HookID:        1,
+		Payloader:     &api.PushPayload{},
+		NextRetryUnix: time.Now().Add(time.Hour).Unix(),
+	}
+	assert.NoError(t, CreateHookTask(due))
+	assert.NoError(t, CreateHookTask(pending))
+	defer HookQueue.Remove(due.RepoID)
+
+	RetryHookTasks()
+	assert.True(t, HookQueue.Exist(due.RepoID))
+	assert.False(t, HookQueue.Exist(pending.RepoID))
+}
 
 // TODO TestDeliverHooks
+
+func TestGetDiscordPayload(t *testing.T) {
+	p := &api.PushPayload{
+		Ref:        "refs/heads/master",
+		CompareURL: "https://example.com/user2/repo1/compare/abc...def",
+		Commits: []*api.PayloadCommit{{
+			ID:      "2c54faec6c45d31c1abfaecdab471eac6633738a",
+			Message: "init\n\nmore details",
+			URL:     "https://example.com/user2/repo1/commit/2c54faec6c45d31c1abfaecdab471eac6633738a",
+			Author:  &api.PayloadUser{Name: "user2"},
+		}},
+		Repo:   &api.Repository{FullName: "user2/repo1", HTMLURL: "https://example.com/user2/repo1"},
+		Sender: &api.User{UserName: "user2", AvatarURL: "https://example.com/avatar"},
+	}
+
+	payload, err := GetDiscordPayload(p, HookEventPush, `{"username": "gitea"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "gitea", payload.Username)
+	if assert.Len(t, payload.Embeds, 1) {
+		embed := payload.Embeds[0]
+		assert.Equal(t, "[user2/repo1:master] 1 new commit", embed.Title)
+		assert.Equal(t, "[2c54fae](https://example.com/user2/repo1/commit/2c54faec6c45d31c1abfaecdab471eac6633738a) init - user2", embed.Description)
+		assert.Equal(t, p.CompareURL, embed.URL)
+		assert.Equal(t, "user2", embed.Author.Name)
+	}
+
+	_, err = GetDiscordPayload(p, HookEventPush, "invalid")
+	assert.Error(t, err)
+}
+
+func TestGetMSTeamsPayload(t *testing.T) {
+	p := &api.PullRequestPayload{
+		Action: api.HookIssueClosed,
+		Index:  2,
+		PullRequest: &api.PullRequest{
+			Title:     "Fix bug",
+			HTMLURL:   "https://example.com/user2/repo1/pulls/2",
+			HasMerged: true,
+		},
+		Repository: &api.Repository{FullName: "user2/repo1", HTMLURL: "https://example.com/user2/repo1"},
+		Sender:     &api.User{UserName: "user2", FullName: "User Two"},
+	}
+
+	payload, err := GetMSTeamsPayload(p, HookEventPullRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "MessageCard", payload.Type)
+	assert.Equal(t, "[user2/repo1] Pull request merged: #2 Fix bug", payload.Title)
+	assert.Equal(t, "1ac600", payload.ThemeColor)
+	if assert.Len(t, payload.Sections, 1) {
+		assert.Equal(t, "User Two", payload.Sections[0].ActivityTitle)
+	}
+	if assert.Len(t, payload.PotentialAction, 1) {
+		assert.Equal(t, p.PullRequest.HTMLURL, payload.PotentialAction[0].Targets[0].URI)
+	}
+}
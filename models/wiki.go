@@ -17,6 +17,7 @@ import (
 
 	"code.gitea.io/git"
 
+	"code.gitea.io/gitea/modules/gitutil"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/sync"
 )
@@ -158,10 +159,7 @@ func (repo *Repository) updateWikiPage(doer *User, oldWikiPath, wikiPath, conten
 	}
 	if err = git.AddChanges(localPath, true); err != nil {
 		return fmt.Errorf("AddChanges: %v", err)
-	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
-		Committer: doer.NewGitSig(),
-		Message:   message,
-	}); err != nil {
+	} else if err = gitutil.CommitChanges(localPath, signedCommitChangesOptions(doer, message, repo.signWikiCommit(doer))); err != nil {
 		return fmt.Errorf("CommitChanges: %v", err)
 	} else if err = git.Push(localPath, git.PushOptions{
 		Remote: "origin",
@@ -207,10 +205,7 @@ func (repo *Repository) DeleteWikiPage(doer *User, wikiPath string) (err error)
 
 	if err = git.AddChanges(localPath, true); err != nil {
 		return fmt.Errorf("AddChanges: %v", err)
-	} else if err = git.CommitChanges(localPath, git.CommitChangesOptions{
-		Committer: doer.NewGitSig(),
-		Message:   message,
-	}); err != nil {
+	} else if err = gitutil.CommitChanges(localPath, signedCommitChangesOptions(doer, message, repo.signWikiCommit(doer))); err != nil {
 		return fmt.Errorf("CommitChanges: %v", err)
 	} else if err = git.Push(localPath, git.PushOptions{
 		Remote: "origin",
@@ -34,6 +34,7 @@ type AdminEditUserForm struct {
 	Password                string `binding:"MaxSize(255)"`
 	Website                 string `binding:"ValidUrl;MaxSize(255)"`
 	Location                string `binding:"MaxSize(50)"`
+	Visibility              string `binding:"In(,public,limited,private)"`
 	MaxRepoCreation         int
 	Active                  bool
 	Admin                   bool
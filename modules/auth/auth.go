@@ -16,6 +16,7 @@ import (
 	"gopkg.in/macaron.v1"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -27,6 +28,30 @@ func IsAPIPath(url string) bool {
 	return strings.HasPrefix(url, "/api/")
 }
 
+// CheckOAuthAccessToken returns the grant an OAuth2 access token has been issued for,
+// or nil if the token is invalid, expired or its grant has been revoked.
+func CheckOAuthAccessToken(accessToken string) *models.OAuth2Grant {
+	if !setting.OAuth2.Enable {
+		return nil
+	}
+	token, err := oauth2.ParseToken(accessToken)
+	if err != nil {
+		log.Trace("ParseToken: %v", err)
+		return nil
+	}
+	if token.Type != oauth2.TypeAccessToken {
+		return nil
+	}
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil {
+		if !models.IsErrOAuth2GrantNotExist(err) {
+			log.Error(4, "GetOAuth2GrantByID: %v", err)
+		}
+		return nil
+	}
+	return grant
+}
+
 // SignedInID returns the id of signed in user.
 func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 	if !models.HasEngine {
@@ -44,7 +69,7 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 			auHead := ctx.Req.Header.Get("Authorization")
 			if len(auHead) > 0 {
 				auths := strings.Fields(auHead)
-				if len(auths) == 2 && auths[0] == "token" {
+				if len(auths) == 2 && (auths[0] == "token" || strings.ToLower(auths[0]) == "bearer") {
 					tokenSHA = auths[1]
 				}
 			}
@@ -52,17 +77,33 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 
 		// Let's see if token is valid.
 		if len(tokenSHA) > 0 {
+			if strings.Contains(tokenSHA, ".") {
+				// Access tokens issued to OAuth2 applications are JWTs.
+				grant := CheckOAuthAccessToken(tokenSHA)
+				if grant == nil {
+					return 0
+				}
+				ctx.Data["AccessToken"] = &models.AccessToken{
+					UID:    grant.UserID,
+					Scopes: grant.AccessTokenScopes(),
+				}
+				return grant.UserID
+			}
 			t, err := models.GetAccessTokenBySHA(tokenSHA)
 			if err != nil {
 				if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
 					log.Error(4, "GetAccessTokenBySHA: %v", err)
 				}
 				return 0
+			} else if t.IsExpired() {
+				return 0
 			}
 			t.Updated = time.Now()
 			if err = models.UpdateAccessToken(t); err != nil {
 				log.Error(4, "UpdateAccessToken: %v", err)
 			}
+			// Remember the token to enforce its scopes.
+			ctx.Data["AccessToken"] = t
 			return t.UID
 		}
 	}
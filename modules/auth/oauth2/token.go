@@ -0,0 +1,80 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// TokenType represents the type of an OAuth2 token issued by Gitea
+type TokenType int
+
+const (
+	// TypeAccessToken is a token with short lifetime to access the API
+	TypeAccessToken TokenType = iota
+	// TypeRefreshToken is a token with long lifetime to request new access tokens
+	TypeRefreshToken
+)
+
+// Token represents an access or refresh token issued for an OAuth2 grant
+type Token struct {
+	GrantID int64     `json:"gnt"`
+	Type    TokenType `json:"tt"`
+	// Counter is the grant counter the refresh token was issued for
+	Counter int64 `json:"cnt,omitempty"`
+	jwt.StandardClaims
+}
+
+// ParseToken parses and validates a signed token
+func ParseToken(jwtToken string) (*Token, error) {
+	parsedToken, err := jwt.ParseWithClaims(jwtToken, &Token{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return setting.OAuth2.JWTSecretBytes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := parsedToken.Claims.(*Token)
+	if !ok || !parsedToken.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return token, nil
+}
+
+// SignToken signs the token with the JWT secret
+func (token *Token) SignToken() (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS512, token).SignedString(setting.OAuth2.JWTSecretBytes)
+}
+
+// OIDCToken represents an OpenID Connect ID token
+type OIDCToken struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+
+	// Scope profile
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Website           string `json:"website,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+
+	// Scope email
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+}
+
+// SignToken signs the ID token with the client secret of the application
+// it is issued for, as described for the HS256 algorithm by OpenID Connect.
+func (token *OIDCToken) SignToken(clientSecret []byte) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, token).SignedString(clientSecret)
+}
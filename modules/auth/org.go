@@ -20,7 +20,8 @@ import (
 
 // CreateOrgForm form for creating organization
 type CreateOrgForm struct {
-	OrgName string `binding:"Required;AlphaDashDot;MaxSize(35)" locale:"org.org_name_holder"`
+	OrgName    string `binding:"Required;AlphaDashDot;MaxSize(35)" locale:"org.org_name_holder"`
+	Visibility string `binding:"In(,public,limited,private)"`
 }
 
 // Validate validates the fields
@@ -35,6 +36,7 @@ type UpdateOrgSettingForm struct {
 	Description     string `binding:"MaxSize(255)"`
 	Website         string `binding:"ValidUrl;MaxSize(255)"`
 	Location        string `binding:"MaxSize(50)"`
+	Visibility      string `binding:"In(,public,limited,private)"`
 	MaxRepoCreation int
 }
 
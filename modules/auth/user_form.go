@@ -107,6 +107,7 @@ type UpdateProfileForm struct {
 	KeepEmailPrivate bool
 	Website          string `binding:"ValidUrl;MaxSize(255)"`
 	Location         string `binding:"MaxSize(50)"`
+	Visibility       string `binding:"In(,public,limited,private)"`
 }
 
 // Validate validates the fields
@@ -179,7 +180,10 @@ func (f *AddKeyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding
 
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
-	Name string `binding:"Required"`
+	Name          string `binding:"Required"`
+	Scopes        []string
+	Repositories  string
+	ExpiresInDays int `binding:"Range(0,365)"`
 }
 
 // Validate valideates the fields
@@ -187,6 +191,66 @@ func (f *NewAccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// EditOAuth2ApplicationForm form for creating and editing an OAuth2 application
+type EditOAuth2ApplicationForm struct {
+	Name         string `binding:"Required;MaxSize(255)"`
+	RedirectURIs string `binding:"Required" form:"redirect_uris"`
+}
+
+// Validate valideates the fields
+func (f *EditOAuth2ApplicationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// AuthorizationForm form for authorizing an OAuth2 client
+type AuthorizationForm struct {
+	ResponseType string
+	ClientID     string `form:"client_id"`
+	RedirectURI  string `form:"redirect_uri"`
+	State        string
+	Scope        string
+	Nonce        string
+
+	// PKCE support
+	CodeChallengeMethod string // S256 or plain
+	CodeChallenge       string
+}
+
+// Validate valideates the fields
+func (f *AuthorizationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// GrantApplicationForm form for confirming the authorization of an OAuth2 client
+type GrantApplicationForm struct {
+	ClientID    string `binding:"Required" form:"client_id"`
+	RedirectURI string `form:"redirect_uri"`
+	State       string
+}
+
+// Validate valideates the fields
+func (f *GrantApplicationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// AccessTokenForm for issuing OAuth2 access tokens from authorization codes or refresh tokens
+type AccessTokenForm struct {
+	GrantType    string
+	ClientID     string `form:"client_id"`
+	ClientSecret string
+	RedirectURI  string `form:"redirect_uri"`
+	Code         string
+	RefreshToken string
+
+	// PKCE support
+	CodeVerifier string
+}
+
+// Validate valideates the fields
+func (f *AccessTokenForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // TwoFactorAuthForm for logging in with 2FA token.
 type TwoFactorAuthForm struct {
 	Passcode string `binding:"Required"`
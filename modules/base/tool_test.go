@@ -361,6 +361,12 @@ func TestInt64sToStrings(t *testing.T) {
 	)
 }
 
+func TestInt64sContains(t *testing.T) {
+	assert.False(t, Int64sContains([]int64{}, 1))
+	assert.True(t, Int64sContains([]int64{1, 4, 16}, 4))
+	assert.False(t, Int64sContains([]int64{1, 4, 16}, 5))
+}
+
 func TestInt64sToMap(t *testing.T) {
 	assert.Equal(t, map[int64]bool{}, Int64sToMap([]int64{}))
 	assert.Equal(t,
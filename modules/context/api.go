@@ -6,6 +6,7 @@ package context
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/git"
@@ -71,21 +72,28 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 	})
 }
 
+// pageLink returns the link to given page of the current request, with the same query.
+func (ctx *APIContext) pageLink(page int, rel string) string {
+	query := ctx.Req.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf("<%s%s?%s>; rel=\"%s\"", setting.AppURL, ctx.Req.URL.Path[1:], query.Encode(), rel)
+}
+
 // SetLinkHeader sets pagination link header by given total number and page size.
 func (ctx *APIContext) SetLinkHeader(total, pageSize int) {
 	page := paginater.New(total, pageSize, ctx.QueryInt("page"), 0)
 	links := make([]string, 0, 4)
 	if page.HasNext() {
-		links = append(links, fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", setting.AppURL, ctx.Req.URL.Path[1:], page.Next()))
+		links = append(links, ctx.pageLink(page.Next(), "next"))
 	}
 	if !page.IsLast() {
-		links = append(links, fmt.Sprintf("<%s%s?page=%d>; rel=\"last\"", setting.AppURL, ctx.Req.URL.Path[1:], page.TotalPages()))
+		links = append(links, ctx.pageLink(page.TotalPages(), "last"))
 	}
 	if !page.IsFirst() {
-		links = append(links, fmt.Sprintf("<%s%s?page=1>; rel=\"first\"", setting.AppURL, ctx.Req.URL.Path[1:]))
+		links = append(links, ctx.pageLink(1, "first"))
 	}
 	if page.HasPrevious() {
-		links = append(links, fmt.Sprintf("<%s%s?page=%d>; rel=\"prev\"", setting.AppURL, ctx.Req.URL.Path[1:], page.Previous()))
+		links = append(links, ctx.pageLink(page.Previous(), "prev"))
 	}
 
 	if len(links) > 0 {
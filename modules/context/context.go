@@ -39,6 +39,7 @@ type Context struct {
 	User        *models.User
 	IsSigned    bool
 	IsBasicAuth bool
+	AccessToken *models.AccessToken // access token used to sign in, if any
 
 	Repo *Repository
 	Org  *Organization
@@ -204,6 +205,7 @@ func Contexter() macaron.Handler {
 
 		// Get user from session if logged in.
 		ctx.User, ctx.IsBasicAuth = auth.SignedInUser(ctx.Context, ctx.Session)
+		ctx.AccessToken, _ = ctx.Data["AccessToken"].(*models.AccessToken)
 
 		if ctx.User != nil {
 			ctx.IsSigned = true
@@ -235,6 +237,8 @@ func Contexter() macaron.Handler {
 		ctx.Data["ShowRegistrationButton"] = setting.Service.ShowRegistrationButton
 		ctx.Data["ShowFooterBranding"] = setting.ShowFooterBranding
 		ctx.Data["ShowFooterVersion"] = setting.ShowFooterVersion
+		ctx.Data["EnableOAuth2"] = setting.OAuth2.Enable
+		ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
 		ctx.Data["EnableOpenIDSignIn"] = setting.Service.EnableOpenIDSignIn
 
 		c.Map(ctx)
@@ -58,6 +58,10 @@ func HandleOrgAssignment(ctx *Context, args ...bool) {
 		return
 	}
 	org := ctx.Org.Organization
+	if !org.IsVisibleTo(ctx.User) {
+		ctx.Handle(404, "GetUserByName", nil)
+		return
+	}
 	ctx.Data["Org"] = org
 
 	// Force redirection when username is actually a user.
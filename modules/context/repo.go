@@ -76,13 +76,16 @@ func (r *Repository) CanEnableEditor() bool {
 }
 
 // CanCommitToBranch returns true if repository is editable and user has proper access level
-//   and branch is not protected
-func (r *Repository) CanCommitToBranch() (bool, error) {
-	protectedBranch, err := r.Repository.IsProtectedBranch(r.BranchName)
+//   and branch is not protected, or the user may commit to the protected branch directly
+func (r *Repository) CanCommitToBranch(doer *models.User) (bool, error) {
+	protectedBranch, err := models.GetProtectedBranchBy(r.Repository.ID, r.BranchName)
 	if err != nil {
 		return false, err
 	}
-	return r.CanEnableEditor() && !protectedBranch, nil
+	if protectedBranch != nil && !protectedBranch.CanUserCommit(r.Repository, doer) {
+		return false, nil
+	}
+	return r.CanEnableEditor(), nil
 }
 
 // GetEditorconfig returns the .editorconfig definition if found in the
@@ -556,5 +559,6 @@ func UnitTypes() macaron.Handler {
 		ctx.Data["UnitTypeWiki"] = models.UnitTypeWiki
 		ctx.Data["UnitTypeExternalWiki"] = models.UnitTypeExternalWiki
 		ctx.Data["UnitTypeExternalTracker"] = models.UnitTypeExternalTracker
+		ctx.Data["UnitTypeProjects"] = models.UnitTypeProjects
 	}
 }
@@ -0,0 +1,29 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"code.gitea.io/git"
+)
+
+// Blob represents a blob object of a repository
+type Blob struct {
+	ID   git.SHA1
+	repo *git.Repository
+}
+
+// GetBlob finds the blob object with given ID in the repository.
+func GetBlob(repo *git.Repository, idStr string) (*Blob, error) {
+	id, err := getObjectOfType(repo, idStr, git.ObjectBlob)
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{ID: id, repo: repo}, nil
+}
+
+// Data returns the content of the blob
+func (b *Blob) Data() ([]byte, error) {
+	return git.NewCommand("cat-file", "blob", b.ID.String()).RunInDirBytes(b.repo.Path)
+}
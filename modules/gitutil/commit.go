@@ -0,0 +1,119 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/git"
+)
+
+// CommitChangesOptions the options when a commit created
+type CommitChangesOptions struct {
+	Committer *git.Signature
+	Author    *git.Signature
+	Message   string
+	// SigningKey is the GPG key to sign the commit with, the commit is not signed if empty.
+	SigningKey string
+}
+
+// CommitChanges commits local changes with given committer, author and message,
+// signed with given key. If author is nil, it will be the same as committer.
+func CommitChanges(repoPath string, opts CommitChangesOptions) error {
+	cmd := git.NewCommand()
+	if opts.Committer != nil {
+		cmd.AddArguments("-c", "user.name="+opts.Committer.Name, "-c", "user.email="+opts.Committer.Email)
+	}
+	cmd.AddArguments("commit")
+
+	if opts.Author == nil {
+		opts.Author = opts.Committer
+	}
+	if opts.Author != nil {
+		cmd.AddArguments(fmt.Sprintf("--author='%s <%s>'", opts.Author.Name, opts.Author.Email))
+	}
+	if opts.SigningKey != "" {
+		cmd.AddArguments("-S" + opts.SigningKey)
+	} else {
+		cmd.AddArguments("--no-gpg-sign")
+	}
+	cmd.AddArguments("-m", opts.Message)
+
+	_, err := cmd.RunInDir(repoPath)
+	// No stderr but exit status 1 means nothing to commit.
+	if err != nil && err.Error() == "exit status 1" {
+		return nil
+	}
+	return err
+}
+
+// parseCommitData parses the raw data of a commit object, headers are
+// separated from the message by an empty line. The parents of the commit
+// are not parsed and it is not bound to a repository.
+func parseCommitData(data []byte) (*git.Commit, error) {
+	commit := new(git.Commit)
+	for pos := 0; pos < len(data); {
+		eol := bytes.IndexByte(data[pos:], '\n')
+		if eol == 0 {
+			commit.CommitMessage = string(data[pos+1:])
+			break
+		} else if eol < 0 {
+			eol = len(data) - pos
+		}
+		start := pos
+		line := data[pos : pos+eol]
+		pos += eol + 1
+
+		// continuation lines of a multi-line header start with a space
+		value := append([]byte(nil), line...)
+		for pos < len(data) && data[pos] == ' ' {
+			eol = bytes.IndexByte(data[pos:], '\n')
+			if eol < 0 {
+				eol = len(data) - pos
+			}
+			value = append(value, '\n')
+			value = append(value, data[pos+1:pos+eol]...)
+			pos += eol + 1
+		}
+		if pos > len(data) {
+			pos = len(data)
+		}
+
+		spacepos := bytes.IndexByte(value, ' ')
+		if spacepos < 0 {
+			continue
+		}
+		key := string(value[:spacepos])
+		value = value[spacepos+1:]
+		switch key {
+		case "tree":
+			id, err := git.NewIDFromString(string(value))
+			if err != nil {
+				return nil, err
+			}
+			commit.Tree.ID = id
+		case "author":
+			sig, err := parseSignature(value)
+			if err != nil {
+				return nil, err
+			}
+			commit.Author = sig
+		case "committer":
+			sig, err := parseSignature(value)
+			if err != nil {
+				return nil, err
+			}
+			commit.Committer = sig
+		case "gpgsig":
+			// the signed payload is the commit without the signature header
+			commit.Signature = &git.CommitGPGSignature{
+				Signature: string(value),
+				Payload:   string(data[:start]) + string(data[pos:]),
+			}
+		}
+	}
+	return commit, nil
+}
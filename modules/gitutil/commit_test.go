@@ -0,0 +1,111 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitChangesWithoutSigningKey(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "gitea-gitutil-commit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	assert.NoError(t, git.InitRepository(repoPath, false))
+
+	// the commit must not be signed even if the repository asks for it
+	_, err = git.NewCommand("config", "commit.gpgsign", "true").RunInDir(repoPath)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, "README"), []byte("readme"), 0644))
+	assert.NoError(t, git.AddChanges(repoPath, true))
+	assert.NoError(t, CommitChanges(repoPath, CommitChangesOptions{
+		Committer: &git.Signature{Name: "Committer", Email: "committer@example.com"},
+		Author:    &git.Signature{Name: "Author", Email: "author@example.com"},
+		Message:   "Add README",
+	}))
+
+	stdout, err := git.NewCommand("log", "-1", "--format=%an <%ae>|%cn <%ce>|%s").RunInDir(repoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Author <author@example.com>|Committer <committer@example.com>|Add README\n", stdout)
+}
+
+func TestParseCommitData(t *testing.T) {
+	data := []byte(`tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author User One <user1@example.com> 1500000000 +0200
+committer User Two <user2@example.com> 1500000060 +0200
+gpgsig -----BEGIN PGP SIGNATURE-----
+ 
+ c2lnbmF0dXJl
+ -----END PGP SIGNATURE-----
+
+Add README
+`)
+	commit, err := parseCommitData(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", commit.Tree.ID.String())
+	assert.Equal(t, "user1@example.com", commit.Author.Email)
+	assert.Equal(t, "User Two", commit.Committer.Name)
+	assert.EqualValues(t, 1500000060, commit.Committer.When.Unix())
+	assert.Equal(t, "Add README\n", commit.CommitMessage)
+	if assert.NotNil(t, commit.Signature) {
+		assert.Equal(t, "-----BEGIN PGP SIGNATURE-----\n\nc2lnbmF0dXJl\n-----END PGP SIGNATURE-----", commit.Signature.Signature)
+		assert.Equal(t, `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author User One <user1@example.com> 1500000000 +0200
+committer User Two <user2@example.com> 1500000060 +0200
+
+Add README
+`, commit.Signature.Payload)
+	}
+}
+
+func TestGetCommitsInRange(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "gitea-gitutil-range")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	assert.NoError(t, git.InitRepository(repoPath, false))
+
+	commitFile := func(name string) string {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, name), []byte(name), 0644))
+		assert.NoError(t, git.AddChanges(repoPath, true))
+		assert.NoError(t, CommitChanges(repoPath, CommitChangesOptions{
+			Committer: &git.Signature{Name: "Committer", Email: "committer@example.com"},
+			Message:   "Add " + name,
+		}))
+		stdout, err := git.NewCommand("rev-parse", "HEAD").RunInDir(repoPath)
+		assert.NoError(t, err)
+		return stdout[:40]
+	}
+	first := commitFile("first")
+	second := commitFile("second")
+
+	commits, err := GetCommitsInRange(repoPath, nil, first, second)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, second, commits[0].ID.String())
+		assert.Equal(t, "committer@example.com", commits[0].Committer.Email)
+		assert.Equal(t, "Add second\n", commits[0].CommitMessage)
+		assert.Nil(t, commits[0].Signature)
+	}
+
+	// the objects of another repository are only found through the environment,
+	// like the objects of a push in quarantine
+	otherPath, err := ioutil.TempDir("", "gitea-gitutil-range")
+	assert.NoError(t, err)
+	defer os.RemoveAll(otherPath)
+	assert.NoError(t, git.InitRepository(otherPath, true))
+
+	_, err = GetCommitsInRange(otherPath, nil, git.EmptySHA, second)
+	assert.Error(t, err)
+	commits, err = GetCommitsInRange(otherPath, []string{"GIT_ALTERNATE_OBJECT_DIRECTORIES=" + filepath.Join(repoPath, ".git", "objects")}, git.EmptySHA, second)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// CommitsByFileAndRangeSize returns the commits of given page and page size
+// according to the revision, and which changed the file if it is not empty.
+func CommitsByFileAndRangeSize(repo *git.Repository, revision, file string, page, pageSize int) ([]*git.Commit, error) {
+	cmd := git.NewCommand("log", revision, "--skip="+strconv.Itoa((page-1)*pageSize),
+		"--max-count="+strconv.Itoa(pageSize), "--format=%H")
+	if len(file) > 0 {
+		cmd.AddArguments("--", file)
+	}
+	stdout, err := cmd.RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*git.Commit, 0, pageSize)
+	for _, sha := range strings.Fields(stdout) {
+		commit, err := repo.GetCommit(sha)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// GetCommitsInRange returns the commits of the repository which are reachable
+// from newRev but not from oldRev, or from none of the references of the
+// repository if oldRev is empty or git.EmptySHA. The environment variables
+// are added to the git commands, e.g. to read the objects of a push which is
+// still in quarantine. The parents of the commits are not loaded.
+func GetCommitsInRange(repoPath string, env []string, oldRev, newRev string) ([]*git.Commit, error) {
+	args := []string{"rev-list", newRev}
+	if len(oldRev) == 0 || oldRev == git.EmptySHA {
+		args = append(args, "--not", "--all")
+	} else {
+		args = append(args, "^"+oldRev)
+	}
+	stdout, err := runInDirWithEnv(repoPath, env, args...)
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list: %v", err)
+	}
+
+	ids := strings.Fields(string(stdout))
+	commits := make([]*git.Commit, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := git.NewIDFromString(idStr)
+		if err != nil {
+			return nil, err
+		}
+		data, err := runInDirWithEnv(repoPath, env, "cat-file", "commit", id.String())
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: %v", err)
+		}
+		commit, err := parseCommitData(data)
+		if err != nil {
+			return nil, err
+		}
+		commit.ID = id
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
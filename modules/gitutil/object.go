@@ -0,0 +1,35 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// GetObjectType returns the type of the object with given ID or revision.
+func GetObjectType(repo *git.Repository, rev string) (git.ObjectType, error) {
+	stdout, err := git.NewCommand("cat-file", "-t", rev).RunInDir(repo.Path)
+	if err != nil {
+		return "", git.ErrNotExist{ID: rev}
+	}
+	return git.ObjectType(strings.TrimSpace(stdout)), nil
+}
+
+// getObjectOfType returns the ID of the object with given ID if it has given type.
+func getObjectOfType(repo *git.Repository, idStr string, expected git.ObjectType) (git.SHA1, error) {
+	id, err := git.NewIDFromString(idStr)
+	if err != nil {
+		return id, git.ErrNotExist{ID: idStr}
+	}
+
+	if tp, err := GetObjectType(repo, id.String()); err != nil {
+		return id, err
+	} else if tp != expected {
+		return id, git.ErrNotExist{ID: id.String()}
+	}
+	return id, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// Reference represents a Git reference.
+type Reference struct {
+	Name   string
+	Object git.SHA1 // The id of the object the reference points to
+	Type   string
+}
+
+// GetRefsFiltered returns the references of the repository whose name starts
+// with given pattern, relative to refs/ (e.g. "heads" or "tags/v1").
+func GetRefsFiltered(repo *git.Repository, pattern string) ([]*Reference, error) {
+	stdout, err := git.NewCommand("for-each-ref", "--format=%(objectname) %(objecttype) %(refname)").RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*Reference, 0, 10)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if len(pattern) > 0 && !strings.HasPrefix(fields[2], "refs/"+pattern) {
+			continue
+		}
+		id, err := git.NewIDFromString(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, &Reference{
+			Name:   fields[2],
+			Object: id,
+			Type:   fields[1],
+		})
+	}
+	return refs, nil
+}
+
+// IsValidRefName returns true if the full name of a reference, e.g. "refs/tags/v1.0",
+// is acceptable to git.
+func IsValidRefName(name string) bool {
+	_, err := git.NewCommand("check-ref-format", name).Run()
+	return err == nil
+}
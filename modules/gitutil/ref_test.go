@@ -0,0 +1,20 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidRefName(t *testing.T) {
+	assert.True(t, IsValidRefName("refs/tags/v1.0"))
+	assert.True(t, IsValidRefName("refs/tags/release/v1.1"))
+	assert.False(t, IsValidRefName("refs/tags/v1..0"))
+	assert.False(t, IsValidRefName("refs/tags/v1.0.lock"))
+	assert.False(t, IsValidRefName("refs/tags/v1 0"))
+	assert.False(t, IsValidRefName("refs/tags/"))
+}
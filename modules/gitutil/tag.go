@@ -0,0 +1,121 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"code.gitea.io/git"
+)
+
+// CreateAnnotatedTag creates an annotated tag with given message and tagger in the repository
+func CreateAnnotatedTag(repo *git.Repository, name, message, revision string, tagger *git.Signature) error {
+	_, err := git.NewCommand("-c", "user.name="+tagger.Name, "-c", "user.email="+tagger.Email,
+		"tag", "-a", "-m", message, name, revision).RunInDir(repo.Path)
+	return err
+}
+
+// DeleteTag deletes a tag of the repository
+func DeleteTag(repo *git.Repository, name string) error {
+	_, err := git.NewCommand("tag", "-d", name).RunInDir(repo.Path)
+	return err
+}
+
+// GetAnnotatedTag returns a Git annotated tag by its SHA.
+func GetAnnotatedTag(repo *git.Repository, idStr string) (*git.Tag, error) {
+	id, err := getObjectOfType(repo, idStr, git.ObjectTag)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := git.NewCommand("cat-file", "tag", id.String()).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := parseTagData(data)
+	if err != nil {
+		return nil, err
+	}
+	tag.ID = id
+	return tag, nil
+}
+
+// parseTagData parses the raw data of an annotated tag object,
+// headers are separated from the message by an empty line.
+func parseTagData(data []byte) (*git.Tag, error) {
+	tag := new(git.Tag)
+	for len(data) > 0 {
+		eol := bytes.IndexByte(data, '\n')
+		if eol == 0 {
+			tag.Message = string(data[1:])
+			break
+		} else if eol < 0 {
+			eol = len(data)
+		}
+		line := data[:eol]
+		data = data[eol:]
+		if len(data) > 0 {
+			data = data[1:]
+		}
+
+		spacepos := bytes.IndexByte(line, ' ')
+		if spacepos < 0 {
+			continue
+		}
+		value := line[spacepos+1:]
+		switch string(line[:spacepos]) {
+		case "object":
+			id, err := git.NewIDFromString(string(value))
+			if err != nil {
+				return nil, err
+			}
+			tag.Object = id
+		case "type":
+			tag.Type = string(value)
+		case "tag":
+			tag.Name = string(value)
+		case "tagger":
+			sig, err := parseSignature(value)
+			if err != nil {
+				return nil, err
+			}
+			tag.Tagger = sig
+		}
+	}
+	return tag, nil
+}
+
+// parseSignature parses a signature line of a Git object,
+// e.g. "User One <user1@example.com> 1500000000 +0200".
+func parseSignature(line []byte) (*git.Signature, error) {
+	emailStart := bytes.IndexByte(line, '<')
+	emailEnd := bytes.IndexByte(line, '>')
+	if emailStart < 0 || emailEnd < emailStart {
+		return nil, fmt.Errorf("invalid signature: %s", line)
+	}
+
+	sig := &git.Signature{
+		Name:  string(bytes.TrimSpace(line[:emailStart])),
+		Email: string(line[emailStart+1 : emailEnd]),
+	}
+	fields := bytes.Fields(line[emailEnd+1:])
+	if len(fields) == 0 {
+		return sig, nil
+	}
+	seconds, err := strconv.ParseInt(string(fields[0]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature time: %s", line)
+	}
+	sig.When = time.Unix(seconds, 0)
+	if len(fields) > 1 {
+		if zone, err := time.Parse("-0700", string(fields[1])); err == nil {
+			sig.When = sig.When.In(zone.Location())
+		}
+	}
+	return sig, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagData(t *testing.T) {
+	tag, err := parseTagData([]byte(`object 65f1bf27bc3bf70f64657658635e66094edbcb4d
+type commit
+tag v1.1
+tagger User Two <user2@example.com> 1500000000 +0200
+
+Release v1.1
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", tag.Object.String())
+	assert.Equal(t, "commit", tag.Type)
+	assert.Equal(t, "v1.1", tag.Name)
+	assert.Equal(t, "Release v1.1\n", tag.Message)
+	if assert.NotNil(t, tag.Tagger) {
+		assert.Equal(t, "User Two", tag.Tagger.Name)
+		assert.Equal(t, "user2@example.com", tag.Tagger.Email)
+		assert.EqualValues(t, 1500000000, tag.Tagger.When.Unix())
+		_, offset := tag.Tagger.When.Zone()
+		assert.Equal(t, 2*60*60, offset)
+	}
+}
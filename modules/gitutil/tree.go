@@ -0,0 +1,72 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/git"
+)
+
+// TreeEntry is an entry of a tree listed with its mode and size
+type TreeEntry struct {
+	Path string // relative to the listed tree
+	Mode string // octal mode as used by git, e.g. "100644"
+	Type git.ObjectType
+	ID   git.SHA1
+	Size int64 // only set for blobs
+}
+
+// ListTreeEntries returns the entries of the tree with given ID, including
+// the entries of its sub trees if recursive is true.
+func ListTreeEntries(repo *git.Repository, treeID git.SHA1, recursive bool) ([]*TreeEntry, error) {
+	cmd := git.NewCommand("ls-tree", "-l", "-z")
+	if recursive {
+		cmd.AddArguments("-t", "-r")
+	}
+	stdout, err := cmd.AddArguments(treeID.String()).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTreeEntries(stdout)
+}
+
+// parseTreeEntries parses the output of "git ls-tree -l -z", whose entries
+// are "<mode> SP <type> SP <object> SP+ <size> TAB <path> NUL".
+func parseTreeEntries(data []byte) ([]*TreeEntry, error) {
+	entries := make([]*TreeEntry, 0, 10)
+	for _, line := range bytes.Split(data, []byte{0}) {
+		if len(line) == 0 {
+			continue
+		}
+		tab := bytes.IndexByte(line, '\t')
+		if tab < 0 {
+			return nil, fmt.Errorf("invalid ls-tree output: %s", line)
+		}
+		fields := bytes.Fields(line[:tab])
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid ls-tree output: %s", line)
+		}
+		id, err := git.NewIDFromString(string(fields[2]))
+		if err != nil {
+			return nil, err
+		}
+		entry := &TreeEntry{
+			Path: string(line[tab+1:]),
+			Mode: string(fields[0]),
+			Type: git.ObjectType(fields[1]),
+			ID:   id,
+		}
+		if entry.Type == git.ObjectBlob {
+			if entry.Size, err = strconv.ParseInt(string(fields[3]), 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTreeEntries(t *testing.T) {
+	entries, err := parseTreeEntries([]byte("100644 blob 4b4851ad51df6a7d9f25c979345979eaeb5b349f      30\tREADME.md\x00" +
+		"040000 tree 65f1bf27bc3bf70f64657658635e66094edbcb4d       -\tdocs\x00" +
+		"100755 blob 2a47ca4b614a9f5a43abbd5ad851a54a616ffee6    1024\tdocs/a file\twith tab\x00"))
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, &TreeEntry{
+			Path: "README.md",
+			Mode: "100644",
+			Type: git.ObjectBlob,
+			ID:   git.MustIDFromString("4b4851ad51df6a7d9f25c979345979eaeb5b349f"),
+			Size: 30,
+		}, entries[0])
+		assert.Equal(t, "docs", entries[1].Path)
+		assert.Equal(t, git.ObjectTree, entries[1].Type)
+		assert.EqualValues(t, 0, entries[1].Size)
+		assert.Equal(t, "docs/a file\twith tab", entries[2].Path)
+		assert.Equal(t, "100755", entries[2].Mode)
+		assert.EqualValues(t, 1024, entries[2].Size)
+	}
+}
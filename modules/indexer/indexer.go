@@ -6,9 +6,13 @@ package indexer
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // NewContext start indexer service
 func NewContext() {
 	models.InitIssueIndexer()
+	if setting.Indexer.RepoIndexerEnabled {
+		models.InitRepoIndexer()
+	}
 }
@@ -0,0 +1,48 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentStorePut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitea-lfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s, err := storage.NewLocalStorage(dir)
+	assert.NoError(t, err)
+	contentStore := &ContentStore{ObjectStorage: s}
+
+	content := "gitea lfs object"
+	hash := sha256.Sum256([]byte(content))
+	meta := &models.LFSMetaObject{Oid: hex.EncodeToString(hash[:]), Size: int64(len(content))}
+
+	assert.Equal(t, errSizeMismatch, contentStore.Put(meta, strings.NewReader("gitea")))
+	assert.False(t, contentStore.Exists(meta))
+
+	assert.NoError(t, contentStore.Put(meta, strings.NewReader(content)))
+	assert.True(t, contentStore.Exists(meta))
+
+	// a broken upload must not touch the stored object
+	assert.Equal(t, errSizeMismatch, contentStore.Put(meta, strings.NewReader("gitea")))
+	assert.Equal(t, errHashMismatch, contentStore.Put(meta, strings.NewReader("GITEA LFS OBJECT")))
+	f, err := contentStore.Get(meta, 0)
+	assert.NoError(t, err)
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
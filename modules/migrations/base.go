@@ -0,0 +1,186 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotSupported is returned by a Downloader which cannot provide the
+	// requested kind of data
+	ErrNotSupported = errors.New("not supported")
+)
+
+// MigrateOptions defines what gets migrated from where
+type MigrateOptions struct {
+	// RemoteURL is the git clone address, user credentials included
+	RemoteURL    string
+	AuthUsername string
+	AuthPassword string
+	Name         string
+	Description  string
+	Private      bool
+	Mirror       bool
+
+	Issues       bool
+	PullRequests bool
+	Labels       bool
+	Milestones   bool
+	Releases     bool
+}
+
+// hasTrackerItems returns true if anything besides the git data should be
+// migrated
+func (opts MigrateOptions) hasTrackerItems() bool {
+	return opts.Issues || opts.PullRequests || opts.Labels || opts.Milestones || opts.Releases
+}
+
+// Repository describes a repository on the remote site
+type Repository struct {
+	Name        string
+	Owner       string
+	IsPrivate   bool
+	Description string
+	CloneURL    string
+	OriginalURL string
+}
+
+// Milestone describes a milestone
+type Milestone struct {
+	Title       string
+	Description string
+	Deadline    *time.Time
+	Created     time.Time
+	Updated     *time.Time
+	Closed      *time.Time
+	State       string // open or closed
+}
+
+// Label describes a label
+type Label struct {
+	Name  string
+	Color string // six hexadecimal digits without leading '#'
+}
+
+// Issue describes an issue
+type Issue struct {
+	Number      int64
+	PosterID    int64
+	PosterName  string
+	PosterEmail string
+	Title       string
+	Content     string
+	Milestone   string
+	State       string // open or closed
+	Created     time.Time
+	Updated     time.Time
+	Closed      *time.Time
+	Labels      []*Label
+}
+
+// Comment describes a comment of an issue or pull request
+type Comment struct {
+	IssueNumber int64
+	PosterID    int64
+	PosterName  string
+	PosterEmail string
+	Created     time.Time
+	Updated     time.Time
+	Content     string
+}
+
+// PullRequestBranch describes the head or base of a pull request
+type PullRequestBranch struct {
+	CloneURL  string
+	Ref       string
+	SHA       string
+	RepoName  string
+	OwnerName string
+}
+
+// PullRequest describes a pull request
+type PullRequest struct {
+	Number         int64
+	PosterID       int64
+	PosterName     string
+	PosterEmail    string
+	Title          string
+	Content        string
+	Milestone      string
+	State          string // open or closed
+	Created        time.Time
+	Updated        time.Time
+	Closed         *time.Time
+	Labels         []*Label
+	Merged         bool
+	MergedTime     *time.Time
+	MergeCommitSHA string
+	Head           PullRequestBranch
+	Base           PullRequestBranch
+}
+
+// IsForkPullRequest returns true if the head of the pull request is in
+// another repository than its base
+func (pr *PullRequest) IsForkPullRequest() bool {
+	return pr.Head.RepoName != pr.Base.RepoName || pr.Head.OwnerName != pr.Base.OwnerName
+}
+
+// Release describes a release
+type Release struct {
+	TagName         string
+	TargetCommitish string
+	Name            string
+	Body            string
+	Draft           bool
+	Prerelease      bool
+	PublisherID     int64
+	PublisherName   string
+	PublisherEmail  string
+	Created         time.Time
+	Published       time.Time
+}
+
+// Downloader downloads the data of a repository from a remote site.
+// Issues are always downloaded before pull requests.
+type Downloader interface {
+	GetRepoInfo() (*Repository, error)
+	GetMilestones() ([]*Milestone, error)
+	GetLabels() ([]*Label, error)
+	GetReleases() ([]*Release, error)
+	// GetIssues returns a page of issues, pages are counted from 1, and
+	// whether it was the last page
+	GetIssues(page, perPage int) ([]*Issue, bool, error)
+	// GetComments returns the comments of the issue or pull request
+	// with the given number
+	GetComments(number int64) ([]*Comment, error)
+	// GetPullRequests returns a page of pull requests, pages are counted
+	// from 1, and whether it was the last page
+	GetPullRequests(page, perPage int) ([]*PullRequest, bool, error)
+}
+
+// DownloaderFactory creates a Downloader for the remote sites it supports
+type DownloaderFactory interface {
+	// Match returns true if the factory can handle the remote URL
+	Match(opts MigrateOptions) (bool, error)
+	New(opts MigrateOptions) (Downloader, error)
+	// Provider returns the name of the OAuth2 provider of the remote site,
+	// used to map remote users to local ones
+	Provider() string
+}
+
+// Uploader stores the data of a downloaded repository
+type Uploader interface {
+	CreateRepo(repo *Repository, opts MigrateOptions) error
+	CreateMilestones(milestones ...*Milestone) error
+	CreateLabels(labels ...*Label) error
+	CreateReleases(releases ...*Release) error
+	CreateIssues(issues ...*Issue) error
+	CreateComments(comments ...*Comment) error
+	CreatePullRequests(prs ...*PullRequest) error
+	// Rollback removes everything stored so far
+	Rollback() error
+}
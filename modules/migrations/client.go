@@ -0,0 +1,63 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/httplib"
+)
+
+// maxPerPage is the page size used to list all items of a kind
+const maxPerPage = 100
+
+// apiClient requests the JSON API of a remote site
+type apiClient struct {
+	baseURL  string
+	username string
+	password string
+	headers  map[string]string
+}
+
+// getJSON requests the given path below the base URL and decodes the JSON
+// response into v
+func (c *apiClient) getJSON(path string, v interface{}) error {
+	req := httplib.Get(c.baseURL+path).
+		SetTimeout(30*time.Second, time.Minute).
+		Header("Accept", "application/json")
+	if len(c.username)+len(c.password) > 0 {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for key, value := range c.headers {
+		req.Header(key, value)
+	}
+
+	resp, err := req.Response()
+	if err != nil {
+		return fmt.Errorf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode %s: %v", path, err)
+	}
+	return nil
+}
+
+// pagePath appends the pagination parameters to the given path
+func pagePath(path string, page, perPage int) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%spage=%d&per_page=%d", path, sep, page, perPage)
+}
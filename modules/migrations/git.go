@@ -0,0 +1,67 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+var (
+	_ Downloader = &PlainGitDownloader{}
+)
+
+// PlainGitDownloader implements a Downloader interface to clone a plain git
+// repository, which has no tracker data to migrate
+type PlainGitDownloader struct {
+	ownerName string
+	repoName  string
+	remoteURL string
+}
+
+// NewPlainGitDownloader creates a git Downloader
+func NewPlainGitDownloader(ownerName, repoName, remoteURL string) *PlainGitDownloader {
+	return &PlainGitDownloader{
+		ownerName: ownerName,
+		repoName:  repoName,
+		remoteURL: remoteURL,
+	}
+}
+
+// GetRepoInfo returns a repository information
+func (g *PlainGitDownloader) GetRepoInfo() (*Repository, error) {
+	// the remote address may contain credentials, so it is not exposed
+	// as the original URL
+	return &Repository{
+		Owner:    g.ownerName,
+		Name:     g.repoName,
+		CloneURL: g.remoteURL,
+	}, nil
+}
+
+// GetMilestones returns milestones
+func (g *PlainGitDownloader) GetMilestones() ([]*Milestone, error) {
+	return nil, ErrNotSupported
+}
+
+// GetLabels returns labels
+func (g *PlainGitDownloader) GetLabels() ([]*Label, error) {
+	return nil, ErrNotSupported
+}
+
+// GetReleases returns releases
+func (g *PlainGitDownloader) GetReleases() ([]*Release, error) {
+	return nil, ErrNotSupported
+}
+
+// GetIssues returns issues according page and perPage
+func (g *PlainGitDownloader) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	return nil, false, ErrNotSupported
+}
+
+// GetComments returns comments according issueNumber
+func (g *PlainGitDownloader) GetComments(number int64) ([]*Comment, error) {
+	return nil, ErrNotSupported
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (g *PlainGitDownloader) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	return nil, false, ErrNotSupported
+}
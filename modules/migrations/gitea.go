@@ -0,0 +1,410 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+var (
+	_ Uploader = &GiteaLocalUploader{}
+)
+
+// GiteaLocalUploader implements an Uploader storing the migrated data in a
+// new repository of this instance
+type GiteaLocalUploader struct {
+	doer     *models.User
+	owner    *models.User
+	repoName string
+	provider string
+	repo     *models.Repository
+	gitRepo  *git.Repository
+
+	labels     map[string]*models.Label
+	milestones map[string]int64
+	// issues maps the issue and pull request numbers to their IDs
+	issues map[int64]int64
+	// userIDs maps remote user IDs to local ones, 0 if there is none
+	userIDs map[int64]int64
+}
+
+// NewGiteaLocalUploader creates a gitea Uploader. Remote users are mapped to
+// the local users who linked their account of the OAuth2 provider, e.g.
+// "github", to their local one.
+func NewGiteaLocalUploader(doer, owner *models.User, repoName, provider string) *GiteaLocalUploader {
+	return &GiteaLocalUploader{
+		doer:       doer,
+		owner:      owner,
+		repoName:   repoName,
+		provider:   provider,
+		labels:     make(map[string]*models.Label),
+		milestones: make(map[string]int64),
+		issues:     make(map[int64]int64),
+		userIDs:    make(map[int64]int64),
+	}
+}
+
+// CreateRepo clones the git data, the wiki included, into a new repository
+func (g *GiteaLocalUploader) CreateRepo(repo *Repository, opts MigrateOptions) error {
+	r, err := models.MigrateRepository(g.doer, g.owner, models.MigrateRepoOptions{
+		Name:        g.repoName,
+		Description: opts.Description,
+		IsPrivate:   opts.Private,
+		IsMirror:    opts.Mirror,
+		RemoteAddr:  opts.RemoteURL,
+	})
+	// the repository may have been created even if the migration failed
+	g.repo = r
+	if err != nil {
+		return err
+	}
+
+	g.gitRepo, err = git.OpenRepository(r.RepoPath())
+	return err
+}
+
+// localUserID returns the ID of the local user of the given remote user, or
+// 0 if there is none.
+func (g *GiteaLocalUploader) localUserID(remoteID int64, email string) (int64, error) {
+	if userID, ok := g.userIDs[remoteID]; ok && remoteID > 0 {
+		return userID, nil
+	}
+
+	var userID int64
+	if len(g.provider) > 0 && remoteID > 0 {
+		var err error
+		userID, err = models.GetUserIDByExternalUserID(g.provider, strconv.FormatInt(remoteID, 10))
+		if err != nil {
+			return 0, fmt.Errorf("GetUserIDByExternalUserID: %v", err)
+		}
+	}
+	if userID == 0 && len(email) > 0 {
+		u, err := models.GetUserByEmail(email)
+		if err == nil {
+			userID = u.ID
+		} else if !models.IsErrUserNotExist(err) {
+			return 0, fmt.Errorf("GetUserByEmail: %v", err)
+		}
+	}
+
+	if remoteID > 0 {
+		g.userIDs[remoteID] = userID
+	}
+	return userID, nil
+}
+
+// poster returns the local user to post content of the given remote user as,
+// which is the migrating user if the remote one is not known locally. The
+// content is prefixed with the name of the original author in that case.
+func (g *GiteaLocalUploader) poster(remoteID int64, name, email, content string) (int64, string, error) {
+	userID, err := g.localUserID(remoteID, email)
+	if err != nil {
+		return 0, "", err
+	} else if userID > 0 {
+		return userID, content, nil
+	}
+
+	if len(name) > 0 {
+		content = fmt.Sprintf("*Originally posted by %s*\n\n%s", name, content)
+	}
+	return g.doer.ID, content, nil
+}
+
+// CreateMilestones creates milestones
+func (g *GiteaLocalUploader) CreateMilestones(milestones ...*Milestone) error {
+	defaultDeadline, _ := time.ParseInLocation("2006-01-02", "9999-12-31", time.Local)
+
+	ms := make([]*models.Milestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		m := &models.Milestone{
+			RepoID:   g.repo.ID,
+			Name:     milestone.Title,
+			Content:  milestone.Description,
+			IsClosed: milestone.State == "closed",
+			Deadline: defaultDeadline,
+		}
+		if milestone.Deadline != nil {
+			m.Deadline = *milestone.Deadline
+		}
+		if milestone.Closed != nil {
+			m.ClosedDateUnix = milestone.Closed.Unix()
+		}
+		ms = append(ms, m)
+	}
+
+	if err := models.InsertMilestones(ms...); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		g.milestones[m.Name] = m.ID
+	}
+	return nil
+}
+
+// CreateLabels creates labels
+func (g *GiteaLocalUploader) CreateLabels(labels ...*Label) error {
+	ls := make([]*models.Label, 0, len(labels))
+	for _, label := range labels {
+		color := "#" + strings.ToLower(label.Color)
+		if len(color) != 7 {
+			color = "#e4e4e4"
+		}
+		ls = append(ls, &models.Label{
+			RepoID: g.repo.ID,
+			Name:   label.Name,
+			Color:  color,
+		})
+	}
+
+	if err := models.NewLabels(ls...); err != nil {
+		return err
+	}
+	for _, l := range ls {
+		g.labels[l.Name] = l
+	}
+	return nil
+}
+
+// convertLabels returns the migrated labels with the names of the given
+// ones, other labels are dropped
+func (g *GiteaLocalUploader) convertLabels(labels []*Label) []*models.Label {
+	ls := make([]*models.Label, 0, len(labels))
+	for _, label := range labels {
+		if l, ok := g.labels[label.Name]; ok {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// CreateReleases creates releases
+func (g *GiteaLocalUploader) CreateReleases(releases ...*Release) error {
+	rels := make([]*models.Release, 0, len(releases))
+	for _, release := range releases {
+		publisherID, note, err := g.poster(release.PublisherID, release.PublisherName, release.PublisherEmail, release.Body)
+		if err != nil {
+			return err
+		}
+
+		rel := &models.Release{
+			RepoID:       g.repo.ID,
+			PublisherID:  publisherID,
+			TagName:      release.TagName,
+			LowerTagName: strings.ToLower(release.TagName),
+			Target:       release.TargetCommitish,
+			Title:        release.Name,
+			Note:         note,
+			IsDraft:      release.Draft,
+			IsPrerelease: release.Prerelease,
+			CreatedUnix:  release.Published.Unix(),
+		}
+		if !rel.IsDraft {
+			commit, err := g.gitRepo.GetTagCommit(rel.TagName)
+			if err != nil {
+				// keep the release without its missing tag
+				log.Warn("Release %s of %s has no tag, migrating it as draft: %v", rel.TagName, g.repoName, err)
+				rel.IsDraft = true
+			} else {
+				rel.Sha1 = commit.ID.String()
+				if rel.NumCommits, err = commit.CommitsCount(); err != nil {
+					return fmt.Errorf("CommitsCount: %v", err)
+				}
+			}
+		}
+		rels = append(rels, rel)
+	}
+
+	return models.InsertReleases(rels...)
+}
+
+// newIssue converts the common fields of issues and pull requests
+func (g *GiteaLocalUploader) newIssue(number, posterID int64, posterName, posterEmail, title, content, milestone, state string,
+	created, updated time.Time, labels []*Label) (*models.Issue, error) {
+	posterID, content, err := g.poster(posterID, posterName, posterEmail, content)
+	if err != nil {
+		return nil, err
+	}
+	if updated.IsZero() {
+		updated = created
+	}
+
+	return &models.Issue{
+		RepoID:      g.repo.ID,
+		Repo:        g.repo,
+		Index:       number,
+		PosterID:    posterID,
+		Title:       title,
+		Content:     content,
+		MilestoneID: g.milestones[milestone],
+		IsClosed:    state == "closed",
+		CreatedUnix: created.Unix(),
+		UpdatedUnix: updated.Unix(),
+		Labels:      g.convertLabels(labels),
+	}, nil
+}
+
+// CreateIssues creates issues
+func (g *GiteaLocalUploader) CreateIssues(issues ...*Issue) error {
+	iss := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		is, err := g.newIssue(issue.Number, issue.PosterID, issue.PosterName, issue.PosterEmail, issue.Title, issue.Content,
+			issue.Milestone, issue.State, issue.Created, issue.Updated, issue.Labels)
+		if err != nil {
+			return err
+		}
+		iss = append(iss, is)
+	}
+
+	if err := models.InsertIssues(iss...); err != nil {
+		return err
+	}
+	for _, is := range iss {
+		g.issues[is.Index] = is.ID
+	}
+	return nil
+}
+
+// CreateComments creates comments of issues and pull requests
+func (g *GiteaLocalUploader) CreateComments(comments ...*Comment) error {
+	cms := make([]*models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		issueID, ok := g.issues[comment.IssueNumber]
+		if !ok {
+			return fmt.Errorf("issue #%d has not been migrated", comment.IssueNumber)
+		}
+
+		posterID, content, err := g.poster(comment.PosterID, comment.PosterName, comment.PosterEmail, comment.Content)
+		if err != nil {
+			return err
+		}
+		updated := comment.Updated
+		if updated.IsZero() {
+			updated = comment.Created
+		}
+		cms = append(cms, &models.Comment{
+			Type:        models.CommentTypeComment,
+			IssueID:     issueID,
+			PosterID:    posterID,
+			Content:     content,
+			CreatedUnix: comment.Created.Unix(),
+			UpdatedUnix: updated.Unix(),
+		})
+	}
+
+	return models.InsertIssueComments(cms)
+}
+
+// hasCommit returns true if the commit is part of the migrated git data
+func (g *GiteaLocalUploader) hasCommit(sha string) bool {
+	if len(sha) == 0 {
+		return false
+	}
+	_, err := git.NewCommand("cat-file", "-e", sha+"^{commit}").RunInDir(g.gitRepo.Path)
+	return err == nil
+}
+
+// newPullRequest converts a pull request. Its head commit is referenced as
+// refs/pull/<index>/head like the ones of local pull requests, the head branch
+// is only used if it belongs to the migrated repository itself.
+func (g *GiteaLocalUploader) newPullRequest(pr *PullRequest) (*models.PullRequest, error) {
+	issue, err := g.newIssue(pr.Number, pr.PosterID, pr.PosterName, pr.PosterEmail, pr.Title, pr.Content,
+		pr.Milestone, pr.State, pr.Created, pr.Updated, pr.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	hasHead := g.hasCommit(pr.Head.SHA)
+	if hasHead {
+		headRef := fmt.Sprintf("refs/pull/%d/head", pr.Number)
+		if _, err = git.NewCommand("update-ref", headRef, pr.Head.SHA).RunInDir(g.gitRepo.Path); err != nil {
+			return nil, fmt.Errorf("update-ref %s: %v", headRef, err)
+		}
+	}
+
+	var mergeBase string
+	if hasHead {
+		base := pr.Base.SHA
+		if !g.hasCommit(base) {
+			base = pr.Base.Ref
+		}
+		if mergeBase, err = g.gitRepo.GetMergeBase(base, pr.Head.SHA); err != nil {
+			log.Warn("No merge base of pull request #%d of %s: %v", pr.Number, g.repoName, err)
+			mergeBase = ""
+		}
+	}
+
+	gpr := &models.PullRequest{
+		Type:         models.PullRequestGitea,
+		Status:       models.PullRequestStatusMergeable,
+		Issue:        issue,
+		BaseRepoID:   g.repo.ID,
+		HeadUserName: pr.Head.OwnerName,
+		HeadBranch:   pr.Head.Ref,
+		BaseBranch:   pr.Base.Ref,
+		MergeBase:    mergeBase,
+	}
+	if !pr.IsForkPullRequest() && g.gitRepo.IsBranchExist(pr.Head.Ref) {
+		gpr.HeadRepoID = g.repo.ID
+		gpr.HeadUserName = g.owner.Name
+	}
+
+	// merged pull requests are only shown as such if their commits are known,
+	// the merger is not part of the downloaded data
+	if pr.Merged && len(mergeBase) > 0 && g.hasCommit(pr.MergeCommitSHA) {
+		gpr.HasMerged = true
+		gpr.MergedCommitID = pr.MergeCommitSHA
+		if pr.MergedTime != nil {
+			gpr.MergedUnix = pr.MergedTime.Unix()
+		}
+	}
+
+	if !issue.IsClosed && len(mergeBase) > 0 {
+		patch, err := g.gitRepo.GetPatch(mergeBase, pr.Head.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("GetPatch: %v", err)
+		}
+		if err = g.repo.SavePatch(pr.Number, patch); err != nil {
+			return nil, fmt.Errorf("SavePatch: %v", err)
+		}
+	}
+	return gpr, nil
+}
+
+// CreatePullRequests creates pull requests
+func (g *GiteaLocalUploader) CreatePullRequests(prs ...*PullRequest) error {
+	gprs := make([]*models.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		gpr, err := g.newPullRequest(pr)
+		if err != nil {
+			return err
+		}
+		gprs = append(gprs, gpr)
+	}
+
+	if err := models.InsertPullRequests(gprs...); err != nil {
+		return err
+	}
+	for _, gpr := range gprs {
+		g.issues[gpr.Index] = gpr.IssueID
+	}
+	return nil
+}
+
+// Rollback deletes the repository created so far
+func (g *GiteaLocalUploader) Rollback() error {
+	if g.repo != nil && g.repo.ID > 0 {
+		if err := models.DeleteRepository(g.doer, g.owner.ID, g.repo.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	_ Downloader        = &GithubDownloaderV3{}
+	_ DownloaderFactory = &GithubDownloaderV3Factory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&GithubDownloaderV3Factory{})
+}
+
+// GithubDownloaderV3Factory defines a github downloader v3 factory
+type GithubDownloaderV3Factory struct {
+}
+
+// Match returns true if the migration remote URL matched this downloader factory
+func (f *GithubDownloaderV3Factory) Match(opts MigrateOptions) (bool, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(u.Host, "github.com"), nil
+}
+
+// New returns a Downloader related to this factory according MigrateOptions
+func (f *GithubDownloaderV3Factory) New(opts MigrateOptions) (Downloader, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid GitHub repository path: %s", u.Path)
+	}
+	oldOwner := fields[0]
+	oldName := strings.TrimSuffix(fields[1], ".git")
+
+	return NewGithubDownloaderV3("https://api.github.com", opts.AuthUsername, opts.AuthPassword, oldOwner, oldName), nil
+}
+
+// Provider returns the name of the OAuth2 provider of github
+func (f *GithubDownloaderV3Factory) Provider() string {
+	return "github"
+}
+
+// GithubDownloaderV3 implements a Downloader interface to get repository
+// information from github via APIv3
+type GithubDownloaderV3 struct {
+	client    *apiClient
+	repoOwner string
+	repoName  string
+}
+
+// NewGithubDownloaderV3 creates a github Downloader via github v3 API. The
+// password may also be a personal access token.
+func NewGithubDownloaderV3(baseURL, userName, password, repoOwner, repoName string) *GithubDownloaderV3 {
+	return &GithubDownloaderV3{
+		client: &apiClient{
+			baseURL:  strings.TrimSuffix(baseURL, "/"),
+			username: userName,
+			password: password,
+			headers:  map[string]string{"Accept": "application/vnd.github.v3+json"},
+		},
+		repoOwner: repoOwner,
+		repoName:  repoName,
+	}
+}
+
+func (g *GithubDownloaderV3) repoPath(path string) string {
+	return fmt.Sprintf("/repos/%s/%s%s", url.PathEscape(g.repoOwner), url.PathEscape(g.repoName), path)
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+type githubLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func convertGithubLabels(labels []githubLabel) []*Label {
+	result := make([]*Label, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, &Label{
+			Name:  label.Name,
+			Color: label.Color,
+		})
+	}
+	return result
+}
+
+type githubMilestone struct {
+	Title string `json:"title"`
+}
+
+func (m *githubMilestone) title() string {
+	if m == nil {
+		return ""
+	}
+	return m.Title
+}
+
+// GetRepoInfo returns a repository information
+func (g *GithubDownloaderV3) GetRepoInfo() (*Repository, error) {
+	var repo struct {
+		Name        string     `json:"name"`
+		Owner       githubUser `json:"owner"`
+		Private     bool       `json:"private"`
+		Description string     `json:"description"`
+		CloneURL    string     `json:"clone_url"`
+		HTMLURL     string     `json:"html_url"`
+	}
+	if err := g.client.getJSON(g.repoPath(""), &repo); err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Owner:       repo.Owner.Login,
+		Name:        repo.Name,
+		IsPrivate:   repo.Private,
+		Description: repo.Description,
+		CloneURL:    repo.CloneURL,
+		OriginalURL: repo.HTMLURL,
+	}, nil
+}
+
+// GetMilestones returns milestones
+func (g *GithubDownloaderV3) GetMilestones() ([]*Milestone, error) {
+	milestones := make([]*Milestone, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var ms []struct {
+			Title     string     `json:"title"`
+			Body      string     `json:"description"`
+			State     string     `json:"state"`
+			DueOn     *time.Time `json:"due_on"`
+			CreatedAt time.Time  `json:"created_at"`
+			UpdatedAt *time.Time `json:"updated_at"`
+			ClosedAt  *time.Time `json:"closed_at"`
+		}
+		if err := g.client.getJSON(pagePath(g.repoPath("/milestones?state=all"), page, maxPerPage), &ms); err != nil {
+			return nil, err
+		}
+
+		for _, m := range ms {
+			milestones = append(milestones, &Milestone{
+				Title:       m.Title,
+				Description: m.Body,
+				Deadline:    m.DueOn,
+				State:       m.State,
+				Created:     m.CreatedAt,
+				Updated:     m.UpdatedAt,
+				Closed:      m.ClosedAt,
+			})
+		}
+		if len(ms) < maxPerPage {
+			return milestones, nil
+		}
+	}
+}
+
+// GetLabels returns labels
+func (g *GithubDownloaderV3) GetLabels() ([]*Label, error) {
+	labels := make([]*Label, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var ls []githubLabel
+		if err := g.client.getJSON(pagePath(g.repoPath("/labels"), page, maxPerPage), &ls); err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, convertGithubLabels(ls)...)
+		if len(ls) < maxPerPage {
+			return labels, nil
+		}
+	}
+}
+
+// GetReleases returns releases
+func (g *GithubDownloaderV3) GetReleases() ([]*Release, error) {
+	releases := make([]*Release, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var rels []struct {
+			TagName         string     `json:"tag_name"`
+			TargetCommitish string     `json:"target_commitish"`
+			Name            string     `json:"name"`
+			Body            string     `json:"body"`
+			Draft           bool       `json:"draft"`
+			Prerelease      bool       `json:"prerelease"`
+			Author          githubUser `json:"author"`
+			CreatedAt       time.Time  `json:"created_at"`
+			PublishedAt     *time.Time `json:"published_at"`
+		}
+		if err := g.client.getJSON(pagePath(g.repoPath("/releases"), page, maxPerPage), &rels); err != nil {
+			return nil, err
+		}
+
+		for _, rel := range rels {
+			release := &Release{
+				TagName:         rel.TagName,
+				TargetCommitish: rel.TargetCommitish,
+				Name:            rel.Name,
+				Body:            rel.Body,
+				Draft:           rel.Draft,
+				Prerelease:      rel.Prerelease,
+				PublisherID:     rel.Author.ID,
+				PublisherName:   rel.Author.Login,
+				PublisherEmail:  rel.Author.Email,
+				Created:         rel.CreatedAt,
+				Published:       rel.CreatedAt,
+			}
+			if rel.PublishedAt != nil {
+				release.Published = *rel.PublishedAt
+			}
+			releases = append(releases, release)
+		}
+		if len(rels) < maxPerPage {
+			return releases, nil
+		}
+	}
+}
+
+// GetIssues returns issues according page and perPage
+func (g *GithubDownloaderV3) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	var issues []struct {
+		Number    int64            `json:"number"`
+		Title     string           `json:"title"`
+		Body      string           `json:"body"`
+		User      githubUser       `json:"user"`
+		State     string           `json:"state"`
+		Labels    []githubLabel    `json:"labels"`
+		Milestone *githubMilestone `json:"milestone"`
+		CreatedAt time.Time        `json:"created_at"`
+		UpdatedAt time.Time        `json:"updated_at"`
+		ClosedAt  *time.Time       `json:"closed_at"`
+		// PullRequest is set if the issue is actually a pull request
+		PullRequest *struct{} `json:"pull_request"`
+	}
+	path := g.repoPath("/issues?state=all&sort=created&direction=asc")
+	if err := g.client.getJSON(pagePath(path, page, perPage), &issues); err != nil {
+		return nil, false, err
+	}
+
+	result := make([]*Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.PullRequest != nil {
+			continue
+		}
+		result = append(result, &Issue{
+			Number:      issue.Number,
+			Title:       issue.Title,
+			Content:     issue.Body,
+			PosterID:    issue.User.ID,
+			PosterName:  issue.User.Login,
+			PosterEmail: issue.User.Email,
+			Milestone:   issue.Milestone.title(),
+			State:       issue.State,
+			Created:     issue.CreatedAt,
+			Updated:     issue.UpdatedAt,
+			Closed:      issue.ClosedAt,
+			Labels:      convertGithubLabels(issue.Labels),
+		})
+	}
+	return result, len(issues) < perPage, nil
+}
+
+// GetComments returns comments according issueNumber
+func (g *GithubDownloaderV3) GetComments(number int64) ([]*Comment, error) {
+	comments := make([]*Comment, 0, 10)
+	for page := 1; ; page++ {
+		var cs []struct {
+			User      githubUser `json:"user"`
+			Body      string     `json:"body"`
+			CreatedAt time.Time  `json:"created_at"`
+			UpdatedAt time.Time  `json:"updated_at"`
+		}
+		path := g.repoPath(fmt.Sprintf("/issues/%d/comments", number))
+		if err := g.client.getJSON(pagePath(path, page, maxPerPage), &cs); err != nil {
+			return nil, err
+		}
+
+		for _, c := range cs {
+			comments = append(comments, &Comment{
+				IssueNumber: number,
+				PosterID:    c.User.ID,
+				PosterName:  c.User.Login,
+				PosterEmail: c.User.Email,
+				Content:     c.Body,
+				Created:     c.CreatedAt,
+				Updated:     c.UpdatedAt,
+			})
+		}
+		if len(cs) < maxPerPage {
+			return comments, nil
+		}
+	}
+}
+
+type githubPullRequestBranch struct {
+	Ref  string `json:"ref"`
+	SHA  string `json:"sha"`
+	Repo *struct {
+		Name     string     `json:"name"`
+		Owner    githubUser `json:"owner"`
+		CloneURL string     `json:"clone_url"`
+	} `json:"repo"`
+}
+
+func (b githubPullRequestBranch) convert() PullRequestBranch {
+	branch := PullRequestBranch{
+		Ref: b.Ref,
+		SHA: b.SHA,
+	}
+	// the repository is null if it has been deleted
+	if b.Repo != nil {
+		branch.RepoName = b.Repo.Name
+		branch.OwnerName = b.Repo.Owner.Login
+		branch.CloneURL = b.Repo.CloneURL
+	}
+	return branch
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (g *GithubDownloaderV3) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	var prs []struct {
+		Number         int64                   `json:"number"`
+		Title          string                  `json:"title"`
+		Body           string                  `json:"body"`
+		User           githubUser              `json:"user"`
+		State          string                  `json:"state"`
+		Labels         []githubLabel           `json:"labels"`
+		Milestone      *githubMilestone        `json:"milestone"`
+		CreatedAt      time.Time               `json:"created_at"`
+		UpdatedAt      time.Time               `json:"updated_at"`
+		ClosedAt       *time.Time              `json:"closed_at"`
+		MergedAt       *time.Time              `json:"merged_at"`
+		MergeCommitSHA string                  `json:"merge_commit_sha"`
+		Head           githubPullRequestBranch `json:"head"`
+		Base           githubPullRequestBranch `json:"base"`
+	}
+	path := g.repoPath("/pulls?state=all&sort=created&direction=asc")
+	if err := g.client.getJSON(pagePath(path, page, perPage), &prs); err != nil {
+		return nil, false, err
+	}
+
+	result := make([]*PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		result = append(result, &PullRequest{
+			Number:         pr.Number,
+			Title:          pr.Title,
+			Content:        pr.Body,
+			PosterID:       pr.User.ID,
+			PosterName:     pr.User.Login,
+			PosterEmail:    pr.User.Email,
+			Milestone:      pr.Milestone.title(),
+			State:          pr.State,
+			Created:        pr.CreatedAt,
+			Updated:        pr.UpdatedAt,
+			Closed:         pr.ClosedAt,
+			Labels:         convertGithubLabels(pr.Labels),
+			Merged:         pr.MergedAt != nil,
+			MergedTime:     pr.MergedAt,
+			MergeCommitSHA: pr.MergeCommitSHA,
+			Head:           pr.Head.convert(),
+			Base:           pr.Base.convert(),
+		})
+	}
+	return result, len(prs) < perPage, nil
+}
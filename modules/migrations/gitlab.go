@@ -0,0 +1,416 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	_ Downloader        = &GitlabDownloader{}
+	_ DownloaderFactory = &GitlabDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&GitlabDownloaderFactory{})
+}
+
+// GitlabDownloaderFactory defines a gitlab downloader factory
+type GitlabDownloaderFactory struct {
+}
+
+// Match returns true if the migration remote URL matched this downloader factory
+func (f *GitlabDownloaderFactory) Match(opts MigrateOptions) (bool, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(u.Host, "gitlab.com"), nil
+}
+
+// New returns a Downloader related to this factory according MigrateOptions
+func (f *GitlabDownloaderFactory) New(opts MigrateOptions) (Downloader, error) {
+	u, err := url.Parse(opts.RemoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// projects may be nested in subgroups
+	repoPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if strings.Count(repoPath, "/") < 1 {
+		return nil, fmt.Errorf("invalid GitLab repository path: %s", u.Path)
+	}
+
+	return NewGitlabDownloader(u.Scheme+"://"+u.Host+"/api/v4", opts.AuthPassword, repoPath), nil
+}
+
+// Provider returns the name of the OAuth2 provider of gitlab
+func (f *GitlabDownloaderFactory) Provider() string {
+	return "gitlab"
+}
+
+// GitlabDownloader implements a Downloader interface to get repository
+// information from gitlab via API v4.
+// Issues and merge requests are numbered independently on gitlab, so the
+// merge requests are numbered after the highest issue number.
+type GitlabDownloader struct {
+	client   *apiClient
+	repoPath string
+
+	maxIssueNumber int64
+}
+
+// NewGitlabDownloader creates a gitlab Downloader via gitlab API v4. The
+// token is a personal access token and may be empty for public projects.
+func NewGitlabDownloader(baseURL, token, repoPath string) *GitlabDownloader {
+	headers := make(map[string]string, 1)
+	if len(token) > 0 {
+		headers["Private-Token"] = token
+	}
+	return &GitlabDownloader{
+		client: &apiClient{
+			baseURL: strings.TrimSuffix(baseURL, "/"),
+			headers: headers,
+		},
+		repoPath: repoPath,
+	}
+}
+
+func (g *GitlabDownloader) projectPath(path string) string {
+	return "/projects/" + url.PathEscape(g.repoPath) + path
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type gitlabMilestone struct {
+	Title string `json:"title"`
+}
+
+func (m *gitlabMilestone) title() string {
+	if m == nil {
+		return ""
+	}
+	return m.Title
+}
+
+func convertGitlabLabels(names []string) []*Label {
+	labels := make([]*Label, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, &Label{Name: name})
+	}
+	return labels
+}
+
+// convertGitlabState maps the state of gitlab issues and merge requests
+func convertGitlabState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return "closed"
+}
+
+// GetRepoInfo returns a repository information
+func (g *GitlabDownloader) GetRepoInfo() (*Repository, error) {
+	var project struct {
+		Path        string `json:"path"`
+		Visibility  string `json:"visibility"`
+		Description string `json:"description"`
+		HTTPURL     string `json:"http_url_to_repo"`
+		WebURL      string `json:"web_url"`
+		Namespace   struct {
+			FullPath string `json:"full_path"`
+		} `json:"namespace"`
+	}
+	if err := g.client.getJSON(g.projectPath(""), &project); err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Owner:       project.Namespace.FullPath,
+		Name:        project.Path,
+		IsPrivate:   project.Visibility == "private",
+		Description: project.Description,
+		CloneURL:    project.HTTPURL,
+		OriginalURL: project.WebURL,
+	}, nil
+}
+
+// GetMilestones returns milestones
+func (g *GitlabDownloader) GetMilestones() ([]*Milestone, error) {
+	milestones := make([]*Milestone, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var ms []struct {
+			Title       string     `json:"title"`
+			Description string     `json:"description"`
+			State       string     `json:"state"`
+			DueDate     string     `json:"due_date"`
+			CreatedAt   time.Time  `json:"created_at"`
+			UpdatedAt   *time.Time `json:"updated_at"`
+		}
+		if err := g.client.getJSON(pagePath(g.projectPath("/milestones"), page, maxPerPage), &ms); err != nil {
+			return nil, err
+		}
+
+		for _, m := range ms {
+			milestone := &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				State:       "open",
+				Created:     m.CreatedAt,
+				Updated:     m.UpdatedAt,
+			}
+			if len(m.DueDate) > 0 {
+				if deadline, err := time.Parse("2006-01-02", m.DueDate); err == nil {
+					milestone.Deadline = &deadline
+				}
+			}
+			// gitlab has no closing time of milestones
+			if m.State == "closed" {
+				milestone.State = "closed"
+				milestone.Closed = m.UpdatedAt
+			}
+			milestones = append(milestones, milestone)
+		}
+		if len(ms) < maxPerPage {
+			return milestones, nil
+		}
+	}
+}
+
+// GetLabels returns labels
+func (g *GitlabDownloader) GetLabels() ([]*Label, error) {
+	labels := make([]*Label, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var ls []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		}
+		if err := g.client.getJSON(pagePath(g.projectPath("/labels"), page, maxPerPage), &ls); err != nil {
+			return nil, err
+		}
+
+		for _, l := range ls {
+			labels = append(labels, &Label{
+				Name:  l.Name,
+				Color: strings.TrimPrefix(l.Color, "#"),
+			})
+		}
+		if len(ls) < maxPerPage {
+			return labels, nil
+		}
+	}
+}
+
+// GetReleases returns releases
+func (g *GitlabDownloader) GetReleases() ([]*Release, error) {
+	releases := make([]*Release, 0, maxPerPage)
+	for page := 1; ; page++ {
+		var rels []struct {
+			TagName     string     `json:"tag_name"`
+			Name        string     `json:"name"`
+			Description string     `json:"description"`
+			CreatedAt   time.Time  `json:"created_at"`
+			ReleasedAt  *time.Time `json:"released_at"`
+			Author      gitlabUser `json:"author"`
+			Commit      struct {
+				ID string `json:"id"`
+			} `json:"commit"`
+		}
+		if err := g.client.getJSON(pagePath(g.projectPath("/releases"), page, maxPerPage), &rels); err != nil {
+			return nil, err
+		}
+
+		for _, rel := range rels {
+			release := &Release{
+				TagName:         rel.TagName,
+				TargetCommitish: rel.Commit.ID,
+				Name:            rel.Name,
+				Body:            rel.Description,
+				PublisherID:     rel.Author.ID,
+				PublisherName:   rel.Author.Username,
+				PublisherEmail:  rel.Author.Email,
+				Created:         rel.CreatedAt,
+				Published:       rel.CreatedAt,
+			}
+			if rel.ReleasedAt != nil {
+				release.Published = *rel.ReleasedAt
+			}
+			releases = append(releases, release)
+		}
+		if len(rels) < maxPerPage {
+			return releases, nil
+		}
+	}
+}
+
+// GetIssues returns issues according page and perPage
+func (g *GitlabDownloader) GetIssues(page, perPage int) ([]*Issue, bool, error) {
+	var issues []struct {
+		IID         int64            `json:"iid"`
+		Title       string           `json:"title"`
+		Description string           `json:"description"`
+		Author      gitlabUser       `json:"author"`
+		State       string           `json:"state"`
+		Labels      []string         `json:"labels"`
+		Milestone   *gitlabMilestone `json:"milestone"`
+		CreatedAt   time.Time        `json:"created_at"`
+		UpdatedAt   time.Time        `json:"updated_at"`
+		ClosedAt    *time.Time       `json:"closed_at"`
+	}
+	path := g.projectPath("/issues?scope=all&state=all&order_by=created_at&sort=asc")
+	if err := g.client.getJSON(pagePath(path, page, perPage), &issues); err != nil {
+		return nil, false, err
+	}
+
+	result := make([]*Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.IID > g.maxIssueNumber {
+			g.maxIssueNumber = issue.IID
+		}
+		result = append(result, &Issue{
+			Number:      issue.IID,
+			Title:       issue.Title,
+			Content:     issue.Description,
+			PosterID:    issue.Author.ID,
+			PosterName:  issue.Author.Username,
+			PosterEmail: issue.Author.Email,
+			Milestone:   issue.Milestone.title(),
+			State:       convertGitlabState(issue.State),
+			Created:     issue.CreatedAt,
+			Updated:     issue.UpdatedAt,
+			Closed:      issue.ClosedAt,
+			Labels:      convertGitlabLabels(issue.Labels),
+		})
+	}
+	return result, len(issues) < perPage, nil
+}
+
+// GetComments returns comments according issueNumber
+func (g *GitlabDownloader) GetComments(number int64) ([]*Comment, error) {
+	path := fmt.Sprintf("/issues/%d/notes", number)
+	if number > g.maxIssueNumber {
+		path = fmt.Sprintf("/merge_requests/%d/notes", number-g.maxIssueNumber)
+	}
+
+	comments := make([]*Comment, 0, 10)
+	for page := 1; ; page++ {
+		var notes []struct {
+			Body      string     `json:"body"`
+			Author    gitlabUser `json:"author"`
+			CreatedAt time.Time  `json:"created_at"`
+			UpdatedAt time.Time  `json:"updated_at"`
+			// System is set for notes generated for changes
+			System bool `json:"system"`
+		}
+		notesPath := g.projectPath(path + "?order_by=created_at&sort=asc")
+		if err := g.client.getJSON(pagePath(notesPath, page, maxPerPage), &notes); err != nil {
+			return nil, err
+		}
+
+		for _, note := range notes {
+			if note.System {
+				continue
+			}
+			comments = append(comments, &Comment{
+				IssueNumber: number,
+				PosterID:    note.Author.ID,
+				PosterName:  note.Author.Username,
+				PosterEmail: note.Author.Email,
+				Content:     note.Body,
+				Created:     note.CreatedAt,
+				Updated:     note.UpdatedAt,
+			})
+		}
+		if len(notes) < maxPerPage {
+			return comments, nil
+		}
+	}
+}
+
+// GetPullRequests returns pull requests according page and perPage
+func (g *GitlabDownloader) GetPullRequests(page, perPage int) ([]*PullRequest, bool, error) {
+	var mrs []struct {
+		IID             int64            `json:"iid"`
+		Title           string           `json:"title"`
+		Description     string           `json:"description"`
+		Author          gitlabUser       `json:"author"`
+		State           string           `json:"state"`
+		Labels          []string         `json:"labels"`
+		Milestone       *gitlabMilestone `json:"milestone"`
+		CreatedAt       time.Time        `json:"created_at"`
+		UpdatedAt       time.Time        `json:"updated_at"`
+		ClosedAt        *time.Time       `json:"closed_at"`
+		MergedAt        *time.Time       `json:"merged_at"`
+		MergeCommitSHA  string           `json:"merge_commit_sha"`
+		SourceBranch    string           `json:"source_branch"`
+		TargetBranch    string           `json:"target_branch"`
+		SourceProjectID int64            `json:"source_project_id"`
+		TargetProjectID int64            `json:"target_project_id"`
+		SHA             string           `json:"sha"`
+		DiffRefs        *struct {
+			BaseSHA string `json:"base_sha"`
+		} `json:"diff_refs"`
+	}
+	path := g.projectPath("/merge_requests?state=all&order_by=created_at&sort=asc")
+	if err := g.client.getJSON(pagePath(path, page, perPage), &mrs); err != nil {
+		return nil, false, err
+	}
+
+	ownerName := g.repoPath[:strings.LastIndex(g.repoPath, "/")]
+	repoName := g.repoPath[strings.LastIndex(g.repoPath, "/")+1:]
+	result := make([]*PullRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		pr := &PullRequest{
+			Number:      mr.IID + g.maxIssueNumber,
+			Title:       mr.Title,
+			Content:     mr.Description,
+			PosterID:    mr.Author.ID,
+			PosterName:  mr.Author.Username,
+			PosterEmail: mr.Author.Email,
+			Milestone:   mr.Milestone.title(),
+			State:       convertGitlabState(mr.State),
+			Created:     mr.CreatedAt,
+			Updated:     mr.UpdatedAt,
+			Closed:      mr.ClosedAt,
+			Labels:      convertGitlabLabels(mr.Labels),
+			Merged:      mr.State == "merged",
+			MergedTime:  mr.MergedAt,
+			Head: PullRequestBranch{
+				Ref:       mr.SourceBranch,
+				SHA:       mr.SHA,
+				OwnerName: ownerName,
+				RepoName:  repoName,
+			},
+			Base: PullRequestBranch{
+				Ref:       mr.TargetBranch,
+				OwnerName: ownerName,
+				RepoName:  repoName,
+			},
+		}
+		if pr.Merged {
+			pr.MergeCommitSHA = mr.MergeCommitSHA
+			if pr.Closed == nil {
+				pr.Closed = mr.MergedAt
+			}
+		}
+		if mr.DiffRefs != nil {
+			pr.Base.SHA = mr.DiffRefs.BaseSHA
+		}
+		// the source project of a fork is only known by its ID, but forks
+		// usually belong to the author
+		if mr.SourceProjectID != mr.TargetProjectID {
+			pr.Head.OwnerName = mr.Author.Username
+		}
+		result = append(result, pr)
+	}
+	return result, len(mrs) < perPage, nil
+}
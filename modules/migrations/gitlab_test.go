@@ -0,0 +1,146 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var gitlabRoutes = map[string]string{
+	"/api/v4/projects/gitea%2Ftest_repo":                                                                           "project.json",
+	"/api/v4/projects/gitea%2Ftest_repo/milestones?page=1&per_page=100":                                            "milestones.json",
+	"/api/v4/projects/gitea%2Ftest_repo/labels?page=1&per_page=100":                                                "labels.json",
+	"/api/v4/projects/gitea%2Ftest_repo/releases?page=1&per_page=100":                                              "releases.json",
+	"/api/v4/projects/gitea%2Ftest_repo/issues?scope=all&state=all&order_by=created_at&sort=asc&page=1&per_page=2": "issues_1.json",
+	"/api/v4/projects/gitea%2Ftest_repo/issues?scope=all&state=all&order_by=created_at&sort=asc&page=2&per_page=2": "empty.json",
+	"/api/v4/projects/gitea%2Ftest_repo/issues/2/notes?order_by=created_at&sort=asc&page=1&per_page=100":           "issue_2_notes.json",
+	"/api/v4/projects/gitea%2Ftest_repo/merge_requests?state=all&order_by=created_at&sort=asc&page=1&per_page=2":   "merge_requests_1.json",
+	"/api/v4/projects/gitea%2Ftest_repo/merge_requests/1/notes?order_by=created_at&sort=asc&page=1&per_page=100":   "merge_request_1_notes.json",
+}
+
+func TestGitlabDownloaderFactory_Match(t *testing.T) {
+	factory := &GitlabDownloaderFactory{}
+	for remoteURL, expected := range map[string]bool{
+		"https://gitlab.com/gitea/test_repo.git":    true,
+		"https://gitlab.com/gitea/group/test_repo":  true,
+		"https://github.com/go-gitea/test_repo.git": false,
+	} {
+		match, err := factory.Match(MigrateOptions{RemoteURL: remoteURL})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, match, remoteURL)
+	}
+
+	downloader, err := factory.New(MigrateOptions{RemoteURL: "https://gitlab.com/gitea/group/test_repo.git"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/api/v4", downloader.(*GitlabDownloader).client.baseURL)
+	assert.Equal(t, "gitea/group/test_repo", downloader.(*GitlabDownloader).repoPath)
+}
+
+func TestGitlabDownloader(t *testing.T) {
+	server := newFixtureServer(t, "gitlab", gitlabRoutes, func(r *http.Request) bool {
+		return r.Header.Get("Private-Token") == "token"
+	})
+	defer server.Close()
+	downloader := NewGitlabDownloader(server.URL+"/api/v4", "token", "gitea/test_repo")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Repository{
+		Name:        "test_repo",
+		Owner:       "gitea",
+		Description: "Test repository for testing migration from gitlab to gitea",
+		CloneURL:    "https://gitlab.com/gitea/test_repo.git",
+		OriginalURL: "https://gitlab.com/gitea/test_repo",
+	}, repo)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 2) {
+		assert.Equal(t, "1.1.0", milestones[0].Title)
+		assert.Equal(t, "open", milestones[0].State)
+		assert.Nil(t, milestones[0].Deadline)
+		assert.Nil(t, milestones[0].Closed)
+		assert.Equal(t, "1.0.0", milestones[1].Title)
+		assert.Equal(t, "closed", milestones[1].State)
+		assert.Equal(t, time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC), *milestones[1].Deadline)
+		assert.Equal(t, time.Date(2017, 11, 28, 15, 57, 52, 401000000, time.UTC), *milestones[1].Closed)
+	}
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*Label{
+		{Name: "bug", Color: "d9534f"},
+		{Name: "confirmed", Color: "D9534F"},
+	}, labels)
+
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	if assert.Len(t, releases, 1) {
+		assert.Equal(t, "v0.9.99", releases[0].TagName)
+		assert.Equal(t, "0720a3ec57c1f843568298117b874319e7deee75", releases[0].TargetCommitish)
+		assert.Equal(t, "lafriks", releases[0].PublisherName)
+	}
+
+	issues, isEnd, err := downloader.GetIssues(1, 2)
+	assert.NoError(t, err)
+	assert.False(t, isEnd)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].Number)
+		assert.Equal(t, "closed", issues[0].State)
+		assert.Equal(t, "1.0.0", issues[0].Milestone)
+		assert.EqualValues(t, []*Label{{Name: "bug"}, {Name: "discussion"}}, issues[0].Labels)
+		assert.EqualValues(t, 2, issues[1].Number)
+		assert.Equal(t, "open", issues[1].State)
+	}
+	issues, isEnd, err = downloader.GetIssues(2, 2)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	assert.Len(t, issues, 0)
+
+	// system notes are skipped
+	comments, err := downloader.GetComments(2)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 2, comments[0].IssueNumber)
+		assert.Equal(t, "This is a comment", comments[0].Content)
+	}
+
+	// merge requests are numbered after the issues
+	prs, isEnd, err := downloader.GetPullRequests(1, 2)
+	assert.NoError(t, err)
+	assert.False(t, isEnd)
+	if assert.Len(t, prs, 2) {
+		assert.EqualValues(t, 3, prs[0].Number)
+		assert.Equal(t, "closed", prs[0].State)
+		assert.True(t, prs[0].Merged)
+		assert.Equal(t, "f4a70e01c7c7a3e4f5cd6f3f5b5f2d1e7a9b8c6d", prs[0].MergeCommitSHA)
+		assert.Equal(t, prs[0].MergedTime, prs[0].Closed)
+		assert.False(t, prs[0].IsForkPullRequest())
+		assert.Equal(t, PullRequestBranch{
+			Ref:       "feat/test",
+			SHA:       "9f733b96b98a4175276edf6a2e1231489c3bdd23",
+			RepoName:  "test_repo",
+			OwnerName: "gitea",
+		}, prs[0].Head)
+		assert.Equal(t, "c59c9b451acca9d106cc19d61d87afe3fbbb8b83", prs[0].Base.SHA)
+
+		assert.EqualValues(t, 4, prs[1].Number)
+		assert.Equal(t, "open", prs[1].State)
+		assert.False(t, prs[1].Merged)
+		assert.True(t, prs[1].IsForkPullRequest())
+		assert.Equal(t, "axifive", prs[1].Head.OwnerName)
+	}
+
+	comments, err = downloader.GetComments(3)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 3, comments[0].IssueNumber)
+		assert.Equal(t, "axifive", comments[0].PosterName)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+var factories []DownloaderFactory
+
+// RegisterDownloaderFactory registers a downloader factory
+func RegisterDownloaderFactory(factory DownloaderFactory) {
+	factories = append(factories, factory)
+}
+
+// MigrateRepository migrates a repository from a remote site into a new
+// repository of owner. Tracker items are only migrated from the sites a
+// registered downloader factory supports, and never into mirrors. Everything
+// migrated so far is removed again if the migration fails.
+func MigrateRepository(doer, owner *models.User, opts MigrateOptions) (*models.Repository, error) {
+	var (
+		downloader Downloader
+		provider   string
+	)
+	if opts.Mirror {
+		// a mirror only keeps the git data in sync
+		opts.Issues, opts.PullRequests = false, false
+		opts.Labels, opts.Milestones, opts.Releases = false, false, false
+	} else if opts.hasTrackerItems() {
+		for _, factory := range factories {
+			match, err := factory.Match(opts)
+			if err != nil {
+				return nil, err
+			} else if match {
+				if downloader, err = factory.New(opts); err != nil {
+					return nil, err
+				}
+				provider = factory.Provider()
+				break
+			}
+		}
+	}
+
+	if downloader == nil {
+		opts.Issues, opts.PullRequests = false, false
+		opts.Labels, opts.Milestones, opts.Releases = false, false, false
+		downloader = NewPlainGitDownloader(owner.Name, opts.Name, opts.RemoteURL)
+		log.Trace("Will migrate %s as plain git repository", opts.Name)
+	}
+
+	uploader := NewGiteaLocalUploader(doer, owner, opts.Name, provider)
+	if err := migrateRepository(downloader, uploader, opts); err != nil {
+		if errRollback := uploader.Rollback(); errRollback != nil {
+			log.Error(4, "Rollback: %v", errRollback)
+		}
+		return nil, err
+	}
+	return uploader.repo, nil
+}
+
+// migrateRepository downloads everything requested by opts and stores it.
+func migrateRepository(downloader Downloader, uploader Uploader, opts MigrateOptions) error {
+	repo, err := downloader.GetRepoInfo()
+	if err != nil {
+		return fmt.Errorf("GetRepoInfo: %v", err)
+	}
+	if len(opts.Description) == 0 {
+		opts.Description = repo.Description
+	}
+	if err = uploader.CreateRepo(repo, opts); err != nil {
+		return err
+	}
+
+	if opts.Milestones {
+		milestones, err := downloader.GetMilestones()
+		if err != nil {
+			return fmt.Errorf("GetMilestones: %v", err)
+		}
+		if err = uploader.CreateMilestones(milestones...); err != nil {
+			return fmt.Errorf("CreateMilestones: %v", err)
+		}
+	}
+
+	if opts.Labels {
+		labels, err := downloader.GetLabels()
+		if err != nil {
+			return fmt.Errorf("GetLabels: %v", err)
+		}
+		if err = uploader.CreateLabels(labels...); err != nil {
+			return fmt.Errorf("CreateLabels: %v", err)
+		}
+	}
+
+	if opts.Releases {
+		releases, err := downloader.GetReleases()
+		if err != nil {
+			return fmt.Errorf("GetReleases: %v", err)
+		}
+		if err = uploader.CreateReleases(releases...); err != nil {
+			return fmt.Errorf("CreateReleases: %v", err)
+		}
+	}
+
+	if opts.Issues {
+		for page := 1; ; page++ {
+			issues, isEnd, err := downloader.GetIssues(page, maxPerPage)
+			if err != nil {
+				return fmt.Errorf("GetIssues: %v", err)
+			}
+			if err = uploader.CreateIssues(issues...); err != nil {
+				return fmt.Errorf("CreateIssues: %v", err)
+			}
+			for _, issue := range issues {
+				if err = migrateComments(downloader, uploader, issue.Number); err != nil {
+					return err
+				}
+			}
+			if isEnd {
+				break
+			}
+		}
+	}
+
+	if opts.PullRequests {
+		for page := 1; ; page++ {
+			prs, isEnd, err := downloader.GetPullRequests(page, maxPerPage)
+			if err != nil {
+				return fmt.Errorf("GetPullRequests: %v", err)
+			}
+			if err = uploader.CreatePullRequests(prs...); err != nil {
+				return fmt.Errorf("CreatePullRequests: %v", err)
+			}
+			for _, pr := range prs {
+				if err = migrateComments(downloader, uploader, pr.Number); err != nil {
+					return err
+				}
+			}
+			if isEnd {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func migrateComments(downloader Downloader, uploader Uploader, number int64) error {
+	comments, err := downloader.GetComments(number)
+	if err != nil {
+		return fmt.Errorf("GetComments [number: %d]: %v", number, err)
+	}
+	if err = uploader.CreateComments(comments...); err != nil {
+		return fmt.Errorf("CreateComments [number: %d]: %v", number, err)
+	}
+	return nil
+}
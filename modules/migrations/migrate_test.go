@@ -0,0 +1,139 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFixtureServer serves the recorded API responses in testdata/<dir>.
+// routes maps the requested paths, query included, to the fixture files and
+// authorized checks the credentials of a request.
+func newFixtureServer(t *testing.T, dir string, routes map[string]string, authorized func(*http.Request) bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		route := r.URL.EscapedPath()
+		if len(r.URL.RawQuery) > 0 {
+			route += "?" + r.URL.RawQuery
+		}
+		fixture, ok := routes[route]
+		if !ok {
+			t.Errorf("Unexpected request: %s", route)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join("testdata", dir, fixture))
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+}
+
+// recordingUploader records everything uploaded to it
+type recordingUploader struct {
+	repo       *Repository
+	opts       MigrateOptions
+	milestones []*Milestone
+	labels     []*Label
+	releases   []*Release
+	issues     []*Issue
+	comments   []*Comment
+	prs        []*PullRequest
+}
+
+func (u *recordingUploader) CreateRepo(repo *Repository, opts MigrateOptions) error {
+	u.repo, u.opts = repo, opts
+	return nil
+}
+
+func (u *recordingUploader) CreateMilestones(milestones ...*Milestone) error {
+	u.milestones = append(u.milestones, milestones...)
+	return nil
+}
+
+func (u *recordingUploader) CreateLabels(labels ...*Label) error {
+	u.labels = append(u.labels, labels...)
+	return nil
+}
+
+func (u *recordingUploader) CreateReleases(releases ...*Release) error {
+	u.releases = append(u.releases, releases...)
+	return nil
+}
+
+func (u *recordingUploader) CreateIssues(issues ...*Issue) error {
+	u.issues = append(u.issues, issues...)
+	return nil
+}
+
+func (u *recordingUploader) CreateComments(comments ...*Comment) error {
+	u.comments = append(u.comments, comments...)
+	return nil
+}
+
+func (u *recordingUploader) CreatePullRequests(prs ...*PullRequest) error {
+	u.prs = append(u.prs, prs...)
+	return nil
+}
+
+func (u *recordingUploader) Rollback() error {
+	return nil
+}
+
+func TestMigrateRepository(t *testing.T) {
+	routes := map[string]string{
+		"/repos/go-gitea/test_repo": "repo.json",
+		"/repos/go-gitea/test_repo/milestones?state=all&page=1&per_page=100":                        "milestones.json",
+		"/repos/go-gitea/test_repo/labels?page=1&per_page=100":                                      "labels.json",
+		"/repos/go-gitea/test_repo/releases?page=1&per_page=100":                                    "releases.json",
+		"/repos/go-gitea/test_repo/issues?state=all&sort=created&direction=asc&page=1&per_page=100": "issues_1.json",
+		"/repos/go-gitea/test_repo/issues/1/comments?page=1&per_page=100":                           "comments_1.json",
+		"/repos/go-gitea/test_repo/pulls?state=all&sort=created&direction=asc&page=1&per_page=100":  "pulls_1.json",
+		"/repos/go-gitea/test_repo/issues/2/comments?page=1&per_page=100":                           "empty.json",
+		"/repos/go-gitea/test_repo/issues/4/comments?page=1&per_page=100":                           "empty.json",
+	}
+	downloader, closeServer := newGithubTestDownloader(t, routes)
+	defer closeServer()
+
+	uploader := &recordingUploader{}
+	assert.NoError(t, migrateRepository(downloader, uploader, MigrateOptions{
+		Name:         "migrated",
+		Issues:       true,
+		PullRequests: true,
+		Labels:       true,
+		Milestones:   true,
+		Releases:     true,
+	}))
+	assert.Equal(t, "test_repo", uploader.repo.Name)
+	assert.Equal(t, "Test repository for testing migration from github to gitea", uploader.opts.Description)
+	assert.Len(t, uploader.milestones, 2)
+	assert.Len(t, uploader.labels, 2)
+	assert.Len(t, uploader.releases, 1)
+	assert.Len(t, uploader.issues, 1)
+	assert.Len(t, uploader.comments, 2)
+	assert.Len(t, uploader.prs, 2)
+
+	// nothing but the repository information is downloaded without items
+	uploader = &recordingUploader{}
+	assert.NoError(t, migrateRepository(downloader, uploader, MigrateOptions{
+		Name:        "migrated",
+		Description: "description",
+	}))
+	assert.Equal(t, "description", uploader.opts.Description)
+	assert.Len(t, uploader.milestones, 0)
+	assert.Len(t, uploader.issues, 0)
+	assert.Len(t, uploader.prs, 0)
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type actionNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &actionNotifier{}
+)
+
+// NewNotifier create a new actionNotifier notifier, it adds the events to
+// the activity feeds of the watchers
+func NewNotifier() base.Notifier {
+	return &actionNotifier{}
+}
+
+func (a *actionNotifier) NotifyNewIssue(issue *models.Issue) {
+	if err := models.NotifyWatchers(&models.Action{
+		ActUserID: issue.Poster.ID,
+		ActUser:   issue.Poster,
+		OpType:    models.ActionCreateIssue,
+		Content:   fmt.Sprintf("%d|%s", issue.Index, issue.Title),
+		RepoID:    issue.Repo.ID,
+		Repo:      issue.Repo,
+		IsPrivate: issue.Repo.IsPrivate,
+	}); err != nil {
+		log.Error(4, "NotifyWatchers: %v", err)
+	}
+}
+
+func (a *actionNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	if err := models.NotifyWatchers(&models.Action{
+		ActUserID: doer.ID,
+		ActUser:   doer,
+		OpType:    models.ActionCommentIssue,
+		Content:   fmt.Sprintf("%d|%s", issue.Index, strings.Split(comment.Content, "\n")[0]),
+		RepoID:    repo.ID,
+		Repo:      repo,
+		Comment:   comment,
+		CommentID: comment.ID,
+		IsPrivate: repo.IsPrivate,
+	}); err != nil {
+		log.Error(4, "NotifyWatchers: %v", err)
+	}
+}
+
+func (a *actionNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+	issue := pr.Issue
+	if err := models.NotifyWatchers(&models.Action{
+		ActUserID: issue.Poster.ID,
+		ActUser:   issue.Poster,
+		OpType:    models.ActionCreatePullRequest,
+		Content:   fmt.Sprintf("%d|%s", issue.Index, issue.Title),
+		RepoID:    issue.Repo.ID,
+		Repo:      issue.Repo,
+		IsPrivate: issue.Repo.IsPrivate,
+	}); err != nil {
+		log.Error(4, "NotifyWatchers: %v", err)
+	}
+}
+
+func (a *actionNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := models.MergePullRequestAction(doer, pr.Issue.Repo, pr.Issue); err != nil {
+		log.Error(4, "MergePullRequestAction [%d]: %v", pr.ID, err)
+	}
+}
+
+func (a *actionNotifier) NotifyPushCommits(push *models.Push) {
+	opType := models.ActionCommitRepo
+	if strings.HasPrefix(push.RefFullName, git.TagPrefix) {
+		opType = models.ActionPushTag
+	}
+
+	data, err := json.Marshal(push.Commits)
+	if err != nil {
+		log.Error(4, "Marshal: %v", err)
+		return
+	}
+
+	if err = models.NotifyWatchers(&models.Action{
+		ActUserID: push.Pusher.ID,
+		ActUser:   push.Pusher,
+		OpType:    opType,
+		Content:   string(data),
+		RepoID:    push.Repo.ID,
+		Repo:      push.Repo,
+		RefName:   git.RefEndName(push.RefFullName),
+		IsPrivate: push.Repo.IsPrivate,
+	}); err != nil {
+		log.Error(4, "NotifyWatchers: %v", err)
+	}
+}
+
+func (a *actionNotifier) NotifyTransferRepository(doer, oldOwner *models.User, repo *models.Repository) {
+	if err := models.TransferRepoAction(doer, oldOwner, repo); err != nil {
+		log.Error(4, "TransferRepoAction [%d]: %v", repo.ID, err)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"code.gitea.io/gitea/models"
+)
+
+// Notifier defines an interface to notify receiver
+type Notifier interface {
+	// Run is started in its own goroutine when the notifier is registered
+	Run()
+
+	NotifyNewIssue(issue *models.Issue)
+	NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, isClosed bool)
+	NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+		issue *models.Issue, comment *models.Comment)
+
+	NotifyNewPullRequest(pr *models.PullRequest)
+	NotifyMergePullRequest(pr *models.PullRequest, doer *models.User)
+
+	NotifyPushCommits(push *models.Push)
+
+	NotifyNewRelease(rel *models.Release)
+
+	NotifyTransferRepository(doer, oldOwner *models.User, repo *models.Repository)
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"code.gitea.io/gitea/models"
+)
+
+// NullNotifier implements a blank notifier, notifiers embed it so they only
+// have to implement the events they handle
+type NullNotifier struct {
+}
+
+var (
+	_ Notifier = &NullNotifier{}
+)
+
+// Run places a place holder function
+func (*NullNotifier) Run() {
+}
+
+// NotifyNewIssue places a place holder function
+func (*NullNotifier) NotifyNewIssue(issue *models.Issue) {
+}
+
+// NotifyIssueChangeStatus places a place holder function
+func (*NullNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, isClosed bool) {
+}
+
+// NotifyCreateIssueComment places a place holder function
+func (*NullNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+}
+
+// NotifyNewPullRequest places a place holder function
+func (*NullNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+}
+
+// NotifyMergePullRequest places a place holder function
+func (*NullNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+}
+
+// NotifyPushCommits places a place holder function
+func (*NullNotifier) NotifyPushCommits(push *models.Push) {
+}
+
+// NotifyNewRelease places a place holder function
+func (*NullNotifier) NotifyNewRelease(rel *models.Release) {
+}
+
+// NotifyTransferRepository places a place holder function
+func (*NullNotifier) NotifyTransferRepository(doer, oldOwner *models.User, repo *models.Repository) {
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type mailNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &mailNotifier{}
+)
+
+// NewNotifier create a new mailNotifier notifier, it mails the watchers,
+// participants and mentioned users of issues and pull requests
+func NewNotifier() base.Notifier {
+	return &mailNotifier{}
+}
+
+func (m *mailNotifier) NotifyNewIssue(issue *models.Issue) {
+	if err := issue.MailParticipants(); err != nil {
+		log.Error(4, "MailParticipants: %v", err)
+	}
+}
+
+func (m *mailNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	if err := comment.MailParticipants(models.ActionCommentIssue, issue); err != nil {
+		log.Error(4, "MailParticipants: %v", err)
+	}
+}
+
+func (m *mailNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+	if err := pr.Issue.MailParticipants(); err != nil {
+		log.Error(4, "MailParticipants: %v", err)
+	}
+}
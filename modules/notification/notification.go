@@ -6,45 +6,95 @@ package notification
 
 import (
 	"code.gitea.io/gitea/models"
-	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/action"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/notification/mail"
+	"code.gitea.io/gitea/modules/notification/ui"
+	"code.gitea.io/gitea/modules/notification/webhook"
 )
 
-type (
-	notificationService struct {
-		issueQueue chan issueNotificationOpts
+var (
+	notifiers []base.Notifier
+)
+
+// RegisterNotifier registers a notifier, it receives all the events sent
+// through this package in the order of registration
+func RegisterNotifier(notifier base.Notifier) {
+	go notifier.Run()
+	notifiers = append(notifiers, notifier)
+}
+
+func init() {
+	RegisterNotifier(ui.NewNotifier())
+	RegisterNotifier(action.NewNotifier())
+	RegisterNotifier(webhook.NewNotifier())
+	RegisterNotifier(mail.NewNotifier())
+}
+
+// NotifyNewIssue notifies the creation of an issue to the notifiers
+func NotifyNewIssue(issue *models.Issue) {
+	for _, notifier := range notifiers {
+		notifier.NotifyNewIssue(issue)
 	}
+}
 
-	issueNotificationOpts struct {
-		issue                *models.Issue
-		notificationAuthorID int64
+// NotifyIssueChangeStatus notifies that an issue has been closed or reopened
+func NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, isClosed bool) {
+	for _, notifier := range notifiers {
+		notifier.NotifyIssueChangeStatus(doer, issue, isClosed)
 	}
-)
+}
 
-var (
-	// Service is the notification service
-	Service = &notificationService{
-		issueQueue: make(chan issueNotificationOpts, 100),
+// NotifyCreateIssueComment notifies a new comment on an issue or pull
+// request to the notifiers
+func NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateIssueComment(doer, repo, issue, comment)
 	}
-)
+}
 
-func init() {
-	go Service.Run()
+// NotifyNewPullRequest notifies the creation of a pull request to the
+// notifiers
+func NotifyNewPullRequest(pr *models.PullRequest) {
+	for _, notifier := range notifiers {
+		notifier.NotifyNewPullRequest(pr)
+	}
+}
+
+// NotifyMergePullRequest notifies that a pull request has been merged
+func NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	for _, notifier := range notifiers {
+		notifier.NotifyMergePullRequest(pr, doer)
+	}
+}
+
+// NotifyPushCommits notifies a push to the notifiers, followed by the status
+// changes of the issues closed or reopened by its commits. A nil push, of
+// commits whose push could not be retrieved, is ignored.
+func NotifyPushCommits(push *models.Push) {
+	if push == nil {
+		return
+	}
+	for _, notifier := range notifiers {
+		notifier.NotifyPushCommits(push)
+	}
+	for _, issue := range push.ChangedIssues {
+		NotifyIssueChangeStatus(push.Pusher, issue, issue.IsClosed)
+	}
 }
 
-func (ns *notificationService) Run() {
-	for {
-		select {
-		case opts := <-ns.issueQueue:
-			if err := models.CreateOrUpdateIssueNotifications(opts.issue, opts.notificationAuthorID); err != nil {
-				log.Error(4, "Was unable to create issue notification: %v", err)
-			}
-		}
+// NotifyNewRelease notifies the creation of a release to the notifiers
+func NotifyNewRelease(rel *models.Release) {
+	for _, notifier := range notifiers {
+		notifier.NotifyNewRelease(rel)
 	}
 }
 
-func (ns *notificationService) NotifyIssue(issue *models.Issue, notificationAuthorID int64) {
-	ns.issueQueue <- issueNotificationOpts{
-		issue,
-		notificationAuthorID,
+// NotifyTransferRepository notifies that a repository has been transferred
+// from oldOwner to its current owner
+func NotifyTransferRepository(doer, oldOwner *models.User, repo *models.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyTransferRepository(doer, oldOwner, repo)
 	}
 }
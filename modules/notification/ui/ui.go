@@ -0,0 +1,74 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ui
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type (
+	notificationService struct {
+		base.NullNotifier
+		issueQueue chan issueNotificationOpts
+	}
+
+	issueNotificationOpts struct {
+		issue                *models.Issue
+		notificationAuthorID int64
+	}
+)
+
+var (
+	_ base.Notifier = &notificationService{}
+)
+
+// NewNotifier create a new notificationService notifier, it creates the
+// notifications shown in the UI in the background
+func NewNotifier() base.Notifier {
+	return &notificationService{
+		issueQueue: make(chan issueNotificationOpts, 100),
+	}
+}
+
+func (ns *notificationService) Run() {
+	for {
+		select {
+		case opts := <-ns.issueQueue:
+			if err := models.CreateOrUpdateIssueNotifications(opts.issue, opts.notificationAuthorID); err != nil {
+				log.Error(4, "Was unable to create issue notification: %v", err)
+			}
+		}
+	}
+}
+
+func (ns *notificationService) notifyIssue(issue *models.Issue, notificationAuthorID int64) {
+	ns.issueQueue <- issueNotificationOpts{
+		issue,
+		notificationAuthorID,
+	}
+}
+
+func (ns *notificationService) NotifyNewIssue(issue *models.Issue) {
+	ns.notifyIssue(issue, issue.Poster.ID)
+}
+
+func (ns *notificationService) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, isClosed bool) {
+	ns.notifyIssue(issue, doer.ID)
+}
+
+func (ns *notificationService) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	ns.notifyIssue(issue, doer.ID)
+}
+
+func (ns *notificationService) NotifyNewPullRequest(pr *models.PullRequest) {
+	ns.notifyIssue(pr.Issue, pr.Issue.Poster.ID)
+}
+
+func (ns *notificationService) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	ns.notifyIssue(pr.Issue, doer.ID)
+}
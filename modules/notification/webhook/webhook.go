@@ -0,0 +1,181 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+type webhookNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &webhookNotifier{}
+)
+
+// NewNotifier create a new webhookNotifier notifier, it delivers the events
+// to the webhooks of the repository
+func NewNotifier() base.Notifier {
+	return &webhookNotifier{}
+}
+
+// prepareWebhooks adds the webhooks of the repository to the delivery queue,
+// errors are only logged
+func prepareWebhooks(repo *models.Repository, event models.HookEventType, p api.Payloader) {
+	if err := models.PrepareWebhooks(repo, event, p); err != nil {
+		log.Error(4, "PrepareWebhooks [repo_id: %d, event: %s]: %v", repo.ID, event, err)
+		return
+	}
+	go models.HookQueue.Add(repo.ID)
+}
+
+func (w *webhookNotifier) NotifyNewIssue(issue *models.Issue) {
+	prepareWebhooks(issue.Repo, models.HookEventIssues, &api.IssuePayload{
+		Action:     api.HookIssueOpened,
+		Index:      issue.Index,
+		Issue:      issue.APIFormat(),
+		Repository: issue.Repo.APIFormat(models.AccessModeNone),
+		Sender:     issue.Poster.APIFormat(),
+	})
+}
+
+func (w *webhookNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, isClosed bool) {
+	if err := issue.LoadRepo(); err != nil {
+		log.Error(4, "LoadRepo: %v", err)
+		return
+	}
+
+	action := api.HookIssueReOpened
+	if isClosed {
+		action = api.HookIssueClosed
+	}
+	if !issue.IsPull {
+		prepareWebhooks(issue.Repo, models.HookEventIssues, &api.IssuePayload{
+			Action:     action,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(models.AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
+		return
+	}
+
+	if err := issue.LoadPullRequest(); err != nil {
+		log.Error(4, "LoadPullRequest: %v", err)
+		return
+	}
+	issue.PullRequest.Issue = issue
+	prepareWebhooks(issue.Repo, models.HookEventPullRequest, &api.PullRequestPayload{
+		Action:      action,
+		Index:       issue.Index,
+		PullRequest: issue.PullRequest.APIFormat(),
+		Repository:  issue.Repo.APIFormat(models.AccessModeNone),
+		Sender:      doer.APIFormat(),
+	})
+}
+
+func (w *webhookNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	prepareWebhooks(repo, models.HookEventIssueComment, &api.IssueCommentPayload{
+		Action:     api.HookIssueCommentCreated,
+		Issue:      issue.APIFormat(),
+		Comment:    comment.APIFormat(),
+		Repository: repo.APIFormat(models.AccessModeNone),
+		Sender:     doer.APIFormat(),
+	})
+}
+
+func (w *webhookNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+	prepareWebhooks(pr.Issue.Repo, models.HookEventPullRequest, &api.PullRequestPayload{
+		Action:      api.HookIssueOpened,
+		Index:       pr.Issue.Index,
+		PullRequest: pr.APIFormat(),
+		Repository:  pr.Issue.Repo.APIFormat(models.AccessModeNone),
+		Sender:      pr.Issue.Poster.APIFormat(),
+	})
+}
+
+func (w *webhookNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	prepareWebhooks(pr.Issue.Repo, models.HookEventPullRequest, &api.PullRequestPayload{
+		Action:      api.HookIssueClosed,
+		Index:       pr.Index,
+		PullRequest: pr.APIFormat(),
+		Repository:  pr.Issue.Repo.APIFormat(models.AccessModeNone),
+		Sender:      doer.APIFormat(),
+	})
+}
+
+func (w *webhookNotifier) NotifyPushCommits(push *models.Push) {
+	apiPusher := push.Pusher.APIFormat()
+	apiRepo := push.Repo.APIFormat(models.AccessModeNone)
+	refName := git.RefEndName(push.RefFullName)
+
+	isTag := strings.HasPrefix(push.RefFullName, git.TagPrefix)
+	if !isTag {
+		prepareWebhooks(push.Repo, models.HookEventPush, &api.PushPayload{
+			Ref:        push.RefFullName,
+			Before:     push.OldCommitID,
+			After:      push.NewCommitID,
+			CompareURL: setting.AppURL + push.Commits.CompareURL,
+			Commits:    push.Commits.ToAPIPayloadCommits(push.Repo.HTMLURL()),
+			Repo:       apiRepo,
+			Pusher:     apiPusher,
+			Sender:     apiPusher,
+		})
+		if push.OldCommitID != git.EmptySHA {
+			return
+		}
+	}
+
+	// Tags and new branches are created
+	gitRepo, err := git.OpenRepository(push.Repo.RepoPath())
+	if err != nil {
+		log.Error(4, "OpenRepository[%s]: %v", push.Repo.RepoPath(), err)
+		return
+	}
+	refType := "branch"
+	var shaSum string
+	if isTag {
+		refType = "tag"
+		shaSum, err = gitRepo.GetTagCommitID(refName)
+	} else {
+		shaSum, err = gitRepo.GetBranchCommitID(refName)
+	}
+	if err != nil {
+		log.Error(4, "GetRefCommitID[%s]: %v", push.RefFullName, err)
+	}
+	prepareWebhooks(push.Repo, models.HookEventCreate, &api.CreatePayload{
+		Ref:     refName,
+		Sha:     shaSum,
+		RefType: refType,
+		Repo:    apiRepo,
+		Sender:  apiPusher,
+	})
+}
+
+func (w *webhookNotifier) NotifyNewRelease(rel *models.Release) {
+	if rel.IsDraft {
+		return
+	}
+	if err := rel.LoadAttributes(); err != nil {
+		log.Error(4, "LoadAttributes: %v", err)
+		return
+	}
+
+	prepareWebhooks(rel.Repo, models.HookEventRelease, &api.ReleasePayload{
+		Action:     api.HookReleasePublished,
+		Release:    rel.APIFormat(),
+		Repository: rel.Repo.APIFormat(models.AccessModeNone),
+		Sender:     rel.Publisher.APIFormat(),
+	})
+}
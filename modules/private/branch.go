@@ -41,3 +41,99 @@ func GetProtectedBranchBy(repoID int64, branchName string) (*models.ProtectedBra
 
 	return &branch, nil
 }
+
+// CanUserPush returns if user can push to the protected branch
+func CanUserPush(protectedBranchID, userID int64) (bool, error) {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/protectedbranch/%d/%d", protectedBranchID, userID)
+	log.GitLogger.Trace("CanUserPush: %s", reqURL)
+
+	resp, err := newRequest(reqURL, "GET").SetTLSClientConfig(&tls.Config{
+		InsecureSkipVerify: true,
+	}).Response()
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	// All 2XX status codes are accepted and others will return an error
+	if resp.StatusCode/100 != 2 {
+		return false, fmt.Errorf("Failed to check if user can push: %s", decodeJSONError(resp).Err)
+	}
+
+	var res struct {
+		CanPush bool `json:"can_push"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return false, err
+	}
+
+	return res.CanPush, nil
+}
+
+// VerifyCommitsOptions represents the commits of a push to verify
+type VerifyCommitsOptions struct {
+	OldCommitID string `json:"old_commit_id"`
+	NewCommitID string `json:"new_commit_id"`
+	// The pushed objects are in quarantine while the pre-receive hook runs,
+	// git tells the hook where to find them through these environment variables.
+	GitObjectDirectory              string `json:"git_object_directory"`
+	GitAlternativeObjectDirectories string `json:"git_alternative_object_directories"`
+	GitQuarantinePath               string `json:"git_quarantine_path"`
+}
+
+// Env returns the git environment variables to read the pushed objects
+func (opts *VerifyCommitsOptions) Env() []string {
+	var env []string
+	if opts.GitObjectDirectory != "" {
+		env = append(env, "GIT_OBJECT_DIRECTORY="+opts.GitObjectDirectory)
+	}
+	if opts.GitAlternativeObjectDirectories != "" {
+		env = append(env, "GIT_ALTERNATE_OBJECT_DIRECTORIES="+opts.GitAlternativeObjectDirectories)
+	}
+	if opts.GitQuarantinePath != "" {
+		env = append(env, "GIT_QUARANTINE_PATH="+opts.GitQuarantinePath)
+	}
+	return env
+}
+
+// VerifyCommits returns an ErrUnverifiedCommit for the first of the pushed commits
+// of the repository whose signature can not be verified against a known key
+func VerifyCommits(repoID int64, opts VerifyCommitsOptions) error {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/verify/commits/%d", repoID)
+	log.GitLogger.Trace("VerifyCommits: %s", reqURL)
+
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := newRequest(reqURL, "POST").Body(body).SetTLSClientConfig(&tls.Config{
+		InsecureSkipVerify: true,
+	}).Response()
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// All 2XX status codes are accepted and others will return an error
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Failed to verify commits: %s", decodeJSONError(resp).Err)
+	}
+
+	var res struct {
+		Verified bool   `json:"verified"`
+		CommitID string `json:"commit_id"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return err
+	} else if !res.Verified {
+		return models.ErrUnverifiedCommit{
+			ID:     res.CommitID,
+			Reason: res.Reason,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"bytes"
+	gotemplate "html/template"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/highlight"
+)
+
+// contextLines number of lines shown before and after the first match
+const contextLines = 2
+
+// Result a search result to display
+type Result struct {
+	RepoID         int64
+	Filename       string
+	HighlightClass string
+	LineNumbers    []int
+	FormattedLines gotemplate.HTML
+}
+
+// lineOffsets returns the byte offset of the start of every line of content
+func lineOffsets(content string) []int {
+	offsets := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' && i+1 < len(content) {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// lineOf returns the index of the line containing the byte at offset
+func lineOf(offsets []int, offset int) int {
+	line := 0
+	for line+1 < len(offsets) && offsets[line+1] <= offset {
+		line++
+	}
+	return line
+}
+
+// searchResult formats the lines around the first match of a result, with
+// all matches on these lines highlighted.
+func searchResult(result *models.RepoSearchResult) *Result {
+	content := result.Content
+	offsets := lineOffsets(content)
+
+	firstLine := 0
+	if len(result.Matches) > 0 {
+		firstLine = lineOf(offsets, result.Matches[0].Start) - contextLines
+		if firstLine < 0 {
+			firstLine = 0
+		}
+	}
+	lastLine := firstLine + 2*contextLines
+	if len(result.Matches) > 0 {
+		lastLine = lineOf(offsets, result.Matches[0].Start) + contextLines
+	}
+	if lastLine >= len(offsets) {
+		lastLine = len(offsets) - 1
+	}
+
+	lineNumbers := make([]int, 0, lastLine-firstLine+1)
+	matches := result.Matches
+	var formattedLines bytes.Buffer
+	for line := firstLine; line <= lastLine; line++ {
+		start := offsets[line]
+		end := strings.LastIndexByte(content, '\n')
+		if line+1 < len(offsets) {
+			end = offsets[line+1] - 1
+		} else if end < start {
+			// last line without a trailing newline
+			end = len(content)
+		}
+
+		lineNumbers = append(lineNumbers, line+1)
+		formattedLines.WriteString(`<li>`)
+		index := start
+		for len(matches) > 0 && matches[0].Start < end {
+			match := matches[0]
+			matches = matches[1:]
+			if match.Start < index || match.End > end {
+				// overlaps a previous match or spans several lines
+				continue
+			}
+			formattedLines.WriteString(gotemplate.HTMLEscapeString(content[index:match.Start]))
+			formattedLines.WriteString(`<span class="active">`)
+			formattedLines.WriteString(gotemplate.HTMLEscapeString(content[match.Start:match.End]))
+			formattedLines.WriteString(`</span>`)
+			index = match.End
+		}
+		formattedLines.WriteString(gotemplate.HTMLEscapeString(strings.TrimSuffix(content[index:end], "\r")))
+		formattedLines.WriteString("\n</li>")
+	}
+
+	return &Result{
+		RepoID:         result.RepoID,
+		Filename:       result.Filename,
+		HighlightClass: highlight.FileNameToHighlightClass(result.Filename),
+		LineNumbers:    lineNumbers,
+		FormattedLines: gotemplate.HTML(formattedLines.String()),
+	}
+}
+
+// PerformSearch searches the code of the given repositories, or of all
+// repositories if repoIDs is nil, and formats the results of the page
+func PerformSearch(repoIDs []int64, keyword string, isFilename bool, page, pageSize int) (int, []*Result, error) {
+	if len(keyword) == 0 {
+		return 0, nil, nil
+	}
+
+	total, results, err := models.SearchRepoByKeyword(repoIDs, keyword, isFilename, page, pageSize)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	displayResults := make([]*Result, len(results))
+	for i, result := range results {
+		displayResults[i] = searchResult(result)
+	}
+	return int(total), displayResults, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	gotemplate "html/template"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchResult(t *testing.T) {
+	content := "line 1\nline 2\nline 3\nfoo <b>bar</b> foo\nline 5\nline 6\nline 7\n"
+	result := searchResult(&models.RepoSearchResult{
+		RepoID:   1,
+		Filename: "test.html",
+		Content:  content,
+		Matches: []models.RepoSearchMatch{
+			{Start: 21, End: 24},
+			{Start: 36, End: 39},
+		},
+	})
+
+	assert.EqualValues(t, 1, result.RepoID)
+	assert.Equal(t, "test.html", result.Filename)
+	assert.Equal(t, []int{2, 3, 4, 5, 6}, result.LineNumbers)
+	assert.Equal(t, gotemplate.HTML("<li>line 2\n</li>"+
+		"<li>line 3\n</li>"+
+		`<li><span class="active">foo</span> &lt;b&gt;bar&lt;/b&gt; <span class="active">foo</span>`+"\n</li>"+
+		"<li>line 5\n</li>"+
+		"<li>line 6\n</li>"), result.FormattedLines)
+}
+
+func TestSearchResultWithoutMatches(t *testing.T) {
+	result := searchResult(&models.RepoSearchResult{
+		RepoID:   1,
+		Filename: "README.md",
+		Content:  "# README\r\n\r\nDescription",
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, result.LineNumbers)
+	assert.Equal(t, gotemplate.HTML("<li># README\n</li><li>\n</li><li>Description\n</li>"), result.FormattedLines)
+}
@@ -115,6 +115,21 @@ var (
 		JWTSecretBytes  []byte `ini:"-"`
 	}
 
+	// OAuth2 provider settings
+	OAuth2 = struct {
+		Enable                     bool   `ini:"ENABLE"`
+		AccessTokenExpirationTime  int64  `ini:"ACCESS_TOKEN_EXPIRATION_TIME"`
+		RefreshTokenExpirationTime int64  `ini:"REFRESH_TOKEN_EXPIRATION_TIME"`
+		InvalidateRefreshTokens    bool   `ini:"INVALIDATE_REFRESH_TOKENS"`
+		JWTSecretBase64            string `ini:"JWT_SECRET"`
+		JWTSecretBytes             []byte `ini:"-"`
+	}{
+		Enable:                     true,
+		AccessTokenExpirationTime:  3600,
+		RefreshTokenExpirationTime: 730,
+		InvalidateRefreshTokens:    false,
+	}
+
 	// Security settings
 	InstallLock          bool
 	SecretKey            string
@@ -134,8 +149,11 @@ var (
 
 	// Indexer settings
 	Indexer struct {
-		IssuePath         string
-		UpdateQueueLength int
+		IssuePath          string
+		RepoIndexerEnabled bool
+		RepoPath           string
+		UpdateQueueLength  int
+		MaxIndexerFileSize int64
 	}
 
 	// Webhook settings
@@ -145,11 +163,15 @@ var (
 		SkipTLSVerify  bool
 		Types          []string
 		PagingNum      int
+		MaxRetries     int
+		RetryBackoff   int
 	}{
 		QueueLength:    1000,
 		DeliverTimeout: 5,
 		SkipTLSVerify:  false,
 		PagingNum:      10,
+		MaxRetries:     3,
+		RetryBackoff:   10,
 	}
 
 	// Repository settings
@@ -181,6 +203,17 @@ var (
 		Local struct {
 			LocalCopyPath string
 		} `ini:"-"`
+
+		// Repository signing settings
+		Signing struct {
+			SigningKey    string
+			SigningName   string
+			SigningEmail  string
+			InitialCommit []string
+			CRUDActions   []string `ini:"CRUD_ACTIONS"`
+			Merges        []string
+			Wiki          []string
+		} `ini:"-"`
 	}{
 		AnsiCharset:            "",
 		ForcePrivate:           false,
@@ -220,18 +253,39 @@ var (
 		}{
 			LocalCopyPath: "tmp/local-repo",
 		},
+
+		// Repository signing settings
+		Signing: struct {
+			SigningKey    string
+			SigningName   string
+			SigningEmail  string
+			InitialCommit []string
+			CRUDActions   []string `ini:"CRUD_ACTIONS"`
+			Merges        []string
+			Wiki          []string
+		}{
+			SigningKey:    "default",
+			SigningName:   "",
+			SigningEmail:  "",
+			InitialCommit: []string{"always"},
+			CRUDActions:   []string{"pubkey", "protected"},
+			Merges:        []string{"pubkey", "protected"},
+			Wiki:          []string{"never"},
+		},
 	}
 	RepoRootPath string
 	ScriptType   = "bash"
 
 	// UI settings
 	UI = struct {
-		ExplorePagingNum   int
-		IssuePagingNum     int
-		FeedMaxCommitNum   int
-		ThemeColorMetaTag  string
-		MaxDisplayFileSize int64
-		ShowUserEmail      bool
+		ExplorePagingNum    int
+		IssuePagingNum      int
+		RepoSearchPagingNum int
+		FeedMaxCommitNum    int
+		ThemeColorMetaTag   string
+		MaxDisplayFileSize  int64
+		ShowUserEmail       bool
+		Reactions           []string
 
 		Admin struct {
 			UserPagingNum   int
@@ -248,11 +302,13 @@ var (
 			Keywords    string
 		} `ini:"ui.meta"`
 	}{
-		ExplorePagingNum:   20,
-		IssuePagingNum:     10,
-		FeedMaxCommitNum:   5,
-		ThemeColorMetaTag:  `#6cc644`,
-		MaxDisplayFileSize: 8388608,
+		ExplorePagingNum:    20,
+		IssuePagingNum:      10,
+		RepoSearchPagingNum: 10,
+		FeedMaxCommitNum:    5,
+		ThemeColorMetaTag:   `#6cc644`,
+		MaxDisplayFileSize:  8388608,
+		Reactions:           []string{"+1", "-1", "laughing", "confused", "heart", "tada"},
 		Admin: struct {
 			UserPagingNum   int
 			RepoPagingNum   int
@@ -333,6 +389,16 @@ var (
 			RunAtStart bool
 			Schedule   string
 		} `ini:"cron.update_mirrors"`
+		UpdatePushMirror struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.update_push_mirrors"`
+		RetryHooks struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.retry_hooks"`
 		RepoHealthCheck struct {
 			Enabled    bool
 			RunAtStart bool
@@ -367,6 +433,24 @@ var (
 			RunAtStart: false,
 			Schedule:   "@every 10m",
 		},
+		UpdatePushMirror: struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		}{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 10m",
+		},
+		RetryHooks: struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		}{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 1m",
+		},
 		RepoHealthCheck: struct {
 			Enabled    bool
 			RunAtStart bool
@@ -457,9 +541,11 @@ var (
 
 	// API settings
 	API = struct {
-		MaxResponseItems int
+		MaxResponseItems       int
+		DefaultGitTreesPerPage int
 	}{
-		MaxResponseItems: 50,
+		MaxResponseItems:       50,
+		DefaultGitTreesPerPage: 1000,
 	}
 
 	// I18n settings
@@ -713,6 +799,11 @@ func NewContext() {
 	if err = Cfg.Section("server").MapTo(&LFS); err != nil {
 		log.Fatal(4, "Failed to map LFS settings: %v", err)
 	}
+	LFS.ContentPath = sec.Key("LFS_CONTENT_PATH").MustString(path.Join(AppDataPath, "lfs"))
+	forcePathSeparator(LFS.ContentPath)
+	if !filepath.IsAbs(LFS.ContentPath) {
+		LFS.ContentPath = path.Join(workDir, LFS.ContentPath)
+	}
 
 	if LFS.StartServer {
 
@@ -791,6 +882,39 @@ func NewContext() {
 		}
 	}
 
+	if err = Cfg.Section("oauth2").MapTo(&OAuth2); err != nil {
+		log.Fatal(4, "Failed to map OAuth2 settings: %v", err)
+	}
+
+	if OAuth2.Enable {
+		OAuth2.JWTSecretBytes = make([]byte, 32)
+		n, err := base64.RawURLEncoding.Decode(OAuth2.JWTSecretBytes, []byte(OAuth2.JWTSecretBase64))
+
+		if err != nil || n != 32 {
+			// Generate new secret and save to config
+			if _, err := io.ReadFull(rand.Reader, OAuth2.JWTSecretBytes); err != nil {
+				log.Fatal(4, "Error reading random bytes: %v", err)
+			}
+			OAuth2.JWTSecretBase64 = base64.RawURLEncoding.EncodeToString(OAuth2.JWTSecretBytes)
+
+			cfg := ini.Empty()
+			if com.IsFile(CustomConf) {
+				// Keeps custom settings if there is already something.
+				if err := cfg.Append(CustomConf); err != nil {
+					log.Error(4, "Failed to load custom conf '%s': %v", CustomConf, err)
+				}
+			}
+			cfg.Section("oauth2").Key("JWT_SECRET").SetValue(OAuth2.JWTSecretBase64)
+
+			if err := os.MkdirAll(filepath.Dir(CustomConf), os.ModePerm); err != nil {
+				log.Fatal(4, "Failed to create '%s': %v", CustomConf, err)
+			}
+			if err := cfg.SaveTo(CustomConf); err != nil {
+				log.Fatal(4, "Error saving generated OAuth2 JWT Secret to custom config: %v", err)
+			}
+		}
+	}
+
 	sec = Cfg.Section("security")
 	InstallLock = sec.Key("INSTALL_LOCK").MustBool(false)
 	SecretKey = sec.Key("SECRET_KEY").MustString("!#@FDEWREWR&*(")
@@ -905,6 +1029,8 @@ func NewContext() {
 		log.Fatal(4, "Failed to map Repository.Upload settings: %v", err)
 	} else if err = Cfg.Section("repository.local").MapTo(&Repository.Local); err != nil {
 		log.Fatal(4, "Failed to map Repository.Local settings: %v", err)
+	} else if err = Cfg.Section("repository.signing").MapTo(&Repository.Signing); err != nil {
+		log.Fatal(4, "Failed to map Repository.Signing settings: %v", err)
 	}
 
 	if !filepath.IsAbs(Repository.Upload.TempPath) {
@@ -951,6 +1077,8 @@ func NewContext() {
 		}
 	}
 
+	newStorageService()
+
 	if err = Cfg.Section("ui").MapTo(&UI); err != nil {
 		log.Fatal(4, "Failed to map UI settings: %v", err)
 	} else if err = Cfg.Section("markdown").MapTo(&Markdown); err != nil {
@@ -992,26 +1120,31 @@ func NewContext() {
 	ShowFooterTemplateLoadTime = Cfg.Section("other").Key("SHOW_FOOTER_TEMPLATE_LOAD_TIME").MustBool(true)
 
 	UI.ShowUserEmail = Cfg.Section("ui").Key("SHOW_USER_EMAIL").MustBool(true)
+	if reactions := Cfg.Section("ui").Key("REACTIONS").Strings(","); len(reactions) > 0 {
+		UI.Reactions = reactions
+	}
 
 	HasRobotsTxt = com.IsFile(path.Join(CustomPath, "robots.txt"))
 }
 
 // Service settings
 var Service struct {
-	ActiveCodeLives                int
-	ResetPwdCodeLives              int
-	RegisterEmailConfirm           bool
-	DisableRegistration            bool
-	ShowRegistrationButton         bool
-	RequireSignInView              bool
-	EnableNotifyMail               bool
-	EnableReverseProxyAuth         bool
-	EnableReverseProxyAutoRegister bool
-	EnableCaptcha                  bool
-	DefaultKeepEmailPrivate        bool
-	DefaultAllowCreateOrganization bool
-	DefaultEnableTimetracking      bool
-	NoReplyAddress                 string
+	ActiveCodeLives                  int
+	ResetPwdCodeLives                int
+	RegisterEmailConfirm             bool
+	DisableRegistration              bool
+	ShowRegistrationButton           bool
+	RequireSignInView                bool
+	EnableNotifyMail                 bool
+	EnableReverseProxyAuth           bool
+	EnableReverseProxyAutoRegister   bool
+	EnableCaptcha                    bool
+	DefaultKeepEmailPrivate          bool
+	DefaultAllowCreateOrganization   bool
+	DefaultEnableTimetracking        bool
+	DefaultEnableDependencies        bool
+	AllowCrossRepositoryDependencies bool
+	NoReplyAddress                   string
 
 	// OpenID settings
 	EnableOpenIDSignIn bool
@@ -1033,6 +1166,8 @@ func newService() {
 	Service.DefaultKeepEmailPrivate = sec.Key("DEFAULT_KEEP_EMAIL_PRIVATE").MustBool()
 	Service.DefaultAllowCreateOrganization = sec.Key("DEFAULT_ALLOW_CREATE_ORGANIZATION").MustBool(true)
 	Service.DefaultEnableTimetracking = sec.Key("DEFAULT_ENABLE_TIMETRACKING").MustBool(true)
+	Service.DefaultEnableDependencies = sec.Key("DEFAULT_ENABLE_DEPENDENCIES").MustBool(true)
+	Service.AllowCrossRepositoryDependencies = sec.Key("ALLOW_CROSS_REPOSITORY_DEPENDENCIES").MustBool(true)
 	Service.NoReplyAddress = sec.Key("NO_REPLY_ADDRESS").MustString("noreply.example.org")
 
 	sec = Cfg.Section("openid")
@@ -1351,8 +1486,10 @@ func newWebhookService() {
 	Webhook.QueueLength = sec.Key("QUEUE_LENGTH").MustInt(1000)
 	Webhook.DeliverTimeout = sec.Key("DELIVER_TIMEOUT").MustInt(5)
 	Webhook.SkipTLSVerify = sec.Key("SKIP_TLS_VERIFY").MustBool()
-	Webhook.Types = []string{"gitea", "gogs", "slack"}
+	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "msteams"}
 	Webhook.PagingNum = sec.Key("PAGING_NUM").MustInt(10)
+	Webhook.MaxRetries = sec.Key("MAX_RETRIES").MustInt(3)
+	Webhook.RetryBackoff = sec.Key("RETRY_BACKOFF").MustInt(10)
 }
 
 // NewServices initializes the services
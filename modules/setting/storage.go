@@ -0,0 +1,75 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+)
+
+// enumerates all the storage types
+const (
+	LocalStorageType = "local"
+	MinioStorageType = "minio"
+)
+
+// Storage describes where a class of binary objects (attachments, LFS objects, avatars) is kept
+type Storage struct {
+	Type string
+
+	// Path is the root directory used by local storage
+	Path string
+
+	// Minio settings are used by the S3 compatible storage
+	Minio struct {
+		Endpoint        string
+		AccessKeyID     string
+		SecretAccessKey string
+		Bucket          string
+		Location        string
+		BasePath        string
+		UseSSL          bool
+	}
+}
+
+// Storage settings
+var (
+	AttachmentStorage Storage
+	LFSStorage        Storage
+	AvatarStorage     Storage
+)
+
+// getStorage reads the storage configuration of the named section. Every key
+// that is missing falls back to the [storage] section, so a shared MinIO setup
+// only needs to be configured once.
+func getStorage(sectionName, defaultPath string) Storage {
+	sec := Cfg.Section(sectionName)
+	defaults := Cfg.Section("storage")
+
+	get := func(key, def string) string {
+		if sec.HasKey(key) {
+			return sec.Key(key).String()
+		}
+		return defaults.Key(key).MustString(def)
+	}
+
+	var s Storage
+	s.Type = strings.ToLower(get("STORAGE_TYPE", LocalStorageType))
+	s.Path = defaultPath
+	s.Minio.Endpoint = get("MINIO_ENDPOINT", "localhost:9000")
+	s.Minio.AccessKeyID = get("MINIO_ACCESS_KEY_ID", "")
+	s.Minio.SecretAccessKey = get("MINIO_SECRET_ACCESS_KEY", "")
+	s.Minio.Bucket = get("MINIO_BUCKET", "gitea")
+	s.Minio.Location = get("MINIO_LOCATION", "us-east-1")
+	s.Minio.UseSSL = get("MINIO_USE_SSL", "false") == "true"
+	// Objects of different kinds share a bucket, so each kind gets its own prefix by default.
+	s.Minio.BasePath = sec.Key("MINIO_BASE_PATH").MustString(sectionName + "/")
+	return s
+}
+
+func newStorageService() {
+	AttachmentStorage = getStorage("attachment", AttachmentPath)
+	LFSStorage = getStorage("lfs", LFS.ContentPath)
+	AvatarStorage = getStorage("picture", AvatarUploadPath)
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+var _ ObjectStorage = &LocalStorage{}
+
+// localTmpDir is the directory below the storage root where files are written
+// before they are moved to their path
+const localTmpDir = ".tmp"
+
+// LocalStorage represents a local files storage
+type LocalStorage struct {
+	dir    string
+	tmpDir string
+}
+
+// NewLocalStorage returns a local files storage rooted at dir
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	tmpDir := filepath.Join(dir, localTmpDir)
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{
+		dir:    dir,
+		tmpDir: tmpDir,
+	}, nil
+}
+
+func (l *LocalStorage) buildLocalPath(p string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+// Open a file
+func (l *LocalStorage) Open(path string) (Object, error) {
+	return os.Open(l.buildLocalPath(path))
+}
+
+// Save a file, the content is written to a temporary file first so readers
+// never see partially written objects.
+func (l *LocalStorage) Save(path string, r io.Reader) (int64, error) {
+	p := l.buildLocalPath(path)
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	tmp, err := ioutil.TempFile(l.tmpDir, "object")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	n, err := io.Copy(tmp, r)
+	tmp.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmpPath, p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Stat returns the info of the file
+func (l *LocalStorage) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(l.buildLocalPath(path))
+}
+
+// Delete delete a file
+func (l *LocalStorage) Delete(path string) error {
+	return os.Remove(l.buildLocalPath(path))
+}
+
+// IterateObjects iterates across the objects in the local storage
+func (l *LocalStorage) IterateObjects(fn func(path string, obj Object) error) error {
+	return filepath.Walk(l.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == l.tmpDir {
+			return filepath.SkipDir
+		} else if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			return err
+		}
+		obj, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer obj.Close()
+		return fn(filepath.ToSlash(relPath), obj)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go"
+)
+
+var _ ObjectStorage = &MinioStorage{}
+
+// MinioStorageConfig represents the configuration of a minio or any other
+// S3 compatible object storage
+type MinioStorageConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+	Location        string
+	BasePath        string
+	UseSSL          bool
+}
+
+// MinioStorage stores objects in a bucket of a minio or any other S3 compatible object storage
+type MinioStorage struct {
+	client   *minio.Client
+	bucket   string
+	basePath string
+}
+
+// NewMinioStorage returns a minio storage and creates its bucket if it does not exist yet
+func NewMinioStorage(cfg MinioStorageConfig) (*MinioStorage, error) {
+	if cfg.Location == "" {
+		cfg.Location = "us-east-1"
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("Minio bucket is not specified")
+	}
+
+	client, err := minio.NewWithRegion(cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.UseSSL, cfg.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	// BucketExists reports a missing bucket either as false or as a NoSuchBucket error
+	exists, err := client.BucketExists(cfg.Bucket)
+	if err != nil && convertMinioErr(err) != ErrObjectNotExist {
+		return nil, err
+	} else if !exists {
+		if err = client.MakeBucket(cfg.Bucket, cfg.Location); err != nil {
+			return nil, fmt.Errorf("create bucket %s: %v", cfg.Bucket, err)
+		}
+	}
+
+	return &MinioStorage{
+		client:   client,
+		bucket:   cfg.Bucket,
+		basePath: strings.Trim(cfg.BasePath, "/"),
+	}, nil
+}
+
+func (m *MinioStorage) buildMinioPath(p string) string {
+	return strings.TrimPrefix(path.Join(m.basePath, path.Clean("/"+p)), "/")
+}
+
+// convertMinioErr maps a missing key to ErrObjectNotExist
+func convertMinioErr(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket":
+		return ErrObjectNotExist
+	}
+	return err
+}
+
+// Open opens an object for reading
+func (m *MinioStorage) Open(path string) (Object, error) {
+	// GetObject does not talk to the server until the first read, so
+	// check whether the object exists first.
+	if _, err := m.Stat(path); err != nil {
+		return nil, err
+	}
+	obj, err := m.client.GetObject(m.bucket, m.buildMinioPath(path))
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	return obj, nil
+}
+
+// Save saves an object. The content is spooled to a temporary file first,
+// uploads need to know their size in advance.
+func (m *MinioStorage) Save(path string, r io.Reader) (int64, error) {
+	tmp, err := ioutil.TempFile("", "gitea-minio")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		return 0, err
+	} else if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return m.client.PutObject(m.bucket, m.buildMinioPath(path), tmp, "application/octet-stream")
+}
+
+// Stat returns the stat information of the object
+func (m *MinioStorage) Stat(path string) (os.FileInfo, error) {
+	info, err := m.client.StatObject(m.bucket, m.buildMinioPath(path))
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	return &minioFileInfo{info}, nil
+}
+
+// Delete deletes an object
+func (m *MinioStorage) Delete(path string) error {
+	return convertMinioErr(m.client.RemoveObject(m.bucket, m.buildMinioPath(path)))
+}
+
+// IterateObjects iterates across the objects below the base path
+func (m *MinioStorage) IterateObjects(fn func(path string, obj Object) error) error {
+	prefix := ""
+	if m.basePath != "" {
+		prefix = m.basePath + "/"
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for info := range m.client.ListObjectsV2(m.bucket, prefix, true, doneCh) {
+		if info.Err != nil {
+			return info.Err
+		}
+		obj, err := m.client.GetObject(m.bucket, info.Key)
+		if err != nil {
+			return convertMinioErr(err)
+		}
+		err = fn(strings.TrimPrefix(info.Key, prefix), obj)
+		obj.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type minioFileInfo struct {
+	minio.ObjectInfo
+}
+
+func (fi *minioFileInfo) Name() string {
+	return path.Base(fi.Key)
+}
+
+func (fi *minioFileInfo) Size() int64 {
+	return fi.ObjectInfo.Size
+}
+
+func (fi *minioFileInfo) Mode() os.FileMode {
+	return 0644
+}
+
+func (fi *minioFileInfo) ModTime() time.Time {
+	return fi.LastModified
+}
+
+func (fi *minioFileInfo) IsDir() bool {
+	return false
+}
+
+func (fi *minioFileInfo) Sys() interface{} {
+	return nil
+}
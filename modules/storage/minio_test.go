@@ -0,0 +1,159 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3 compatible server,
+// supporting just enough of the API for MinioStorage.
+type fakeS3 struct {
+	t       *testing.T
+	mutex   sync.Mutex
+	buckets map[string]map[string][]byte
+	// maxKeys limits the size of a list page to exercise continuation
+	maxKeys int
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	return &fakeS3{
+		t:       t,
+		buckets: map[string]map[string][]byte{},
+		maxKeys: 1,
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access-key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	bucket, hasBucket := f.buckets[parts[0]]
+	if len(parts) == 1 || parts[1] == "" {
+		switch {
+		case r.Method == "PUT":
+			f.buckets[parts[0]] = map[string][]byte{}
+		case !hasBucket:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET":
+			f.list(w, r, bucket)
+		}
+		return
+	}
+
+	if !hasBucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := parts[1]
+	switch r.Method {
+	case "PUT":
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(f.t, err)
+		sum := sha256.Sum256(data)
+		assert.Equal(f.t, hex.EncodeToString(sum[:]), r.Header.Get("X-Amz-Content-Sha256"))
+		bucket[key] = data
+	case "HEAD", "GET":
+		data, ok := bucket[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, key, time.Now(), bytes.NewReader(data))
+	case "DELETE":
+		delete(bucket, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type fakeListEntry struct {
+	Key  string
+	Size int64
+}
+
+type fakeListBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Contents              []fakeListEntry
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request, bucket map[string][]byte) {
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for k := range bucket {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("continuation-token"))
+	end := start + f.maxKeys
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	var result fakeListBucketResult
+	for _, k := range keys[start:end] {
+		result.Contents = append(result.Contents, fakeListEntry{k, int64(len(bucket[k]))})
+	}
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = fmt.Sprint(end)
+	}
+	xml.NewEncoder(w).Encode(&result)
+}
+
+func TestMinioStorage(t *testing.T) {
+	fake := newFakeS3(t)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := NewMinioStorage(MinioStorageConfig{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+		Bucket:          "gitea",
+		BasePath:        "attachments/",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, fake.buckets, "gitea")
+
+	testObjectStorage(t, s)
+
+	// objects are stored below the base path
+	assert.Contains(t, fake.buckets["gitea"], "attachments/other")
+
+	_, err = NewMinioStorage(MinioStorageConfig{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		AccessKeyID:     "wrong-key",
+		SecretAccessKey: "secret-key",
+		Bucket:          "gitea",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	// ErrObjectNotExist is returned when a requested object is not in the storage
+	ErrObjectNotExist = errors.New("Object does not exist")
+)
+
+// IsErrObjectNotExist returns true if the error means the object is missing
+func IsErrObjectNotExist(err error) bool {
+	return err == ErrObjectNotExist || os.IsNotExist(err)
+}
+
+// Object represents a stored object which can be read from any position
+type Object interface {
+	io.ReadCloser
+	io.Seeker
+}
+
+// ObjectStorage represents an object storage to handle a bucket and files.
+// Paths are always slash separated and relative to the root of the storage.
+type ObjectStorage interface {
+	Open(path string) (Object, error)
+	Save(path string, r io.Reader) (int64, error)
+	Stat(path string) (os.FileInfo, error)
+	Delete(path string) error
+	IterateObjects(fn func(path string, obj Object) error) error
+}
+
+// Copy copies a file from the source ObjectStorage to the destination ObjectStorage
+func Copy(dstStorage ObjectStorage, dstPath string, srcStorage ObjectStorage, srcPath string) (int64, error) {
+	f, err := srcStorage.Open(srcPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return dstStorage.Save(dstPath, f)
+}
+
+var (
+	// Attachments represents attachments storage, release assets are attachments as well
+	Attachments ObjectStorage
+
+	// LFS represents lfs storage
+	LFS ObjectStorage
+
+	// Avatars represents user and organization avatars storage
+	Avatars ObjectStorage
+)
+
+// NewStorage creates the storage described by the given configuration
+func NewStorage(cfg setting.Storage) (ObjectStorage, error) {
+	switch cfg.Type {
+	case setting.LocalStorageType:
+		return NewLocalStorage(cfg.Path)
+	case setting.MinioStorageType:
+		return NewMinioStorage(MinioStorageConfig{
+			Endpoint:        cfg.Minio.Endpoint,
+			AccessKeyID:     cfg.Minio.AccessKeyID,
+			SecretAccessKey: cfg.Minio.SecretAccessKey,
+			Bucket:          cfg.Minio.Bucket,
+			Location:        cfg.Minio.Location,
+			BasePath:        cfg.Minio.BasePath,
+			UseSSL:          cfg.Minio.UseSSL,
+		})
+	}
+	return nil, fmt.Errorf("Unsupported storage type: %s", cfg.Type)
+}
+
+// Init initializes the storages of all object kinds
+func Init() (err error) {
+	if Attachments, err = NewStorage(setting.AttachmentStorage); err != nil {
+		return fmt.Errorf("attachments: %v", err)
+	}
+	if LFS, err = NewStorage(setting.LFSStorage); err != nil {
+		return fmt.Errorf("lfs: %v", err)
+	}
+	if Avatars, err = NewStorage(setting.AvatarStorage); err != nil {
+		return fmt.Errorf("avatars: %v", err)
+	}
+	return nil
+}
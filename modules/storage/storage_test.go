@@ -0,0 +1,113 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testObjectStorage runs the same set of operations against any ObjectStorage
+func testObjectStorage(t *testing.T, s ObjectStorage) {
+	n, err := s.Save("a/b/object", strings.NewReader("hello world"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, n)
+
+	_, err = s.Save("other", strings.NewReader("other content"))
+	assert.NoError(t, err)
+
+	fi, err := s.Stat("a/b/object")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, fi.Size())
+	assert.Equal(t, "object", fi.Name())
+
+	obj, err := s.Open("a/b/object")
+	assert.NoError(t, err)
+	_, err = obj.Seek(6, io.SeekStart)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(data))
+	size, err := obj.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, size)
+	assert.NoError(t, obj.Close())
+
+	var paths []string
+	assert.NoError(t, s.IterateObjects(func(path string, obj Object) error {
+		paths = append(paths, path)
+		return nil
+	}))
+	sort.Strings(paths)
+	assert.Equal(t, []string{"a/b/object", "other"}, paths)
+
+	dstDir := tempDir(t)
+	defer os.RemoveAll(dstDir)
+	dst, err := NewLocalStorage(dstDir)
+	assert.NoError(t, err)
+	n, err = Copy(dst, "copied", s, "other")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 13, n)
+
+	assert.NoError(t, s.Delete("a/b/object"))
+	_, err = s.Stat("a/b/object")
+	assert.True(t, IsErrObjectNotExist(err))
+	_, err = s.Open("a/b/object")
+	assert.True(t, IsErrObjectNotExist(err))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "gitea-storage")
+	assert.NoError(t, err)
+	return dir
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	s, err := NewLocalStorage(dir)
+	assert.NoError(t, err)
+	testObjectStorage(t, s)
+
+	// paths can not escape the storage root
+	_, err = s.Save("../../escape", strings.NewReader("content"))
+	assert.NoError(t, err)
+	_, err = os.Stat(dir + "/escape")
+	assert.NoError(t, err)
+
+	// only the temporary files of Save are skipped
+	_, err = s.Save("avatar.tmp.png", strings.NewReader("content"))
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, localTmpDir, "object123"), []byte("partial"), 0644))
+	var paths []string
+	assert.NoError(t, s.IterateObjects(func(path string, obj Object) error {
+		paths = append(paths, path)
+		return nil
+	}))
+	sort.Strings(paths)
+	assert.Equal(t, []string{"avatar.tmp.png", "escape", "other"}, paths)
+}
+
+func TestInitWithEmptyConfig(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	setting.CustomConf = filepath.Join(dir, "app.ini")
+	setting.NewContext()
+
+	assert.NotEmpty(t, setting.LFSStorage.Path)
+	assert.NoError(t, Init())
+	assert.NotNil(t, LFS)
+}
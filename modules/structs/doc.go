@@ -0,0 +1,7 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package structs contains the types of the API of Gitea: the options and
+// responses of its routes and the payloads of its webhooks.
+package structs
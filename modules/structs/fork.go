@@ -2,4 +2,9 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-package gitea // import "code.gitea.io/sdk/gitea"
+package structs
+
+// CreateForkOption options for creating a fork
+type CreateForkOption struct {
+	Organization *string `json:"organization"`
+}
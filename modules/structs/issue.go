@@ -0,0 +1,76 @@
+// Copyright 2016 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// StateType issue state type
+type StateType string
+
+const (
+	// StateOpen pr is opend
+	StateOpen StateType = "open"
+	// StateClosed pr is closed
+	StateClosed StateType = "closed"
+)
+
+// PullRequestMeta PR info if an issue is a PR
+type PullRequestMeta struct {
+	HasMerged bool       `json:"merged"`
+	Merged    *time.Time `json:"merged_at"`
+}
+
+// Issue an issue to a repository
+type Issue struct {
+	ID        int64      `json:"id"`
+	URL       string     `json:"url"`
+	Index     int64      `json:"number"`
+	Poster    *User      `json:"user"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Labels    []*Label   `json:"labels"`
+	Milestone *Milestone `json:"milestone"`
+	Assignee  *User      `json:"assignee"`
+	Assignees []*User    `json:"assignees"`
+	State     StateType  `json:"state"`
+	Comments  int        `json:"comments"`
+	Created   time.Time  `json:"created_at"`
+	Updated   time.Time  `json:"updated_at"`
+
+	PullRequest *PullRequestMeta `json:"pull_request"`
+}
+
+// IssueList represents a list of issues
+// swagger:response IssueList
+type IssueList []*Issue
+
+// ListIssueOption list issue options
+type ListIssueOption struct {
+	Page  int
+	State string
+}
+
+// CreateIssueOption options to create one issue
+type CreateIssueOption struct {
+	Title     string   `json:"title" binding:"Required"`
+	Body      string   `json:"body"`
+	Assignee  string   `json:"assignee"`
+	Assignees []string `json:"assignees"`
+	Milestone int64    `json:"milestone"`
+	Labels    []int64  `json:"labels"`
+	Closed    bool     `json:"closed"`
+}
+
+// EditIssueOption edit issue options
+type EditIssueOption struct {
+	Title     string   `json:"title"`
+	Body      *string  `json:"body"`
+	Assignee  *string  `json:"assignee"`
+	Assignees []string `json:"assignees"`
+	Milestone *int64   `json:"milestone"`
+	State     *string  `json:"state"`
+}
@@ -0,0 +1,31 @@
+// Copyright 2016 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Comment represents a comment in commit and issue page.
+type Comment struct {
+	ID       int64     `json:"id"`
+	HTMLURL  string    `json:"html_url"`
+	PRURL    string    `json:"pull_request_url"`
+	IssueURL string    `json:"issue_url"`
+	Poster   *User     `json:"user"`
+	Body     string    `json:"body"`
+	Created  time.Time `json:"created_at"`
+	Updated  time.Time `json:"updated_at"`
+}
+
+// CreateIssueCommentOption is option when creating an issue comment.
+type CreateIssueCommentOption struct {
+	Body string `json:"body" binding:"Required"`
+}
+
+// EditIssueCommentOption is option when editing an issue comment.
+type EditIssueCommentOption struct {
+	Body string `json:"body" binding:"Required"`
+}
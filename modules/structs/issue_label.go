@@ -0,0 +1,30 @@
+// Copyright 2016 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Label a label to an issue or a pr
+type Label struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	URL   string `json:"url"`
+}
+
+// CreateLabelOption create options when one label of repository
+type CreateLabelOption struct {
+	Name  string `json:"name" binding:"Required"`
+	Color string `json:"color" binding:"Required;Size(7)"`
+}
+
+// EditLabelOption edit label options
+type EditLabelOption struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// IssueLabelsOption list one issue's labels options
+type IssueLabelsOption struct {
+	Labels []int64 `json:"labels"`
+}
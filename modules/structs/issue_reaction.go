@@ -0,0 +1,26 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Reaction represents a reaction of a user to an issue or a comment
+// swagger:response Reaction
+type Reaction struct {
+	User     *User     `json:"user"`
+	Reaction string    `json:"content"`
+	Created  time.Time `json:"created_at"`
+}
+
+// ReactionList represents a list of reactions
+// swagger:response ReactionList
+type ReactionList []*Reaction
+
+// EditReactionOption contains the reaction to add or remove
+type EditReactionOption struct {
+	Reaction string `json:"content" binding:"Required"`
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-package gitea
+package structs
 
 // SearchResults results of search
 // swagger:response SearchResults
@@ -48,9 +48,3 @@ type MarkdownRender string
 type ServerVersion struct {
 	Version string
 }
-
-// ServerVersion returns the version of the server
-func (c *Client) ServerVersion() (string, error) {
-	v := ServerVersion{}
-	return v.Version, c.getParsedResponse("GET", "/api/v1/version", nil, nil, &v)
-}
@@ -0,0 +1,52 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// NotificationThread expose Notification on API
+// swagger:response NotificationThread
+type NotificationThread struct {
+	ID         int64                `json:"id"`
+	Repository *Repository          `json:"repository"`
+	Subject    *NotificationSubject `json:"subject"`
+	Unread     bool                 `json:"unread"`
+	Pinned     bool                 `json:"pinned"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+	URL        string               `json:"url"`
+}
+
+// NotificationThreads represents a list of notification threads
+// swagger:response NotificationThreads
+type NotificationThreads []*NotificationThread
+
+// NotificationSubject contains the issue or pull request a notification is about
+type NotificationSubject struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+	// Type is "Issue" or "Pull"
+	Type string `json:"type"`
+	// State is "open" or "closed"
+	State string `json:"state"`
+}
+
+// NotificationCount number of unread notifications
+// swagger:response NotificationCount
+type NotificationCount struct {
+	New int64 `json:"new"`
+}
+
+// ListNotificationOptions options for listing notifications
+type ListNotificationOptions struct {
+	// All includes the read notifications
+	All    bool
+	Since  time.Time
+	Before time.Time
+	Page   int
+	Limit  int
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Organization a group of some repositories, users and teams
+type Organization struct {
+	ID          int64  `json:"id"`
+	UserName    string `json:"username"`
+	FullName    string `json:"full_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+	// Visibility is one of public, limited or private
+	Visibility string `json:"visibility"`
+}
+
+// CreateOrgOption create one organization options
+type CreateOrgOption struct {
+	UserName    string `json:"username" binding:"Required"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+	// Visibility is one of public, limited or private, public by default
+	Visibility string `json:"visibility" binding:"In(,public,limited,private)"`
+}
+
+// EditOrgOption edit one organization options
+type EditOrgOption struct {
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+	// Visibility is one of public, limited or private, empty leaves it unchanged
+	Visibility string `json:"visibility" binding:"In(,public,limited,private)"`
+}
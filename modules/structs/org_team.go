@@ -2,7 +2,7 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-package gitea
+package structs
 
 // Team is a sub virtual organization of one Organization
 type Team struct {
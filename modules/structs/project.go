@@ -0,0 +1,79 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// Project is a board of a repository, its issues and pull requests are
+// arranged in ordered columns
+// swagger:response Project
+type Project struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       StateType  `json:"state"`
+	Creator     *User      `json:"creator"`
+	Created     time.Time  `json:"created_at"`
+	Updated     time.Time  `json:"updated_at"`
+	Closed      *time.Time `json:"closed_at"`
+}
+
+// ProjectList represents a list of projects
+// swagger:response ProjectList
+type ProjectList []*Project
+
+// ProjectBoard is a column of a project
+// swagger:response ProjectBoard
+type ProjectBoard struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Sorting int    `json:"position"`
+	// ClosedColumn is set for the board collecting the closed issues
+	ClosedColumn bool `json:"closed_column"`
+}
+
+// ProjectBoardList represents a list of project boards
+// swagger:response ProjectBoardList
+type ProjectBoardList []*ProjectBoard
+
+// CreateProjectOption options when creating a project
+type CreateProjectOption struct {
+	Title       string `json:"title" binding:"Required"`
+	Description string `json:"description"`
+}
+
+// EditProjectOption options when modifying a project
+type EditProjectOption struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	State       *string `json:"state"`
+}
+
+// CreateProjectBoardOption options when adding a board to a project
+type CreateProjectBoardOption struct {
+	Title        string `json:"title" binding:"Required"`
+	Sorting      int    `json:"position"`
+	ClosedColumn bool   `json:"closed_column"`
+}
+
+// EditProjectBoardOption options when modifying a board of a project
+type EditProjectBoardOption struct {
+	Title        *string `json:"title"`
+	Sorting      *int    `json:"position"`
+	ClosedColumn *bool   `json:"closed_column"`
+}
+
+// MoveProjectIssueOption options when adding an issue to a project or
+// moving it to another position
+type MoveProjectIssueOption struct {
+	// Issue is the index of the issue or pull request in the repository
+	Issue   int64 `json:"issue" binding:"Required"`
+	BoardID int64 `json:"board_id"`
+	// Sorting is the position on the board, the issue is appended if it is
+	// negative or beyond the end
+	Sorting int `json:"position"`
+}
@@ -0,0 +1,20 @@
+// Copyright 2016 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Branch represents a repository branch.
+// swagger:response Branch
+type Branch struct {
+	Name   string         `json:"name"`
+	Commit *PayloadCommit `json:"commit"`
+}
+
+// CreateBranchOption options when creating a branch in a repository
+type CreateBranchOption struct {
+	// Name of the branch to create
+	BranchName string `json:"new_branch_name" binding:"Required;GitRefName;MaxSize(100)"`
+	// Name of the branch to create it from, defaults to the default branch of the repository
+	OldBranchName string `json:"old_branch_name" binding:"GitRefName;MaxSize(100)"`
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// BranchProtection represents the protection rules of a branch
+// swagger:response BranchProtection
+type BranchProtection struct {
+	BranchName string `json:"branch_name"`
+	EnablePush bool   `json:"enable_push"`
+	// EnablePushWhitelist restricts pushing to the whitelisted users and teams
+	EnablePushWhitelist     bool      `json:"enable_push_whitelist"`
+	PushWhitelistUsernames  []string  `json:"push_whitelist_usernames"`
+	PushWhitelistTeams      []string  `json:"push_whitelist_teams"`
+	EnableMergeWhitelist    bool      `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames []string  `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams     []string  `json:"merge_whitelist_teams"`
+	RequiredApprovals       int64     `json:"required_approvals"`
+	EnableStatusCheck       bool      `json:"enable_status_check"`
+	StatusCheckContexts     []string  `json:"status_check_contexts"`
+	RequireSignedCommits    bool      `json:"require_signed_commits"`
+	Created                 time.Time `json:"created_at"`
+	Updated                 time.Time `json:"updated_at"`
+}
+
+// BranchProtectionList represents a list of branch protections
+// swagger:response BranchProtectionList
+type BranchProtectionList []*BranchProtection
+
+// CreateBranchProtectionOption options when protecting a branch
+type CreateBranchProtectionOption struct {
+	BranchName              string   `json:"branch_name" binding:"Required"`
+	EnablePush              bool     `json:"enable_push"`
+	EnablePushWhitelist     bool     `json:"enable_push_whitelist"`
+	PushWhitelistUsernames  []string `json:"push_whitelist_usernames"`
+	PushWhitelistTeams      []string `json:"push_whitelist_teams"`
+	EnableMergeWhitelist    bool     `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames []string `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams     []string `json:"merge_whitelist_teams"`
+	RequiredApprovals       int64    `json:"required_approvals"`
+	EnableStatusCheck       bool     `json:"enable_status_check"`
+	StatusCheckContexts     []string `json:"status_check_contexts"`
+	RequireSignedCommits    bool     `json:"require_signed_commits"`
+}
+
+// EditBranchProtectionOption options when editing the protection of a branch,
+// the fields which are not set are left unchanged
+type EditBranchProtectionOption struct {
+	EnablePush              *bool    `json:"enable_push"`
+	EnablePushWhitelist     *bool    `json:"enable_push_whitelist"`
+	PushWhitelistUsernames  []string `json:"push_whitelist_usernames"`
+	PushWhitelistTeams      []string `json:"push_whitelist_teams"`
+	EnableMergeWhitelist    *bool    `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames []string `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams     []string `json:"merge_whitelist_teams"`
+	RequiredApprovals       *int64   `json:"required_approvals"`
+	EnableStatusCheck       *bool    `json:"enable_status_check"`
+	StatusCheckContexts     []string `json:"status_check_contexts"`
+	RequireSignedCommits    *bool    `json:"require_signed_commits"`
+}
@@ -0,0 +1,10 @@
+// Copyright 2016 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// AddCollaboratorOption options when add some user as a collaborator of a repository
+type AddCollaboratorOption struct {
+	Permission *string `json:"permission"`
+}
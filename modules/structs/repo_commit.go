@@ -0,0 +1,57 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// CommitMeta contains meta information of a commit in terms of API.
+type CommitMeta struct {
+	URL string `json:"url"`
+	SHA string `json:"sha"`
+}
+
+// CommitUser contains information of a user in the context of a commit.
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// RepoCommit contains information of a commit in the context of a repository.
+type RepoCommit struct {
+	URL          string                     `json:"url"`
+	Author       *CommitUser                `json:"author"`
+	Committer    *CommitUser                `json:"committer"`
+	Message      string                     `json:"message"`
+	Tree         *CommitMeta                `json:"tree"`
+	Verification *PayloadCommitVerification `json:"verification"`
+}
+
+// Commit contains information generated from a Git commit.
+// swagger:response Commit
+type Commit struct {
+	*CommitMeta
+	HTMLURL    string        `json:"html_url"`
+	RepoCommit *RepoCommit   `json:"commit"`
+	Author     *User         `json:"author"`
+	Committer  *User         `json:"committer"`
+	Parents    []*CommitMeta `json:"parents"`
+}
+
+// CommitList represents a list of commits
+// swagger:response CommitList
+type CommitList []*Commit
+
+// ListCommitOptions options for listing the commits of a repository
+type ListCommitOptions struct {
+	// SHA or branch to start listing commits from, defaults to the default branch
+	SHA string
+	// Path of the file or directory the commits must change
+	Path  string
+	Page  int
+	Limit int
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IdentityOptions for a person's identity like an author or committer
+type IdentityOptions struct {
+	Name  string `json:"name" binding:"MaxSize(100)"`
+	Email string `json:"email" binding:"MaxSize(254)"`
+}
+
+// FileOptions options for all file APIs
+type FileOptions struct {
+	// Message of the commit, a default one is used if empty
+	Message string `json:"message"`
+	// BranchName to base the commit on, defaults to the default branch
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+	// NewBranchName to commit to, defaults to BranchName
+	NewBranchName string `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	// Author of the commit, defaults to the committer
+	Author IdentityOptions `json:"author"`
+	// Committer of the commit, defaults to the authenticated user
+	Committer IdentityOptions `json:"committer"`
+}
+
+// CreateFileOptions options for creating files
+type CreateFileOptions struct {
+	FileOptions
+	// Content of the file, base64 encoded
+	Content string `json:"content"`
+}
+
+// UpdateFileOptions options for updating files
+type UpdateFileOptions struct {
+	FileOptions
+	// SHA of the blob being replaced
+	SHA string `json:"sha" binding:"Required"`
+	// Content of the file, base64 encoded
+	Content string `json:"content"`
+	// FromPath to move the file from, if not empty
+	FromPath string `json:"from_path" binding:"MaxSize(500)"`
+}
+
+// DeleteFileOptions options for deleting files
+type DeleteFileOptions struct {
+	FileOptions
+	// SHA of the blob being deleted
+	SHA string `json:"sha" binding:"Required"`
+}
+
+// FileLinksResponse contains the links of a file or directory
+type FileLinksResponse struct {
+	Self    string `json:"self"`
+	GitURL  string `json:"git"`
+	HTMLURL string `json:"html"`
+}
+
+// ContentsResponse contains information about a file or directory of a repository
+// swagger:response ContentsResponse
+type ContentsResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	// Type is "file", "dir", "symlink" or "submodule"
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	// Encoding of the content, "base64" for files and empty otherwise
+	Encoding    string            `json:"encoding,omitempty"`
+	Content     string            `json:"content,omitempty"`
+	URL         string            `json:"url"`
+	HTMLURL     string            `json:"html_url"`
+	GitURL      string            `json:"git_url"`
+	DownloadURL string            `json:"download_url"`
+	Links       FileLinksResponse `json:"_links"`
+}
+
+// ContentsListResponse contains the entries of a directory of a repository
+// swagger:response ContentsListResponse
+type ContentsListResponse []*ContentsResponse
+
+// FileResponse contains the file and the commit of a change made with the contents API
+// swagger:response FileResponse
+type FileResponse struct {
+	// Content is nil if the file has been deleted
+	Content *ContentsResponse `json:"content"`
+	Commit  *PayloadCommit    `json:"commit"`
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// GitObject represents a Git object.
+type GitObject struct {
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+	URL  string `json:"url"`
+}
+
+// Reference represents a Git reference.
+// swagger:response Reference
+type Reference struct {
+	Ref    string     `json:"ref"`
+	URL    string     `json:"url"`
+	Object *GitObject `json:"object"`
+}
+
+// ReferenceList represents a list of Git references
+// swagger:response ReferenceList
+type ReferenceList []*Reference
+
+// GitEntry represents an entry of a Git tree.
+type GitEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	SHA  string `json:"sha"`
+	URL  string `json:"url"`
+}
+
+// GitTreeResponse returns a Git tree.
+// swagger:response GitTreeResponse
+type GitTreeResponse struct {
+	SHA     string     `json:"sha"`
+	URL     string     `json:"url"`
+	Entries []GitEntry `json:"tree"`
+	// Truncated is true if there are more entries on the next pages
+	Truncated  bool `json:"truncated"`
+	Page       int  `json:"page"`
+	TotalCount int  `json:"total_count"`
+}
+
+// GitBlobResponse returns a Git blob.
+// swagger:response GitBlobResponse
+type GitBlobResponse struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+	URL      string `json:"url"`
+	SHA      string `json:"sha"`
+	Size     int64  `json:"size"`
+}
+
+// AnnotatedTagObject contains meta information of the object a tag points to.
+type AnnotatedTagObject struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	SHA  string `json:"sha"`
+}
+
+// AnnotatedTag represents an annotated Git tag.
+// swagger:response AnnotatedTag
+type AnnotatedTag struct {
+	Tag     string              `json:"tag"`
+	SHA     string              `json:"sha"`
+	URL     string              `json:"url"`
+	Message string              `json:"message"`
+	Tagger  *CommitUser         `json:"tagger"`
+	Object  *AnnotatedTagObject `json:"object"`
+}
@@ -0,0 +1,36 @@
+// Copyright 2015 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// DeployKey a deploy key
+type DeployKey struct {
+	ID       int64     `json:"id"`
+	Key      string    `json:"key"`
+	URL      string    `json:"url"`
+	Title    string    `json:"title"`
+	Created  time.Time `json:"created_at"`
+	ReadOnly bool      `json:"read_only"`
+}
+
+// CreateKeyOption options when create deploy key
+// swagger:parameters userCurrentPostKey
+type CreateKeyOption struct {
+	// Title of the key to add
+	//
+	// in: body
+	// required: true
+	// unique: true
+	Title string `json:"title" binding:"Required"`
+	// An armored SSH key to add
+	//
+	// in: body
+	// required: true
+	// unique: true
+	Key string `json:"key" binding:"Required"`
+}
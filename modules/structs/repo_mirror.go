@@ -0,0 +1,30 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PushMirror a remote the git data of a repository is pushed to
+type PushMirror struct {
+	ID            int64  `json:"id"`
+	RemoteName    string `json:"remote_name"`
+	RemoteAddress string `json:"remote_address"`
+	// Interval between scheduled pushes, empty if they are disabled
+	Interval   string     `json:"interval"`
+	Created    time.Time  `json:"created_at"`
+	LastUpdate *time.Time `json:"last_update"`
+	LastError  string     `json:"last_error"`
+}
+
+// CreatePushMirrorOption options when creating a push mirror
+type CreatePushMirrorOption struct {
+	RemoteAddress  string `json:"remote_address" binding:"Required"`
+	RemoteUsername string `json:"remote_username"`
+	RemotePassword string `json:"remote_password"`
+	// Interval between scheduled pushes, e.g. "8h", "0" disables them
+	Interval string `json:"interval"`
+}
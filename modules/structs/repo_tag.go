@@ -0,0 +1,30 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Tag represents a repository tag
+// swagger:response Tag
+type Tag struct {
+	Name string `json:"name"`
+	// ID is the SHA of the tag object for annotated tags, of the commit otherwise
+	ID         string      `json:"id"`
+	Message    string      `json:"message"`
+	Commit     *CommitMeta `json:"commit"`
+	ZipballURL string      `json:"zipball_url"`
+	TarballURL string      `json:"tarball_url"`
+}
+
+// TagList represents a list of tags
+// swagger:response TagList
+type TagList []*Tag
+
+// CreateTagOption options when creating a tag in a repository
+type CreateTagOption struct {
+	TagName string `json:"tag_name" binding:"Required;GitRefName;MaxSize(50)"`
+	// Target is the branch or commit to tag, defaults to the default branch of the repository
+	Target string `json:"target"`
+	// Message makes the tag an annotated tag if not empty
+	Message string `json:"message"`
+}
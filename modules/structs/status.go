@@ -2,12 +2,9 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-package gitea
+package structs
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
 	"time"
 )
 
@@ -21,7 +18,7 @@ const (
 	// StatusSuccess is for when the Status is Success
 	StatusSuccess StatusState = "success"
 	// StatusError is for when the Status is Error
-	StatusError   StatusState = "error"
+	StatusError StatusState = "error"
 	// StatusFailure is for when the Status is Failure
 	StatusFailure StatusState = "failure"
 	// StatusWarning is for when the Status is Warning
@@ -64,32 +61,3 @@ type CreateStatusOption struct {
 type ListStatusesOption struct {
 	Page int
 }
-
-// CreateStatus creates a new Status for a given Commit
-//
-// POST /repos/:owner/:repo/statuses/:sha
-func (c *Client) CreateStatus(owner, repo, sha string, opts CreateStatusOption) (*Status, error) {
-	body, err := json.Marshal(&opts)
-	if err != nil {
-		return nil, err
-	}
-	status := &Status{}
-	return status, c.getParsedResponse("POST", fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, repo, sha),
-		jsonHeader, bytes.NewReader(body), status)
-}
-
-// ListStatuses returns all statuses for a given Commit
-//
-// GET /repos/:owner/:repo/commits/:ref/statuses
-func (c *Client) ListStatuses(owner, repo, sha string, opts ListStatusesOption) ([]*Status, error) {
-	statuses := make([]*Status, 0, 10)
-	return statuses, c.getParsedResponse("GET", fmt.Sprintf("/repos/%s/%s/commits/%s/statuses?page=%d", owner, repo, sha, opts.Page), nil, nil, &statuses)
-}
-
-// GetCombinedStatus returns the CombinedStatus for a given Commit
-//
-// GET /repos/:owner/:repo/commits/:ref/status
-func (c *Client) GetCombinedStatus(owner, repo, sha string) (*CombinedStatus, error) {
-	status := &CombinedStatus{}
-	return status, c.getParsedResponse("GET", fmt.Sprintf("/repos/%s/%s/commits/%s/status", owner, repo, sha), nil, nil, status)
-}
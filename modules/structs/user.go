@@ -2,11 +2,10 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-package gitea
+package structs
 
 import (
 	"encoding/json"
-	"fmt"
 )
 
 // User represents a API user.
@@ -32,10 +31,3 @@ func (u User) MarshalJSON() ([]byte, error) {
 		CompatUserName string `json:"username"`
 	}{shadow(u), u.UserName})
 }
-
-// GetUserInfo get user info by user's name
-func (c *Client) GetUserInfo(user string) (*User, error) {
-	u := new(User)
-	err := c.getParsedResponse("GET", fmt.Sprintf("/users/%s", user), nil, nil, u)
-	return u, err
-}
@@ -0,0 +1,34 @@
+// Copyright 2014 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// AccessToken represents a API access token.
+// swagger:response AccessToken
+type AccessToken struct {
+	Name         string     `json:"name"`
+	Sha1         string     `json:"sha1"`
+	Scopes       []string   `json:"scopes"`
+	Repositories []string   `json:"repositories"`
+	Expires      *time.Time `json:"expires_at"`
+}
+
+// AccessTokenList represents a list of API access token.
+// swagger:response AccessTokenList
+type AccessTokenList []*AccessToken
+
+// CreateAccessTokenOption options when create access token
+// swagger:parameters userCreateToken
+type CreateAccessTokenOption struct {
+	Name string `json:"name" binding:"Required"`
+	// Scopes like "read:repo" or "write:issue", defaults to full access
+	Scopes []string `json:"scopes"`
+	// Full names of the repositories the token is restricted to
+	Repositories []string   `json:"repositories"`
+	Expires      *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,17 @@
+// Copyright 2015 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Email en email information of user
+type Email struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+	Primary  bool   `json:"primary"`
+}
+
+// CreateEmailOption options when create an email
+type CreateEmailOption struct {
+	Emails []string `json:"emails"`
+}
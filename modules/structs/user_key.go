@@ -0,0 +1,23 @@
+// Copyright 2015 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PublicKeyList represents a list of PublicKey
+// swagger:response PublicKeyList
+type PublicKeyList []*PublicKey
+
+// PublicKey publickey is a user key to push code to repository
+// swagger:response PublicKey
+type PublicKey struct {
+	ID      int64     `json:"id"`
+	Key     string    `json:"key"`
+	URL     string    `json:"url,omitempty"`
+	Title   string    `json:"title,omitempty"`
+	Created time.Time `json:"created_at,omitempty"`
+}
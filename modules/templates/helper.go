@@ -154,9 +154,31 @@ func NewFuncMap() []template.FuncMap {
 			}
 			return out.String()
 		},
+		"Printf": fmt.Sprintf,
+		"Dict":   Dict,
+		"AllowedReactions": func() []string {
+			return setting.UI.Reactions
+		},
 	}}
 }
 
+// Dict creates a map from its arguments, alternating keys and values, so
+// that several values can be passed to a sub template
+func Dict(values ...interface{}) (map[string]interface{}, error) {
+	if len(values)%2 != 0 {
+		return nil, fmt.Errorf("invalid dict call: odd number of arguments")
+	}
+	dict := make(map[string]interface{}, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key, ok := values[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid dict call: key %v is not a string", values[i])
+		}
+		dict[key] = values[i+1]
+	}
+	return dict, nil
+}
+
 // Safe render raw as HTML
 func Safe(raw string) template.HTML {
 	return template.HTML(raw)
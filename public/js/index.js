@@ -108,81 +108,85 @@ function initCommentForm() {
 
     initCommentPreviewTab($('.comment.form'));
 
-    // Labels
-    var $list = $('.ui.labels.list');
-    var $noSelect = $list.find('.no-select');
-    var $labelMenu = $('.select-label .menu');
-    var hasLabelUpdateAction = $labelMenu.data('action') == 'update';
+    // Lists where several items can be selected, like labels and assignees
+    function initListSubmits(selector, outerSelector) {
+        var $list = $('.ui.' + outerSelector + '.list');
+        var $noSelect = $list.find('.no-select');
+        var $listMenu = $('.' + selector + ' .menu');
+        var hasUpdateAction = $listMenu.data('action') == 'update';
+
+        $('.' + selector).dropdown('setting', 'onHide', function(){
+            if (hasUpdateAction) {
+                location.reload();
+            }
+        });
 
-    $('.select-label').dropdown('setting', 'onHide', function(){
-        if (hasLabelUpdateAction) {
-            location.reload();
-        }
-    });
+        $listMenu.find('.item:not(.no-select)').click(function () {
+            if ($(this).hasClass('checked')) {
+                $(this).removeClass('checked');
+                $(this).find('.octicon').removeClass('octicon-check');
+                if (hasUpdateAction) {
+                    updateIssuesMeta(
+                        $listMenu.data('update-url'),
+                        "detach",
+                        $listMenu.data('issue-id'),
+                        $(this).data('id')
+                    );
+                }
+            } else {
+                $(this).addClass('checked');
+                $(this).find('.octicon').addClass('octicon-check');
+                if (hasUpdateAction) {
+                    updateIssuesMeta(
+                        $listMenu.data('update-url'),
+                        "attach",
+                        $listMenu.data('issue-id'),
+                        $(this).data('id')
+                    );
+                }
+            }
 
-    $labelMenu.find('.item:not(.no-select)').click(function () {
-        if ($(this).hasClass('checked')) {
-            $(this).removeClass('checked');
-            $(this).find('.octicon').removeClass('octicon-check');
-            if (hasLabelUpdateAction) {
-                updateIssuesMeta(
-                    $labelMenu.data('update-url'),
-                    "detach",
-                    $labelMenu.data('issue-id'),
-                    $(this).data('id')
-                );
+            var listIds = [];
+            $(this).parent().find('.item').each(function () {
+                if ($(this).hasClass('checked')) {
+                    listIds.push($(this).data('id'));
+                    $($(this).data('id-selector')).removeClass('hide');
+                } else {
+                    $($(this).data('id-selector')).addClass('hide');
+                }
+            });
+            if (listIds.length == 0) {
+                $noSelect.removeClass('hide');
+            } else {
+                $noSelect.addClass('hide');
             }
-        } else {
-            $(this).addClass('checked');
-            $(this).find('.octicon').addClass('octicon-check');
-            if (hasLabelUpdateAction) {
+            $($(this).parent().data('id')).val(listIds.join(","));
+            return false;
+        });
+        $listMenu.find('.no-select.item').click(function () {
+            if (hasUpdateAction) {
                 updateIssuesMeta(
-                    $labelMenu.data('update-url'),
-                    "attach",
-                    $labelMenu.data('issue-id'),
-                    $(this).data('id')
+                    $listMenu.data('update-url'),
+                    "clear",
+                    $listMenu.data('issue-id'),
+                    ""
                 );
             }
-        }
 
-        var labelIds = [];
-        $(this).parent().find('.item').each(function () {
-            if ($(this).hasClass('checked')) {
-                labelIds.push($(this).data('id'));
-                $($(this).data('id-selector')).removeClass('hide');
-            } else {
+            $(this).parent().find('.item').each(function () {
+                $(this).removeClass('checked');
+                $(this).find('.octicon').removeClass('octicon-check');
                 $($(this).data('id-selector')).addClass('hide');
-            }
-        });
-        if (labelIds.length == 0) {
-            $noSelect.removeClass('hide');
-        } else {
-            $noSelect.addClass('hide');
-        }
-        $($(this).parent().data('id')).val(labelIds.join(","));
-        return false;
-    });
-    $labelMenu.find('.no-select.item').click(function () {
-        if (hasLabelUpdateAction) {
-            updateIssuesMeta(
-                $labelMenu.data('update-url'),
-                "clear",
-                $labelMenu.data('issue-id'),
-                ""
-            );
-        }
+            });
 
-        $(this).parent().find('.item').each(function () {
-            $(this).removeClass('checked');
-            $(this).find('.octicon').removeClass('octicon-check');
+            $noSelect.removeClass('hide');
+            $($(this).parent().data('id')).val('');
         });
+    }
 
-        $list.find('.item').each(function () {
-            $(this).addClass('hide');
-        });
-        $noSelect.removeClass('hide');
-        $($(this).parent().data('id')).val('');
-    });
+    // Labels and assignees
+    initListSubmits('select-label', 'labels');
+    initListSubmits('select-assignees', 'assignees');
 
     function selectItem(select_id, input_id) {
         var $menu = $(select_id + ' .menu');
@@ -206,13 +210,10 @@ function initCommentForm() {
             }
             switch (input_id) {
                 case '#milestone_id':
+                case '#project_id':
                     $list.find('.selected').html('<a class="item" href=' + $(this).data('href') + '>' +
                         $(this).text() + '</a>');
                     break;
-                case '#assignee_id':
-                    $list.find('.selected').html('<a class="item" href=' + $(this).data('href') + '>' +
-                        '<img class="ui avatar image" src=' + $(this).data('avatar') + '>' +
-                        $(this).text() + '</a>');
             }
             $('.ui' + select_id + '.list .no-select').addClass('hide');
             $(input_id).val($(this).data('id'));
@@ -238,9 +239,11 @@ function initCommentForm() {
         });
     }
 
-    // Milestone and assignee
+    // Milestone
     selectItem('.select-milestone', '#milestone_id');
-    selectItem('.select-assignee', '#assignee_id');
+
+    // Project
+    selectItem('.select-project', '#project_id');
 }
 
 function initInstall() {
@@ -647,6 +650,77 @@ function initProtectedBranch() {
     });
 }
 
+function initProjectBoard() {
+    var $board = $('.project.board');
+    if ($board.length === 0 || !$board.data('url')) {
+        return;
+    }
+
+    var $dragged = null;
+    $board.find('.board-card').on('dragstart', function (e) {
+        $dragged = $(this);
+        $dragged.addClass('dragging');
+        e.originalEvent.dataTransfer.effectAllowed = 'move';
+        e.originalEvent.dataTransfer.setData('text', $dragged.data('issue'));
+    }).on('dragend', function () {
+        $(this).removeClass('dragging');
+        $board.find('.board-column').removeClass('drop-target');
+    });
+
+    $board.find('.board-column').on('dragover', function (e) {
+        if (!$dragged) {
+            return;
+        }
+        e.preventDefault();
+        $board.find('.board-column').removeClass('drop-target');
+        $(this).addClass('drop-target');
+
+        // Insert the card before the first card below the cursor.
+        var $cards = $(this).find('.board-cards');
+        var $before = null;
+        $cards.children('.board-card').not($dragged).each(function () {
+            if (e.originalEvent.clientY < this.getBoundingClientRect().top + this.offsetHeight / 2) {
+                $before = $(this);
+                return false;
+            }
+        });
+        if ($before) {
+            $before.before($dragged);
+        } else {
+            $cards.append($dragged);
+        }
+    }).on('drop', function (e) {
+        e.preventDefault();
+        if (!$dragged) {
+            return;
+        }
+        var $column = $(this);
+        $column.removeClass('drop-target');
+        $.post($board.data('url'), {
+                "_csrf": csrf,
+                "issue": $dragged.data('issue'),
+                "board": $column.data('id'),
+                "position": $column.find('.board-card').index($dragged)
+            },
+            function (data) {
+                if (!data.ok) {
+                    if (data.error) {
+                        alert(data.error);
+                    }
+                    location.reload();
+                    return;
+                }
+                $board.find('.board-column').each(function () {
+                    $(this).find('.board-column-header .label').text($(this).find('.board-card').length);
+                });
+            }
+        ).fail(function () {
+            location.reload();
+        });
+        $dragged = null;
+    });
+}
+
 function initRepositoryCollaboration() {
     console.log('initRepositoryCollaboration');
 
@@ -1569,6 +1643,7 @@ $(document).ready(function () {
     initEditor();
     initOrganization();
     initProtectedBranch();
+    initProjectBoard();
     initWebhook();
     initAdmin();
     initCodeView();
@@ -24,9 +24,9 @@ func Organizations(ctx *context.Context) {
 
 	routers.RenderUserSearch(ctx, &routers.UserSearchOptions{
 		Type:     models.UserTypeOrganization,
-		Counter:  models.CountOrganizations,
 		Ranger:   models.Organizations,
 		PageSize: setting.UI.Admin.OrgPagingNum,
+		Private:  true,
 		TplName:  tplOrgs,
 	})
 }
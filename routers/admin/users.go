@@ -32,9 +32,9 @@ func Users(ctx *context.Context) {
 
 	routers.RenderUserSearch(ctx, &routers.UserSearchOptions{
 		Type:     models.UserTypeIndividual,
-		Counter:  models.CountUsers,
 		Ranger:   models.Users,
 		PageSize: setting.UI.Admin.UserPagingNum,
+		Private:  true,
 		TplName:  tplUsers,
 	})
 }
@@ -210,6 +210,9 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 	u.Email = form.Email
 	u.Website = form.Website
 	u.Location = form.Location
+	if len(form.Visibility) > 0 {
+		u.Visibility, _ = models.ParseVisibleType(form.Visibility)
+	}
 	u.MaxRepoCreation = form.MaxRepoCreation
 	u.IsActive = form.Active
 	u.IsAdmin = form.Admin
@@ -5,10 +5,9 @@
 package admin
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/api/v1/user"
 )
@@ -30,6 +29,9 @@ func CreateOrg(ctx *context.APIContext, form api.CreateOrgOption) {
 		IsActive:    true,
 		Type:        models.UserTypeOrganization,
 	}
+	if len(form.Visibility) > 0 {
+		org.Visibility, _ = models.ParseVisibleType(form.Visibility)
+	}
 	if err := models.CreateOrganization(org, u); err != nil {
 		if models.IsErrUserAlreadyExist(err) ||
 			models.IsErrNameReserved(err) ||
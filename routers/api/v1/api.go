@@ -32,13 +32,13 @@ import (
 	"github.com/go-macaron/binding"
 	"gopkg.in/macaron.v1"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/admin"
 	"code.gitea.io/gitea/routers/api/v1/misc"
+	"code.gitea.io/gitea/routers/api/v1/notify"
 	"code.gitea.io/gitea/routers/api/v1/org"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/user"
@@ -106,6 +106,11 @@ func repoAssignment() macaron.Handler {
 			return
 		}
 
+		if ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(repo.ID) {
+			ctx.Status(404)
+			return
+		}
+
 		ctx.Repo.Repository = repo
 	}
 }
@@ -147,6 +152,15 @@ func reqRepoWriter() macaron.Handler {
 	}
 }
 
+func reqRepoAdmin() macaron.Handler {
+	return func(ctx *context.Context) {
+		if !ctx.Repo.IsAdmin() {
+			ctx.Error(403)
+			return
+		}
+	}
+}
+
 func reqOrgMembership() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		var orgID int64
@@ -217,6 +231,9 @@ func orgAssignment(args ...bool) macaron.Handler {
 					ctx.Error(500, "GetOrgByName", err)
 				}
 				return
+			} else if !ctx.Org.Organization.IsVisibleTo(ctx.User) {
+				ctx.Status(404)
+				return
 			}
 		}
 
@@ -241,6 +258,13 @@ func mustEnableIssues(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableProjects(ctx *context.APIContext) {
+	if !ctx.Repo.Repository.EnableUnit(models.UnitTypeProjects) {
+		ctx.Status(404)
+		return
+	}
+}
+
 func mustAllowPulls(ctx *context.Context) {
 	if !ctx.Repo.Repository.AllowsPulls() {
 		ctx.Status(404)
@@ -251,13 +275,17 @@ func mustAllowPulls(ctx *context.Context) {
 // RegisterRoutes registers all v1 APIs routes to web application.
 // FIXME: custom form error response
 func RegisterRoutes(m *macaron.Macaron) {
+	registerRoutes(newScopedRouter(m))
+}
+
+func registerRoutes(m *scopedRouter) {
 	bind := binding.Bind
 
 	m.Group("/v1", func() {
 		// Miscellaneous
-		m.Get("/version", misc.Version)
-		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
-		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Get("/version", noTokenScope(), misc.Version)
+		m.Post("/markdown", noTokenScope(), bind(api.MarkdownOption{}), misc.Markdown)
+		m.Post("/markdown/raw", noTokenScope(), misc.MarkdownRaw)
 
 		// Users
 		m.Group("/users", func() {
@@ -272,7 +300,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
 				}, reqBasicAuth())
 			})
-		})
+		}, reqTokenScope(models.AccessTokenScopeAreaUser))
 
 		m.Group("/users", func() {
 			m.Group("/:username", func() {
@@ -290,7 +318,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("/subscriptions", user.GetWatchedRepos)
 				m.Get("/times", repo.ListTrackedTimesByUser)
 			})
-		}, reqToken())
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeAreaUser))
 
 		m.Group("/user", func() {
 			m.Get("", user.GetAuthenticatedUser)
@@ -319,7 +347,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			})
 
 			m.Combo("/repos").Get(user.ListMyRepos).
-				Post(bind(api.CreateRepoOption{}), repo.Create)
+				Post(reqTokenScope(models.AccessTokenScopeAreaRepo), bind(api.CreateRepoOption{}), repo.Create)
 
 			m.Group("/starred", func() {
 				m.Get("", user.GetMyStarredRepos)
@@ -332,49 +360,83 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/times", repo.ListMyTrackedTimes)
 
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
-		}, reqToken())
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeAreaUser))
+
+		// Notifications
+		m.Group("/notifications", func() {
+			m.Combo("").Get(notify.ListNotifications).
+				Put(notify.ReadNotifications)
+			m.Get("/new", notify.NewAvailable)
+			m.Combo("/threads/:id").Get(notify.GetThread).
+				Put(notify.ReadThread)
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeAreaUser))
 
 		// Repositories
-		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+		m.Post("/org/:org/repos", reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo),
+			bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
-		})
+		}, reqTokenScope(models.AccessTokenScopeAreaRepo))
 
-		m.Combo("/repositories/:id", reqToken()).Get(repo.GetByID)
+		m.Combo("/repositories/:id", reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo)).Get(repo.GetByID)
 
 		m.Group("/repos", func() {
-			m.Post("/migrate", reqToken(), bind(auth.MigrateRepoForm{}), repo.Migrate)
+			m.Post("/migrate", reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo), bind(auth.MigrateRepoForm{}), repo.Migrate)
 
 			m.Group("/:username/:reponame", func() {
-				m.Combo("").Get(repo.Get).Delete(reqToken(), repo.Delete)
+				m.Combo("", reqTokenScope(models.AccessTokenScopeAreaRepo)).Get(repo.Get).
+					Delete(reqToken(), reqTokenDeleteRepo(), repo.Delete)
 				m.Group("/hooks", func() {
 					m.Combo("").Get(repo.ListHooks).
 						Post(bind(api.CreateHookOption{}), repo.CreateHook)
 					m.Combo("/:id").Get(repo.GetHook).
 						Patch(bind(api.EditHookOption{}), repo.EditHook).
 						Delete(repo.DeleteHook)
-				}, reqToken(), reqRepoWriter())
+					m.Get("/:id/deliveries", repo.ListHookDeliveries)
+					m.Post("/:id/deliveries/:delivery/redeliver", repo.RedeliverHook)
+					m.Post("/:id/tests", repo.TestHook)
+				}, reqToken(), reqRepoWriter(), reqTokenScope(models.AccessTokenScopeAreaRepo))
 				m.Group("/collaborators", func() {
 					m.Get("", repo.ListCollaborators)
 					m.Combo("/:collaborator").Get(repo.IsCollaborator).
 						Put(bind(api.AddCollaboratorOption{}), repo.AddCollaborator).
 						Delete(repo.DeleteCollaborator)
-				}, reqToken())
-				m.Get("/raw/*", context.RepoRef(), repo.GetRawFile)
-				m.Get("/archive/*", repo.GetArchive)
-				m.Combo("/forks").Get(repo.ListForks).
+				}, reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Get("/raw/*", reqTokenScope(models.AccessTokenScopeAreaRepo), context.RepoRef(), repo.GetRawFile)
+				m.Get("/archive/*", reqTokenScope(models.AccessTokenScopeAreaRepo), repo.GetArchive)
+				m.Get("/contents", reqTokenScope(models.AccessTokenScopeAreaRepo), repo.GetContents)
+				m.Combo("/contents/*", reqTokenScope(models.AccessTokenScopeAreaRepo)).Get(repo.GetContents).
+					Post(reqToken(), reqRepoWriter(), bind(api.CreateFileOptions{}), repo.CreateFile).
+					Put(reqToken(), reqRepoWriter(), bind(api.UpdateFileOptions{}), repo.UpdateFile).
+					Delete(reqToken(), reqRepoWriter(), bind(api.DeleteFileOptions{}), repo.DeleteFile)
+				m.Combo("/forks", reqTokenScope(models.AccessTokenScopeAreaRepo)).Get(repo.ListForks).
 					Post(reqToken(), bind(api.CreateForkOption{}), repo.CreateFork)
 				m.Group("/branches", func() {
-					m.Get("", repo.ListBranches)
+					m.Combo("").Get(repo.ListBranches).
+						Post(reqToken(), reqRepoWriter(), bind(api.CreateBranchOption{}), repo.CreateBranch)
 					m.Get("/*", context.RepoRef(), repo.GetBranch)
-				})
+					m.Delete("/*", reqToken(), reqRepoWriter(), repo.DeleteBranch)
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Group("/branch_protections", func() {
+					m.Combo("").Get(repo.ListBranchProtections).
+						Post(bind(api.CreateBranchProtectionOption{}), repo.CreateBranchProtection)
+					m.Combo("/*").Get(repo.GetBranchProtection).
+						Patch(bind(api.EditBranchProtectionOption{}), repo.EditBranchProtection).
+						Delete(repo.DeleteBranchProtection)
+				}, reqToken(), reqRepoAdmin(), reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Group("/tags", func() {
+					m.Combo("").Get(repo.ListTags).
+						Post(reqToken(), reqRepoWriter(), bind(api.CreateTagOption{}), repo.CreateTag)
+					m.Combo("/*").Get(repo.GetTag).
+						Delete(reqToken(), reqRepoWriter(), repo.DeleteTag)
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
 					m.Combo("/:id").Get(repo.GetDeployKey).
 						Delete(repo.DeleteDeploykey)
-				}, reqToken())
+				}, reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo))
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), bind(api.CreateIssueOption{}), repo.CreateIssue)
@@ -382,6 +444,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Combo("/:id", reqToken()).
 							Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueComment)
+						m.Combo("/:id/reactions").
+							Get(repo.GetIssueCommentReactions).
+							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueCommentReaction).
+							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueCommentReaction)
 					})
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetIssue).
@@ -407,38 +473,75 @@ func RegisterRoutes(m *macaron.Macaron) {
 								Post(reqToken(), bind(api.AddTimeOption{}), repo.AddTime)
 						})
 
+						m.Combo("/reactions").
+							Get(repo.GetIssueReactions).
+							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
+							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
+
 					})
-				}, mustEnableIssues)
+				}, mustEnableIssues, reqTokenScope(models.AccessTokenScopeAreaIssue))
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
 						Post(reqToken(), bind(api.CreateLabelOption{}), repo.CreateLabel)
 					m.Combo("/:id").Get(repo.GetLabel).
 						Patch(reqToken(), bind(api.EditLabelOption{}), repo.EditLabel).
 						Delete(reqToken(), repo.DeleteLabel)
-				})
+				}, reqTokenScope(models.AccessTokenScopeAreaIssue))
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
 						Post(reqToken(), reqRepoWriter(), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
 					m.Combo("/:id").Get(repo.GetMilestone).
 						Patch(reqToken(), reqRepoWriter(), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(), repo.DeleteMilestone)
-				})
-				m.Get("/stargazers", repo.ListStargazers)
-				m.Get("/subscribers", repo.ListSubscribers)
+				}, reqTokenScope(models.AccessTokenScopeAreaIssue))
+				m.Get("/times", reqTokenScope(models.AccessTokenScopeAreaIssue), repo.ListTrackedTimesByRepository)
+				m.Group("/projects", func() {
+					m.Combo("").Get(repo.ListProjects).
+						Post(reqToken(), reqRepoWriter(), bind(api.CreateProjectOption{}), repo.CreateProject)
+					m.Group("/:id", func() {
+						m.Combo("").Get(repo.GetProject).
+							Patch(reqToken(), reqRepoWriter(), bind(api.EditProjectOption{}), repo.EditProject).
+							Delete(reqToken(), reqRepoWriter(), repo.DeleteProject)
+						m.Group("/boards", func() {
+							m.Combo("").Get(repo.ListProjectBoards).
+								Post(reqToken(), reqRepoWriter(), bind(api.CreateProjectBoardOption{}), repo.CreateProjectBoard)
+							m.Combo("/:boardID").
+								Patch(reqToken(), reqRepoWriter(), bind(api.EditProjectBoardOption{}), repo.EditProjectBoard).
+								Delete(reqToken(), reqRepoWriter(), repo.DeleteProjectBoard)
+							m.Get("/:boardID/issues", repo.ListProjectBoardIssues)
+						})
+						m.Group("/issues", func() {
+							m.Post("", bind(api.MoveProjectIssueOption{}), repo.MoveProjectIssue)
+							m.Delete("/:index", repo.RemoveProjectIssue)
+						}, reqToken(), reqRepoWriter())
+					})
+				}, mustEnableProjects, reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Get("/stargazers", reqTokenScope(models.AccessTokenScopeAreaRepo), repo.ListStargazers)
+				m.Get("/subscribers", reqTokenScope(models.AccessTokenScopeAreaRepo), repo.ListSubscribers)
 				m.Group("/subscription", func() {
 					m.Get("", user.IsWatching)
 					m.Put("", reqToken(), user.Watch)
 					m.Delete("", reqToken(), user.Unwatch)
-				})
+				}, reqTokenScope(models.AccessTokenScopeAreaUser))
+				m.Combo("/notifications", reqToken(), reqTokenScope(models.AccessTokenScopeAreaUser)).
+					Get(notify.ListRepoNotifications).
+					Put(notify.ReadRepoNotifications)
 				m.Group("/releases", func() {
 					m.Combo("").Get(repo.ListReleases).
 						Post(reqToken(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
 					m.Combo("/:id").Get(repo.GetRelease).
 						Patch(reqToken(), bind(api.EditReleaseOption{}), repo.EditRelease).
 						Delete(reqToken(), repo.DeleteRelease)
-				})
-				m.Post("/mirror-sync", reqToken(), repo.MirrorSync)
-				m.Get("/editorconfig/:filename", context.RepoRef(), repo.GetEditorconfig)
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Post("/mirror-sync", reqToken(), reqTokenScope(models.AccessTokenScopeAreaRepo), repo.MirrorSync)
+				m.Group("/push_mirrors", func() {
+					m.Combo("").Get(repo.ListPushMirrors).
+						Post(bind(api.CreatePushMirrorOption{}), repo.CreatePushMirror)
+					m.Combo("/:id").Get(repo.GetPushMirror).
+						Delete(repo.DeletePushMirror)
+					m.Post("/:id/sync", repo.SyncPushMirror)
+				}, reqToken(), reqRepoAdmin(), reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Get("/editorconfig/:filename", reqTokenScope(models.AccessTokenScopeAreaRepo), context.RepoRef(), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(bind(api.ListPullRequestsOptions{}), repo.ListPullRequests).
 						Post(reqToken(), reqRepoWriter(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
@@ -446,24 +549,43 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(), reqRepoWriter(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), reqRepoWriter(), repo.MergePullRequest)
+							Post(reqToken(), reqRepoWriter(), bind(api.MergePullRequestOption{}), repo.MergePullRequest)
+						m.Group("/reviews", func() {
+							m.Combo("").Get(repo.ListPullReviews).
+								Post(reqToken(), bind(api.CreatePullReviewOptions{}), repo.CreatePullReview)
+							m.Group("/:id", func() {
+								m.Combo("").Get(repo.GetPullReview).
+									Post(reqToken(), bind(api.SubmitPullReviewOptions{}), repo.SubmitPullReview).
+									Delete(reqToken(), repo.DeletePullReview)
+								m.Get("/comments", repo.GetPullReviewComments)
+							})
+						})
 					})
 
-				}, mustAllowPulls, context.ReferencesGitRepo())
+				}, mustAllowPulls, reqTokenScope(models.AccessTokenScopeAreaRepo), context.ReferencesGitRepo())
 				m.Group("/statuses", func() {
 					m.Combo("/:sha").Get(repo.GetCommitStatuses).
 						Post(reqToken(), reqRepoWriter(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
-				})
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Get("/commits", reqTokenScope(models.AccessTokenScopeAreaRepo), repo.ListCommits)
 				m.Group("/commits/:ref", func() {
 					m.Get("/status", repo.GetCombinedCommitStatus)
 					m.Get("/statuses", repo.GetCommitStatuses)
-				})
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
+				m.Group("/git", func() {
+					m.Get("/commits/:sha", repo.GetSingleCommit)
+					m.Get("/trees/:sha", repo.GetTree)
+					m.Get("/blobs/:sha", repo.GetBlob)
+					m.Get("/refs", repo.ListRefs)
+					m.Get("/refs/*", repo.ListRefs)
+					m.Get("/tags/:sha", repo.GetAnnotatedTag)
+				}, reqTokenScope(models.AccessTokenScopeAreaRepo))
 			}, repoAssignment())
 		})
 
 		// Organizations
-		m.Get("/user/orgs", reqToken(), org.ListMyOrgs)
-		m.Get("/users/:username/orgs", org.ListUserOrgs)
+		m.Get("/user/orgs", reqToken(), reqTokenScope(models.AccessTokenScopeAreaOrg), org.ListMyOrgs)
+		m.Get("/users/:username/orgs", reqTokenScope(models.AccessTokenScopeAreaOrg), org.ListUserOrgs)
 		m.Group("/orgs/:orgname", func() {
 			m.Get("/repos", user.ListOrgRepos)
 			m.Combo("").Get(org.Get).
@@ -488,7 +610,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Patch(reqOrgOwnership(), bind(api.EditHookOption{}), org.EditHook).
 					Delete(reqOrgOwnership(), org.DeleteHook)
 			}, reqToken(), reqOrgMembership())
-		}, orgAssignment(true))
+		}, orgAssignment(true), reqTokenScope(models.AccessTokenScopeAreaOrg))
 		m.Group("/teams/:teamid", func() {
 			m.Combo("").Get(org.GetTeam).
 				Patch(reqOrgOwnership(), bind(api.EditTeamOption{}), org.EditTeam).
@@ -505,9 +627,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Put(org.AddTeamRepository).
 					Delete(org.RemoveTeamRepository)
 			})
-		}, orgAssignment(false, true), reqToken(), reqOrgMembership())
+		}, orgAssignment(false, true), reqToken(), reqOrgMembership(), reqTokenScope(models.AccessTokenScopeAreaOrg))
 
-		m.Any("/*", func(ctx *context.Context) {
+		m.Any("/*", noTokenScope(), func(ctx *context.Context) {
 			ctx.Error(404)
 		})
 
@@ -522,6 +644,6 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				})
 			})
-		}, reqAdmin())
+		}, reqAdmin(), reqTokenScope(models.AccessTokenScopeAreaAdmin))
 	}, context.APIContexter())
 }
@@ -6,13 +6,13 @@ package convert
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Unknwon/com"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // ToEmail convert models.EmailAddress to api.Email
@@ -130,6 +130,23 @@ func ToGPGKeyEmail(email *models.EmailAddress) *api.GPGKeyEmail {
 	}
 }
 
+// ToAccessToken convert models.AccessToken to api.AccessToken
+func ToAccessToken(t *models.AccessToken, repos []*models.Repository) *api.AccessToken {
+	apiToken := &api.AccessToken{
+		Name:         t.Name,
+		Sha1:         t.Sha1,
+		Scopes:       t.Scopes,
+		Repositories: make([]string, len(repos)),
+	}
+	for i := range repos {
+		apiToken.Repositories[i] = repos[i].FullName()
+	}
+	if t.ExpiresUnix > 0 {
+		apiToken.Expires = &t.Expires
+	}
+	return apiToken
+}
+
 // ToHook convert models.Webhook to api.Hook
 func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	config := map[string]string{
@@ -142,6 +159,10 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 		config["username"] = s.Username
 		config["icon_url"] = s.IconURL
 		config["color"] = s.Color
+	} else if w.HookTaskType == models.DISCORD {
+		s := w.GetDiscordHook()
+		config["username"] = s.Username
+		config["icon_url"] = s.IconURL
 	}
 
 	return &api.Hook{
@@ -156,6 +177,28 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	}
 }
 
+// ToHookDelivery convert models.HookTask to api.HookDelivery
+func ToHookDelivery(t *models.HookTask) *api.HookDelivery {
+	d := &api.HookDelivery{
+		ID:             t.ID,
+		UUID:           t.UUID,
+		URL:            t.URL,
+		Event:          string(t.EventType),
+		IsDelivered:    t.IsDelivered,
+		IsSucceed:      t.IsSucceed,
+		Attempts:       t.Attempts,
+		RequestPayload: t.PayloadContent,
+	}
+	if t.Delivered > 0 {
+		d.Delivered = time.Unix(0, t.Delivered)
+	}
+	if t.ResponseInfo != nil {
+		d.StatusCode = t.ResponseInfo.Status
+		d.ResponseBody = t.ResponseInfo.Body
+	}
+	return d
+}
+
 // ToDeployKey convert models.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *models.DeployKey) *api.DeployKey {
 	return &api.DeployKey{
@@ -178,6 +221,7 @@ func ToOrganization(org *models.User) *api.Organization {
 		Description: org.Description,
 		Website:     org.Website,
 		Location:    org.Location,
+		Visibility:  org.Visibility.String(),
 	}
 }
 
@@ -7,7 +7,7 @@ package misc
 import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // Version shows the version of the Gitea server
@@ -24,5 +24,5 @@ func Version(ctx *context.APIContext) {
 	//     Responses:
 	//       200: ServerVersion
 
-	ctx.JSON(200, &gitea.ServerVersion{Version: setting.AppVer})
+	ctx.JSON(200, &api.ServerVersion{Version: setting.AppVer})
 }
@@ -0,0 +1,157 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+// parseTimeQuery parses the RFC3339 time of given query parameter, it writes
+// the error response and returns false if the time is invalid.
+func parseTimeQuery(ctx *context.APIContext, name string) (int64, bool) {
+	value := ctx.Query(name)
+	if len(value) == 0 {
+		return 0, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		ctx.Error(422, "", fmt.Sprintf("invalid %s: %v", name, err))
+		return 0, false
+	}
+	return t.Unix(), true
+}
+
+// findOptions returns the options to find the notifications of the signed in
+// user, restricted to the repositories the access token can access.
+func findOptions(ctx *context.APIContext) models.FindNotificationOptions {
+	opts := models.FindNotificationOptions{
+		UserID: ctx.User.ID,
+	}
+	if ctx.AccessToken != nil {
+		opts.RepoIDs = ctx.AccessToken.RepoIDs
+	}
+	if ctx.Repo.Repository != nil {
+		opts.RepoID = ctx.Repo.Repository.ID
+	}
+	return opts
+}
+
+// listNotifications writes the notifications of the signed in user, the unread
+// and pinned ones unless all of them are requested.
+func listNotifications(ctx *context.APIContext) {
+	opts := findOptions(ctx)
+	opts.Statuses = []models.NotificationStatus{models.NotificationStatusUnread, models.NotificationStatusPinned}
+	if ctx.QueryBool("all") {
+		opts.Statuses = append(opts.Statuses, models.NotificationStatusRead)
+	}
+
+	var ok bool
+	if opts.UpdatedAfterUnix, ok = parseTimeQuery(ctx, "since"); !ok {
+		return
+	}
+	if opts.UpdatedBeforeUnix, ok = parseTimeQuery(ctx, "before"); !ok {
+		return
+	}
+
+	opts.Page = ctx.QueryInt("page")
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	opts.PageSize = convert.ToCorrectPageSize(ctx.QueryInt("limit"))
+
+	total, err := models.CountNotifications(opts)
+	if err != nil {
+		ctx.Error(500, "CountNotifications", err)
+		return
+	}
+	notifications, err := models.GetNotifications(opts)
+	if err != nil {
+		ctx.Error(500, "GetNotifications", err)
+		return
+	}
+	if err = notifications.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+
+	threads := make([]*api.NotificationThread, len(notifications))
+	for i, notification := range notifications {
+		threads[i] = notification.APIFormat()
+	}
+
+	ctx.SetLinkHeader(int(total), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprint(total))
+	ctx.JSON(200, &threads)
+}
+
+// readNotifications marks the unread notifications of the signed in user
+// updated before the last_read_at time as read, all of them if it is not given.
+func readNotifications(ctx *context.APIContext) {
+	opts := findOptions(ctx)
+	var ok bool
+	if opts.UpdatedBeforeUnix, ok = parseTimeQuery(ctx, "last_read_at"); !ok {
+		return
+	}
+
+	if err := models.UpdateNotificationStatuses(opts, models.NotificationStatusUnread, models.NotificationStatusRead); err != nil {
+		ctx.Error(500, "UpdateNotificationStatuses", err)
+		return
+	}
+	ctx.Status(205)
+}
+
+// ListNotifications lists the notifications of the signed in user
+func ListNotifications(ctx *context.APIContext) {
+	// swagger:route GET /notifications notifyGetList
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: NotificationThreads
+	//       401: unauthorized
+	//       422: validationError
+	//       500: error
+	listNotifications(ctx)
+}
+
+// ReadNotifications marks the notifications of the signed in user as read
+func ReadNotifications(ctx *context.APIContext) {
+	// swagger:route PUT /notifications notifyReadList
+	//
+	//     Responses:
+	//       205: empty
+	//       401: unauthorized
+	//       422: validationError
+	//       500: error
+	readNotifications(ctx)
+}
+
+// NewAvailable returns the number of unread notifications of the signed in user
+func NewAvailable(ctx *context.APIContext) {
+	// swagger:route GET /notifications/new notifyNewAvailable
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: NotificationCount
+	//       401: unauthorized
+	//       500: error
+	opts := findOptions(ctx)
+	opts.Statuses = []models.NotificationStatus{models.NotificationStatusUnread}
+	count, err := models.CountNotifications(opts)
+	if err != nil {
+		ctx.Error(500, "CountNotifications", err)
+		return
+	}
+	ctx.JSON(200, &api.NotificationCount{New: count})
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListRepoNotifications lists the notifications of the signed in user in a repository
+func ListRepoNotifications(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/notifications notifyGetRepoList
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: NotificationThreads
+	//       401: unauthorized
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	listNotifications(ctx)
+}
+
+// ReadRepoNotifications marks the notifications of the signed in user in a repository as read
+func ReadRepoNotifications(ctx *context.APIContext) {
+	// swagger:route PUT /repos/{username}/{reponame}/notifications notifyReadRepoList
+	//
+	//     Responses:
+	//       205: empty
+	//       401: unauthorized
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	readNotifications(ctx)
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// getThread returns the notification of the signed in user given by the :id
+// parameter, it writes the error response and returns nil if there is none.
+func getThread(ctx *context.APIContext) *models.Notification {
+	notification, err := models.GetNotificationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrNotificationNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetNotificationByID", err)
+		}
+		return nil
+	}
+	if notification.UserID != ctx.User.ID ||
+		(ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(notification.RepoID)) {
+		ctx.Status(404)
+		return nil
+	}
+	return notification
+}
+
+// GetThread gets a notification thread of the signed in user
+func GetThread(ctx *context.APIContext) {
+	// swagger:route GET /notifications/threads/{id} notifyGetThread
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: NotificationThread
+	//       401: unauthorized
+	//       404: notFound
+	//       500: error
+	notification := getThread(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.NotificationList([]*models.Notification{notification}).LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(200, notification.APIFormat())
+}
+
+// ReadThread changes the status of a notification thread of the signed in
+// user, it is marked as read unless the to-status parameter says otherwise.
+func ReadThread(ctx *context.APIContext) {
+	// swagger:route PUT /notifications/threads/{id} notifyReadThread
+	//
+	//     Responses:
+	//       205: empty
+	//       401: unauthorized
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	notification := getThread(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var status models.NotificationStatus
+	switch toStatus := ctx.QueryTrim("to-status"); toStatus {
+	case "", "read":
+		status = models.NotificationStatusRead
+	case "unread":
+		status = models.NotificationStatusUnread
+	case "pinned":
+		status = models.NotificationStatusPinned
+	default:
+		ctx.Error(422, "", fmt.Sprintf("invalid notification status: %s", toStatus))
+		return
+	}
+
+	if err := models.SetNotificationStatus(notification.ID, ctx.User, status); err != nil {
+		ctx.Error(500, "SetNotificationStatus", err)
+		return
+	}
+	ctx.Status(205)
+}
@@ -5,10 +5,9 @@
 package org
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/api/v1/user"
 )
@@ -19,9 +18,11 @@ func listUserOrgs(ctx *context.APIContext, u *models.User, all bool) {
 		return
 	}
 
-	apiOrgs := make([]*api.Organization, len(u.Orgs))
-	for i := range u.Orgs {
-		apiOrgs[i] = convert.ToOrganization(u.Orgs[i])
+	apiOrgs := make([]*api.Organization, 0, len(u.Orgs))
+	for _, org := range u.Orgs {
+		if all || org.IsVisibleTo(ctx.User) {
+			apiOrgs = append(apiOrgs, convert.ToOrganization(org))
+		}
 	}
 	ctx.JSON(200, &apiOrgs)
 }
@@ -56,6 +57,9 @@ func Edit(ctx *context.APIContext, form api.EditOrgOption) {
 	org.Description = form.Description
 	org.Website = form.Website
 	org.Location = form.Location
+	if len(form.Visibility) > 0 {
+		org.Visibility, _ = models.ParseVisibleType(form.Visibility)
+	}
 	if err := models.UpdateUser(org); err != nil {
 		ctx.Error(500, "UpdateUser", err)
 		return
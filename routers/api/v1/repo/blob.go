@@ -0,0 +1,55 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetBlob gets a blob of a repository by its SHA
+func GetBlob(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/git/blobs/{sha} repoGetBlob
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: GitBlobResponse
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	blob, err := gitutil.GetBlob(gitRepo, ctx.Params(":sha"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetBlob", err)
+		}
+		return
+	}
+	data, err := blob.Data()
+	if err != nil {
+		ctx.Error(500, "Data", err)
+		return
+	}
+
+	ctx.JSON(200, &api.GitBlobResponse{
+		Content:  base64.StdEncoding.EncodeToString(data),
+		Encoding: "base64",
+		URL:      ctx.Repo.Repository.APIURL() + "/git/blobs/" + blob.ID.String(),
+		SHA:      blob.ID.String(),
+		Size:     int64(len(data)),
+	})
+}
@@ -5,10 +5,9 @@
 package repo
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 )
 
@@ -62,3 +61,76 @@ func ListBranches(ctx *context.APIContext) {
 
 	ctx.JSON(200, &apiBranches)
 }
+
+// CreateBranch creates a branch in a repository
+func CreateBranch(ctx *context.APIContext, form api.CreateBranchOption) {
+	// swagger:route POST /repos/{username}/{reponame}/branches repoCreateBranch
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: Branch
+	//       404: notFound
+	//       409: error
+	//       422: validationError
+	//       500: error
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+	if len(form.OldBranchName) == 0 {
+		form.OldBranchName = ctx.Repo.Repository.DefaultBranch
+	}
+
+	if err := ctx.Repo.Repository.CreateNewBranch(ctx.User, form.OldBranchName, form.BranchName); err != nil {
+		switch {
+		case models.IsErrBranchNotExist(err):
+			ctx.Error(404, "", err)
+		case models.IsErrBranchAlreadyExists(err):
+			ctx.Error(409, "", err)
+		default:
+			ctx.Error(500, "CreateNewBranch", err)
+		}
+		return
+	}
+
+	branch, err := ctx.Repo.Repository.GetBranch(form.BranchName)
+	if err != nil {
+		ctx.Error(500, "GetBranch", err)
+		return
+	}
+	c, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(500, "GetCommit", err)
+		return
+	}
+	ctx.JSON(201, convert.ToBranch(branch, c))
+}
+
+// DeleteBranch deletes a branch of a repository, the default branch and
+// protected branches can not be deleted.
+func DeleteBranch(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/branches/{branch} repoDeleteBranch
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	if err := ctx.Repo.Repository.DeleteBranch(ctx.User, ctx.Params("*")); err != nil {
+		switch {
+		case models.IsErrBranchNotExist(err):
+			ctx.Status(404)
+		case models.IsErrBranchIsDefault(err), models.IsErrBranchIsProtected(err):
+			ctx.Error(403, "", err)
+		default:
+			ctx.Error(500, "DeleteBranch", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
@@ -0,0 +1,310 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// userNames returns the names of the users with given IDs
+func userNames(userIDs []int64) ([]string, error) {
+	users, err := models.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(users))
+	for i := range users {
+		names[i] = users[i].Name
+	}
+	return names, nil
+}
+
+// teamNames returns the names of the teams with given IDs
+func teamNames(teamIDs []int64) ([]string, error) {
+	names := make([]string, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		team, err := models.GetTeamByID(teamID)
+		if err != nil {
+			if err == models.ErrTeamNotExist {
+				continue
+			}
+			return nil, err
+		}
+		names = append(names, team.Name)
+	}
+	return names, nil
+}
+
+// teamIDs returns the IDs of the teams of the organization with given names
+func teamIDs(orgID int64, names []string) []int64 {
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		team, err := models.GetTeam(orgID, name)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, team.ID)
+	}
+	return ids
+}
+
+// toBranchProtection converts a protected branch to its API format
+func toBranchProtection(bp *models.ProtectedBranch) (_ *api.BranchProtection, err error) {
+	apiBP := &api.BranchProtection{
+		BranchName:           bp.BranchName,
+		EnablePush:           bp.CanPush,
+		EnablePushWhitelist:  bp.EnableWhitelist,
+		EnableMergeWhitelist: bp.EnableMergeWhitelist,
+		RequiredApprovals:    bp.RequiredApprovals,
+		EnableStatusCheck:    bp.EnableStatusCheck,
+		StatusCheckContexts:  bp.StatusCheckContexts,
+		RequireSignedCommits: bp.RequireSignedCommits,
+		Created:              time.Unix(bp.CreatedUnix, 0).Local(),
+		Updated:              time.Unix(bp.UpdatedUnix, 0).Local(),
+	}
+	if apiBP.PushWhitelistUsernames, err = userNames(bp.WhitelistUserIDs); err != nil {
+		return nil, err
+	}
+	if apiBP.MergeWhitelistUsernames, err = userNames(bp.MergeWhitelistUserIDs); err != nil {
+		return nil, err
+	}
+	if apiBP.PushWhitelistTeams, err = teamNames(bp.WhitelistTeamIDs); err != nil {
+		return nil, err
+	}
+	if apiBP.MergeWhitelistTeams, err = teamNames(bp.MergeWhitelistTeamIDs); err != nil {
+		return nil, err
+	}
+	return apiBP, nil
+}
+
+// writeBranchProtection writes the API format of given protected branch
+func writeBranchProtection(ctx *context.APIContext, status int, bp *models.ProtectedBranch) {
+	apiBP, err := toBranchProtection(bp)
+	if err != nil {
+		ctx.Error(500, "toBranchProtection", err)
+		return
+	}
+	ctx.JSON(status, apiBP)
+}
+
+// getBranchProtection returns the protection of the branch given by the URL,
+// it writes the error response and returns nil if the branch is not protected.
+func getBranchProtection(ctx *context.APIContext) *models.ProtectedBranch {
+	bp, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, ctx.Params("*"))
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return nil
+	} else if bp == nil {
+		ctx.Status(404)
+		return nil
+	}
+	return bp
+}
+
+// updateBranchProtection saves given protected branch with the whitelists given
+// by names, the whitelists are left unchanged if nil.
+func updateBranchProtection(ctx *context.APIContext, bp *models.ProtectedBranch, users, teams, mergeUsers, mergeTeams []string) {
+	if bp.RequiredApprovals < 0 {
+		ctx.Error(422, "", "required_approvals must not be negative")
+		return
+	}
+
+	opts := models.UpdateProtectBranchOptions{
+		WhitelistUserIDs:      bp.WhitelistUserIDs,
+		WhitelistTeamIDs:      bp.WhitelistTeamIDs,
+		MergeWhitelistUserIDs: bp.MergeWhitelistUserIDs,
+		MergeWhitelistTeamIDs: bp.MergeWhitelistTeamIDs,
+	}
+	if users != nil {
+		opts.WhitelistUserIDs = models.GetUserIDsByNames(users)
+	}
+	if mergeUsers != nil {
+		opts.MergeWhitelistUserIDs = models.GetUserIDsByNames(mergeUsers)
+	}
+	if owner := ctx.Repo.Owner; owner.IsOrganization() {
+		if teams != nil {
+			opts.WhitelistTeamIDs = teamIDs(owner.ID, teams)
+		}
+		if mergeTeams != nil {
+			opts.MergeWhitelistTeamIDs = teamIDs(owner.ID, mergeTeams)
+		}
+	}
+
+	if err := models.UpdateProtectBranch(ctx.Repo.Repository, bp, opts); err != nil {
+		ctx.Error(500, "UpdateProtectBranch", err)
+	}
+}
+
+// ListBranchProtections lists the branch protections of a repository
+func ListBranchProtections(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/branch_protections repoListBranchProtections
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: BranchProtectionList
+	//       403: forbidden
+	//       500: error
+	bps, err := models.GetProtectedBranchByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchByRepoID", err)
+		return
+	}
+	apiBPs := make([]*api.BranchProtection, len(bps))
+	for i := range bps {
+		if apiBPs[i], err = toBranchProtection(bps[i]); err != nil {
+			ctx.Error(500, "toBranchProtection", err)
+			return
+		}
+	}
+	ctx.JSON(200, &apiBPs)
+}
+
+// GetBranchProtection gets the protection of a branch of a repository
+func GetBranchProtection(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/branch_protections/{branch} repoGetBranchProtection
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: BranchProtection
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	bp := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+	writeBranchProtection(ctx, 200, bp)
+}
+
+// CreateBranchProtection protects a branch of a repository
+func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtectionOption) {
+	// swagger:route POST /repos/{username}/{reponame}/branch_protections repoCreateBranchProtection
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: BranchProtection
+	//       403: forbidden
+	//       404: notFound
+	//       409: error
+	//       422: validationError
+	//       500: error
+	if _, err := ctx.Repo.Repository.GetBranch(form.BranchName); err != nil {
+		if models.IsErrBranchNotExist(err) {
+			ctx.Error(404, "", err)
+		} else {
+			ctx.Error(500, "GetBranch", err)
+		}
+		return
+	}
+	if bp, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, form.BranchName); err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return
+	} else if bp != nil {
+		ctx.Error(409, "", "branch is already protected")
+		return
+	}
+
+	bp := &models.ProtectedBranch{
+		BranchName:           form.BranchName,
+		CanPush:              form.EnablePush,
+		EnableWhitelist:      form.EnablePush && form.EnablePushWhitelist,
+		EnableMergeWhitelist: form.EnableMergeWhitelist,
+		RequiredApprovals:    form.RequiredApprovals,
+		EnableStatusCheck:    form.EnableStatusCheck,
+		StatusCheckContexts:  form.StatusCheckContexts,
+		RequireSignedCommits: form.RequireSignedCommits,
+	}
+	updateBranchProtection(ctx, bp, form.PushWhitelistUsernames, form.PushWhitelistTeams,
+		form.MergeWhitelistUsernames, form.MergeWhitelistTeams)
+	if ctx.Written() {
+		return
+	}
+	writeBranchProtection(ctx, 201, bp)
+}
+
+// EditBranchProtection edits the protection of a branch of a repository
+func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtectionOption) {
+	// swagger:route PATCH /repos/{username}/{reponame}/branch_protections/{branch} repoEditBranchProtection
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: BranchProtection
+	//       403: forbidden
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	bp := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.EnablePush != nil {
+		bp.CanPush = *form.EnablePush
+	}
+	if form.EnablePushWhitelist != nil {
+		bp.EnableWhitelist = *form.EnablePushWhitelist
+	}
+	bp.EnableWhitelist = bp.CanPush && bp.EnableWhitelist
+	if form.EnableMergeWhitelist != nil {
+		bp.EnableMergeWhitelist = *form.EnableMergeWhitelist
+	}
+	if form.RequiredApprovals != nil {
+		bp.RequiredApprovals = *form.RequiredApprovals
+	}
+	if form.EnableStatusCheck != nil {
+		bp.EnableStatusCheck = *form.EnableStatusCheck
+	}
+	if form.StatusCheckContexts != nil {
+		bp.StatusCheckContexts = form.StatusCheckContexts
+	}
+	if form.RequireSignedCommits != nil {
+		bp.RequireSignedCommits = *form.RequireSignedCommits
+	}
+
+	updateBranchProtection(ctx, bp, form.PushWhitelistUsernames, form.PushWhitelistTeams,
+		form.MergeWhitelistUsernames, form.MergeWhitelistTeams)
+	if ctx.Written() {
+		return
+	}
+	writeBranchProtection(ctx, 200, bp)
+}
+
+// DeleteBranchProtection removes the protection of a branch of a repository
+func DeleteBranchProtection(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/branch_protections/{branch} repoDeleteBranchProtection
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	bp := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := ctx.Repo.Repository.DeleteProtectedBranch(bp.ID); err != nil {
+		ctx.Error(500, "DeleteProtectedBranch", err)
+		return
+	}
+	ctx.Status(204)
+}
@@ -0,0 +1,193 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+// openGitRepo opens the Git repository of the current repository, it writes
+// the error response and returns nil if it is empty or can not be opened.
+func openGitRepo(ctx *context.APIContext) *git.Repository {
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return nil
+	}
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(500, "OpenRepository", err)
+		return nil
+	}
+	return gitRepo
+}
+
+// toCommitUser converts a Git signature to its API format
+func toCommitUser(sig *git.Signature) *api.CommitUser {
+	return &api.CommitUser{
+		Name:  sig.Name,
+		Email: sig.Email,
+		Date:  sig.When,
+	}
+}
+
+// commitUsers caches the users of commit signatures by email
+type commitUsers map[string]*api.User
+
+// get returns the user with the email of given signature, or nil if there is none.
+func (users commitUsers) get(sig *git.Signature) *api.User {
+	email := strings.ToLower(sig.Email)
+	if u, ok := users[email]; ok {
+		return u
+	}
+	var apiUser *api.User
+	if u, err := models.GetUserByEmail(sig.Email); err == nil {
+		apiUser = u.APIFormat()
+	}
+	users[email] = apiUser
+	return apiUser
+}
+
+// toCommit converts a Git commit of given repository to its API format
+func toCommit(r *models.Repository, c *git.Commit, users commitUsers) *api.Commit {
+	verif := models.ParseCommitWithSignature(c)
+	var signature, payload string
+	if c.Signature != nil {
+		signature = c.Signature.Signature
+		payload = c.Signature.Payload
+	}
+
+	apiURL := r.APIURL() + "/git/commits/" + c.ID.String()
+	parents := make([]*api.CommitMeta, c.ParentCount())
+	for i := range parents {
+		id, _ := c.ParentID(i)
+		parents[i] = &api.CommitMeta{
+			URL: r.APIURL() + "/git/commits/" + id.String(),
+			SHA: id.String(),
+		}
+	}
+	return &api.Commit{
+		CommitMeta: &api.CommitMeta{
+			URL: apiURL,
+			SHA: c.ID.String(),
+		},
+		HTMLURL: r.HTMLURL() + "/commit/" + c.ID.String(),
+		RepoCommit: &api.RepoCommit{
+			URL:       apiURL,
+			Author:    toCommitUser(c.Author),
+			Committer: toCommitUser(c.Committer),
+			Message:   c.Message(),
+			Tree: &api.CommitMeta{
+				URL: r.APIURL() + "/git/trees/" + c.Tree.ID.String(),
+				SHA: c.Tree.ID.String(),
+			},
+			Verification: &api.PayloadCommitVerification{
+				Verified:  verif.Verified,
+				Reason:    verif.Reason,
+				Signature: signature,
+				Payload:   payload,
+			},
+		},
+		Author:    users.get(c.Author),
+		Committer: users.get(c.Committer),
+		Parents:   parents,
+	}
+}
+
+// ListCommits lists the commits of a repository, newest first
+func ListCommits(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/commits repoListCommits
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: CommitList
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	ref := ctx.Query("sha")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	head, err := getCommitByRef(gitRepo, ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "getCommitByRef", err)
+		}
+		return
+	}
+
+	treePath := strings.Trim(ctx.Query("path"), "/")
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := convert.ToCorrectPageSize(ctx.QueryInt("limit"))
+
+	total, err := gitRepo.FileCommitsCount(head.ID.String(), treePath)
+	if err != nil {
+		ctx.Error(500, "FileCommitsCount", err)
+		return
+	}
+	commits, err := gitutil.CommitsByFileAndRangeSize(gitRepo, head.ID.String(), treePath, page, pageSize)
+	if err != nil {
+		ctx.Error(500, "CommitsByFileAndRangeSize", err)
+		return
+	}
+
+	users := make(commitUsers)
+	apiCommits := make([]*api.Commit, len(commits))
+	for i, commit := range commits {
+		apiCommits[i] = toCommit(ctx.Repo.Repository, commit, users)
+	}
+
+	ctx.SetLinkHeader(int(total), pageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprint(total))
+	ctx.JSON(200, &apiCommits)
+}
+
+// GetSingleCommit gets a commit of a repository by its SHA
+func GetSingleCommit(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/git/commits/{sha} repoGetSingleCommit
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Commit
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	sha := ctx.Params(":sha")
+	if tp, err := gitutil.GetObjectType(gitRepo, sha); err != nil || tp != git.ObjectCommit {
+		ctx.Status(404)
+		return
+	}
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		ctx.Error(500, "GetCommit", err)
+		return
+	}
+	ctx.JSON(200, toCommit(ctx.Repo.Repository, commit, make(commitUsers)))
+}
@@ -5,10 +5,20 @@
 package repo
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
 	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/repo"
 )
 
@@ -73,3 +83,455 @@ func GetEditorconfig(ctx *context.APIContext) {
 	}
 	ctx.JSON(200, def)
 }
+
+// toContentsResponse converts the tree entry at given path of given ref to its
+// API format, with the base64 encoded content of the blob if withContent is true.
+func toContentsResponse(r *models.Repository, ref, treePath string, entry *git.TreeEntry, withContent bool) (*api.ContentsResponse, error) {
+	cr := &api.ContentsResponse{
+		Name:    path.Base(treePath),
+		Path:    treePath,
+		SHA:     entry.ID.String(),
+		Type:    "file",
+		Size:    entry.Size(),
+		URL:     fmt.Sprintf("%s/contents/%s?ref=%s", r.APIURL(), treePath, ref),
+		HTMLURL: fmt.Sprintf("%s/src/%s/%s", r.HTMLURL(), ref, treePath),
+	}
+	switch {
+	case entry.IsDir():
+		cr.Type = "dir"
+		cr.GitURL = fmt.Sprintf("%s/git/trees/%s", r.APIURL(), cr.SHA)
+	case entry.IsLink():
+		cr.Type = "symlink"
+	case entry.IsSubModule():
+		cr.Type = "submodule"
+	default:
+		cr.DownloadURL = fmt.Sprintf("%s/raw/%s/%s", r.HTMLURL(), ref, treePath)
+	}
+	if entry.Type == git.ObjectBlob {
+		cr.GitURL = fmt.Sprintf("%s/git/blobs/%s", r.APIURL(), cr.SHA)
+	}
+	cr.Links = api.FileLinksResponse{
+		Self:    cr.URL,
+		GitURL:  cr.GitURL,
+		HTMLURL: cr.HTMLURL,
+	}
+
+	if withContent && !entry.IsDir() && !entry.IsSubModule() {
+		reader, err := entry.Blob().Data()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		cr.Encoding = "base64"
+		cr.Content = base64.StdEncoding.EncodeToString(data)
+	}
+	return cr, nil
+}
+
+// getCommitByRef returns the commit of given branch, tag or commit ID.
+func getCommitByRef(gitRepo *git.Repository, ref string) (*git.Commit, error) {
+	if gitRepo.IsBranchExist(ref) {
+		return gitRepo.GetBranchCommit(ref)
+	} else if gitRepo.IsTagExist(ref) {
+		return gitRepo.GetTagCommit(ref)
+	}
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, git.ErrNotExist{ID: ref}
+	}
+	return commit, nil
+}
+
+// GetContents gets the metadata and content of a file, or the entries of a directory
+func GetContents(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/contents/{filepath} repoGetContents
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ContentsResponse
+	//       404: notFound
+	//       500: error
+	if !ctx.Repo.HasAccess() || ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	treePath := strings.Trim(ctx.Params("*"), "/")
+	ref := ctx.Query("ref")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(500, "OpenRepository", err)
+		return
+	}
+	commit, err := getCommitByRef(gitRepo, ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "getCommitByRef", err)
+		}
+		return
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+		}
+		return
+	}
+
+	if !entry.IsDir() {
+		cr, err := toContentsResponse(ctx.Repo.Repository, ref, treePath, entry, true)
+		if err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+		ctx.JSON(200, cr)
+		return
+	}
+
+	tree, err := commit.SubTree(treePath)
+	if err != nil {
+		ctx.Error(500, "SubTree", err)
+		return
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		ctx.Error(500, "ListEntries", err)
+		return
+	}
+	entries.Sort()
+	crs := make([]*api.ContentsResponse, len(entries))
+	for i, e := range entries {
+		crs[i], err = toContentsResponse(ctx.Repo.Repository, ref, path.Join(treePath, e.Name()), e, false)
+		if err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+	ctx.JSON(200, &crs)
+}
+
+// toGitSignature converts the identity of an author or committer to a git
+// signature, it returns nil if the identity is not given.
+func toGitSignature(identity api.IdentityOptions) *git.Signature {
+	if len(identity.Name) == 0 && len(identity.Email) == 0 {
+		return nil
+	}
+	return &git.Signature{
+		Name:  identity.Name,
+		Email: identity.Email,
+		When:  time.Now(),
+	}
+}
+
+// checkFilePath checks the path of a file changed with the contents API and returns
+// it cleaned, it writes the error response and returns false if it is invalid.
+func checkFilePath(ctx *context.APIContext, treePath string) (string, bool) {
+	treePath = strings.TrimSuffix(treePath, "/")
+	if len(treePath) == 0 {
+		ctx.Error(422, "", "path of the file is required")
+		return "", false
+	} else if !models.IsValidRepoFilePath(treePath) {
+		ctx.Error(422, "", fmt.Sprintf("path %s is invalid", treePath))
+		return "", false
+	}
+	return path.Clean(treePath), true
+}
+
+// fileCommitOptions holds the checked options of a change made with the contents API
+type fileCommitOptions struct {
+	LastCommitID string
+	OldBranch    string
+	NewBranch    string
+	Author       *git.Signature
+	Committer    *git.Signature
+}
+
+// checkFileOptions checks the branches and identities of a change made with the
+// contents API, it writes the error response and returns false if they are invalid.
+func checkFileOptions(ctx *context.APIContext, form api.FileOptions) (*fileCommitOptions, bool) {
+	if ctx.Repo.Repository.IsBare || ctx.Repo.Repository.IsMirror {
+		ctx.Error(422, "", "repository is empty or a mirror")
+		return nil, false
+	}
+
+	opts := &fileCommitOptions{
+		OldBranch: form.BranchName,
+		NewBranch: form.NewBranchName,
+		Author:    toGitSignature(form.Author),
+		Committer: toGitSignature(form.Committer),
+	}
+	for _, sig := range []*git.Signature{opts.Author, opts.Committer} {
+		if sig != nil && (len(sig.Name) == 0 || len(sig.Email) == 0) {
+			ctx.Error(422, "", "name and email of author and committer are both required")
+			return nil, false
+		}
+	}
+
+	if len(opts.OldBranch) == 0 {
+		opts.OldBranch = ctx.Repo.Repository.DefaultBranch
+	}
+	branch, err := ctx.Repo.Repository.GetBranch(opts.OldBranch)
+	if err != nil {
+		if models.IsErrBranchNotExist(err) {
+			ctx.Error(404, "GetBranch", err)
+		} else {
+			ctx.Error(500, "GetBranch", err)
+		}
+		return nil, false
+	}
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(500, "GetCommit", err)
+		return nil, false
+	}
+	opts.LastCommitID = commit.ID.String()
+
+	if len(opts.NewBranch) == 0 {
+		opts.NewBranch = opts.OldBranch
+	} else if opts.NewBranch != opts.OldBranch {
+		if _, err := ctx.Repo.Repository.GetBranch(opts.NewBranch); err == nil {
+			ctx.Error(422, "", fmt.Sprintf("branch %s already exists", opts.NewBranch))
+			return nil, false
+		}
+	}
+
+	protectedBranch, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, opts.NewBranch)
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return nil, false
+	} else if protectedBranch != nil && !protectedBranch.CanUserCommit(ctx.Repo.Repository, ctx.User) {
+		ctx.Error(403, "", fmt.Sprintf("branch %s is protected", opts.NewBranch))
+		return nil, false
+	}
+	return opts, true
+}
+
+// fileResponse responds with the file at given path and the last commit of the branch
+func fileResponse(ctx *context.APIContext, status int, branch, treePath string) {
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(500, "OpenRepository", err)
+		return
+	}
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		ctx.Error(500, "GetBranchCommit", err)
+		return
+	}
+
+	fr := &api.FileResponse{
+		Commit: convert.ToCommit(commit),
+	}
+	if len(treePath) > 0 {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+			return
+		}
+		if fr.Content, err = toContentsResponse(ctx.Repo.Repository, branch, treePath, entry, false); err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+	ctx.JSON(status, fr)
+}
+
+// CreateFile creates a file in a repository
+func CreateFile(ctx *context.APIContext, form api.CreateFileOptions) {
+	// swagger:route POST /repos/{username}/{reponame}/contents/{filepath} repoCreateFile
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: FileResponse
+	//       403: forbidden
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	treePath, ok := checkFilePath(ctx, ctx.Params("*"))
+	if !ok {
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(form.Content)
+	if err != nil {
+		ctx.Error(422, "content", err)
+		return
+	}
+	opts, ok := checkFileOptions(ctx, form.FileOptions)
+	if !ok {
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.add", treePath)
+	}
+	push, err := ctx.Repo.Repository.UpdateRepoFile(ctx.User, models.UpdateRepoFileOptions{
+		LastCommitID: opts.LastCommitID,
+		OldBranch:    opts.OldBranch,
+		NewBranch:    opts.NewBranch,
+		OldTreeName:  treePath,
+		NewTreeName:  treePath,
+		Message:      message,
+		Content:      string(content),
+		IsNewFile:    true,
+		Author:       opts.Author,
+		Committer:    opts.Committer,
+	})
+	if err != nil {
+		if models.IsErrRepoFileAlreadyExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("file %s already exists", treePath))
+		} else {
+			ctx.Error(500, "UpdateRepoFile", err)
+		}
+		return
+	}
+	notification.NotifyPushCommits(push)
+	fileResponse(ctx, 201, opts.NewBranch, treePath)
+}
+
+// UpdateFile updates a file in a repository
+func UpdateFile(ctx *context.APIContext, form api.UpdateFileOptions) {
+	// swagger:route PUT /repos/{username}/{reponame}/contents/{filepath} repoUpdateFile
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: FileResponse
+	//       403: forbidden
+	//       404: notFound
+	//       409: error
+	//       422: validationError
+	//       500: error
+	treePath, ok := checkFilePath(ctx, ctx.Params("*"))
+	if !ok {
+		return
+	}
+	fromPath := treePath
+	if len(form.FromPath) > 0 {
+		if fromPath, ok = checkFilePath(ctx, form.FromPath); !ok {
+			return
+		}
+	}
+	if len(form.SHA) == 0 {
+		ctx.Error(422, "", "sha of the file is required")
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(form.Content)
+	if err != nil {
+		ctx.Error(422, "content", err)
+		return
+	}
+	opts, ok := checkFileOptions(ctx, form.FileOptions)
+	if !ok {
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.update", treePath)
+	}
+	push, err := ctx.Repo.Repository.UpdateRepoFile(ctx.User, models.UpdateRepoFileOptions{
+		LastCommitID: opts.LastCommitID,
+		OldBranch:    opts.OldBranch,
+		NewBranch:    opts.NewBranch,
+		OldTreeName:  fromPath,
+		NewTreeName:  treePath,
+		Message:      message,
+		Content:      string(content),
+		SHA:          form.SHA,
+		Author:       opts.Author,
+		Committer:    opts.Committer,
+	})
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(404, "", fmt.Sprintf("file %s does not exist", fromPath))
+		} else if models.IsErrSHADoesNotMatch(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "UpdateRepoFile", err)
+		}
+		return
+	}
+	notification.NotifyPushCommits(push)
+	fileResponse(ctx, 200, opts.NewBranch, treePath)
+}
+
+// DeleteFile deletes a file from a repository
+func DeleteFile(ctx *context.APIContext, form api.DeleteFileOptions) {
+	// swagger:route DELETE /repos/{username}/{reponame}/contents/{filepath} repoDeleteFile
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: FileResponse
+	//       403: forbidden
+	//       404: notFound
+	//       409: error
+	//       422: validationError
+	//       500: error
+	treePath, ok := checkFilePath(ctx, ctx.Params("*"))
+	if !ok {
+		return
+	}
+	if len(form.SHA) == 0 {
+		ctx.Error(422, "", "sha of the file is required")
+		return
+	}
+	opts, ok := checkFileOptions(ctx, form.FileOptions)
+	if !ok {
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.delete", treePath)
+	}
+	push, err := ctx.Repo.Repository.DeleteRepoFile(ctx.User, models.DeleteRepoFileOptions{
+		LastCommitID: opts.LastCommitID,
+		OldBranch:    opts.OldBranch,
+		NewBranch:    opts.NewBranch,
+		TreePath:     treePath,
+		Message:      message,
+		SHA:          form.SHA,
+		Author:       opts.Author,
+		Committer:    opts.Committer,
+	})
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(404, "", fmt.Sprintf("file %s does not exist", treePath))
+		} else if models.IsErrSHADoesNotMatch(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "DeleteRepoFile", err)
+		}
+		return
+	}
+	notification.NotifyPushCommits(push)
+	fileResponse(ctx, 200, opts.NewBranch, "")
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListRefs lists the references of a repository, optionally only those whose
+// name starts with the given filter, e.g. "heads" or "tags/v1"
+func ListRefs(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/git/refs/{filter} repoListRefs
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ReferenceList
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	refs, err := gitutil.GetRefsFiltered(gitRepo, strings.Trim(ctx.Params("*"), "/"))
+	if err != nil {
+		ctx.Error(500, "GetRefsFiltered", err)
+		return
+	}
+
+	repoURL := ctx.Repo.Repository.APIURL()
+	apiRefs := make([]*api.Reference, len(refs))
+	for i, ref := range refs {
+		objectURL := repoURL + "/git/" + ref.Type + "s/" + ref.Object.String()
+		apiRefs[i] = &api.Reference{
+			Ref: ref.Name,
+			URL: repoURL + "/git/" + ref.Name,
+			Object: &api.GitObject{
+				Type: ref.Type,
+				SHA:  ref.Object.String(),
+				URL:  objectURL,
+			},
+		}
+	}
+	ctx.JSON(200, &apiRefs)
+}
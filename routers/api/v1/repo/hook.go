@@ -5,10 +5,11 @@
 package repo
 
 import (
-	api "code.gitea.io/sdk/gitea"
+	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
@@ -107,3 +108,108 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(204)
 }
+
+// ListHookDeliveries list the recent deliveries of a hook of a repository
+func ListHookDeliveries(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/hooks/{id}/deliveries
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: apiHookDeliveries
+	//       404: notFound
+	//       500: error
+
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	tasks, err := hook.History(page)
+	if err != nil {
+		ctx.Error(500, "History", err)
+		return
+	}
+
+	apiDeliveries := make([]*api.HookDelivery, len(tasks))
+	for i := range tasks {
+		apiDeliveries[i] = convert.ToHookDelivery(tasks[i])
+	}
+	ctx.JSON(200, &apiDeliveries)
+}
+
+// RedeliverHook send the payload of a delivery of a hook of a repository again
+func RedeliverHook(ctx *context.APIContext) {
+	// swagger:route POST /repos/{username}/{reponame}/hooks/{id}/deliveries/{delivery}/redeliver
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: apiHookDelivery
+	//       404: notFound
+	//       500: error
+
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	t, err := models.RedeliverHookTask(hook.ID, ctx.ParamsInt64(":delivery"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "RedeliverHookTask", err)
+		}
+		return
+	}
+	go models.HookQueue.Add(t.RepoID)
+
+	ctx.JSON(200, convert.ToHookDelivery(t))
+}
+
+// TestHook send a test push event to a hook of a repository
+func TestHook(ctx *context.APIContext) {
+	// swagger:route POST /repos/{username}/{reponame}/hooks/{id}/tests
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       204: empty
+	//       404: notFound
+	//       500: error
+
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	// Use latest commit of default branch or fake one if it's empty repository.
+	var commit *git.Commit
+	if !ctx.Repo.Repository.IsBare {
+		gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+		if err != nil {
+			ctx.Error(500, "OpenRepository", err)
+			return
+		}
+		if commit, err = gitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch); err != nil {
+			ctx.Error(500, "GetBranchCommit", err)
+			return
+		}
+	}
+
+	if err = models.PrepareTestWebhook(hook, ctx.Repo.Repository, commit, ctx.User); err != nil {
+		ctx.Error(500, "PrepareTestWebhook", err)
+		return
+	}
+	go models.HookQueue.Add(ctx.Repo.Repository.ID)
+
+	ctx.Status(204)
+}
@@ -6,13 +6,12 @@ package repo
 
 import (
 	"fmt"
-	"strings"
-
-	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -77,34 +76,35 @@ func CreateIssue(ctx *context.APIContext, form api.CreateIssueOption) {
 		Content:  form.Body,
 	}
 
+	var assigneeIDs []int64
 	if ctx.Repo.IsWriter() {
-		if len(form.Assignee) > 0 {
-			assignee, err := models.GetUserByName(form.Assignee)
-			if err != nil {
-				if models.IsErrUserNotExist(err) {
-					ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: [name: %s]", form.Assignee))
-				} else {
-					ctx.Error(500, "GetUserByName", err)
-				}
-				return
+		var err error
+		assigneeIDs, err = models.GetAssigneeIDsByNames(form.Assignee, form.Assignees)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: [name: %s]", err.(models.ErrUserNotExist).Name))
+			} else {
+				ctx.Error(500, "GetAssigneeIDsByNames", err)
 			}
-			issue.AssigneeID = assignee.ID
+			return
 		}
 		issue.MilestoneID = form.Milestone
 	} else {
 		form.Labels = nil
 	}
 
-	if err := models.NewIssue(ctx.Repo.Repository, issue, form.Labels, nil); err != nil {
+	if err := models.NewIssue(ctx.Repo.Repository, issue, form.Labels, assigneeIDs, nil); err != nil {
 		ctx.Error(500, "NewIssue", err)
 		return
 	}
+	notification.NotifyNewIssue(issue)
 
 	if form.Closed {
 		if err := issue.ChangeStatus(ctx.User, ctx.Repo.Repository, true); err != nil {
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
+		notification.NotifyIssueChangeStatus(ctx.User, issue, true)
 	}
 
 	// Refetch from database to assign some automatic values
@@ -141,25 +141,23 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 		issue.Content = *form.Body
 	}
 
-	if ctx.Repo.IsWriter() && form.Assignee != nil &&
-		(issue.Assignee == nil || issue.Assignee.LowerName != strings.ToLower(*form.Assignee)) {
-		if len(*form.Assignee) == 0 {
-			issue.AssigneeID = 0
-		} else {
-			assignee, err := models.GetUserByName(*form.Assignee)
-			if err != nil {
-				if models.IsErrUserNotExist(err) {
-					ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", *form.Assignee))
-				} else {
-					ctx.Error(500, "GetUserByName", err)
-				}
-				return
+	if ctx.Repo.IsWriter() && (form.Assignee != nil || form.Assignees != nil) {
+		var oneAssignee string
+		if form.Assignee != nil {
+			oneAssignee = *form.Assignee
+		}
+		assigneeIDs, err := models.GetAssigneeIDsByNames(oneAssignee, form.Assignees)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", err.(models.ErrUserNotExist).Name))
+			} else {
+				ctx.Error(500, "GetAssigneeIDsByNames", err)
 			}
-			issue.AssigneeID = assignee.ID
+			return
 		}
 
-		if err = models.UpdateIssueUserByAssignee(issue); err != nil {
-			ctx.Error(500, "UpdateIssueUserByAssignee", err)
+		if err = issue.UpdateAssignees(ctx.User, assigneeIDs); err != nil {
+			ctx.Error(500, "UpdateAssignees", err)
 			return
 		}
 	}
@@ -179,9 +177,14 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	}
 	if form.State != nil {
 		if err = issue.ChangeStatus(ctx.User, ctx.Repo.Repository, api.StateClosed == api.StateType(*form.State)); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Error(412, "DependenciesLeft", "cannot close this issue because it still has open dependencies")
+				return
+			}
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
+		notification.NotifyIssueChangeStatus(ctx.User, issue, issue.IsClosed)
 	}
 
 	// Refetch from database to assign some automatic values
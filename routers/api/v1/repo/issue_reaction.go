@@ -0,0 +1,213 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getReactionIssue returns the issue of the repository given by index
+func getReactionIssue(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// getReactionComment returns the comment given by id along with its issue,
+// which must belong to the repository
+func getReactionComment(ctx *context.APIContext) (*models.Issue, *models.Comment) {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrCommentNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetCommentByID", err)
+		}
+		return nil, nil
+	}
+
+	issue, err := models.GetIssueByID(comment.IssueID)
+	if err != nil {
+		ctx.Error(500, "GetIssueByID", err)
+		return nil, nil
+	} else if issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.Status(404)
+		return nil, nil
+	}
+	return issue, comment
+}
+
+func reactionsAPIFormat(reactions models.ReactionList) []*api.Reaction {
+	apiReactions := make([]*api.Reaction, len(reactions))
+	for i := range reactions {
+		apiReactions[i] = reactions[i].APIFormat()
+	}
+	return apiReactions
+}
+
+// createReaction adds the reaction, the existing reaction is returned if
+// the user already reacted the same way
+func createReaction(ctx *context.APIContext, issue *models.Issue, comment *models.Comment, content string) {
+	var reaction *models.Reaction
+	var err error
+	if comment == nil {
+		reaction, err = models.CreateIssueReaction(ctx.User, issue, content)
+	} else {
+		reaction, err = models.CreateCommentReaction(ctx.User, issue, comment, content)
+	}
+	if err != nil {
+		if models.IsErrForbiddenReaction(err) {
+			ctx.Error(422, "", err)
+		} else if models.IsErrReactionAlreadyExist(err) {
+			ctx.JSON(200, &api.Reaction{
+				User:     ctx.User.APIFormat(),
+				Reaction: content,
+			})
+		} else {
+			ctx.Error(500, "CreateReaction", err)
+		}
+		return
+	}
+	ctx.JSON(201, reaction.APIFormat())
+}
+
+// GetIssueReactions lists the reactions to an issue
+func GetIssueReactions(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/issues/{index}/reactions issueGetReactions
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ReactionList
+	//       404: notFound
+	//       500: error
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, reactionsAPIFormat(issue.Reactions))
+}
+
+// PostIssueReaction adds a reaction to an issue
+func PostIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:route POST /repos/{username}/{reponame}/issues/{index}/reactions issuePostReaction
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Reaction
+	//       201: Reaction
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	createReaction(ctx, issue, nil, form.Reaction)
+}
+
+// DeleteIssueReaction removes a reaction from an issue
+func DeleteIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:route DELETE /repos/{username}/{reponame}/issues/{index}/reactions issueDeleteReaction
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Responses:
+	//       204: empty
+	//       404: notFound
+	//       500: error
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteIssueReaction(ctx.User, issue, form.Reaction); err != nil {
+		ctx.Error(500, "DeleteIssueReaction", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// GetIssueCommentReactions lists the reactions to an issue comment
+func GetIssueCommentReactions(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/issues/comments/{id}/reactions issueGetCommentReactions
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ReactionList
+	//       404: notFound
+	//       500: error
+	_, comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := comment.LoadReactions(); err != nil {
+		ctx.Error(500, "LoadReactions", err)
+		return
+	}
+	ctx.JSON(200, reactionsAPIFormat(comment.Reactions))
+}
+
+// PostIssueCommentReaction adds a reaction to an issue comment
+func PostIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:route POST /repos/{username}/{reponame}/issues/comments/{id}/reactions issuePostCommentReaction
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Reaction
+	//       201: Reaction
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	issue, comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	createReaction(ctx, issue, comment, form.Reaction)
+}
+
+// DeleteIssueCommentReaction removes a reaction from an issue comment
+func DeleteIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:route DELETE /repos/{username}/{reponame}/issues/comments/{id}/reactions issueDeleteCommentReaction
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Responses:
+	//       204: empty
+	//       404: notFound
+	//       500: error
+	issue, comment := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteCommentReaction(ctx.User, issue, comment, form.Reaction); err != nil {
+		ctx.Error(500, "DeleteCommentReaction", err)
+		return
+	}
+	ctx.Status(204)
+}
@@ -1,9 +1,11 @@
 package repo
 
 import (
+	"time"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // ListTrackedTimes list all the tracked times of an issue
@@ -16,6 +18,7 @@ func ListTrackedTimes(ctx *context.APIContext) {
 	//     Responses:
 	//       200: TrackedTimes
 	//	 404: error
+	//       422: validationError
 	//       500: error
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -27,8 +30,59 @@ func ListTrackedTimes(ctx *context.APIContext) {
 		return
 	}
 
-	if trackedTimes, err := models.GetTrackedTimesByIssue(issue.ID); err != nil {
-		ctx.Error(500, "GetTrackedTimesByIssue", err)
+	opts, ok := trackedTimesOptions(ctx)
+	if !ok {
+		return
+	}
+	opts.IssueID = issue.ID
+	listTrackedTimes(ctx, opts)
+}
+
+// ListTrackedTimesByRepository lists all tracked times of the issues of a repository
+func ListTrackedTimesByRepository(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/times repoTrackedTimes
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: TrackedTimes
+	//       422: validationError
+	//       500: error
+	opts, ok := trackedTimesOptions(ctx)
+	if !ok {
+		return
+	}
+	opts.RepoID = ctx.Repo.Repository.ID
+	listTrackedTimes(ctx, opts)
+}
+
+// trackedTimesOptions returns the options to find tracked times with the
+// date range given by the since and before parameters in RFC 3339 format.
+func trackedTimesOptions(ctx *context.APIContext) (models.FindTrackedTimesOptions, bool) {
+	var opts models.FindTrackedTimesOptions
+	if since := ctx.Query("since"); len(since) > 0 {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			ctx.Error(422, "since", err)
+			return opts, false
+		}
+		opts.CreatedAfterUnix = t.Unix()
+	}
+	if before := ctx.Query("before"); len(before) > 0 {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			ctx.Error(422, "before", err)
+			return opts, false
+		}
+		opts.CreatedBeforeUnix = t.Unix()
+	}
+	return opts, true
+}
+
+func listTrackedTimes(ctx *context.APIContext, opts models.FindTrackedTimesOptions) {
+	if trackedTimes, err := models.GetTrackedTimes(opts); err != nil {
+		ctx.Error(500, "GetTrackedTimes", err)
 	} else {
 		ctx.JSON(200, &trackedTimes)
 	}
@@ -72,17 +126,19 @@ func ListTrackedTimesByUser(ctx *context.APIContext) {
 	//     Responses:
 	//       200: TrackedTimes
 	//	 404: error
+	//       422: validationError
 	//       500: error
 	user := GetUserByParamsName(ctx, ctx.Params(":username"))
 	if user == nil {
 		return
 	}
 
-	if trackedTimes, err := models.GetTrackedTimesByUser(user.ID); err != nil {
-		ctx.Error(500, "GetTrackedTimesByUser", err)
-	} else {
-		ctx.JSON(200, &trackedTimes)
+	opts, ok := trackedTimesOptions(ctx)
+	if !ok {
+		return
 	}
+	opts.UserID = user.ID
+	listTrackedTimes(ctx, opts)
 }
 
 // ListMyTrackedTimes lists all tracked times of the current user
@@ -94,12 +150,14 @@ func ListMyTrackedTimes(ctx *context.APIContext) {
 	//
 	//     Responses:
 	//       200: TrackedTimes
+	//       422: validationError
 	//       500: error
-	if trackedTimes, err := models.GetTrackedTimesByUser(ctx.User.ID); err != nil {
-		ctx.Error(500, "GetTrackedTimesByUser", err)
-	} else {
-		ctx.JSON(200, &trackedTimes)
+	opts, ok := trackedTimesOptions(ctx)
+	if !ok {
+		return
 	}
+	opts.UserID = ctx.User.ID
+	listTrackedTimes(ctx, opts)
 }
 
 // GetUserByParamsName get user by name
@@ -0,0 +1,458 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getProject returns the project of the repository given by id
+func getProject(ctx *context.APIContext) *models.Project {
+	p, err := models.GetProjectByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetProjectByRepoID", err)
+		}
+		return nil
+	}
+	return p
+}
+
+// getProjectBoard returns the board of the project given by boardID, the
+// default board has the id 0
+func getProjectBoard(ctx *context.APIContext, p *models.Project) *models.ProjectBoard {
+	b, err := models.GetProjectBoard(p.ID, ctx.ParamsInt64(":boardID"))
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetProjectBoard", err)
+		}
+		return nil
+	}
+	return b
+}
+
+// ListProjects list the projects of a repository
+func ListProjects(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/projects repoListProjects
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ProjectList
+	//       500: error
+	projects, err := models.GetProjects(ctx.Repo.Repository.ID, 0, ctx.Query("state") == "closed")
+	if err != nil {
+		ctx.Error(500, "GetProjects", err)
+		return
+	}
+
+	apiProjects := make([]*api.Project, len(projects))
+	for i := range projects {
+		if err = projects[i].LoadCreator(); err != nil {
+			ctx.Error(500, "LoadCreator", err)
+			return
+		}
+		apiProjects[i] = projects[i].APIFormat()
+	}
+	ctx.JSON(200, &apiProjects)
+}
+
+// GetProject get a project of a repository
+func GetProject(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/projects/{id} repoGetProject
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Project
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := p.LoadCreator(); err != nil {
+		ctx.Error(500, "LoadCreator", err)
+		return
+	}
+	ctx.JSON(200, p.APIFormat())
+}
+
+// CreateProject create a project for a repository
+func CreateProject(ctx *context.APIContext, form api.CreateProjectOption) {
+	// swagger:route POST /repos/{username}/{reponame}/projects repoCreateProject
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: Project
+	//       403: forbidden
+	//       422: validationError
+	//       500: error
+	p := &models.Project{
+		RepoID:      ctx.Repo.Repository.ID,
+		CreatorID:   ctx.User.ID,
+		Creator:     ctx.User,
+		Title:       form.Title,
+		Description: form.Description,
+	}
+	if err := models.NewProject(p); err != nil {
+		ctx.Error(500, "NewProject", err)
+		return
+	}
+	ctx.JSON(201, p.APIFormat())
+}
+
+// EditProject modify a project of a repository
+func EditProject(ctx *context.APIContext, form api.EditProjectOption) {
+	// swagger:route PATCH /repos/{username}/{reponame}/projects/{id} repoEditProject
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Project
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Title != nil && len(*form.Title) > 0 {
+		p.Title = *form.Title
+	}
+	if form.Description != nil {
+		p.Description = *form.Description
+	}
+	if err := models.UpdateProject(p); err != nil {
+		ctx.Error(500, "UpdateProject", err)
+		return
+	}
+
+	if form.State != nil {
+		isClosed := api.StateClosed == api.StateType(*form.State)
+		if isClosed != p.IsClosed {
+			if err := models.ChangeProjectStatus(p, isClosed); err != nil {
+				ctx.Error(500, "ChangeProjectStatus", err)
+				return
+			}
+		}
+	}
+
+	if err := p.LoadCreator(); err != nil {
+		ctx.Error(500, "LoadCreator", err)
+		return
+	}
+	ctx.JSON(200, p.APIFormat())
+}
+
+// DeleteProject delete a project of a repository
+func DeleteProject(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/projects/{id} repoDeleteProject
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteProjectByRepoID(ctx.Repo.Repository.ID, p.ID); err != nil {
+		ctx.Error(500, "DeleteProjectByRepoID", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListProjectBoards list the boards of a project in their order
+func ListProjectBoards(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/projects/{id}/boards repoListProjectBoards
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ProjectBoardList
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	boards, err := p.GetBoards()
+	if err != nil {
+		ctx.Error(500, "GetBoards", err)
+		return
+	}
+
+	apiBoards := make([]*api.ProjectBoard, len(boards))
+	for i := range boards {
+		apiBoards[i] = boards[i].APIFormat()
+	}
+	ctx.JSON(200, &apiBoards)
+}
+
+// CreateProjectBoard add a board to a project
+func CreateProjectBoard(ctx *context.APIContext, form api.CreateProjectBoardOption) {
+	// swagger:route POST /repos/{username}/{reponame}/projects/{id}/boards repoCreateProjectBoard
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: ProjectBoard
+	//       403: forbidden
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	b := &models.ProjectBoard{
+		ProjectID: p.ID,
+		Title:     form.Title,
+		Sorting:   form.Sorting,
+		IsClosed:  form.ClosedColumn,
+		CreatorID: ctx.User.ID,
+	}
+	if err := models.NewProjectBoard(b); err != nil {
+		ctx.Error(500, "NewProjectBoard", err)
+		return
+	}
+	ctx.JSON(201, b.APIFormat())
+}
+
+// EditProjectBoard modify a board of a project
+func EditProjectBoard(ctx *context.APIContext, form api.EditProjectBoardOption) {
+	// swagger:route PATCH /repos/{username}/{reponame}/projects/{id}/boards/{boardID} repoEditProjectBoard
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: ProjectBoard
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	b := getProjectBoard(ctx, p)
+	if ctx.Written() {
+		return
+	} else if b.IsDefault() {
+		ctx.Status(404)
+		return
+	}
+
+	if form.Title != nil && len(*form.Title) > 0 {
+		b.Title = *form.Title
+	}
+	if form.Sorting != nil {
+		b.Sorting = *form.Sorting
+	}
+	if form.ClosedColumn != nil {
+		b.IsClosed = *form.ClosedColumn
+	}
+	if err := models.UpdateProjectBoard(b); err != nil {
+		ctx.Error(500, "UpdateProjectBoard", err)
+		return
+	}
+	ctx.JSON(200, b.APIFormat())
+}
+
+// DeleteProjectBoard delete a board of a project, its issues are moved to
+// the default board
+func DeleteProjectBoard(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/projects/{id}/boards/{boardID} repoDeleteProjectBoard
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	b := getProjectBoard(ctx, p)
+	if ctx.Written() {
+		return
+	} else if b.IsDefault() {
+		ctx.Status(404)
+		return
+	}
+
+	if err := models.DeleteProjectBoard(b); err != nil {
+		ctx.Error(500, "DeleteProjectBoard", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListProjectBoardIssues list the issues and pull requests on a board in
+// their order
+func ListProjectBoardIssues(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/projects/{id}/boards/{boardID}/issues repoListProjectBoardIssues
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: IssueList
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	b := getProjectBoard(ctx, p)
+	if ctx.Written() {
+		return
+	}
+	if err := b.LoadIssues(); err != nil {
+		ctx.Error(500, "LoadIssues", err)
+		return
+	}
+
+	apiIssues := make([]*api.Issue, len(b.Issues))
+	for i := range b.Issues {
+		apiIssues[i] = b.Issues[i].APIFormat()
+	}
+	ctx.JSON(200, &apiIssues)
+}
+
+// MoveProjectIssue add an issue to a project or move it to another board or
+// position of the project
+func MoveProjectIssue(ctx *context.APIContext, form api.MoveProjectIssueOption) {
+	// swagger:route POST /repos/{username}/{reponame}/projects/{id}/issues repoMoveProjectIssue
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       412: error
+	//       422: validationError
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, form.Issue)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	b, err := models.GetProjectBoard(p.ID, form.BoardID)
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetProjectBoard", err)
+		}
+		return
+	}
+
+	if err = issue.LoadProject(); err != nil {
+		ctx.Error(500, "LoadProject", err)
+		return
+	}
+	if issue.Project == nil || issue.Project.ID != p.ID {
+		if err = models.ChangeProjectAssign(issue, p); err != nil {
+			ctx.Error(500, "ChangeProjectAssign", err)
+			return
+		}
+	}
+
+	isClosed := issue.IsClosed
+	if err = models.MoveProjectIssue(ctx.User, issue, b, form.Sorting); err != nil {
+		if models.IsErrDependenciesLeft(err) {
+			ctx.Error(412, "", err)
+		} else {
+			ctx.Error(500, "MoveProjectIssue", err)
+		}
+		return
+	}
+	if issue.IsClosed != isClosed {
+		notification.NotifyIssueChangeStatus(ctx.User, issue, issue.IsClosed)
+	}
+	ctx.Status(204)
+}
+
+// RemoveProjectIssue remove an issue from a project
+func RemoveProjectIssue(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/projects/{id}/issues/{index} repoRemoveProjectIssue
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if err = issue.LoadProject(); err != nil {
+		ctx.Error(500, "LoadProject", err)
+		return
+	} else if issue.Project == nil || issue.Project.ID != p.ID {
+		ctx.Status(404)
+		return
+	}
+	if err = models.ChangeProjectAssign(issue, nil); err != nil {
+		ctx.Error(500, "ChangeProjectAssign", err)
+		return
+	}
+	ctx.Status(204)
+}
@@ -12,8 +12,8 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
-
-	api "code.gitea.io/sdk/gitea"
+	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // ListPullRequests returns a list of all PRs
@@ -70,7 +70,7 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 	var (
 		repo        = ctx.Repo.Repository
 		labelIDs    []int64
-		assigneeID  int64
+		assigneeIDs []int64
 		milestoneID int64
 	)
 
@@ -127,24 +127,20 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 		milestoneID = milestone.ID
 	}
 
-	if len(form.Assignee) > 0 {
-		assigneeUser, err := models.GetUserByName(form.Assignee)
-		if err != nil {
-			if models.IsErrUserNotExist(err) {
-				ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", form.Assignee))
-			} else {
-				ctx.Error(500, "GetUserByName", err)
-			}
-			return
+	assigneeIDs, err = models.GetAssigneeIDsByNames(form.Assignee, form.Assignees)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", err.(models.ErrUserNotExist).Name))
+		} else {
+			ctx.Error(500, "GetAssigneeIDsByNames", err)
 		}
-
-		assignee, err := repo.GetAssigneeByID(assigneeUser.ID)
-		if err != nil {
+		return
+	}
+	for _, assigneeID := range assigneeIDs {
+		if _, err = repo.GetAssigneeByID(assigneeID); err != nil {
 			ctx.Error(500, "GetAssigneeByID", err)
 			return
 		}
-
-		assigneeID = assignee.ID
 	}
 
 	patch, err := headGitRepo.GetPatch(prInfo.MergeBase, headBranch)
@@ -155,12 +151,10 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 
 	prIssue := &models.Issue{
 		RepoID:      repo.ID,
-		Index:       repo.NextIssueIndex(),
 		Title:       form.Title,
 		PosterID:    ctx.User.ID,
 		Poster:      ctx.User,
 		MilestoneID: milestoneID,
-		AssigneeID:  assigneeID,
 		IsPull:      true,
 		Content:     form.Body,
 	}
@@ -176,13 +170,14 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 		Type:         models.PullRequestGitea,
 	}
 
-	if err := models.NewPullRequest(repo, prIssue, labelIDs, []string{}, pr, patch); err != nil {
+	if err := models.NewPullRequest(repo, prIssue, labelIDs, assigneeIDs, []string{}, pr, patch); err != nil {
 		ctx.Error(500, "NewPullRequest", err)
 		return
 	} else if err := pr.PushToBaseRepo(); err != nil {
 		ctx.Error(500, "PushToBaseRepo", err)
 		return
 	}
+	notification.NotifyNewPullRequest(pr)
 
 	log.Trace("Pull request created: %d/%d", repo.ID, prIssue.ID)
 	ctx.JSON(201, pr.APIFormat())
@@ -215,25 +210,19 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 		issue.Content = form.Body
 	}
 
-	if ctx.Repo.IsWriter() && len(form.Assignee) > 0 &&
-		(issue.Assignee == nil || issue.Assignee.LowerName != strings.ToLower(form.Assignee)) {
-		if len(form.Assignee) == 0 {
-			issue.AssigneeID = 0
-		} else {
-			assignee, err := models.GetUserByName(form.Assignee)
-			if err != nil {
-				if models.IsErrUserNotExist(err) {
-					ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", form.Assignee))
-				} else {
-					ctx.Error(500, "GetUserByName", err)
-				}
-				return
+	if ctx.Repo.IsWriter() && (len(form.Assignee) > 0 || form.Assignees != nil) {
+		assigneeIDs, err := models.GetAssigneeIDsByNames(form.Assignee, form.Assignees)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", err.(models.ErrUserNotExist).Name))
+			} else {
+				ctx.Error(500, "GetAssigneeIDsByNames", err)
 			}
-			issue.AssigneeID = assignee.ID
+			return
 		}
 
-		if err = models.UpdateIssueUserByAssignee(issue); err != nil {
-			ctx.Error(500, "UpdateIssueUserByAssignee", err)
+		if err = issue.UpdateAssignees(ctx.User, assigneeIDs); err != nil {
+			ctx.Error(500, "UpdateAssignees", err)
 			return
 		}
 	}
@@ -256,6 +245,7 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
+		notification.NotifyIssueChangeStatus(ctx.User, issue, issue.IsClosed)
 	}
 
 	// Refetch from database
@@ -293,7 +283,7 @@ func IsPullRequestMerged(ctx *context.APIContext) {
 }
 
 // MergePullRequest merges a PR given an index
-func MergePullRequest(ctx *context.APIContext) {
+func MergePullRequest(ctx *context.APIContext, form api.MergePullRequestOption) {
 	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrPullRequestNotExist(err) {
@@ -330,10 +320,20 @@ func MergePullRequest(ctx *context.APIContext) {
 		return
 	}
 
-	if err := pr.Merge(ctx.User, ctx.Repo.GitRepo); err != nil {
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	if err := pr.Merge(ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
+		if models.IsErrInvalidMergeStyle(err) || models.IsErrNotAllowedToMerge(err) {
+			ctx.Error(405, "Merge", err)
+			return
+		}
 		ctx.Error(500, "Merge", err)
 		return
 	}
+	notification.NotifyMergePullRequest(pr, ctx.User)
 
 	log.Trace("Pull request merged: %d", pr.ID)
 	ctx.Status(200)
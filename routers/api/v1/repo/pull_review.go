@@ -0,0 +1,363 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getPullRequestIssue returns the issue of the pull request given by index
+func getPullRequestIssue(ctx *context.APIContext) *models.Issue {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(500, "LoadIssue", err)
+		return nil
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+	pr.Issue.PullRequest = pr
+	return pr.Issue
+}
+
+// getPullReview returns the review given by id, pending reviews are only
+// visible to their reviewer.
+func getPullReview(ctx *context.APIContext, issue *models.Issue) *models.Review {
+	review, err := models.GetReviewByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrReviewNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetReviewByID", err)
+		}
+		return nil
+	}
+
+	if review.IssueID != issue.ID {
+		ctx.Status(404)
+		return nil
+	}
+	if review.Type == models.ReviewTypePending && (!ctx.IsSigned || review.ReviewerID != ctx.User.ID) {
+		ctx.Status(404)
+		return nil
+	}
+
+	review.Issue = issue
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return nil
+	}
+	return review
+}
+
+// ListPullReviews lists all reviews of a pull request
+func ListPullReviews(ctx *context.APIContext) {
+	// swagger:route GET /repos/{owner}/{repo}/pulls/{index}/reviews repoListPullReviews
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PullReviewList
+	//       404: notFound
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reviews, err := models.FindReviews(models.FindReviewOptions{
+		Type:    models.ReviewTypeUnknown,
+		IssueID: issue.ID,
+	})
+	if err != nil {
+		ctx.Error(500, "FindReviews", err)
+		return
+	}
+
+	apiReviews := make([]*api.PullReview, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type == models.ReviewTypePending && (!ctx.IsSigned || review.ReviewerID != ctx.User.ID) {
+			continue
+		}
+		review.Issue = issue
+		if err = review.LoadAttributes(); err != nil {
+			ctx.Error(500, "LoadAttributes", err)
+			return
+		}
+		apiReviews = append(apiReviews, review.APIFormat())
+	}
+	ctx.JSON(200, &apiReviews)
+}
+
+// GetPullReview gets a specific review of a pull request
+func GetPullReview(ctx *context.APIContext) {
+	// swagger:route GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repoGetPullReview
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PullReview
+	//       404: notFound
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// GetPullReviewComments lists all code comments of a pull request review
+func GetPullReviewComments(ctx *context.APIContext) {
+	// swagger:route GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments repoGetPullReviewComments
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PullReviewCommentList
+	//       404: notFound
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	apiComments := make([]*api.PullReviewComment, len(review.CodeComments))
+	for i, comment := range review.CodeComments {
+		apiComments[i] = review.APIFormatReviewComment(comment)
+	}
+	ctx.JSON(200, &apiComments)
+}
+
+// DeletePullReview deletes a pending review of the current user
+func DeletePullReview(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repoDeletePullReview
+	//
+	//     Responses:
+	//       204: empty
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	if review.Type != models.ReviewTypePending {
+		ctx.Error(422, "", "only pending reviews can be deleted")
+		return
+	}
+	if err := models.DeleteReview(review); err != nil {
+		ctx.Error(500, "DeleteReview", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// CreatePullReview creates a review with its code comments for a pull request.
+// Without an event the review is kept pending.
+func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions) {
+	// swagger:route POST /repos/{owner}/{repo}/pulls/{index}/reviews repoCreatePullReview
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PullReview
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.PullRequest.HasMerged {
+		ctx.Error(422, "", "pull request has already been merged")
+		return
+	}
+
+	reviewType := models.ReviewTypePending
+	if len(opts.Event) > 0 {
+		reviewType = models.ToReviewType(opts.Event)
+	}
+	if !checkReviewSubmission(ctx, issue, reviewType, opts.Body, len(opts.Comments) > 0) {
+		return
+	}
+
+	if len(opts.CommitID) > 0 {
+		headCommitID, err := issue.PullRequest.GetHeadCommitID()
+		if err != nil {
+			ctx.Error(500, "GetHeadCommitID", err)
+			return
+		}
+		if opts.CommitID != headCommitID {
+			ctx.Error(422, "", fmt.Sprintf("commit %s is not the head of the pull request", opts.CommitID))
+			return
+		}
+	}
+
+	for _, c := range opts.Comments {
+		line := c.NewLineNum
+		if line == 0 {
+			line = -c.OldLineNum
+		}
+		if line == 0 || len(c.Path) == 0 || len(c.Body) == 0 {
+			ctx.Error(422, "", "code comments need a path, a body and a line")
+			return
+		}
+		if _, err := models.CreateCodeComment(ctx.User, issue, c.Path, line, c.Body, true); err != nil {
+			ctx.Error(500, "CreateCodeComment", err)
+			return
+		}
+	}
+
+	var (
+		review *models.Review
+		err    error
+	)
+	if reviewType == models.ReviewTypePending {
+		review, err = models.GetCurrentReview(ctx.User, issue)
+		if err != nil {
+			if !models.IsErrReviewNotExist(err) {
+				ctx.Error(500, "GetCurrentReview", err)
+				return
+			}
+			review, err = models.CreateReview(models.CreateReviewOptions{
+				Type:     models.ReviewTypePending,
+				Issue:    issue,
+				Reviewer: ctx.User,
+				Content:  opts.Body,
+			})
+			if err != nil {
+				ctx.Error(500, "CreateReview", err)
+				return
+			}
+		} else if len(opts.Body) > 0 {
+			review.Content = opts.Body
+			if err = models.UpdateReview(review); err != nil {
+				ctx.Error(500, "UpdateReview", err)
+				return
+			}
+		}
+	} else {
+		review, _, err = models.SubmitReview(ctx.User, issue, reviewType, opts.Body)
+		if err != nil {
+			ctx.Error(500, "SubmitReview", err)
+			return
+		}
+	}
+
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// SubmitPullReview submits a pending review of the current user
+func SubmitPullReview(ctx *context.APIContext, opts api.SubmitPullReviewOptions) {
+	// swagger:route POST /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repoSubmitPullReview
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PullReview
+	//       404: notFound
+	//       422: validationError
+	//       500: error
+	issue := getPullRequestIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	if review.Type != models.ReviewTypePending {
+		ctx.Error(422, "", "only pending reviews can be submitted")
+		return
+	}
+	if issue.PullRequest.HasMerged {
+		ctx.Error(422, "", "pull request has already been merged")
+		return
+	}
+
+	reviewType := models.ToReviewType(opts.Event)
+	if reviewType == models.ReviewTypePending || reviewType == models.ReviewTypeUnknown {
+		ctx.Error(422, "", fmt.Sprintf("invalid review event: %s", opts.Event))
+		return
+	}
+	if !checkReviewSubmission(ctx, issue, reviewType, opts.Body, len(review.CodeComments) > 0) {
+		return
+	}
+
+	review, _, err := models.SubmitReview(ctx.User, issue, reviewType, opts.Body)
+	if err != nil {
+		ctx.Error(500, "SubmitReview", err)
+		return
+	}
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// checkReviewSubmission validates a review about to be created or submitted
+// and writes an error response if it is invalid.
+func checkReviewSubmission(ctx *context.APIContext, issue *models.Issue, reviewType models.ReviewType, body string, hasComments bool) bool {
+	switch reviewType {
+	case models.ReviewTypeUnknown:
+		ctx.Error(422, "", "invalid review event")
+		return false
+	case models.ReviewTypeApprove, models.ReviewTypeReject:
+		if issue.PosterID == ctx.User.ID {
+			ctx.Error(422, "", models.ErrReviewOwnPullRequest{IssueID: issue.ID}.Error())
+			return false
+		}
+		if reviewType == models.ReviewTypeReject && len(body) == 0 {
+			ctx.Error(422, "", "requesting changes needs a body")
+			return false
+		}
+	case models.ReviewTypeComment:
+		if len(body) == 0 && !hasComments {
+			ctx.Error(422, "", "a comment review needs a body or code comments")
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getPushMirror returns the push mirror of the repository given by id
+func getPushMirror(ctx *context.APIContext) *models.PushMirror {
+	m, err := models.GetPushMirrorByID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrPushMirrorNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPushMirrorByID", err)
+		}
+		return nil
+	}
+	return m
+}
+
+// ListPushMirrors lists the push mirrors of a repository
+func ListPushMirrors(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/push_mirrors repoListPushMirrors
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PushMirrorList
+	//       403: forbidden
+	//       500: error
+	mirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(500, "GetPushMirrorsByRepoID", err)
+		return
+	}
+
+	apiMirrors := make([]*api.PushMirror, len(mirrors))
+	for i := range mirrors {
+		apiMirrors[i] = mirrors[i].APIFormat()
+	}
+	ctx.JSON(200, &apiMirrors)
+}
+
+// GetPushMirror gets a push mirror of a repository
+func GetPushMirror(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/push_mirrors/{id} repoGetPushMirror
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: PushMirror
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, m.APIFormat())
+}
+
+// CreatePushMirror adds a push mirror to a repository and pushes to it
+func CreatePushMirror(ctx *context.APIContext, form api.CreatePushMirrorOption) {
+	// swagger:route POST /repos/{username}/{reponame}/push_mirrors repoCreatePushMirror
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: PushMirror
+	//       403: forbidden
+	//       422: validationError
+	//       500: error
+	interval := setting.Mirror.DefaultInterval
+	if len(strings.TrimSpace(form.Interval)) > 0 {
+		var err error
+		interval, err = time.ParseDuration(strings.TrimSpace(form.Interval))
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Error(422, "", fmt.Sprintf("Interval must be 0 or at least %s", setting.Mirror.MinInterval))
+			return
+		}
+	}
+
+	address, err := auth.ParseRemoteAddr(form.RemoteAddress, form.RemoteUsername, form.RemotePassword, ctx.User)
+	if err != nil {
+		if models.IsErrInvalidCloneAddr(err) {
+			addrErr := err.(models.ErrInvalidCloneAddr)
+			switch {
+			case addrErr.IsURLError:
+				ctx.Error(422, "", err)
+			case addrErr.IsPermissionDenied:
+				ctx.Error(422, "", "You are not allowed to push to local repositories.")
+			case addrErr.IsInvalidPath:
+				ctx.Error(422, "", "Invalid local path, it does not exist or not a directory.")
+			default:
+				ctx.Error(500, "ParseRemoteAddr", "Unknown error type (ErrInvalidCloneAddr): "+err.Error())
+			}
+		} else {
+			ctx.Error(500, "ParseRemoteAddr", err)
+		}
+		return
+	}
+
+	m, err := models.AddPushMirror(ctx.Repo.Repository, address, interval)
+	if err != nil {
+		ctx.Error(500, "AddPushMirror", err)
+		return
+	}
+
+	go models.PushMirrorQueue.Add(m.ID)
+	ctx.JSON(201, m.APIFormat())
+}
+
+// DeletePushMirror removes a push mirror from a repository
+func DeletePushMirror(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/push_mirrors/{id} repoDeletePushMirror
+	//
+	//     Responses:
+	//       204: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeletePushMirror(m); err != nil {
+		ctx.Error(500, "DeletePushMirror", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// SyncPushMirror adds a push mirror to the sync queue
+func SyncPushMirror(ctx *context.APIContext) {
+	// swagger:route POST /repos/{username}/{reponame}/push_mirrors/{id}/sync repoSyncPushMirror
+	//
+	//     Responses:
+	//       202: empty
+	//       403: forbidden
+	//       404: notFound
+	//       500: error
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	go models.PushMirrorQueue.Add(m.ID)
+	ctx.Status(202)
+}
@@ -7,10 +7,10 @@ package repo
 import (
 	"strings"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // GetRelease get a single release of a repository
@@ -100,6 +100,8 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 		}
 		return
 	}
+	notification.NotifyNewRelease(rel)
+
 	ctx.JSON(201, rel.APIFormat())
 }
 
@@ -138,7 +140,7 @@ func EditRelease(ctx *context.APIContext, form api.EditReleaseOption) {
 	if form.IsPrerelease != nil {
 		rel.IsPrerelease = *form.IsPrerelease
 	}
-	if err := models.UpdateRelease(ctx.Repo.GitRepo, rel, nil); err != nil {
+	if err := models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, nil); err != nil {
 		ctx.Error(500, "UpdateRelease", err)
 		return
 	}
@@ -7,13 +7,13 @@ package repo
 import (
 	"strings"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 )
 
@@ -36,6 +36,9 @@ func Search(ctx *context.APIContext) {
 	if ctx.User != nil && ctx.User.ID == opts.OwnerID {
 		opts.Searcher = ctx.User
 	}
+	if ctx.AccessToken != nil {
+		opts.RepoIDs = ctx.AccessToken.RepoIDs
+	}
 
 	// Check visibility.
 	if ctx.IsSigned && opts.OwnerID > 0 {
@@ -99,7 +102,7 @@ func Search(ctx *context.APIContext) {
 
 // CreateUserRepo create a repository for a user
 func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateRepoOption) {
-	repo, err := models.CreateRepository(owner, models.CreateRepoOptions{
+	repo, err := models.CreateRepository(ctx.User, owner, models.CreateRepoOptions{
 		Name:        opt.Name,
 		Description: opt.Description,
 		Gitignores:  opt.Gitignores,
@@ -115,7 +118,7 @@ func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateR
 			ctx.Error(422, "", err)
 		} else {
 			if repo != nil {
-				if err = models.DeleteRepository(ctx.User.ID, repo.ID); err != nil {
+				if err = models.DeleteRepository(ctx.User, ctx.User.ID, repo.ID); err != nil {
 					log.Error(4, "DeleteRepository: %v", err)
 				}
 			}
@@ -235,19 +238,21 @@ func Migrate(ctx *context.APIContext, form auth.MigrateRepoForm) {
 		return
 	}
 
-	repo, err := models.MigrateRepository(ctxUser, models.MigrateRepoOptions{
-		Name:        form.RepoName,
-		Description: form.Description,
-		IsPrivate:   form.Private || setting.Repository.ForcePrivate,
-		IsMirror:    form.Mirror,
-		RemoteAddr:  remoteAddr,
+	repo, err := migrations.MigrateRepository(ctx.User, ctxUser, migrations.MigrateOptions{
+		RemoteURL:    remoteAddr,
+		AuthUsername: form.AuthUsername,
+		AuthPassword: form.AuthPassword,
+		Name:         form.RepoName,
+		Description:  form.Description,
+		Private:      form.Private || setting.Repository.ForcePrivate,
+		Mirror:       form.Mirror,
+		Issues:       form.Issues,
+		PullRequests: form.PullRequests,
+		Labels:       form.Labels,
+		Milestones:   form.Milestones,
+		Releases:     form.Releases,
 	})
 	if err != nil {
-		if repo != nil {
-			if errDelete := models.DeleteRepository(ctxUser.ID, repo.ID); errDelete != nil {
-				log.Error(4, "DeleteRepository: %v", errDelete)
-			}
-		}
 		ctx.Error(500, "MigrateRepository", models.HandleCloneUserCredentials(err.Error(), true))
 		return
 	}
@@ -289,6 +294,9 @@ func GetByID(ctx *context.APIContext) {
 			ctx.Error(500, "GetRepositoryByID", err)
 		}
 		return
+	} else if ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(repo.ID) {
+		ctx.Status(404)
+		return
 	}
 
 	access, err := models.AccessLevel(ctx.User.ID, repo)
@@ -323,7 +331,7 @@ func Delete(ctx *context.APIContext) {
 		return
 	}
 
-	if err := models.DeleteRepository(owner.ID, repo.ID); err != nil {
+	if err := models.DeleteRepository(ctx.User, owner.ID, repo.ID); err != nil {
 		ctx.Error(500, "DeleteRepository", err)
 		return
 	}
@@ -0,0 +1,210 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetAnnotatedTag gets an annotated tag of a repository by its SHA
+func GetAnnotatedTag(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/git/tags/{sha} repoGetAnnotatedTag
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: AnnotatedTag
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	tag, err := gitutil.GetAnnotatedTag(gitRepo, ctx.Params(":sha"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetAnnotatedTag", err)
+		}
+		return
+	}
+
+	repoURL := ctx.Repo.Repository.APIURL()
+	apiTag := &api.AnnotatedTag{
+		Tag:     tag.Name,
+		SHA:     tag.ID.String(),
+		URL:     repoURL + "/git/tags/" + tag.ID.String(),
+		Message: tag.Message,
+		Object: &api.AnnotatedTagObject{
+			Type: tag.Type,
+			URL:  repoURL + "/git/" + tag.Type + "s/" + tag.Object.String(),
+			SHA:  tag.Object.String(),
+		},
+	}
+	if tag.Tagger != nil {
+		apiTag.Tagger = toCommitUser(tag.Tagger)
+	}
+	ctx.JSON(200, apiTag)
+}
+
+// toTag converts a Git tag of given repository to its API format
+func toTag(r *models.Repository, t *git.Tag) *api.Tag {
+	return &api.Tag{
+		Name:    t.Name,
+		ID:      t.ID.String(),
+		Message: strings.TrimSpace(t.Message),
+		Commit: &api.CommitMeta{
+			URL: r.APIURL() + "/git/commits/" + t.Object.String(),
+			SHA: t.Object.String(),
+		},
+		ZipballURL: r.HTMLURL() + "/archive/" + t.Name + ".zip",
+		TarballURL: r.HTMLURL() + "/archive/" + t.Name + ".tar.gz",
+	}
+}
+
+// ListTags lists the tags of a repository
+func ListTags(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/tags repoListTags
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: TagList
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	names, err := gitRepo.GetTags()
+	if err != nil {
+		ctx.Error(500, "GetTags", err)
+		return
+	}
+	apiTags := make([]*api.Tag, len(names))
+	for i, name := range names {
+		tag, err := gitRepo.GetTag(name)
+		if err != nil {
+			ctx.Error(500, "GetTag", err)
+			return
+		}
+		apiTags[i] = toTag(ctx.Repo.Repository, tag)
+	}
+	ctx.JSON(200, &apiTags)
+}
+
+// getTag writes the tag of the current repository with given name, or a not found response.
+func getTag(ctx *context.APIContext, status int, name string) {
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+	if !gitRepo.IsTagExist(name) {
+		ctx.Status(404)
+		return
+	}
+	tag, err := gitRepo.GetTag(name)
+	if err != nil {
+		ctx.Error(500, "GetTag", err)
+		return
+	}
+	ctx.JSON(status, toTag(ctx.Repo.Repository, tag))
+}
+
+// GetTag gets a tag of a repository by its name
+func GetTag(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/tags/{tag} repoGetTag
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: Tag
+	//       404: notFound
+	//       500: error
+	getTag(ctx, 200, ctx.Params("*"))
+}
+
+// CreateTag creates a tag in a repository
+func CreateTag(ctx *context.APIContext, form api.CreateTagOption) {
+	// swagger:route POST /repos/{username}/{reponame}/tags repoCreateTag
+	//
+	//     Consumes:
+	//     - application/json
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       201: Tag
+	//       404: notFound
+	//       409: error
+	//       422: validationError
+	//       500: error
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+	if len(form.Target) == 0 {
+		form.Target = ctx.Repo.Repository.DefaultBranch
+	}
+
+	push, err := ctx.Repo.Repository.CreateTag(ctx.User, models.CreateTagOptions{
+		TagName: form.TagName,
+		Target:  form.Target,
+		Message: form.Message,
+	})
+	if err != nil {
+		switch {
+		case models.IsErrTagAlreadyExists(err):
+			ctx.Error(409, "", err)
+		case git.IsErrNotExist(err):
+			ctx.Error(422, "", "target does not exist")
+		case models.IsErrInvalidTagName(err):
+			ctx.Error(422, "", err)
+		default:
+			ctx.Error(500, "CreateTag", err)
+		}
+		return
+	}
+	notification.NotifyPushCommits(push)
+	getTag(ctx, 201, form.TagName)
+}
+
+// DeleteTag deletes a tag of a repository, the tags of releases can not be deleted.
+func DeleteTag(ctx *context.APIContext) {
+	// swagger:route DELETE /repos/{username}/{reponame}/tags/{tag} repoDeleteTag
+	//
+	//     Responses:
+	//       204: empty
+	//       404: notFound
+	//       409: error
+	//       500: error
+	if err := ctx.Repo.Repository.DeleteTag(ctx.User, ctx.Params("*")); err != nil {
+		switch {
+		case models.IsErrTagNotExist(err):
+			ctx.Status(404)
+		case models.IsErrReleaseAlreadyExist(err):
+			ctx.Error(409, "", "tag is used by a release")
+		default:
+			ctx.Error(500, "DeleteTag", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/git"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetTree gets a tree of a repository by its SHA or by the SHA of a commit
+func GetTree(ctx *context.APIContext) {
+	// swagger:route GET /repos/{username}/{reponame}/git/trees/{sha} repoGetTree
+	//
+	//     Produces:
+	//     - application/json
+	//
+	//     Responses:
+	//       200: GitTreeResponse
+	//       404: notFound
+	//       500: error
+	gitRepo := openGitRepo(ctx)
+	if gitRepo == nil {
+		return
+	}
+
+	sha := ctx.Params(":sha")
+	tp, err := gitutil.GetObjectType(gitRepo, sha)
+	if err != nil {
+		ctx.Status(404)
+		return
+	}
+	var treeID git.SHA1
+	switch tp {
+	case git.ObjectCommit:
+		commit, err := gitRepo.GetCommit(sha)
+		if err != nil {
+			ctx.Error(500, "GetCommit", err)
+			return
+		}
+		treeID = commit.Tree.ID
+	case git.ObjectTree:
+		if treeID, err = git.NewIDFromString(sha); err != nil {
+			ctx.Status(404)
+			return
+		}
+	default:
+		ctx.Status(404)
+		return
+	}
+
+	entries, err := gitutil.ListTreeEntries(gitRepo, treeID, ctx.QueryBool("recursive"))
+	if err != nil {
+		ctx.Error(500, "ListTreeEntries", err)
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	perPage := ctx.QueryInt("per_page")
+	if perPage <= 0 || perPage > setting.API.DefaultGitTreesPerPage {
+		perPage = setting.API.DefaultGitTreesPerPage
+	}
+
+	repoURL := ctx.Repo.Repository.APIURL()
+	resp := &api.GitTreeResponse{
+		SHA:        treeID.String(),
+		URL:        repoURL + "/git/trees/" + treeID.String(),
+		Entries:    []api.GitEntry{},
+		Page:       page,
+		TotalCount: len(entries),
+	}
+	start := (page - 1) * perPage
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := start + perPage
+	if end >= len(entries) {
+		end = len(entries)
+	} else {
+		resp.Truncated = true
+	}
+	for _, e := range entries[start:end] {
+		entry := api.GitEntry{
+			Path: e.Path,
+			Mode: e.Mode,
+			Type: string(e.Type),
+			SHA:  e.ID.String(),
+		}
+		switch e.Type {
+		case git.ObjectTree:
+			entry.URL = repoURL + "/git/trees/" + entry.SHA
+		case git.ObjectBlob:
+			entry.Size = e.Size
+			entry.URL = repoURL + "/git/blobs/" + entry.SHA
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+	ctx.JSON(200, resp)
+}
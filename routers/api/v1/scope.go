@@ -0,0 +1,201 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"fmt"
+
+	"gopkg.in/macaron.v1"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// tokenScopeHandler is a handler checking the scopes granted to the access token.
+// Routes declare the scope they require by one of these handlers.
+type tokenScopeHandler func(ctx *context.APIContext)
+
+// reqTokenScope checks if the access token used to sign in is granted the scope area,
+// requests which may modify data require the write level.
+func reqTokenScope(area models.AccessTokenScopeArea) macaron.Handler {
+	return tokenScopeHandler(func(ctx *context.APIContext) {
+		if ctx.AccessToken == nil {
+			return
+		}
+
+		level := models.AccessTokenScopeLevelWrite
+		switch ctx.Req.Method {
+		case "GET", "HEAD", "OPTIONS":
+			level = models.AccessTokenScopeLevelRead
+		}
+		if !ctx.AccessToken.HasScope(area, level) {
+			ctx.Error(403, "", fmt.Sprintf("Access token requires the %s scope", level.Scope(area)))
+			return
+		}
+	})
+}
+
+// reqTokenDeleteRepo checks if the access token is granted to delete repositories.
+func reqTokenDeleteRepo() macaron.Handler {
+	return tokenScopeHandler(func(ctx *context.APIContext) {
+		if ctx.AccessToken != nil && !ctx.AccessToken.CanDeleteRepo() {
+			ctx.Error(403, "", fmt.Sprintf("Access token requires the %s scope", models.AccessTokenScopeDeleteRepo))
+			return
+		}
+	})
+}
+
+// noTokenScope declares a route which does not access any account data,
+// so any access token may use it.
+func noTokenScope() macaron.Handler {
+	return tokenScopeHandler(func(ctx *context.APIContext) {})
+}
+
+// reqDeclaredTokenScope is added to every route which does not declare a scope,
+// only access tokens granted all scopes may use such routes.
+func reqDeclaredTokenScope() macaron.Handler {
+	return tokenScopeHandler(func(ctx *context.APIContext) {
+		if ctx.AccessToken != nil && !ctx.AccessToken.HasAllScopes() {
+			ctx.Error(403, "", fmt.Sprintf("Access token requires the %s scope", models.AccessTokenScopeAll))
+			return
+		}
+	})
+}
+
+func hasTokenScopeHandler(handlers []macaron.Handler) bool {
+	for _, h := range handlers {
+		if _, ok := h.(tokenScopeHandler); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedRoute describes a route registered through a scopedRouter.
+type scopedRoute struct {
+	Method  string
+	Pattern string
+	// Declared is false if the route does not check the token scopes itself
+	// and falls back to reqDeclaredTokenScope.
+	Declared bool
+}
+
+// scopedRouter registers API routes and makes sure every one of them checks the
+// scopes of the access token. Routes without a scope handler in their group or
+// route handlers deny access tokens which are not granted all scopes.
+type scopedRouter struct {
+	m      *macaron.Macaron
+	groups []scopedGroup
+	routes []scopedRoute
+}
+
+type scopedGroup struct {
+	pattern  string
+	handlers []macaron.Handler
+}
+
+func newScopedRouter(m *macaron.Macaron) *scopedRouter {
+	return &scopedRouter{m: m}
+}
+
+// handlers returns the route handlers with reqDeclaredTokenScope in front
+// if neither the groups nor the route declare a scope.
+func (r *scopedRouter) handlers(method, pattern string, preceding, h []macaron.Handler) []macaron.Handler {
+	route := scopedRoute{Method: method}
+	all := make([]macaron.Handler, 0, len(preceding)+len(h))
+	for _, g := range r.groups {
+		route.Pattern += g.pattern
+		all = append(all, g.handlers...)
+	}
+	route.Pattern += pattern
+	all = append(all, preceding...)
+	all = append(all, h...)
+
+	route.Declared = hasTokenScopeHandler(all)
+	r.routes = append(r.routes, route)
+	if route.Declared {
+		return h
+	}
+	return append([]macaron.Handler{reqDeclaredTokenScope()}, h...)
+}
+
+// Group registers a group of routes
+func (r *scopedRouter) Group(pattern string, fn func(), h ...macaron.Handler) {
+	r.groups = append(r.groups, scopedGroup{pattern, h})
+	r.m.Group(pattern, fn, h...)
+	r.groups = r.groups[:len(r.groups)-1]
+}
+
+// Get registers a GET route
+func (r *scopedRouter) Get(pattern string, h ...macaron.Handler) {
+	r.m.Get(pattern, r.handlers("GET", pattern, nil, h)...)
+}
+
+// Post registers a POST route
+func (r *scopedRouter) Post(pattern string, h ...macaron.Handler) {
+	r.m.Post(pattern, r.handlers("POST", pattern, nil, h)...)
+}
+
+// Put registers a PUT route
+func (r *scopedRouter) Put(pattern string, h ...macaron.Handler) {
+	r.m.Put(pattern, r.handlers("PUT", pattern, nil, h)...)
+}
+
+// Patch registers a PATCH route
+func (r *scopedRouter) Patch(pattern string, h ...macaron.Handler) {
+	r.m.Patch(pattern, r.handlers("PATCH", pattern, nil, h)...)
+}
+
+// Delete registers a DELETE route
+func (r *scopedRouter) Delete(pattern string, h ...macaron.Handler) {
+	r.m.Delete(pattern, r.handlers("DELETE", pattern, nil, h)...)
+}
+
+// Any registers a route for all methods
+func (r *scopedRouter) Any(pattern string, h ...macaron.Handler) {
+	r.m.Any(pattern, r.handlers("*", pattern, nil, h)...)
+}
+
+// Combo registers several methods for the same pattern
+func (r *scopedRouter) Combo(pattern string, h ...macaron.Handler) *scopedComboRouter {
+	return &scopedComboRouter{r, r.m.Combo(pattern, h...), pattern, h}
+}
+
+type scopedComboRouter struct {
+	r        *scopedRouter
+	cr       *macaron.ComboRouter
+	pattern  string
+	handlers []macaron.Handler
+}
+
+// Get registers the GET method of the combo
+func (c *scopedComboRouter) Get(h ...macaron.Handler) *scopedComboRouter {
+	c.cr.Get(c.r.handlers("GET", c.pattern, c.handlers, h)...)
+	return c
+}
+
+// Post registers the POST method of the combo
+func (c *scopedComboRouter) Post(h ...macaron.Handler) *scopedComboRouter {
+	c.cr.Post(c.r.handlers("POST", c.pattern, c.handlers, h)...)
+	return c
+}
+
+// Put registers the PUT method of the combo
+func (c *scopedComboRouter) Put(h ...macaron.Handler) *scopedComboRouter {
+	c.cr.Put(c.r.handlers("PUT", c.pattern, c.handlers, h)...)
+	return c
+}
+
+// Patch registers the PATCH method of the combo
+func (c *scopedComboRouter) Patch(h ...macaron.Handler) *scopedComboRouter {
+	c.cr.Patch(c.r.handlers("PATCH", c.pattern, c.handlers, h)...)
+	return c
+}
+
+// Delete registers the DELETE method of the combo
+func (c *scopedComboRouter) Delete(h ...macaron.Handler) *scopedComboRouter {
+	c.cr.Delete(c.r.handlers("DELETE", c.pattern, c.handlers, h)...)
+	return c
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/macaron.v1"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+func TestRoutesDeclareTokenScope(t *testing.T) {
+	r := newScopedRouter(macaron.New())
+	registerRoutes(r)
+
+	assert.NotEmpty(t, r.routes)
+	for _, route := range r.routes {
+		assert.True(t, route.Declared, "%s %s does not declare a token scope", route.Method, route.Pattern)
+	}
+}
+
+func TestUndeclaredRouteDeniesScopedToken(t *testing.T) {
+	var token *models.AccessToken
+	m := macaron.New()
+	m.Use(macaron.Renderer())
+	m.Use(func(c *macaron.Context) {
+		c.Map(&context.APIContext{Context: &context.Context{Context: c, AccessToken: token}})
+	})
+
+	r := newScopedRouter(m)
+	r.Get("/undeclared", func(ctx *context.APIContext) {
+		ctx.Status(200)
+	})
+	assert.Len(t, r.routes, 1)
+	assert.False(t, r.routes[0].Declared)
+
+	test := func(accessToken *models.AccessToken, expectedStatus int) {
+		token = accessToken
+		resp := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/undeclared", nil)
+		assert.NoError(t, err)
+		m.ServeHTTP(resp, req)
+		assert.EqualValues(t, expectedStatus, resp.Code)
+	}
+	test(nil, 200)
+	test(&models.AccessToken{Scopes: []string{models.AccessTokenScopeAll}}, 200)
+	test(&models.AccessToken{Scopes: []string{"write:repo", "write:user"}}, 403)
+}
@@ -5,10 +5,12 @@
 package user
 
 import (
-	api "code.gitea.io/sdk/gitea"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/convert"
 )
 
 // ListAccessTokens list all the access tokens
@@ -30,10 +32,12 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{
-			Name: tokens[i].Name,
-			Sha1: tokens[i].Sha1,
+		repos, err := tokens[i].GetRepositories()
+		if err != nil {
+			ctx.Error(500, "GetRepositories", err)
+			return
 		}
+		apiTokens[i] = convert.ToAccessToken(tokens[i], repos)
 	}
 	ctx.JSON(200, &apiTokens)
 }
@@ -49,19 +53,50 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 	//     - application/json
 	//
 	//     Responses:
-	//       200: AccessToken
+	//       201: AccessToken
+	//       422: validationError
 	//       500: error
 
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:    ctx.User.ID,
+		Name:   form.Name,
+		Scopes: form.Scopes,
 	}
-	if err := models.NewAccessToken(t); err != nil {
-		ctx.Error(500, "NewAccessToken", err)
+	// Tokens have full access unless scopes are given.
+	if len(t.Scopes) == 0 {
+		t.Scopes = []string{models.AccessTokenScopeAll}
+	}
+	if form.Expires != nil {
+		if !form.Expires.After(time.Now()) {
+			ctx.Error(422, "", "Expiration date must be in the future")
+			return
+		}
+		t.ExpiresUnix = form.Expires.Unix()
+		t.Expires = *form.Expires
+	}
+
+	var err error
+	if t.RepoIDs, err = models.GetReadableRepoIDsByFullNames(ctx.User, form.Repositories); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "GetReadableRepoIDsByFullNames", err)
+		}
+		return
+	}
+	repos, err := t.GetRepositories()
+	if err != nil {
+		ctx.Error(500, "GetRepositories", err)
+		return
+	}
+
+	if err = models.NewAccessToken(t); err != nil {
+		if models.IsErrInvalidAccessTokenScope(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "NewAccessToken", err)
+		}
 		return
 	}
-	ctx.JSON(201, &api.AccessToken{
-		Name: t.Name,
-		Sha1: t.Sha1,
-	})
+	ctx.JSON(201, convert.ToAccessToken(t, repos))
 }
@@ -5,10 +5,9 @@
 package user
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 func responseAPIUsers(ctx *context.APIContext, users []*models.User) {
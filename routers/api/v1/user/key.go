@@ -5,16 +5,15 @@
 package user
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 )
 
-// GetUserByParamsName get user by name
+// GetUserByParamsName get user by name, it is not found if the signed user can not see it
 func GetUserByParamsName(ctx *context.APIContext, name string) *models.User {
 	user, err := models.GetUserByName(ctx.Params(name))
 	if err != nil {
@@ -24,6 +23,9 @@ func GetUserByParamsName(ctx *context.APIContext, name string) *models.User {
 			ctx.Error(500, "GetUserByName", err)
 		}
 		return nil
+	} else if !user.IsVisibleTo(ctx.User) {
+		ctx.Status(404)
+		return nil
 	}
 	return user
 }
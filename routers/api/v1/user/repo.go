@@ -7,7 +7,7 @@ package user
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
-	api "code.gitea.io/sdk/gitea"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // listUserRepos - List the repositories owned by the given user.
@@ -18,18 +18,21 @@ func listUserRepos(ctx *context.APIContext, u *models.User) {
 		ctx.Error(500, "GetUserRepositories", err)
 		return
 	}
-	apiRepos := make([]*api.Repository, len(repos))
+	apiRepos := make([]*api.Repository, 0, len(repos))
 	var ctxUserID int64
 	if ctx.User != nil {
 		ctxUserID = ctx.User.ID
 	}
 	for i := range repos {
+		if ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(repos[i].ID) {
+			continue
+		}
 		access, err := models.AccessLevel(ctxUserID, repos[i])
 		if err != nil {
 			ctx.Error(500, "AccessLevel", err)
 			return
 		}
-		apiRepos[i] = repos[i].APIFormat(access)
+		apiRepos = append(apiRepos, repos[i].APIFormat(access))
 	}
 	ctx.JSON(200, &apiRepos)
 }
@@ -73,14 +76,18 @@ func ListMyRepos(ctx *context.APIContext) {
 		return
 	}
 
-	apiRepos := make([]*api.Repository, len(ownRepos)+len(accessibleReposMap))
+	apiRepos := make([]*api.Repository, 0, len(ownRepos)+len(accessibleReposMap))
 	for i := range ownRepos {
-		apiRepos[i] = ownRepos[i].APIFormat(models.AccessModeOwner)
+		if ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(ownRepos[i].ID) {
+			continue
+		}
+		apiRepos = append(apiRepos, ownRepos[i].APIFormat(models.AccessModeOwner))
 	}
-	i := len(ownRepos)
 	for repo, access := range accessibleReposMap {
-		apiRepos[i] = repo.APIFormat(access)
-		i++
+		if ctx.AccessToken != nil && !ctx.AccessToken.CanAccessRepo(repo.ID) {
+			continue
+		}
+		apiRepos = append(apiRepos, repo.APIFormat(access))
 	}
 	ctx.JSON(200, &apiRepos)
 }
@@ -9,10 +9,9 @@ import (
 
 	"github.com/Unknwon/com"
 
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 )
 
 // Search search users
@@ -29,6 +28,7 @@ func Search(ctx *context.APIContext) {
 	opts := &models.SearchUserOptions{
 		Keyword:  strings.Trim(ctx.Query("q"), " "),
 		Type:     models.UserTypeIndividual,
+		Searcher: ctx.User,
 		PageSize: com.StrTo(ctx.Query("limit")).MustInt(),
 	}
 	if opts.PageSize == 0 {
@@ -75,13 +75,8 @@ func GetInfo(ctx *context.APIContext) {
 	//       404: notFound
 	//       500: error
 
-	u, err := models.GetUserByName(ctx.Params(":username"))
-	if err != nil {
-		if models.IsErrUserNotExist(err) {
-			ctx.Status(404)
-		} else {
-			ctx.Error(500, "GetUserByName", err)
-		}
+	u := GetUserByParams(ctx)
+	if ctx.Written() {
 		return
 	}
 
@@ -5,10 +5,9 @@
 package utils
 
 import (
-	api "code.gitea.io/sdk/gitea"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"encoding/json"
 	"github.com/Unknwon/com"
@@ -97,9 +96,15 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		HookEvent: &models.HookEvent{
 			ChooseEvents: true,
 			HookEvents: models.HookEvents{
-				Create:      com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
-				Push:        com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
-				PullRequest: com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				Create:       com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
+				Delete:       com.IsSliceContainsStr(form.Events, string(models.HookEventDelete)),
+				Fork:         com.IsSliceContainsStr(form.Events, string(models.HookEventFork)),
+				Issues:       com.IsSliceContainsStr(form.Events, string(models.HookEventIssues)),
+				IssueComment: com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment)),
+				Push:         com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
+				PullRequest:  com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				Repository:   com.IsSliceContainsStr(form.Events, string(models.HookEventRepository)),
+				Release:      com.IsSliceContainsStr(form.Events, string(models.HookEventRelease)),
 			},
 		},
 		IsActive:     form.Active,
@@ -122,6 +127,16 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 			return nil, false
 		}
 		w.Meta = string(meta)
+	} else if w.HookTaskType == models.DISCORD {
+		meta, err := json.Marshal(&models.DiscordMeta{
+			Username: form.Config["username"],
+			IconURL:  form.Config["icon_url"],
+		})
+		if err != nil {
+			ctx.Error(500, "discord: JSON marshal failed", err)
+			return nil, false
+		}
+		w.Meta = string(meta)
 	}
 
 	if err := w.UpdateEvent(); err != nil {
@@ -197,6 +212,20 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 				}
 				w.Meta = string(meta)
 			}
+		} else if w.HookTaskType == models.DISCORD {
+			discord := w.GetDiscordHook()
+			if username, ok := form.Config["username"]; ok {
+				discord.Username = username
+			}
+			if iconURL, ok := form.Config["icon_url"]; ok {
+				discord.IconURL = iconURL
+			}
+			meta, err := json.Marshal(discord)
+			if err != nil {
+				ctx.Error(500, "discord: JSON marshal failed", err)
+				return false
+			}
+			w.Meta = string(meta)
 		}
 	}
 
@@ -208,8 +237,14 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.SendEverything = false
 	w.ChooseEvents = true
 	w.Create = com.IsSliceContainsStr(form.Events, string(models.HookEventCreate))
+	w.Delete = com.IsSliceContainsStr(form.Events, string(models.HookEventDelete))
+	w.Fork = com.IsSliceContainsStr(form.Events, string(models.HookEventFork))
+	w.Issues = com.IsSliceContainsStr(form.Events, string(models.HookEventIssues))
+	w.IssueComment = com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment))
 	w.Push = com.IsSliceContainsStr(form.Events, string(models.HookEventPush))
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest))
+	w.Repository = com.IsSliceContainsStr(form.Events, string(models.HookEventRepository))
+	w.Release = com.IsSliceContainsStr(form.Events, string(models.HookEventRelease))
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(500, "UpdateEvent", err)
 		return false
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/search"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/routers/user"
 
@@ -28,6 +29,8 @@ const (
 	tplExploreUsers base.TplName = "explore/users"
 	// tplExploreOrganizations explore organizations page template
 	tplExploreOrganizations base.TplName = "explore/organizations"
+	// tplExploreCode explore code page template
+	tplExploreCode base.TplName = "explore/code"
 )
 
 // Home render home page
@@ -164,9 +167,10 @@ func ExploreRepos(ctx *context.Context) {
 // UserSearchOptions options when render search user page
 type UserSearchOptions struct {
 	Type     models.UserType
-	Counter  func() int64
-	Ranger   func(*models.SearchUserOptions) ([]*models.User, error)
+	Ranger   func(*models.SearchUserOptions) ([]*models.User, int64, error)
 	PageSize int
+	Searcher *models.User
+	Private  bool
 	TplName  base.TplName
 }
 
@@ -202,8 +206,10 @@ func RenderUserSearch(ctx *context.Context, opts *UserSearchOptions) {
 
 	keyword := strings.Trim(ctx.Query("q"), " ")
 	if len(keyword) == 0 {
-		users, err = opts.Ranger(&models.SearchUserOptions{
+		users, count, err = opts.Ranger(&models.SearchUserOptions{
 			OrderBy:  orderBy,
+			Searcher: opts.Searcher,
+			Private:  opts.Private,
 			Page:     page,
 			PageSize: opts.PageSize,
 		})
@@ -211,13 +217,14 @@ func RenderUserSearch(ctx *context.Context, opts *UserSearchOptions) {
 			ctx.Handle(500, "opts.Ranger", err)
 			return
 		}
-		count = opts.Counter()
 	} else {
 		if isKeywordValid(keyword) {
 			users, count, err = models.SearchUserByName(&models.SearchUserOptions{
 				Keyword:  keyword,
 				Type:     opts.Type,
 				OrderBy:  orderBy,
+				Searcher: opts.Searcher,
+				Private:  opts.Private,
 				Page:     page,
 				PageSize: opts.PageSize,
 			})
@@ -244,9 +251,9 @@ func ExploreUsers(ctx *context.Context) {
 
 	RenderUserSearch(ctx, &UserSearchOptions{
 		Type:     models.UserTypeIndividual,
-		Counter:  models.CountUsers,
 		Ranger:   models.Users,
 		PageSize: setting.UI.ExplorePagingNum,
+		Searcher: ctx.User,
 		TplName:  tplExploreUsers,
 	})
 }
@@ -259,13 +266,76 @@ func ExploreOrganizations(ctx *context.Context) {
 
 	RenderUserSearch(ctx, &UserSearchOptions{
 		Type:     models.UserTypeOrganization,
-		Counter:  models.CountOrganizations,
 		Ranger:   models.Organizations,
 		PageSize: setting.UI.ExplorePagingNum,
+		Searcher: ctx.User,
 		TplName:  tplExploreOrganizations,
 	})
 }
 
+// ExploreCode render explore code page
+func ExploreCode(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreCode"] = true
+
+	keyword := strings.TrimSpace(ctx.Query("q"))
+	isFilename := ctx.Query("t") == "filename"
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	// site admins can search the code of all repositories
+	var repoIDs []int64
+	if ctx.User == nil || !ctx.User.IsAdmin {
+		var err error
+		repoIDs, err = models.FindUserAccessibleRepoIDs(ctx.User)
+		if err != nil {
+			ctx.Handle(500, "FindUserAccessibleRepoIDs", err)
+			return
+		}
+	}
+
+	var (
+		total         int
+		searchResults []*search.Result
+	)
+	if isKeywordValid(keyword) {
+		var err error
+		total, searchResults, err = search.PerformSearch(repoIDs, keyword, isFilename, page, setting.UI.RepoSearchPagingNum)
+		if err != nil {
+			ctx.Handle(500, "SearchResults", err)
+			return
+		}
+	}
+
+	resultRepoIDs := make([]int64, 0, len(searchResults))
+	for _, result := range searchResults {
+		resultRepoIDs = append(resultRepoIDs, result.RepoID)
+	}
+	repoMaps, err := models.GetRepositoriesMapByIDs(resultRepoIDs)
+	if err != nil {
+		ctx.Handle(500, "GetRepositoriesMapByIDs", err)
+		return
+	}
+	for _, repo := range repoMaps {
+		if err = repo.GetOwner(); err != nil {
+			ctx.Handle(500, "GetOwner", err)
+			return
+		}
+	}
+
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["IsFilename"] = isFilename
+	ctx.Data["RepoMaps"] = repoMaps
+	ctx.Data["SearchResults"] = searchResults
+	ctx.Data["Total"] = total
+	ctx.Data["Page"] = paginater.New(total, setting.UI.RepoSearchPagingNum, page, 5)
+
+	ctx.HTML(200, tplExploreCode)
+}
+
 // NotFound render 404 page
 func NotFound(ctx *context.Context) {
 	ctx.Data["Title"] = "Page Not Found"
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/ssh"
+	"code.gitea.io/gitea/modules/storage"
 	macaron "gopkg.in/macaron.v1"
 )
 
@@ -51,6 +52,9 @@ func GlobalInit() {
 	if setting.InstallLock {
 		highlight.NewContext()
 		markdown.NewSanitizer()
+		if err := storage.Init(); err != nil {
+			log.Fatal(4, "Failed to initialize object storages: %v", err)
+		}
 		if err := models.NewEngine(migrations.Migrate); err != nil {
 			log.Fatal(4, "Failed to initialize ORM engine: %v", err)
 		}
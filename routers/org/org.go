@@ -44,6 +44,7 @@ func CreatePost(ctx *context.Context, form auth.CreateOrgForm) {
 		IsActive: true,
 		Type:     models.UserTypeOrganization,
 	}
+	org.Visibility, _ = models.ParseVisibleType(form.Visibility)
 
 	if err := models.CreateOrganization(org, ctx.User); err != nil {
 		ctx.Data["Err_OrgName"] = true
@@ -79,6 +79,9 @@ func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 	org.Description = form.Description
 	org.Website = form.Website
 	org.Location = form.Location
+	if len(form.Visibility) > 0 {
+		org.Visibility, _ = models.ParseVisibleType(form.Visibility)
+	}
 	if err := models.UpdateUser(org); err != nil {
 		ctx.Handle(500, "UpdateUser", err)
 		return
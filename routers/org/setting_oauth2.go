@@ -0,0 +1,72 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/user"
+)
+
+const (
+	// tplSettingsApplications template path for render OAuth2 application settings
+	tplSettingsApplications base.TplName = "org/settings/applications"
+	// tplSettingsOAuthApplicationEdit template path for render OAuth2 application edit page
+	tplSettingsOAuthApplicationEdit base.TplName = "org/settings/applications_oauth2_edit"
+)
+
+func oauth2Handlers(ctx *context.Context) *user.OAuth2CommonHandlers {
+	return &user.OAuth2CommonHandlers{
+		OwnerID:            ctx.Org.Organization.ID,
+		BasePathList:       ctx.Org.OrgLink + "/settings/applications",
+		BasePathEditPrefix: ctx.Org.OrgLink + "/settings/applications/oauth2",
+		TemplateEdit:       tplSettingsOAuthApplicationEdit,
+	}
+}
+
+// Applications render the OAuth2 applications of the organization
+func Applications(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	if !oauth2Handlers(ctx).RenderApplications(ctx) {
+		return
+	}
+	ctx.HTML(200, tplSettingsApplications)
+}
+
+// OAuthApplicationsPost creates an OAuth2 application of the organization
+func OAuthApplicationsPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	oauth2Handlers(ctx).AddApp(ctx, form)
+}
+
+// OAuthApplicationEdit renders the edit page of an OAuth2 application of the organization
+func OAuthApplicationEdit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	oauth2Handlers(ctx).EditShow(ctx)
+}
+
+// OAuthApplicationEditPost saves an OAuth2 application of the organization
+func OAuthApplicationEditPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	oauth2Handlers(ctx).EditSave(ctx, form)
+}
+
+// OAuthApplicationRegenerateSecret regenerates the client secret of an OAuth2 application of the organization
+func OAuthApplicationRegenerateSecret(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	oauth2Handlers(ctx).RegenerateSecret(ctx)
+}
+
+// DeleteOAuthApplication deletes an OAuth2 application of the organization
+func DeleteOAuthApplication(ctx *context.Context) {
+	oauth2Handlers(ctx).DeleteApp(ctx)
+}
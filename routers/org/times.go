@@ -0,0 +1,102 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const (
+	// tplTimes template path for the time tracking report page
+	tplTimes base.TplName = "org/times"
+
+	timesDateFormat = "2006-01-02"
+)
+
+// trackedTimeSums returns the report of the times tracked on the issues of the
+// organization's repositories between the since and before dates, both included.
+func trackedTimeSums(ctx *context.Context) models.TrackedTimeSums {
+	opts := models.FindTrackedTimesOptions{
+		OwnerID: ctx.Org.Organization.ID,
+	}
+	since, before := ctx.Query("since"), ctx.Query("before")
+	if len(since) > 0 {
+		t, err := time.ParseInLocation(timesDateFormat, since, time.Local)
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("org.times.invalid_date", since), true)
+		} else {
+			opts.CreatedAfterUnix = t.Unix()
+		}
+	}
+	if len(before) > 0 {
+		t, err := time.ParseInLocation(timesDateFormat, before, time.Local)
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("org.times.invalid_date", before), true)
+		} else {
+			opts.CreatedBeforeUnix = t.AddDate(0, 0, 1).Unix()
+		}
+	}
+	ctx.Data["Since"] = since
+	ctx.Data["Before"] = before
+
+	sums, err := models.GetTrackedTimeSums(opts)
+	if err != nil {
+		ctx.Handle(500, "GetTrackedTimeSums", err)
+		return nil
+	}
+	return sums
+}
+
+// Times renders the time tracking report of the organization
+func Times(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.times")
+	ctx.Data["PageIsOrgTimes"] = true
+
+	sums := trackedTimeSums(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["TimeSums"] = sums
+
+	ctx.HTML(200, tplTimes)
+}
+
+// TimesExport exports the time tracking report of the organization as CSV
+func TimesExport(ctx *context.Context) {
+	sums := trackedTimeSums(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{"repository", "milestone", "user", "seconds", "time"}}
+	for _, sum := range sums {
+		var milestone string
+		if sum.Milestone != nil {
+			milestone = sum.Milestone.Name
+		}
+		records = append(records, []string{
+			sum.Repo.FullName(),
+			milestone,
+			sum.User.Name,
+			fmt.Sprint(sum.Time),
+			sum.TimeString(),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		ctx.Handle(500, "WriteAll", err)
+		return
+	}
+
+	ctx.ServeContent(fmt.Sprintf("%s-times.csv", ctx.Org.Organization.Name), bytes.NewReader(buf.Bytes()))
+}
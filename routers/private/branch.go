@@ -5,7 +5,11 @@
 package private
 
 import (
+	"encoding/json"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/gitutil"
+	"code.gitea.io/gitea/modules/private"
 
 	macaron "gopkg.in/macaron.v1"
 )
@@ -28,3 +32,71 @@ func GetProtectedBranchBy(ctx *macaron.Context) {
 		})
 	}
 }
+
+// CanUserPush returns if user push
+func CanUserPush(ctx *macaron.Context) {
+	pbID := ctx.ParamsInt64(":pbid")
+	userID := ctx.ParamsInt64(":userid")
+
+	protectBranch, err := models.GetProtectedBranchByID(pbID)
+	if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	} else if protectBranch != nil {
+		ctx.JSON(200, map[string]interface{}{
+			"can_push": protectBranch.CanUserPush(userID),
+		})
+	} else {
+		ctx.JSON(200, map[string]interface{}{
+			"can_push": false,
+		})
+	}
+}
+
+// VerifyCommits returns the first of the pushed commits of the repository
+// whose signature can not be verified
+func VerifyCommits(ctx *macaron.Context) {
+	repoID := ctx.ParamsInt64(":repoid")
+	var opts private.VerifyCommitsOptions
+	if err := json.NewDecoder(ctx.Req.Request.Body).Decode(&opts); err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+	commits, err := gitutil.GetCommitsInRange(repo.RepoPath(), opts.Env(), opts.OldCommitID, opts.NewCommitID)
+	if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+
+	if err := models.VerifyCommits(commits); models.IsErrUnverifiedCommit(err) {
+		unverified := err.(models.ErrUnverifiedCommit)
+		ctx.JSON(200, map[string]interface{}{
+			"verified":  false,
+			"commit_id": unverified.ID,
+			"reason":    unverified.Reason,
+		})
+		return
+	} else if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(200, map[string]interface{}{
+		"verified": true,
+	})
+}
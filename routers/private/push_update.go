@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 
 	macaron "gopkg.in/macaron.v1"
 )
@@ -32,13 +33,14 @@ func PushUpdate(ctx *macaron.Context) {
 		return
 	}
 
-	repo, err := models.PushUpdate(opt)
+	repo, push, err := models.PushUpdate(opt)
 	if err != nil {
 		ctx.JSON(500, map[string]interface{}{
 			"err": err.Error(),
 		})
 		return
 	}
+	notification.NotifyPushCommits(push)
 
 	pusher, err := models.GetUserByID(opt.PusherID)
 	if err != nil {
@@ -55,6 +57,7 @@ func PushUpdate(ctx *macaron.Context) {
 	log.Trace("TriggerTask '%s/%s' by %s", repo.Name, branch, pusher.Name)
 
 	go models.HookQueue.Add(repo.ID)
+	go models.AddPushMirrorsToQueue(repo.ID)
 	go models.AddTestPullRequestTask(pusher, repo.ID, branch, true)
 	ctx.Status(202)
 }
@@ -105,7 +105,7 @@ func HTTP(ctx *context.Context) {
 	}
 
 	// Only public pull don't need auth.
-	isPublicPull := !repo.IsPrivate && isPull
+	isPublicPull := !repo.IsPrivate && repoUser.Visibility == models.VisibleTypePublic && isPull
 	var (
 		askAuth      = !isPublicPull || setting.Service.RequireSignInView
 		authUser     *models.User
@@ -167,6 +167,20 @@ func HTTP(ctx *context.Context) {
 					}
 					return
 				}
+				if token.IsExpired() {
+					ctx.HandleText(http.StatusUnauthorized, "expired token")
+					return
+				}
+
+				scopeLevel := models.AccessTokenScopeLevelWrite
+				if isPull {
+					scopeLevel = models.AccessTokenScopeLevelRead
+				}
+				if !token.HasScope(models.AccessTokenScopeAreaRepo, scopeLevel) || !token.CanAccessRepo(repo.ID) {
+					ctx.HandleText(http.StatusForbidden, "token permission denied")
+					return
+				}
+
 				token.Updated = time.Now()
 				if err = models.UpdateAccessToken(token); err != nil {
 					ctx.Handle(http.StatusInternalServerError, "UpdateAccessToken", err)
@@ -349,7 +349,7 @@ func NewIssue(ctx *context.Context) {
 }
 
 // ValidateRepoMetas check and returns repository's meta informations
-func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64, int64, int64) {
+func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64, []int64, int64) {
 	var (
 		repo = ctx.Repo.Repository
 		err  error
@@ -357,11 +357,11 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 
 	labels := RetrieveRepoMetas(ctx, ctx.Repo.Repository)
 	if ctx.Written() {
-		return nil, 0, 0
+		return nil, nil, 0
 	}
 
 	if !ctx.Repo.IsWriter() {
-		return nil, 0, 0
+		return nil, nil, 0
 	}
 
 	var labelIDs []int64
@@ -370,7 +370,7 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 	if len(form.LabelIDs) > 0 {
 		labelIDs, err = base.StringsToInt64s(strings.Split(form.LabelIDs, ","))
 		if err != nil {
-			return nil, 0, 0
+			return nil, nil, 0
 		}
 		labelIDMark := base.Int64sToMap(labelIDs)
 
@@ -392,23 +392,30 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 		ctx.Data["Milestone"], err = repo.GetMilestoneByID(milestoneID)
 		if err != nil {
 			ctx.Handle(500, "GetMilestoneByID", err)
-			return nil, 0, 0
+			return nil, nil, 0
 		}
 		ctx.Data["milestone_id"] = milestoneID
 	}
 
-	// Check assignee.
-	assigneeID := form.AssigneeID
-	if assigneeID > 0 {
-		ctx.Data["Assignee"], err = repo.GetAssigneeByID(assigneeID)
+	// Check assignees.
+	var assigneeIDs []int64
+	if len(form.AssigneeIDs) > 0 {
+		assigneeIDs, err = base.StringsToInt64s(strings.Split(form.AssigneeIDs, ","))
 		if err != nil {
-			ctx.Handle(500, "GetAssigneeByID", err)
-			return nil, 0, 0
+			return nil, nil, 0
+		}
+
+		for _, assigneeID := range assigneeIDs {
+			if _, err = repo.GetAssigneeByID(assigneeID); err != nil {
+				ctx.Handle(500, "GetAssigneeByID", err)
+				return nil, nil, 0
+			}
 		}
-		ctx.Data["assignee_id"] = assigneeID
 	}
+	ctx.Data["SelectedAssigneeIDs"] = base.Int64sToMap(assigneeIDs)
+	ctx.Data["assignee_ids"] = form.AssigneeIDs
 
-	return labelIDs, milestoneID, assigneeID
+	return labelIDs, assigneeIDs, milestoneID
 }
 
 // NewIssuePost response for creating new issue
@@ -424,7 +431,7 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		attachments []string
 	)
 
-	labelIDs, milestoneID, assigneeID := ValidateRepoMetas(ctx, form)
+	labelIDs, assigneeIDs, milestoneID := ValidateRepoMetas(ctx, form)
 	if ctx.Written() {
 		return
 	}
@@ -444,15 +451,14 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		PosterID:    ctx.User.ID,
 		Poster:      ctx.User,
 		MilestoneID: milestoneID,
-		AssigneeID:  assigneeID,
 		Content:     form.Content,
 	}
-	if err := models.NewIssue(repo, issue, labelIDs, attachments); err != nil {
+	if err := models.NewIssue(repo, issue, labelIDs, assigneeIDs, attachments); err != nil {
 		ctx.Handle(500, "NewIssue", err)
 		return
 	}
 
-	notification.Service.NotifyIssue(issue, ctx.User.ID)
+	notification.NotifyNewIssue(issue)
 
 	log.Trace("Issue created: %d/%d", repo.ID, issue.ID)
 	ctx.Redirect(ctx.Repo.RepoLink + "/issues/" + com.ToStr(issue.Index))
@@ -622,6 +628,24 @@ func ViewIssue(ctx *context.Context) {
 			if !isAdded && !issue.IsPoster(comment.Poster.ID) {
 				participants = append(participants, comment.Poster)
 			}
+		} else if comment.Type == models.CommentTypeReview {
+			if err = comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
+				ctx.Handle(500, "LoadReview", err)
+				return
+			}
+			if comment.Review == nil {
+				continue
+			}
+			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
+				ctx.Repo.Repository.ComposeMetas()))
+			if err = comment.Review.LoadCodeComments(); err != nil {
+				ctx.Handle(500, "LoadCodeComments", err)
+				return
+			}
+			for _, codeComment := range comment.Review.CodeComments {
+				codeComment.RenderedContent = string(markdown.Render([]byte(codeComment.Content), ctx.Repo.RepoLink,
+					ctx.Repo.Repository.ComposeMetas()))
+			}
 		} else if comment.Type == models.CommentTypeLabel {
 			if err = comment.LoadLabel(); err != nil {
 				ctx.Handle(500, "LoadLabel", err)
@@ -643,10 +667,23 @@ func ViewIssue(ctx *context.Context) {
 				comment.Milestone = ghostMilestone
 			}
 		} else if comment.Type == models.CommentTypeAssignees {
-			if err = comment.LoadAssignees(); err != nil {
-				ctx.Handle(500, "LoadAssignees", err)
+			if err = comment.LoadAssigneeUser(); err != nil {
+				ctx.Handle(500, "LoadAssigneeUser", err)
+				return
+			}
+		} else if comment.Type == models.CommentTypeAddDependency || comment.Type == models.CommentTypeRemoveDependency {
+			if err = comment.LoadDepIssueDetails(); err != nil && !models.IsErrIssueNotExist(err) {
+				ctx.Handle(500, "LoadDepIssueDetails", err)
 				return
 			}
+			if comment.DependentIssue != nil {
+				if readable, err := canReadDependency(ctx, comment.DependentIssue); err != nil {
+					ctx.Handle(500, "canReadDependency", err)
+					return
+				} else if !readable {
+					comment.DependentIssue = nil
+				}
+			}
 		}
 	}
 
@@ -654,6 +691,40 @@ func ViewIssue(ctx *context.Context) {
 		pull := issue.PullRequest
 		canDelete := false
 
+		if ctx.Repo.IsWriter() && !issue.IsClosed && !pull.HasMerged {
+			if err := pull.CheckUserAllowedToMerge(ctx.User); err != nil {
+				if !models.IsErrNotAllowedToMerge(err) {
+					ctx.Handle(500, "CheckUserAllowedToMerge", err)
+					return
+				}
+				ctx.Data["MergeBlockedReason"] = err.(models.ErrNotAllowedToMerge).Reason
+			}
+
+			prUnit, err := ctx.Repo.Repository.GetUnit(models.UnitTypePullRequests)
+			if err != nil {
+				ctx.Handle(500, "GetUnit", err)
+				return
+			}
+			protectBranch, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, pull.BaseBranch)
+			if err != nil {
+				ctx.Handle(500, "GetProtectedBranchBy", err)
+				return
+			}
+			styles := prUnit.PullRequestsConfig().AllowedMergeStyles()
+			if protectBranch != nil {
+				allowed := styles[:0]
+				for _, style := range styles {
+					if protectBranch.IsMergeStyleAllowed(style) {
+						allowed = append(allowed, style)
+					}
+				}
+				styles = allowed
+			}
+			ctx.Data["AllowedMergeStyles"] = styles
+			ctx.Data["DefaultMergeMessage"] = pull.GetDefaultMergeMessage()
+			ctx.Data["DefaultSquashMessage"] = pull.GetDefaultSquashMessage()
+		}
+
 		if ctx.IsSigned {
 			if err := pull.GetHeadRepo(); err != nil {
 				log.Error(4, "GetHeadRepo: %v", err)
@@ -671,6 +742,44 @@ func ViewIssue(ctx *context.Context) {
 		ctx.Data["IsPullBranchDeletable"] = canDelete && pull.HeadRepo != nil && git.IsBranchExist(pull.HeadRepo.RepoPath(), pull.HeadBranch)
 	}
 
+	if ctx.Repo.Repository.IsDependenciesEnabled() {
+		blockedBy, err := issue.BlockedByDependencies()
+		if err == nil {
+			blockedBy, err = readableDependencies(ctx, blockedBy)
+		}
+		if err != nil {
+			ctx.Handle(500, "BlockedByDependencies", err)
+			return
+		}
+		blocking, err := issue.BlockingDependencies()
+		if err == nil {
+			blocking, err = readableDependencies(ctx, blocking)
+		}
+		if err != nil {
+			ctx.Handle(500, "BlockingDependencies", err)
+			return
+		}
+		ctx.Data["IsDependenciesEnabled"] = true
+		ctx.Data["BlockedByDependencies"] = blockedBy
+		ctx.Data["BlockingDependencies"] = blocking
+	}
+
+	if ctx.Repo.Repository.EnableUnit(models.UnitTypeProjects) {
+		if err := issue.LoadProject(); err != nil {
+			ctx.Handle(500, "LoadProject", err)
+			return
+		}
+		if ctx.Repo.IsWriter() {
+			openProjects, err := models.GetProjects(repo.ID, 0, false)
+			if err != nil {
+				ctx.Handle(500, "GetProjects", err)
+				return
+			}
+			ctx.Data["OpenProjects"] = openProjects
+		}
+		ctx.Data["IsProjectsEnabled"] = true
+	}
+
 	ctx.Data["Participants"] = participants
 	ctx.Data["NumParticipants"] = len(participants)
 	ctx.Data["Issue"] = issue
@@ -790,23 +899,80 @@ func UpdateIssueMilestone(ctx *context.Context) {
 	})
 }
 
-// UpdateIssueAssignee change issue's assignee
+// UpdateIssueAssignee change issue's assignees
 func UpdateIssueAssignee(ctx *context.Context) {
 	issues := getActionIssues(ctx)
 	if ctx.Written() {
 		return
 	}
 
-	assigneeID := ctx.QueryInt64("id")
-	for _, issue := range issues {
-		if issue.AssigneeID == assigneeID {
-			continue
+	switch action := ctx.Query("action"); action {
+	case "clear":
+		for _, issue := range issues {
+			if err := issue.ClearAssignees(ctx.User); err != nil {
+				ctx.Handle(500, "ClearAssignees", err)
+				return
+			}
 		}
-		if err := issue.ChangeAssignee(ctx.User, assigneeID); err != nil {
-			ctx.Handle(500, "ChangeAssignee", err)
+	case "attach", "detach", "toggle":
+		assignee, err := models.GetUserByID(ctx.QueryInt64("id"))
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(404, "GetUserByID")
+			} else {
+				ctx.Handle(500, "GetUserByID", err)
+			}
 			return
 		}
+
+		for _, issue := range issues {
+			if err = issue.LoadAssignees(); err != nil {
+				ctx.Handle(500, "LoadAssignees", err)
+				return
+			}
+		}
+
+		if action == "toggle" {
+			anyAssigned := false
+			for _, issue := range issues {
+				if issue.IsAssignedTo(assignee.ID) {
+					anyAssigned = true
+					break
+				}
+			}
+			if anyAssigned {
+				action = "detach"
+			} else {
+				action = "attach"
+			}
+		}
+
+		if action == "attach" {
+			if _, err = ctx.Repo.Repository.GetAssigneeByID(assignee.ID); err != nil {
+				if models.IsErrUserNotExist(err) {
+					ctx.Error(404, "GetAssigneeByID")
+				} else {
+					ctx.Handle(500, "GetAssigneeByID", err)
+				}
+				return
+			}
+		}
+
+		for _, issue := range issues {
+			if issue.IsAssignedTo(assignee.ID) == (action == "attach") {
+				continue
+			}
+			if _, err = issue.ToggleAssignee(ctx.User, assignee.ID); err != nil {
+				ctx.Handle(500, "ToggleAssignee", err)
+				return
+			}
+		}
+	default:
+		log.Warn("Unrecognized action: %s", action)
+		ctx.Error(500)
+		return
 	}
+
 	ctx.JSON(200, map[string]interface{}{
 		"ok": true,
 	})
@@ -835,9 +1001,14 @@ func UpdateIssueStatus(ctx *context.Context) {
 	}
 	for _, issue := range issues {
 		if err := issue.ChangeStatus(ctx.User, issue.Repo, isClosed); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_batch_close_blocked", issue.Index))
+				continue
+			}
 			ctx.Handle(500, "ChangeStatus", err)
 			return
 		}
+		notification.NotifyIssueChangeStatus(ctx.User, issue, isClosed)
 	}
 	ctx.JSON(200, map[string]interface{}{
 		"ok": true,
@@ -898,11 +1069,15 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 				ctx.Flash.Info(ctx.Tr("repo.pulls.open_unmerged_pull_exists", pr.Index))
 			} else {
 				if err = issue.ChangeStatus(ctx.User, ctx.Repo.Repository, form.Status == "close"); err != nil {
-					log.Error(4, "ChangeStatus: %v", err)
+					if models.IsErrDependenciesLeft(err) {
+						ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_close_blocked"))
+					} else {
+						log.Error(4, "ChangeStatus: %v", err)
+					}
 				} else {
 					log.Trace("Issue [%d] status changed to closed: %v", issue.ID, issue.IsClosed)
 
-					notification.Service.NotifyIssue(issue, ctx.User.ID)
+					notification.NotifyIssueChangeStatus(ctx.User, issue, issue.IsClosed)
 				}
 			}
 		}
@@ -930,7 +1105,7 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 		return
 	}
 
-	notification.Service.NotifyIssue(issue, ctx.User.ID)
+	notification.NotifyCreateIssueComment(ctx.User, ctx.Repo.Repository, issue, comment)
 
 	log.Trace("Comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
 }
@@ -951,6 +1126,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		return
 	}
 
+	oldContent := comment.Content
 	comment.Content = ctx.Query("content")
 	if len(comment.Content) == 0 {
 		ctx.JSON(200, map[string]interface{}{
@@ -958,7 +1134,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		})
 		return
 	}
-	if err = models.UpdateComment(comment); err != nil {
+	if err = models.UpdateComment(ctx.User, comment, oldContent); err != nil {
 		ctx.Handle(500, "UpdateComment", err)
 		return
 	}
@@ -984,7 +1160,7 @@ func DeleteComment(ctx *context.Context) {
 		return
 	}
 
-	if err = models.DeleteComment(comment); err != nil {
+	if err = models.DeleteComment(ctx.User, comment); err != nil {
 		ctx.Handle(500, "DeleteCommentByID", err)
 		return
 	}
@@ -1205,3 +1381,76 @@ func DeleteMilestone(ctx *context.Context) {
 		"redirect": ctx.Repo.RepoLink + "/milestones",
 	})
 }
+
+// changeReaction adds or removes a reaction of the signed in user to the
+// issue, or to the comment if it is given
+func changeReaction(ctx *context.Context, form auth.ReactionForm, issue *models.Issue, comment *models.Comment) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		return
+	}
+
+	var err error
+	switch ctx.Params(":action") {
+	case "react":
+		if comment == nil {
+			_, err = models.CreateIssueReaction(ctx.User, issue, form.Content)
+		} else {
+			_, err = models.CreateCommentReaction(ctx.User, issue, comment, form.Content)
+		}
+		if models.IsErrReactionAlreadyExist(err) {
+			err = nil
+		} else if models.IsErrForbiddenReaction(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.reaction.not_allowed", form.Content))
+			return
+		}
+	case "unreact":
+		if comment == nil {
+			err = models.DeleteIssueReaction(ctx.User, issue, form.Content)
+		} else {
+			err = models.DeleteCommentReaction(ctx.User, issue, comment, form.Content)
+		}
+	default:
+		ctx.Handle(404, fmt.Sprintf("Unknown action %s", ctx.Params(":action")), nil)
+		return
+	}
+	if err != nil {
+		ctx.Handle(500, "ChangeReaction", err)
+	}
+}
+
+// ChangeIssueReaction adds or removes a reaction to an issue
+func ChangeIssueReaction(ctx *context.Context, form auth.ReactionForm) {
+	issue := getActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	changeReaction(ctx, form, issue, nil)
+	if !ctx.Written() {
+		ctx.Redirect(issue.HTMLURL())
+	}
+}
+
+// ChangeCommentReaction adds or removes a reaction to a comment
+func ChangeCommentReaction(ctx *context.Context, form auth.ReactionForm) {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetCommentByID", models.IsErrCommentNotExist, err)
+		return
+	}
+
+	issue, err := models.GetIssueByID(comment.IssueID)
+	if err != nil {
+		ctx.Handle(500, "GetIssueByID", err)
+		return
+	} else if issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.Handle(404, "ChangeCommentReaction", nil)
+		return
+	}
+
+	changeReaction(ctx, form, issue, comment)
+	if !ctx.Written() {
+		ctx.Redirect(issue.HTMLURL() + "#" + comment.HashTag())
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+
+	"github.com/Unknwon/com"
+)
+
+// getDependentIssue returns the issue the dependencies are changed for, it
+// renders a 404 page if dependencies are disabled for the repository.
+func getDependentIssue(ctx *context.Context) *models.Issue {
+	if !ctx.Repo.Repository.IsDependenciesEnabled() {
+		ctx.Handle(404, "IsDependenciesEnabled", nil)
+		return nil
+	}
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Handle(404, "GetIssueByIndex", err)
+		} else {
+			ctx.Handle(500, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// canReadDependency returns true if the signed in user is allowed to read
+// the issue, which might belong to another repository.
+func canReadDependency(ctx *context.Context, issue *models.Issue) (bool, error) {
+	if issue.RepoID == ctx.Repo.Repository.ID {
+		return true, nil
+	}
+	var userID int64
+	if ctx.IsSigned {
+		if ctx.User.IsAdmin {
+			return true, nil
+		}
+		userID = ctx.User.ID
+	}
+	return models.HasAccess(userID, issue.Repo, models.AccessModeRead)
+}
+
+// readableDependencies loads the repositories of the issues and returns the
+// ones the signed in user is allowed to read.
+func readableDependencies(ctx *context.Context, issues []*models.Issue) ([]*models.Issue, error) {
+	if _, err := models.IssueList(issues).LoadRepositories(); err != nil {
+		return nil, err
+	}
+	readable := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if has, err := canReadDependency(ctx, issue); err != nil {
+			return nil, err
+		} else if has {
+			readable = append(readable, issue)
+		}
+	}
+	return readable, nil
+}
+
+// getIssueByDependencyRef returns the issue referenced by "#index", "index"
+// or "owner/repo#index", nil if the issue does not exist or the user is not
+// allowed to read it.
+func getIssueByDependencyRef(ctx *context.Context, ref string) (*models.Issue, error) {
+	ref = strings.TrimSpace(ref)
+	if !strings.Contains(ref, "/") {
+		index, err := com.StrTo(strings.TrimPrefix(ref, "#")).Int64()
+		if err != nil {
+			return nil, nil
+		}
+		issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, index)
+		if models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return issue, err
+	}
+
+	issue, err := models.GetIssueByRef(ref)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) || models.IsErrRepoNotExist(err) || models.IsErrUserNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if has, err := canReadDependency(ctx, issue); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return issue, nil
+}
+
+// AddDependency makes the issue depend on another issue
+func AddDependency(ctx *context.Context) {
+	issue := getDependentIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	dep, err := getIssueByDependencyRef(ctx, ctx.Query("dependency"))
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error", err))
+	} else if dep == nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_exist"))
+	} else if err = models.CreateIssueDependency(ctx.User, issue, dep); err != nil {
+		switch {
+		case models.IsErrDependencyExists(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_exists"))
+		case models.IsErrCircularDependency(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_cannot_create_circular"))
+		case models.IsErrDependencyCrossRepository(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_same_repo"))
+		default:
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error", err))
+		}
+	}
+	ctx.Redirect(issue.HTMLURL())
+}
+
+// RemoveDependency removes a dependency of the issue
+func RemoveDependency(ctx *context.Context) {
+	issue := getDependentIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	dep, err := models.GetIssueByID(ctx.QueryInt64("dependency_id"))
+	if err == nil {
+		err = models.RemoveIssueDependency(ctx.User, issue, dep)
+	}
+	if err != nil {
+		if models.IsErrIssueNotExist(err) || models.IsErrDependencyNotExists(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.add_error_dep_not_exist"))
+		} else {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.remove_error", err))
+		}
+	}
+	ctx.Redirect(issue.HTMLURL())
+}
@@ -0,0 +1,423 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"github.com/Unknwon/paginater"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markdown"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplProjects     base.TplName = "repo/projects/list"
+	tplProjectsNew  base.TplName = "repo/projects/new"
+	tplProjectsView base.TplName = "repo/projects/view"
+)
+
+// Projects renders the projects page
+func Projects(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects")
+	ctx.Data["PageIsProjects"] = true
+
+	isShowClosed := ctx.Query("state") == "closed"
+	openCount, closedCount := models.ProjectStats(ctx.Repo.Repository.ID)
+	ctx.Data["OpenCount"] = openCount
+	ctx.Data["ClosedCount"] = closedCount
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	var total int
+	if !isShowClosed {
+		total = int(openCount)
+	} else {
+		total = int(closedCount)
+	}
+	ctx.Data["Page"] = paginater.New(total, setting.UI.IssuePagingNum, page, 5)
+
+	projects, err := models.GetProjects(ctx.Repo.Repository.ID, page, isShowClosed)
+	if err != nil {
+		ctx.Handle(500, "GetProjects", err)
+		return
+	}
+	for _, p := range projects {
+		if err = p.LoadNumIssues(); err != nil {
+			ctx.Handle(500, "LoadNumIssues", err)
+			return
+		}
+		p.RenderedContent = string(markdown.Render([]byte(p.Description), ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas()))
+	}
+	ctx.Data["Projects"] = projects
+
+	if isShowClosed {
+		ctx.Data["State"] = "closed"
+	} else {
+		ctx.Data["State"] = "open"
+	}
+	ctx.Data["IsShowClosed"] = isShowClosed
+	ctx.HTML(200, tplProjects)
+}
+
+// getProject returns the project of the repository given by id
+func getProject(ctx *context.Context) *models.Project {
+	p, err := models.GetProjectByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.Handle(404, "", nil)
+		} else {
+			ctx.Handle(500, "GetProjectByRepoID", err)
+		}
+		return nil
+	}
+	return p
+}
+
+// getProjectBoard returns the board of the project given by boardID
+func getProjectBoard(ctx *context.Context, p *models.Project) *models.ProjectBoard {
+	b, err := models.GetProjectBoard(p.ID, ctx.ParamsInt64(":boardID"))
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			ctx.Handle(404, "", nil)
+		} else {
+			ctx.Handle(500, "GetProjectBoard", err)
+		}
+		return nil
+	}
+	return b
+}
+
+// NewProject renders the page to create a project
+func NewProject(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["PageIsProjects"] = true
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// NewProjectPost creates a project
+func NewProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["PageIsProjects"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	if err := models.NewProject(&models.Project{
+		RepoID:      ctx.Repo.Repository.ID,
+		CreatorID:   ctx.User.ID,
+		Title:       form.Title,
+		Description: form.Content,
+	}); err != nil {
+		ctx.Handle(500, "NewProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.create_success", form.Title))
+	ctx.Redirect(ctx.Repo.RepoLink + "/projects")
+}
+
+// EditProject renders the page to edit a project
+func EditProject(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["PageIsEditProject"] = true
+
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["title"] = p.Title
+	ctx.Data["content"] = p.Description
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// EditProjectPost updates a project
+func EditProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["PageIsEditProject"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	p.Title = form.Title
+	p.Description = form.Content
+	if err := models.UpdateProject(p); err != nil {
+		ctx.Handle(500, "UpdateProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.edit_success", p.Title))
+	ctx.Redirect(ctx.Repo.RepoLink + "/projects")
+}
+
+// ChangeProjectStatus opens or closes a project
+func ChangeProjectStatus(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	switch ctx.Params(":action") {
+	case "open":
+		if p.IsClosed {
+			if err := models.ChangeProjectStatus(p, false); err != nil {
+				ctx.Handle(500, "ChangeProjectStatus", err)
+				return
+			}
+		}
+		ctx.Redirect(ctx.Repo.RepoLink + "/projects?state=open")
+	case "close":
+		if !p.IsClosed {
+			if err := models.ChangeProjectStatus(p, true); err != nil {
+				ctx.Handle(500, "ChangeProjectStatus", err)
+				return
+			}
+		}
+		ctx.Redirect(ctx.Repo.RepoLink + "/projects?state=closed")
+	default:
+		ctx.Redirect(ctx.Repo.RepoLink + "/projects")
+	}
+}
+
+// DeleteProject deletes a project
+func DeleteProject(ctx *context.Context) {
+	if err := models.DeleteProjectByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteProjectByRepoID: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.projects.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/projects",
+	})
+}
+
+// ViewProject renders the boards of a project
+func ViewProject(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	boards, err := p.GetBoards()
+	if err != nil {
+		ctx.Handle(500, "GetBoards", err)
+		return
+	}
+	defaultBoard, err := models.GetProjectBoard(p.ID, 0)
+	if err != nil {
+		ctx.Handle(500, "GetProjectBoard", err)
+		return
+	}
+	boards = append([]*models.ProjectBoard{defaultBoard}, boards...)
+	for _, b := range boards {
+		if err = b.LoadIssues(); err != nil {
+			ctx.Handle(500, "LoadIssues", err)
+			return
+		}
+	}
+
+	p.RenderedContent = string(markdown.Render([]byte(p.Description), ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas()))
+	ctx.Data["Title"] = p.Title
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["Project"] = p
+	ctx.Data["Boards"] = boards
+	ctx.HTML(200, tplProjectsView)
+}
+
+// AddBoardToProjectPost adds a board to a project
+func AddBoardToProjectPost(ctx *context.Context, form auth.ProjectBoardForm) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	projectLink := fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID)
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(projectLink)
+		return
+	}
+
+	boards, err := p.GetBoards()
+	if err != nil {
+		ctx.Handle(500, "GetBoards", err)
+		return
+	}
+	if err = models.NewProjectBoard(&models.ProjectBoard{
+		ProjectID: p.ID,
+		Title:     form.Title,
+		Sorting:   len(boards),
+		IsClosed:  form.ClosedColumn,
+		CreatorID: ctx.User.ID,
+	}); err != nil {
+		ctx.Handle(500, "NewProjectBoard", err)
+		return
+	}
+	ctx.Redirect(projectLink)
+}
+
+// EditProjectBoardPost updates a board of a project
+func EditProjectBoardPost(ctx *context.Context, form auth.ProjectBoardForm) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	projectLink := fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID)
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(projectLink)
+		return
+	}
+
+	b := getProjectBoard(ctx, p)
+	if ctx.Written() {
+		return
+	} else if b.IsDefault() {
+		ctx.Handle(404, "", nil)
+		return
+	}
+	b.Title = form.Title
+	b.IsClosed = form.ClosedColumn
+	if err := models.UpdateProjectBoard(b); err != nil {
+		ctx.Handle(500, "UpdateProjectBoard", err)
+		return
+	}
+	ctx.Redirect(projectLink)
+}
+
+// DeleteProjectBoard deletes a board of a project
+func DeleteProjectBoard(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	b := getProjectBoard(ctx, p)
+	if ctx.Written() {
+		return
+	} else if b.IsDefault() {
+		ctx.Handle(404, "", nil)
+		return
+	}
+
+	if err := models.DeleteProjectBoard(b); err != nil {
+		ctx.Handle(500, "DeleteProjectBoard", err)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, p.ID))
+}
+
+// MoveProjectIssue moves an issue dropped on a board of the project
+func MoveProjectIssue(ctx *context.Context) {
+	p := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := models.GetIssueByID(ctx.QueryInt64("issue"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Handle(404, "", nil)
+		} else {
+			ctx.Handle(500, "GetIssueByID", err)
+		}
+		return
+	} else if issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.Handle(404, "", nil)
+		return
+	}
+
+	b, err := models.GetProjectBoard(p.ID, ctx.QueryInt64("board"))
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			ctx.Handle(404, "", nil)
+		} else {
+			ctx.Handle(500, "GetProjectBoard", err)
+		}
+		return
+	}
+
+	isClosed := issue.IsClosed
+	if err = models.MoveProjectIssue(ctx.User, issue, b, ctx.QueryInt("position")); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.Handle(404, "", nil)
+		} else if models.IsErrDependenciesLeft(err) {
+			ctx.JSON(200, map[string]interface{}{
+				"ok":    false,
+				"error": ctx.Tr("repo.issues.dependency.issue_close_blocked"),
+			})
+		} else {
+			ctx.Handle(500, "MoveProjectIssue", err)
+		}
+		return
+	}
+	if issue.IsClosed != isClosed {
+		notification.NotifyIssueChangeStatus(ctx.User, issue, issue.IsClosed)
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// UpdateIssueProject puts the issues in a project, or removes them from
+// their project if the project id is zero
+func UpdateIssueProject(ctx *context.Context) {
+	issues := getActionIssues(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var p *models.Project
+	if projectID := ctx.QueryInt64("id"); projectID > 0 {
+		var err error
+		p, err = models.GetProjectByRepoID(ctx.Repo.Repository.ID, projectID)
+		if err != nil {
+			if models.IsErrProjectNotExist(err) {
+				ctx.Error(404, "GetProjectByRepoID")
+			} else {
+				ctx.Handle(500, "GetProjectByRepoID", err)
+			}
+			return
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.RepoID != ctx.Repo.Repository.ID {
+			ctx.Handle(404, "", nil)
+			return
+		}
+	}
+
+	for _, issue := range issues {
+		if err := models.ChangeProjectAssign(issue, p); err != nil {
+			ctx.Handle(500, "ChangeProjectAssign", err)
+			return
+		}
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// CreateCodeComment will create a code comment including a pending review if required
+func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.PullRequest.HasMerged {
+		ctx.Handle(404, "CreateCodeComment", nil)
+		return
+	}
+
+	filesLink := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(filesLink)
+		return
+	}
+
+	line := form.Line
+	if form.Side == "previous" {
+		line *= -1
+	}
+
+	comment, err := models.CreateCodeComment(ctx.User, issue, form.TreePath, line, form.Content, form.IsReview)
+	if err != nil {
+		ctx.Handle(500, "CreateCodeComment", err)
+		return
+	}
+
+	log.Trace("Code comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
+	ctx.Redirect(fmt.Sprintf("%s#%s", filesLink, comment.HashTag()))
+}
+
+// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
+func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.PullRequest.HasMerged {
+		ctx.Handle(404, "SubmitReview", nil)
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(issueLink + "/files")
+		return
+	}
+
+	reviewType := form.ReviewType()
+	if reviewType == models.ReviewTypeReject && len(form.Content) == 0 {
+		ctx.Flash.Error(ctx.Tr("repo.issues.review.reject_empty"))
+		ctx.Redirect(issueLink + "/files")
+		return
+	}
+
+	review, comment, err := models.SubmitReview(ctx.User, issue, reviewType, form.Content)
+	if err != nil {
+		if models.IsErrReviewOwnPullRequest(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.review.self_review"))
+			ctx.Redirect(issueLink + "/files")
+			return
+		}
+		ctx.Handle(500, "SubmitReview", err)
+		return
+	}
+
+	log.Trace("Review submitted: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, review.ID)
+	ctx.Redirect(fmt.Sprintf("%s#%s", issueLink, comment.HashTag()))
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/search"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/Unknwon/paginater"
+)
+
+const tplSearch base.TplName = "repo/search"
+
+// Search render the search page of the code of a repository
+func Search(ctx *context.Context) {
+	keyword := strings.TrimSpace(ctx.Query("q"))
+	isFilename := ctx.Query("t") == "filename"
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	total, searchResults, err := search.PerformSearch([]int64{ctx.Repo.Repository.ID},
+		keyword, isFilename, page, setting.UI.RepoSearchPagingNum)
+	if err != nil {
+		ctx.Handle(500, "SearchResults", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Repo.Repository.FullName()
+	ctx.Data["PageIsViewCode"] = true
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["IsFilename"] = isFilename
+	ctx.Data["SourcePath"] = ctx.Repo.RepoLink + "/src/" + ctx.Repo.Repository.DefaultBranch
+	ctx.Data["SearchResults"] = searchResults
+	ctx.Data["Total"] = total
+
+	pager := paginater.New(total, setting.UI.RepoSearchPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplSearch)
+}
@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 )
 
@@ -31,9 +32,32 @@ const (
 func Settings(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+
+	pushMirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Handle(500, "GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+
 	ctx.HTML(200, tplSettingsOptions)
 }
 
+// parsePushMirrorInterval parses the interval between scheduled pushes to a
+// push mirror, which are disabled by "0".
+func parsePushMirrorInterval(interval string) (time.Duration, bool) {
+	interval = strings.TrimSpace(interval)
+	if len(interval) == 0 {
+		return setting.Mirror.DefaultInterval, true
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil || (d != 0 && d < setting.Mirror.MinInterval) {
+		return 0, false
+	}
+	return d, true
+}
+
 // SettingsPost response for changes of a repository
 func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
@@ -41,6 +65,14 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 
 	repo := ctx.Repo.Repository
 
+	pushMirrors, err := models.GetPushMirrorsByRepoID(repo.ID)
+	if err != nil {
+		ctx.Handle(500, "GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+
 	switch ctx.Query("action") {
 	case "update":
 		if ctx.HasError() {
@@ -141,6 +173,82 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "push-mirror-add":
+		interval, ok := parsePushMirrorInterval(form.PushMirrorInterval)
+		if !ok {
+			ctx.Data["Err_PushMirrorInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.push_mirror_interval_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		address, err := auth.ParseRemoteAddr(form.PushMirrorAddress, form.PushMirrorUsername, form.PushMirrorPassword, ctx.User)
+		if err != nil {
+			if models.IsErrInvalidCloneAddr(err) {
+				ctx.Data["Err_PushMirrorAddress"] = true
+				addrErr := err.(models.ErrInvalidCloneAddr)
+				switch {
+				case addrErr.IsURLError:
+					ctx.RenderWithErr(ctx.Tr("form.url_error"), tplSettingsOptions, &form)
+				case addrErr.IsPermissionDenied:
+					ctx.RenderWithErr(ctx.Tr("repo.migrate.permission_denied"), tplSettingsOptions, &form)
+				case addrErr.IsInvalidPath:
+					ctx.RenderWithErr(ctx.Tr("repo.migrate.invalid_local_path"), tplSettingsOptions, &form)
+				default:
+					ctx.Handle(500, "Unknown error", err)
+				}
+			} else {
+				ctx.Handle(500, "ParseRemoteAddr", err)
+			}
+			return
+		}
+
+		m, err := models.AddPushMirror(repo, address, interval)
+		if err != nil {
+			ctx.Handle(500, "AddPushMirror", err)
+			return
+		}
+		log.Trace("Push mirror added: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.Address())
+
+		go models.PushMirrorQueue.Add(m.ID)
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_add_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-update", "push-mirror-sync", "push-mirror-remove":
+		m, err := models.GetPushMirrorByID(repo.ID, ctx.QueryInt64("push_mirror_id"))
+		if err != nil {
+			if models.IsErrPushMirrorNotExist(err) {
+				ctx.Handle(404, "GetPushMirrorByID", nil)
+			} else {
+				ctx.Handle(500, "GetPushMirrorByID", err)
+			}
+			return
+		}
+
+		switch ctx.Query("action") {
+		case "push-mirror-update":
+			interval, ok := parsePushMirrorInterval(form.PushMirrorInterval)
+			if !ok {
+				ctx.Flash.Error(ctx.Tr("repo.settings.push_mirror_interval_invalid"))
+				break
+			}
+			if err = models.UpdatePushMirrorInterval(m, interval); err != nil {
+				ctx.Handle(500, "UpdatePushMirrorInterval", err)
+				return
+			}
+			ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		case "push-mirror-sync":
+			go models.PushMirrorQueue.Add(m.ID)
+			ctx.Flash.Info(ctx.Tr("repo.settings.push_mirror_sync_in_progress"))
+		case "push-mirror-remove":
+			if err = models.DeletePushMirror(m); err != nil {
+				ctx.Handle(500, "DeletePushMirror", err)
+				return
+			}
+			log.Trace("Push mirror removed: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.Address())
+			ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_remove_success"))
+		}
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
 		var units []models.RepoUnit
 
@@ -202,7 +310,8 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					Type:   models.UnitTypeIssues,
 					Index:  int(models.UnitTypeIssues),
 					Config: &models.IssuesConfig{
-						EnableTimetracker: form.EnableTimetracker,
+						EnableTimetracker:  form.EnableTimetracker,
+						EnableDependencies: form.EnableDependencies,
 					},
 				})
 			}
@@ -213,6 +322,20 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 				RepoID: repo.ID,
 				Type:   models.UnitTypePullRequests,
 				Index:  int(models.UnitTypePullRequests),
+				Config: &models.PullRequestsConfig{
+					AllowMerge:       form.PullsAllowMerge,
+					AllowRebase:      form.PullsAllowRebase,
+					AllowRebaseMerge: form.PullsAllowRebaseMerge,
+					AllowSquash:      form.PullsAllowSquash,
+				},
+			})
+		}
+
+		if form.EnableProjects {
+			units = append(units, models.RepoUnit{
+				RepoID: repo.ID,
+				Type:   models.UnitTypeProjects,
+				Index:  int(models.UnitTypeProjects),
 				Config: new(models.UnitConfig),
 			})
 		}
@@ -287,6 +410,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			return
 		}
 
+		oldOwner := ctx.Repo.Owner
 		if err = models.TransferOwnership(ctx.User, newOwner, repo); err != nil {
 			if models.IsErrRepoAlreadyExist(err) {
 				ctx.RenderWithErr(ctx.Tr("repo.settings.new_owner_has_same_repo"), tplSettingsOptions, nil)
@@ -295,6 +419,8 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			}
 			return
 		}
+		notification.NotifyTransferRepository(ctx.User, oldOwner, repo)
+
 		log.Trace("Repository transferred: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, newOwner)
 		ctx.Flash.Success(ctx.Tr("repo.settings.transfer_succeed"))
 		ctx.Redirect(setting.AppSubURL + "/" + newOwner + "/" + repo.Name)
@@ -316,7 +442,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			}
 		}
 
-		if err := models.DeleteRepository(ctx.Repo.Owner.ID, repo.ID); err != nil {
+		if err := models.DeleteRepository(ctx.User, ctx.Repo.Owner.ID, repo.ID); err != nil {
 			ctx.Handle(500, "DeleteRepository", err)
 			return
 		}
@@ -0,0 +1,176 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	tplProtectedBranch base.TplName = "repo/settings/protected_branch"
+)
+
+// splitNames splits a comma or space separated list of names
+func splitNames(names string) []string {
+	return strings.FieldsFunc(names, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// splitContexts splits a comma separated list of status contexts
+func splitContexts(contexts string) []string {
+	fields := strings.Split(contexts, ",")
+	res := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); len(field) > 0 {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+// joinUserNames returns the names of the given users separated by comma
+func joinUserNames(userIDs []int64) (string, error) {
+	users, err := models.GetUsersByIDs(userIDs)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(users))
+	for i := range users {
+		names[i] = users[i].Name
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// joinTeamNames returns the names of the given teams separated by comma
+func joinTeamNames(teamIDs []int64) (string, error) {
+	names := make([]string, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		team, err := models.GetTeamByID(teamID)
+		if err != nil {
+			if err == models.ErrTeamNotExist {
+				continue
+			}
+			return "", err
+		}
+		names = append(names, team.Name)
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// getTeamIDsByNames returns the IDs of the teams of the organization with the given names
+func getTeamIDsByNames(orgID int64, names []string) []int64 {
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		team, err := models.GetTeam(orgID, name)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, team.ID)
+	}
+	return ids
+}
+
+func getProtectedBranch(ctx *context.Context) *models.ProtectedBranch {
+	branch := ctx.Query("branch")
+	protectBranch, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, branch)
+	if err != nil {
+		ctx.Handle(500, "GetProtectedBranchBy", err)
+		return nil
+	} else if protectBranch == nil {
+		ctx.Handle(404, "GetProtectedBranchBy", nil)
+		return nil
+	}
+	return protectBranch
+}
+
+// SettingsProtectedBranch renders the protection settings of a branch
+func SettingsProtectedBranch(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings")
+	ctx.Data["PageIsSettingsBranches"] = true
+
+	protectBranch := getProtectedBranch(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["Branch"] = protectBranch
+
+	var err error
+	if ctx.Data["WhitelistUsers"], err = joinUserNames(protectBranch.WhitelistUserIDs); err != nil {
+		ctx.Handle(500, "GetUsersByIDs", err)
+		return
+	}
+	if ctx.Data["MergeWhitelistUsers"], err = joinUserNames(protectBranch.MergeWhitelistUserIDs); err != nil {
+		ctx.Handle(500, "GetUsersByIDs", err)
+		return
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		if ctx.Data["WhitelistTeams"], err = joinTeamNames(protectBranch.WhitelistTeamIDs); err != nil {
+			ctx.Handle(500, "GetTeamByID", err)
+			return
+		}
+		if ctx.Data["MergeWhitelistTeams"], err = joinTeamNames(protectBranch.MergeWhitelistTeamIDs); err != nil {
+			ctx.Handle(500, "GetTeamByID", err)
+			return
+		}
+	}
+	ctx.Data["StatusCheckContexts"] = strings.Join(protectBranch.StatusCheckContexts, ", ")
+
+	ctx.HTML(200, tplProtectedBranch)
+}
+
+// SettingsProtectedBranchPost updates the protection settings of a branch
+func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm) {
+	protectBranch := getProtectedBranch(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	link := fmt.Sprintf("%s/settings/branches/edit?branch=%s", ctx.Repo.RepoLink, url.QueryEscape(protectBranch.BranchName))
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(link)
+		return
+	}
+	if f.RequiredApprovals < 0 {
+		ctx.Flash.Error(ctx.Tr("repo.settings.protected_branch_required_approvals_invalid"))
+		ctx.Redirect(link)
+		return
+	}
+
+	protectBranch.CanPush = len(f.EnablePush) > 0
+	protectBranch.EnableWhitelist = f.EnablePush == "whitelist"
+	protectBranch.EnableMergeWhitelist = f.EnableMergeWhitelist
+	protectBranch.RequiredApprovals = f.RequiredApprovals
+	protectBranch.EnableStatusCheck = f.EnableStatusCheck
+	protectBranch.StatusCheckContexts = splitContexts(f.StatusCheckContexts)
+	protectBranch.RequireSignedCommits = f.RequireSignedCommits
+
+	opts := models.UpdateProtectBranchOptions{
+		WhitelistUserIDs:      models.GetUserIDsByNames(splitNames(f.WhitelistUsers)),
+		MergeWhitelistUserIDs: models.GetUserIDsByNames(splitNames(f.MergeWhitelistUsers)),
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		opts.WhitelistTeamIDs = getTeamIDsByNames(ctx.Repo.Owner.ID, splitNames(f.WhitelistTeams))
+		opts.MergeWhitelistTeamIDs = getTeamIDsByNames(ctx.Repo.Owner.ID, splitNames(f.MergeWhitelistTeams))
+	}
+
+	if err := models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, opts); err != nil {
+		ctx.Handle(500, "UpdateProtectBranch", err)
+		return
+	}
+
+	log.Trace("Protected branch updated: %s/%s:%s", ctx.Repo.Owner.Name, ctx.Repo.Repository.Name, protectBranch.BranchName)
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_protect_branch_success", protectBranch.BranchName))
+	ctx.Redirect(link)
+}
@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/templates"
 	"github.com/Unknwon/paginater"
 )
@@ -158,7 +159,7 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 				oid := strings.TrimPrefix(splitLines[1], models.LFSMetaFileOidPrefix)
 				size, err := strconv.ParseInt(strings.TrimPrefix(splitLines[2], "size "), 10, 64)
 				if len(oid) == 64 && err == nil {
-					contentStore := &lfs.ContentStore{BasePath: setting.LFS.ContentPath}
+					contentStore := &lfs.ContentStore{ObjectStorage: storage.LFS}
 					meta := &models.LFSMetaObject{Oid: oid}
 					if contentStore.Exists(meta) {
 						ctx.Data["IsTextFile"] = false
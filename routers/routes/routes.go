@@ -5,8 +5,9 @@
 package routes
 
 import (
-	"os"
+	"net/http"
 	"path"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
@@ -16,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/options"
 	"code.gitea.io/gitea/modules/public"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/templates"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers"
@@ -38,6 +40,49 @@ import (
 	"gopkg.in/macaron.v1"
 )
 
+// storageHandler serves the objects of an object storage below the given URL prefix.
+// The storage is looked up on every request since it is replaced when the configuration
+// is reloaded after installation.
+func storageHandler(prefix string, getStorage func() storage.ObjectStorage) macaron.Handler {
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+	return func(ctx *macaron.Context) {
+		if ctx.Req.Method != "GET" && ctx.Req.Method != "HEAD" {
+			return
+		}
+		if !strings.HasPrefix(ctx.Req.URL.Path, prefix) {
+			return
+		}
+
+		objStore := getStorage()
+		if objStore == nil {
+			ctx.Error(404)
+			return
+		}
+
+		rPath := strings.TrimPrefix(ctx.Req.URL.Path, prefix)
+		fi, err := objStore.Stat(rPath)
+		if err != nil {
+			if storage.IsErrObjectNotExist(err) {
+				ctx.Error(404)
+			} else {
+				log.Error(4, "Stat %s: %v", rPath, err)
+				ctx.Error(500)
+			}
+			return
+		}
+
+		obj, err := objStore.Open(rPath)
+		if err != nil {
+			log.Error(4, "Open %s: %v", rPath, err)
+			ctx.Error(500)
+			return
+		}
+		defer obj.Close()
+
+		http.ServeContent(ctx.Resp, ctx.Req.Request, fi.Name(), fi.ModTime(), obj)
+	}
+}
+
 // NewMacaron initializes Macaron instance.
 func NewMacaron() *macaron.Macaron {
 	m := macaron.New()
@@ -62,14 +107,9 @@ func NewMacaron() *macaron.Macaron {
 			SkipLogging: setting.DisableRouterLog,
 		},
 	))
-	m.Use(macaron.Static(
-		setting.AvatarUploadPath,
-		macaron.StaticOptions{
-			Prefix:      "avatars",
-			SkipLogging: setting.DisableRouterLog,
-			ETag:        true,
-		},
-	))
+	m.Use(storageHandler("avatars", func() storage.ObjectStorage {
+		return storage.Avatars
+	}))
 
 	m.Use(templates.Renderer())
 	models.InitMailRender(templates.Mailer())
@@ -154,6 +194,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/repos", routers.ExploreRepos)
 		m.Get("/users", routers.ExploreUsers)
 		m.Get("/organizations", routers.ExploreOrganizations)
+		if setting.Indexer.RepoIndexerEnabled {
+			m.Get("/code", routers.ExploreCode)
+		}
 	}, ignSignIn)
 	m.Combo("/install", routers.InstallInit).Get(routers.Install).
 		Post(bindIgnErr(auth.InstallForm{}), routers.InstallPost)
@@ -195,6 +238,19 @@ func RegisterRoutes(m *macaron.Macaron) {
 		})
 	}, reqSignOut)
 
+	if setting.OAuth2.Enable {
+		m.Group("/login/oauth", func() {
+			m.Get("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
+			m.Post("/grant", bindIgnErr(auth.GrantApplicationForm{}), user.GrantApplicationOAuth)
+		}, reqSignIn)
+		m.Group("/login/oauth", func() {
+			// RFC 6749 allows client credentials in the body, so the token endpoint has no CSRF check.
+			m.Post("/access_token", bindIgnErr(auth.AccessTokenForm{}), user.AccessTokenOAuth)
+			m.Get("/userinfo", user.InfoOAuth)
+		}, ignSignInAndCsrf)
+		m.Get("/.well-known/openid-configuration", ignSignIn, user.OIDCWellKnown)
+	}
+
 	m.Group("/user/settings", func() {
 		m.Get("", user.Settings)
 		m.Post("", bindIgnErr(auth.UpdateProfileForm{}), user.SettingsPost)
@@ -221,6 +277,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Combo("/applications").Get(user.SettingsApplications).
 			Post(bindIgnErr(auth.NewAccessTokenForm{}), user.SettingsApplicationsPost)
 		m.Post("/applications/delete", user.SettingsDeleteApplication)
+		if setting.OAuth2.Enable {
+			m.Group("/applications/oauth2", func() {
+				m.Post("", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.SettingsOAuthApplicationsPost)
+				m.Combo("/:id").Get(user.SettingsOAuthApplicationEdit).
+					Post(bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.SettingsOAuthApplicationEditPost)
+				m.Post("/:id/regenerate_secret", user.SettingsOAuthApplicationRegenerateSecret)
+				m.Post("/delete", user.SettingsDeleteOAuthApplication)
+			})
+			m.Post("/applications/oauth2_grants/revoke", user.SettingsRevokeOAuthGrant)
+		}
 		m.Route("/delete", "GET,POST", user.SettingsDelete)
 		m.Combo("/account_link").Get(user.SettingsAccountLinks).Post(user.SettingsDeleteAccountLink)
 		m.Get("/organization", user.SettingsOrganization)
@@ -305,7 +371,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				return
 			}
 
-			fr, err := os.Open(attach.LocalPath())
+			fr, err := storage.Attachments.Open(attach.RelativePath())
 			if err != nil {
 				ctx.Handle(500, "Open", err)
 				return
@@ -383,16 +449,35 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/gitea/new", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksNewPost)
 					m.Post("/gogs/new", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
 					m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
+					m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
+					m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 					m.Get("/:id", repo.WebHooksEdit)
+					m.Post("/:id/deliveries/:taskid/redeliver", repo.RedeliverWebhookTask)
 					m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 					m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 					m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
+					m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
+					m.Post("/msteams/:id", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
 				})
 
+				if setting.OAuth2.Enable {
+					m.Group("/applications", func() {
+						m.Get("", org.Applications)
+						m.Post("/oauth2", bindIgnErr(auth.EditOAuth2ApplicationForm{}), org.OAuthApplicationsPost)
+						m.Combo("/oauth2/:id").Get(org.OAuthApplicationEdit).
+							Post(bindIgnErr(auth.EditOAuth2ApplicationForm{}), org.OAuthApplicationEditPost)
+						m.Post("/oauth2/:id/regenerate_secret", org.OAuthApplicationRegenerateSecret)
+						m.Post("/oauth2/delete", org.DeleteOAuthApplication)
+					})
+				}
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 
 			m.Route("/invitations/new", "GET,POST", org.Invitation)
+
+			m.Get("/times", org.Times)
+			m.Get("/times/export", org.TimesExport)
 		}, context.OrgAssignment(true, true))
 	}, reqSignIn)
 	// ***** END: Organization *****
@@ -419,6 +504,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Group("/branches", func() {
 				m.Combo("").Get(repo.ProtectedBranch).Post(repo.ProtectedBranchPost)
 				m.Post("/can_push", repo.ChangeProtectedBranch)
+				m.Combo("/edit").Get(repo.SettingsProtectedBranch).
+					Post(bindIgnErr(auth.ProtectBranchForm{}), repo.SettingsProtectedBranchPost)
 				m.Post("/delete", repo.DeleteProtectedBranch)
 			}, repo.MustBeNotBare)
 
@@ -429,11 +516,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/gitea/new", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksNewPost)
 				m.Post("/gogs/new", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
 				m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
+				m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
+				m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
 				m.Get("/:id", repo.WebHooksEdit)
 				m.Post("/:id/test", repo.TestWebhook)
+				m.Post("/:id/deliveries/:taskid/redeliver", repo.RedeliverWebhookTask)
 				m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 				m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
 				m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
+				m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
+				m.Post("/msteams/:id", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
 
 				m.Group("/git", func() {
 					m.Get("", repo.GitHooks)
@@ -470,16 +562,23 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("/stopwatch", repo.IssueStopwatch)
 				m.Get("/cancel", repo.CancelStopwatch)
 				m.Combo("/comments").Post(bindIgnErr(auth.CreateCommentForm{}), repo.NewComment)
+				m.Post("/reactions/:action", bindIgnErr(auth.ReactionForm{}), repo.ChangeIssueReaction)
+				m.Group("/dependency", func() {
+					m.Post("/add", repo.AddDependency)
+					m.Post("/delete", repo.RemoveDependency)
+				}, reqRepoWriter)
 			})
 
 			m.Post("/labels", repo.UpdateIssueLabel, reqRepoWriter)
 			m.Post("/milestone", repo.UpdateIssueMilestone, reqRepoWriter)
 			m.Post("/assignee", repo.UpdateIssueAssignee, reqRepoWriter)
 			m.Post("/status", repo.UpdateIssueStatus, reqRepoWriter)
+			m.Post("/projects", reqRepoWriter, context.CheckUnit(models.UnitTypeProjects), repo.UpdateIssueProject)
 		}, context.CheckUnit(models.UnitTypeIssues))
 		m.Group("/comments/:id", func() {
 			m.Post("", repo.UpdateCommentContent)
 			m.Post("/delete", repo.DeleteComment)
+			m.Post("/reactions/:action", bindIgnErr(auth.ReactionForm{}), repo.ChangeCommentReaction)
 		}, context.CheckUnit(models.UnitTypeIssues))
 		m.Group("/labels", func() {
 			m.Post("/new", bindIgnErr(auth.CreateLabelForm{}), repo.NewLabel)
@@ -495,6 +594,20 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/:id/:action", repo.ChangeMilestonStatus)
 			m.Post("/delete", repo.DeleteMilestone)
 		}, reqRepoWriter, context.RepoRef(), context.CheckUnit(models.UnitTypeIssues))
+		m.Group("/projects", func() {
+			m.Combo("/new").Get(repo.NewProject).
+				Post(bindIgnErr(auth.CreateProjectForm{}), repo.NewProjectPost)
+			m.Post("/delete", repo.DeleteProject)
+			m.Group("/:id", func() {
+				m.Combo("/edit").Get(repo.EditProject).
+					Post(bindIgnErr(auth.CreateProjectForm{}), repo.EditProjectPost)
+				m.Post("/boards", bindIgnErr(auth.ProjectBoardForm{}), repo.AddBoardToProjectPost)
+				m.Post("/boards/:boardID/edit", bindIgnErr(auth.ProjectBoardForm{}), repo.EditProjectBoardPost)
+				m.Post("/boards/:boardID/delete", repo.DeleteProjectBoard)
+				m.Post("/move", repo.MoveProjectIssue)
+				m.Get("/:action", repo.ChangeProjectStatus)
+			})
+		}, reqRepoWriter, context.RepoRef(), context.CheckUnit(models.UnitTypeProjects))
 
 		m.Combo("/compare/*", repo.MustAllowPulls, repo.SetEditorconfigIfExists).
 			Get(repo.CompareAndPullRequest).
@@ -565,6 +678,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/milestones", repo.Milestones)
 		}, context.RepoRef())
 
+		m.Group("/projects", func() {
+			m.Get("", repo.Projects)
+			m.Get("/:id", repo.ViewProject)
+		}, context.RepoRef(), context.CheckUnit(models.UnitTypeProjects))
+
 		m.Group("/wiki", func() {
 			m.Get("/?:page", repo.Wiki)
 			m.Get("/_pages", repo.WikiPages)
@@ -587,11 +705,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 		m.Group("/pulls/:index", func() {
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
-			m.Get("/files", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.ViewPullFiles)
-			m.Post("/merge", reqRepoWriter, repo.MergePullRequest)
+			m.Group("/files", func() {
+				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.ViewPullFiles)
+				m.Group("/reviews", func() {
+					m.Post("/comments", bindIgnErr(auth.CodeCommentForm{}), repo.CreateCodeComment)
+					m.Post("/submit", bindIgnErr(auth.SubmitReviewForm{}), repo.SubmitReview)
+				}, reqSignIn)
+			})
+			m.Post("/merge", reqRepoWriter, bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cleanup", context.RepoRef(), repo.CleanUpPullRequest)
 		}, repo.MustAllowPulls, context.CheckUnit(models.UnitTypePullRequests))
 
+		if setting.Indexer.RepoIndexerEnabled {
+			m.Get("/search", repo.MustBeNotBare, context.CheckUnit(models.UnitTypeCode), repo.Search)
+		}
+
 		m.Group("", func() {
 			m.Get("/src/*", repo.SetEditorconfigIfExists, repo.Home)
 			m.Get("/raw/*", repo.SingleDownload)
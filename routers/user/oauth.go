@@ -0,0 +1,648 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Unknwon/com"
+	"github.com/dgrijalva/jwt-go"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplGrantAccess base.TplName = "user/auth/grant"
+	tplGrantError  base.TplName = "user/auth/grant_error"
+)
+
+// AuthorizeErrorCode represents an error code of an authorization request as specified in RFC 6749
+type AuthorizeErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest represents the according error in RFC 6749
+	ErrorCodeInvalidRequest AuthorizeErrorCode = "invalid_request"
+	// ErrorCodeUnauthorizedClient represents the according error in RFC 6749
+	ErrorCodeUnauthorizedClient AuthorizeErrorCode = "unauthorized_client"
+	// ErrorCodeAccessDenied represents the according error in RFC 6749
+	ErrorCodeAccessDenied AuthorizeErrorCode = "access_denied"
+	// ErrorCodeUnsupportedResponseType represents the according error in RFC 6749
+	ErrorCodeUnsupportedResponseType AuthorizeErrorCode = "unsupported_response_type"
+	// ErrorCodeInvalidScope represents the according error in RFC 6749
+	ErrorCodeInvalidScope AuthorizeErrorCode = "invalid_scope"
+	// ErrorCodeServerError represents the according error in RFC 6749
+	ErrorCodeServerError AuthorizeErrorCode = "server_error"
+)
+
+// AuthorizeError represents an error of an authorization request as specified in RFC 6749
+type AuthorizeError struct {
+	ErrorCode        AuthorizeErrorCode
+	ErrorDescription string
+	State            string
+}
+
+// Error returns the error message
+func (err AuthorizeError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// AccessTokenErrorCode represents an error code of a token request as specified in RFC 6749
+type AccessTokenErrorCode string
+
+const (
+	// AccessTokenErrorCodeInvalidRequest represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidRequest AccessTokenErrorCode = "invalid_request"
+	// AccessTokenErrorCodeInvalidClient represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidClient AccessTokenErrorCode = "invalid_client"
+	// AccessTokenErrorCodeInvalidGrant represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidGrant AccessTokenErrorCode = "invalid_grant"
+	// AccessTokenErrorCodeUnsupportedGrantType represents an error code specified in RFC 6749
+	AccessTokenErrorCodeUnsupportedGrantType AccessTokenErrorCode = "unsupported_grant_type"
+)
+
+// AccessTokenError represents an error response of a token request as specified in RFC 6749
+type AccessTokenError struct {
+	ErrorCode        AccessTokenErrorCode `json:"error"`
+	ErrorDescription string               `json:"error_description"`
+}
+
+// Error returns the error message
+func (err AccessTokenError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// AccessTokenResponse represents a successful response of a token request as specified in RFC 6749
+type AccessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// oauth2Issuer returns the issuer identifier used in ID tokens and the discovery document
+func oauth2Issuer() string {
+	return strings.TrimSuffix(setting.AppURL, "/")
+}
+
+func newAccessTokenResponse(grant *models.OAuth2Grant, clientSecret string) (*AccessTokenResponse, *AccessTokenError) {
+	if setting.OAuth2.InvalidateRefreshTokens {
+		if err := grant.IncreaseCounter(); err != nil {
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+				ErrorDescription: "cannot increase the grant counter",
+			}
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(setting.OAuth2.AccessTokenExpirationTime) * time.Second)
+	accessToken := &oauth2.Token{
+		GrantID: grant.ID,
+		Type:    oauth2.TypeAccessToken,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	signedAccessToken, err := accessToken.SignToken()
+	if err != nil {
+		log.Error(4, "SignToken: %v", err)
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot sign token",
+		}
+	}
+
+	refreshToken := &oauth2.Token{
+		GrantID: grant.ID,
+		Type:    oauth2.TypeRefreshToken,
+		Counter: grant.Counter,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: now.Add(time.Duration(setting.OAuth2.RefreshTokenExpirationTime) * time.Hour).Unix(),
+		},
+	}
+	signedRefreshToken, err := refreshToken.SignToken()
+	if err != nil {
+		log.Error(4, "SignToken: %v", err)
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot sign token",
+		}
+	}
+
+	// generate the OpenID Connect ID token
+	var signedIDToken string
+	if grant.ScopeContains("openid") {
+		signedIDToken, err = newIDToken(grant, clientSecret, now, expiresAt)
+		if err != nil {
+			log.Error(4, "newIDToken: %v", err)
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot generate id token",
+			}
+		}
+	}
+
+	return &AccessTokenResponse{
+		AccessToken:  signedAccessToken,
+		TokenType:    "bearer",
+		ExpiresIn:    setting.OAuth2.AccessTokenExpirationTime,
+		RefreshToken: signedRefreshToken,
+		IDToken:      signedIDToken,
+	}, nil
+}
+
+// newIDToken returns a signed OpenID Connect ID token for the user of the grant
+func newIDToken(grant *models.OAuth2Grant, clientSecret string, issuedAt, expiresAt time.Time) (string, error) {
+	if err := grant.LoadApplication(); err != nil {
+		return "", err
+	}
+	user, err := models.GetUserByID(grant.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	idToken := &oauth2.OIDCToken{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    oauth2Issuer(),
+			Audience:  grant.Application.ClientID,
+			Subject:   fmt.Sprint(grant.UserID),
+			IssuedAt:  issuedAt.Unix(),
+		},
+		Nonce: grant.Nonce,
+	}
+	if grant.ScopeContains("profile") {
+		idToken.Name = user.FullName
+		idToken.PreferredUsername = user.Name
+		idToken.Profile = user.HTMLURL()
+		idToken.Picture = user.AvatarLink()
+		idToken.Website = user.Website
+		idToken.UpdatedAt = user.UpdatedUnix
+	}
+	if grant.ScopeContains("email") {
+		idToken.Email = user.Email
+		idToken.EmailVerified = user.IsActive
+	}
+	return idToken.SignToken([]byte(clientSecret))
+}
+
+// mergeScopes returns the scopes of a and the scopes of b which are not part of a
+func mergeScopes(a, b string) string {
+	scopes := strings.Fields(a)
+	for _, scope := range strings.Fields(b) {
+		if !com.IsSliceContainsStr(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// grantCoversScope checks if all requested scopes have already been granted
+func grantCoversScope(grant *models.OAuth2Grant, scope string) bool {
+	for _, s := range strings.Fields(scope) {
+		if !grant.ScopeContains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// AuthorizeOAuth manages authorize requests of OAuth2 clients
+func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if models.IsErrOAuth2ApplicationNotExist(err) {
+			handleServerError(ctx, form.State, ctx.Tr("auth.authorize_application_not_exist"))
+			return
+		}
+		ctx.Handle(500, "GetOAuth2ApplicationByClientID", err)
+		return
+	}
+
+	// The redirect URI can only be omitted if the application has registered exactly one.
+	if len(form.RedirectURI) == 0 && len(app.RedirectURIs) == 1 {
+		form.RedirectURI = app.PrimaryRedirectURI()
+	}
+	// Never redirect to an URI which has not been registered for the application.
+	if !app.ContainsRedirectURI(form.RedirectURI) {
+		handleServerError(ctx, form.State, ctx.Tr("auth.authorize_redirect_uri_not_registered"))
+		return
+	}
+
+	if form.ResponseType != "code" {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeUnsupportedResponseType,
+			ErrorDescription: "Only code response type is supported.",
+			State:            form.State,
+		}, form.RedirectURI)
+		return
+	}
+
+	// PKCE support
+	switch form.CodeChallengeMethod {
+	case "S256", "plain":
+	case "":
+		// the method defaults to plain if only a challenge is given (RFC 7636)
+		if len(form.CodeChallenge) > 0 {
+			form.CodeChallengeMethod = "plain"
+		}
+	default:
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: "Unsupported code challenge method.",
+			State:            form.State,
+		}, form.RedirectURI)
+		return
+	}
+
+	grant, err := app.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, err.Error())
+		return
+	}
+
+	// Skip the consent page if the user has already granted all requested scopes.
+	if grant != nil && grantCoversScope(grant, form.Scope) {
+		if err = grant.UpdateScope(grant.Scope, form.Nonce); err != nil {
+			handleServerError(ctx, form.State, err.Error())
+			return
+		}
+		code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, form.CodeChallenge, form.CodeChallengeMethod)
+		if err != nil {
+			handleServerError(ctx, form.State, err.Error())
+			return
+		}
+		redirect, err := code.GenerateRedirectURI(form.State)
+		if err != nil {
+			handleServerError(ctx, form.State, err.Error())
+			return
+		}
+		ctx.Redirect(redirect.String(), 302)
+		return
+	}
+
+	if err = app.LoadUser(); err != nil {
+		ctx.Handle(500, "LoadUser", err)
+		return
+	}
+
+	deny, err := url.Parse(form.RedirectURI)
+	if err != nil {
+		ctx.Handle(500, "Parse", err)
+		return
+	}
+	q := deny.Query()
+	q.Set("error", string(ErrorCodeAccessDenied))
+	if len(form.State) > 0 {
+		q.Set("state", form.State)
+	}
+	deny.RawQuery = q.Encode()
+
+	ctx.Data["Title"] = ctx.Tr("auth.authorize_title", app.Name)
+	ctx.Data["Application"] = app
+	ctx.Data["RedirectURI"] = form.RedirectURI
+	ctx.Data["DenyLink"] = deny.String()
+	ctx.Data["State"] = form.State
+	ctx.Data["Scopes"] = strings.Fields(form.Scope)
+
+	// Remember the parameters which must not be changed by the consent form.
+	ctx.Session.Set("client_id", app.ClientID)
+	ctx.Session.Set("redirect_uri", form.RedirectURI)
+	ctx.Session.Set("state", form.State)
+	ctx.Session.Set("scope", form.Scope)
+	ctx.Session.Set("nonce", form.Nonce)
+	ctx.Session.Set("CodeChallengeMethod", form.CodeChallengeMethod)
+	ctx.Session.Set("CodeChallenge", form.CodeChallenge)
+	ctx.HTML(200, tplGrantAccess)
+}
+
+// GrantApplicationOAuth manages the post request submitted when a user grants access to an application
+func GrantApplicationOAuth(ctx *context.Context, form auth.GrantApplicationForm) {
+	if ctx.Session.Get("client_id") != form.ClientID || ctx.Session.Get("state") != form.State ||
+		ctx.Session.Get("redirect_uri") != form.RedirectURI {
+		ctx.Handle(400, "GrantApplicationOAuth", fmt.Errorf("session does not match the authorization request"))
+		return
+	}
+
+	// The scope and nonce are taken from the authorization request the user has
+	// been asked about, the client must not be able to change them.
+	scope, _ := ctx.Session.Get("scope").(string)
+	nonce, _ := ctx.Session.Get("nonce").(string)
+
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetOAuth2ApplicationByClientID", models.IsErrOAuth2ApplicationNotExist, err)
+		return
+	}
+
+	grant, err := app.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, err.Error())
+		return
+	}
+	if grant == nil {
+		grant, err = app.CreateGrant(ctx.User.ID, scope)
+		if err != nil {
+			handleAuthorizeError(ctx, AuthorizeError{
+				ErrorCode:        ErrorCodeServerError,
+				ErrorDescription: "cannot create grant for user",
+				State:            form.State,
+			}, form.RedirectURI)
+			return
+		}
+	}
+	if err = grant.UpdateScope(mergeScopes(grant.Scope, scope), nonce); err != nil {
+		handleServerError(ctx, form.State, err.Error())
+		return
+	}
+
+	var codeChallenge, codeChallengeMethod string
+	if challenge, ok := ctx.Session.Get("CodeChallenge").(string); ok {
+		codeChallenge = challenge
+	}
+	if method, ok := ctx.Session.Get("CodeChallengeMethod").(string); ok {
+		codeChallengeMethod = method
+	}
+
+	code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, codeChallenge, codeChallengeMethod)
+	if err != nil {
+		handleServerError(ctx, form.State, err.Error())
+		return
+	}
+	redirect, err := code.GenerateRedirectURI(form.State)
+	if err != nil {
+		handleServerError(ctx, form.State, err.Error())
+		return
+	}
+	ctx.Redirect(redirect.String(), 302)
+}
+
+// AccessTokenOAuth manages all access token requests by the client
+func AccessTokenOAuth(ctx *context.Context, form auth.AccessTokenForm) {
+	// The client credentials may also be passed by HTTP basic authentication.
+	if clientID, clientSecret, ok := ctx.Req.Request.BasicAuth(); ok {
+		if len(form.ClientID) == 0 {
+			form.ClientID = clientID
+		}
+		if len(form.ClientSecret) == 0 {
+			form.ClientSecret = clientSecret
+		}
+	}
+
+	switch form.GrantType {
+	case "refresh_token":
+		handleRefreshToken(ctx, form)
+	case "authorization_code":
+		handleAuthorizationCode(ctx, form)
+	default:
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnsupportedGrantType,
+			ErrorDescription: "Only refresh_token or authorization_code grant type is supported",
+		})
+	}
+}
+
+// validateOAuth2Client returns the application identified and authenticated by the client credentials
+func validateOAuth2Client(form auth.AccessTokenForm) (*models.OAuth2Application, *AccessTokenError) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+		}
+	}
+	if !app.ValidateClientSecret(form.ClientSecret) {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: "client is not authorized",
+		}
+	}
+	return app, nil
+}
+
+func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
+	app, tokenErr := validateOAuth2Client(form)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+
+	token, err := oauth2.ParseToken(form.RefreshToken)
+	if err != nil || token.Type != oauth2.TypeRefreshToken {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "refresh token is invalid",
+		})
+		return
+	}
+
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil || grant.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "grant does not exist",
+		})
+		return
+	}
+
+	// check if the refresh token has already been used
+	if setting.OAuth2.InvalidateRefreshTokens && grant.Counter != token.Counter {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "refresh token has already been used",
+		})
+		return
+	}
+
+	resp, tokenErr := newAccessTokenResponse(grant, form.ClientSecret)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
+	app, tokenErr := validateOAuth2Client(form)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+
+	authorizationCode, err := models.GetOAuth2AuthorizationByCode(form.Code)
+	if err != nil || authorizationCode.IsExpired() || authorizationCode.Grant.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "authorization code is invalid",
+		})
+		return
+	}
+	if authorizationCode.RedirectURI != form.RedirectURI {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "redirect URI does not match the authorization request",
+		})
+		return
+	}
+	if !authorizationCode.ValidateCodeChallenge(form.CodeVerifier) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "failed PKCE code challenge",
+		})
+		return
+	}
+
+	// an authorization code can only be used once
+	if err = authorizationCode.Invalidate(); models.IsErrOAuth2AuthorizationCodeNotExist(err) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "authorization code is invalid",
+		})
+		return
+	} else if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot proceed your request",
+		})
+		return
+	}
+
+	resp, tokenErr := newAccessTokenResponse(authorizationCode.Grant, form.ClientSecret)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+// userInfoResponse represents the claims returned by the OpenID Connect userinfo endpoint
+type userInfoResponse struct {
+	Sub               string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+}
+
+// InfoOAuth returns the claims of the user an OAuth2 access token has been issued for
+func InfoOAuth(ctx *context.Context) {
+	var accessToken string
+	auths := strings.Fields(ctx.Req.Header.Get("Authorization"))
+	if len(auths) == 2 && strings.ToLower(auths[0]) == "bearer" {
+		accessToken = auths[1]
+	}
+
+	grant := auth.CheckOAuthAccessToken(accessToken)
+	if grant == nil {
+		ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm=""`)
+		ctx.Status(401)
+		return
+	}
+
+	user, err := models.GetUserByID(grant.UserID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetUserByID", models.IsErrUserNotExist, err)
+		return
+	}
+
+	// Only return the claims of the scopes the user has granted.
+	info := &userInfoResponse{
+		Sub: fmt.Sprint(user.ID),
+	}
+	if grant.ScopeContains("profile") {
+		info.Name = user.FullName
+		info.PreferredUsername = user.Name
+		info.Picture = user.AvatarLink()
+	}
+	if grant.ScopeContains("email") {
+		info.Email = user.Email
+	}
+	ctx.JSON(200, info)
+}
+
+// oidcDiscoveryResponse represents the OpenID Connect discovery document
+type oidcDiscoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// OIDCWellKnown generates the JSON document for the OpenID Connect discovery
+func OIDCWellKnown(ctx *context.Context) {
+	scopes := []string{"openid", "profile", "email", models.AccessTokenScopeAll, models.AccessTokenScopeDeleteRepo}
+	for _, area := range models.AccessTokenScopeAreas {
+		scopes = append(scopes,
+			models.AccessTokenScopeLevelRead.Scope(area),
+			models.AccessTokenScopeLevelWrite.Scope(area))
+	}
+
+	ctx.JSON(200, &oidcDiscoveryResponse{
+		Issuer:                            oauth2Issuer(),
+		AuthorizationEndpoint:             setting.AppURL + "login/oauth/authorize",
+		TokenEndpoint:                     setting.AppURL + "login/oauth/access_token",
+		UserinfoEndpoint:                  setting.AppURL + "login/oauth/userinfo",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"HS256"},
+		ScopesSupported:                   scopes,
+		ClaimsSupported:                   []string{"aud", "exp", "iat", "iss", "sub", "nonce", "name", "preferred_username", "profile", "picture", "website", "updated_at", "email", "email_verified"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{"plain", "S256"},
+	})
+}
+
+func handleAccessTokenError(ctx *context.Context, acErr AccessTokenError) {
+	ctx.JSON(400, acErr)
+}
+
+// handleServerError renders an error page for errors which must not be reported to the redirect URI
+func handleServerError(ctx *context.Context, state, message string) {
+	ctx.Data["Title"] = ctx.Tr("auth.authorize_error")
+	ctx.Data["State"] = state
+	ctx.Data["ErrorDescription"] = message
+	ctx.HTML(400, tplGrantError)
+}
+
+// handleAuthorizeError reports the error to the client by redirecting to its redirect URI
+func handleAuthorizeError(ctx *context.Context, authErr AuthorizeError, redirectURI string) {
+	if redirectURI == "" {
+		log.Warn("Authorization failed: %v", authErr)
+		handleServerError(ctx, authErr.State, authErr.ErrorDescription)
+		return
+	}
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.Handle(500, "Parse", err)
+		return
+	}
+	q := redirect.Query()
+	q.Set("error", string(authErr.ErrorCode))
+	q.Set("error_description", authErr.ErrorDescription)
+	if len(authErr.State) > 0 {
+		q.Set("state", authErr.State)
+	}
+	redirect.RawQuery = q.Encode()
+	ctx.Redirect(redirect.String(), 302)
+}
@@ -23,7 +23,7 @@ const (
 	tplStars     base.TplName = "user/meta/stars"
 )
 
-// GetUserByName get user by name
+// GetUserByName get user by name, it is not found if the signed user can not see it
 func GetUserByName(ctx *context.Context, name string) *models.User {
 	user, err := models.GetUserByName(name)
 	if err != nil {
@@ -33,6 +33,9 @@ func GetUserByName(ctx *context.Context, name string) *models.User {
 			ctx.Handle(500, "GetUserByName", err)
 		}
 		return nil
+	} else if !user.IsVisibleTo(ctx.User) {
+		ctx.Handle(404, "GetUserByName", nil)
+		return nil
 	}
 	return user
 }
@@ -93,6 +96,15 @@ func Profile(ctx *context.Context) {
 		ctx.Handle(500, "GetOrgsByUserIDDesc", err)
 		return
 	}
+	if !showPrivate {
+		visibleOrgs := make([]*models.User, 0, len(orgs))
+		for _, org := range orgs {
+			if org.IsVisibleTo(ctx.User) {
+				visibleOrgs = append(visibleOrgs, org)
+			}
+		}
+		orgs = visibleOrgs
+	}
 
 	ctx.Data["Orgs"] = orgs
 
@@ -10,8 +10,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
-	"github.com/Unknwon/com"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 
@@ -105,6 +105,9 @@ func SettingsPost(ctx *context.Context, form auth.UpdateProfileForm) {
 	ctx.User.KeepEmailPrivate = form.KeepEmailPrivate
 	ctx.User.Website = form.Website
 	ctx.User.Location = form.Location
+	if len(form.Visibility) > 0 {
+		ctx.User.Visibility, _ = models.ParseVisibleType(form.Visibility)
+	}
 	if err := models.UpdateUserSetting(ctx.User); err != nil {
 		if _, ok := err.(models.ErrEmailAlreadyUsed); ok {
 			ctx.Flash.Error(ctx.Tr("form.email_been_used"))
@@ -149,7 +152,7 @@ func UpdateAvatarSetting(ctx *context.Context, form auth.AvatarForm, ctxUser *mo
 	} else {
 		// No avatar is uploaded but setting has been changed to enable,
 		// generate a random one when needed.
-		if ctxUser.UseCustomAvatar && !com.IsFile(ctxUser.CustomAvatarPath()) {
+		if ctxUser.UseCustomAvatar && !ctxUser.HasAvatarFile() {
 			if err := ctxUser.GenerateRandomAvatar(); err != nil {
 				log.Error(4, "GenerateRandomAvatar[%d]: %v", ctxUser.ID, err)
 			}
@@ -449,17 +452,39 @@ func DeleteKey(ctx *context.Context) {
 	})
 }
 
+// loadApplicationsData loads the access tokens, the OAuth2 applications
+// and the OAuth2 grants of the user into ctx.Data
+func loadApplicationsData(ctx *context.Context) bool {
+	tokens, err := models.ListAccessTokens(ctx.User.ID)
+	if err != nil {
+		ctx.Handle(500, "ListAccessTokens", err)
+		return false
+	}
+	ctx.Data["Tokens"] = tokens
+
+	if setting.OAuth2.Enable {
+		if !userOAuth2Handlers(ctx).RenderApplications(ctx) {
+			return false
+		}
+		grants, err := models.GetOAuth2GrantsByUserID(ctx.User.ID)
+		if err != nil {
+			ctx.Handle(500, "GetOAuth2GrantsByUserID", err)
+			return false
+		}
+		ctx.Data["OAuth2Grants"] = grants
+	}
+	return true
+}
+
 // SettingsApplications render user's access tokens page
 func SettingsApplications(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("settings")
 	ctx.Data["PageIsSettingsApplications"] = true
+	ctx.Data["ScopeAreas"] = models.AccessTokenScopeAreas
 
-	tokens, err := models.ListAccessTokens(ctx.User.ID)
-	if err != nil {
-		ctx.Handle(500, "ListAccessTokens", err)
+	if !loadApplicationsData(ctx) {
 		return
 	}
-	ctx.Data["Tokens"] = tokens
 
 	ctx.HTML(200, tplSettingsApplications)
 }
@@ -468,22 +493,40 @@ func SettingsApplications(ctx *context.Context) {
 func SettingsApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
 	ctx.Data["Title"] = ctx.Tr("settings")
 	ctx.Data["PageIsSettingsApplications"] = true
+	ctx.Data["ScopeAreas"] = models.AccessTokenScopeAreas
+
+	if !loadApplicationsData(ctx) {
+		return
+	}
 
 	if ctx.HasError() {
-		tokens, err := models.ListAccessTokens(ctx.User.ID)
-		if err != nil {
-			ctx.Handle(500, "ListAccessTokens", err)
-			return
-		}
-		ctx.Data["Tokens"] = tokens
 		ctx.HTML(200, tplSettingsApplications)
 		return
 	}
 
+	var err error
 	t := &models.AccessToken{
 		UID:  ctx.User.ID,
 		Name: form.Name,
 	}
+	if t.Scopes, err = models.NormalizeAccessTokenScopes(form.Scopes); err != nil {
+		ctx.Data["Err_Scopes"] = true
+		ctx.RenderWithErr(ctx.Tr("settings.token_scopes_required"), tplSettingsApplications, &form)
+		return
+	}
+	if t.RepoIDs, err = models.GetReadableRepoIDsByFullNames(ctx.User, splitRepoNames(form.Repositories)); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Data["Err_Repositories"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.token_repository_not_exist", err.(models.ErrRepoNotExist).Name), tplSettingsApplications, &form)
+		} else {
+			ctx.Handle(500, "GetReadableRepoIDsByFullNames", err)
+		}
+		return
+	}
+	if form.ExpiresInDays > 0 {
+		t.ExpiresUnix = time.Now().AddDate(0, 0, form.ExpiresInDays).Unix()
+	}
+
 	if err := models.NewAccessToken(t); err != nil {
 		ctx.Handle(500, "NewAccessToken", err)
 		return
@@ -495,6 +538,13 @@ func SettingsApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm
 	ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
 }
 
+// splitRepoNames splits a comma or space separated list of repository full names
+func splitRepoNames(names string) []string {
+	return strings.FieldsFunc(names, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
 // SettingsDeleteApplication response for delete user access token
 func SettingsDeleteApplication(ctx *context.Context) {
 	if err := models.DeleteAccessTokenByID(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
@@ -0,0 +1,205 @@
+// Copyright 2017 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsOAuthApplicationEdit base.TplName = "user/settings/applications_oauth2_edit"
+)
+
+// OAuth2CommonHandlers implements the OAuth2 application settings shared by users and organizations
+type OAuth2CommonHandlers struct {
+	OwnerID            int64        // user or organization owning the applications
+	BasePathList       string       // path to the list of applications
+	BasePathEditPrefix string       // path prefix of the edit pages of the applications
+	TemplateEdit       base.TplName // template of the edit page
+}
+
+// splitRedirectURIs splits a newline or space separated list of redirect URIs
+func splitRedirectURIs(uris string) []string {
+	return strings.Fields(uris)
+}
+
+// RenderApplications loads the applications of the owner into ctx.Data
+func (oa *OAuth2CommonHandlers) RenderApplications(ctx *context.Context) bool {
+	apps, err := models.GetOAuth2ApplicationsByUserID(oa.OwnerID)
+	if err != nil {
+		ctx.Handle(500, "GetOAuth2ApplicationsByUserID", err)
+		return false
+	}
+	ctx.Data["OAuth2Applications"] = apps
+	ctx.Data["OAuth2BasePathList"] = oa.BasePathList
+	ctx.Data["OAuth2BasePathEditPrefix"] = oa.BasePathEditPrefix
+	return true
+}
+
+// AddApp adds an OAuth2 application and shows its client secret
+func (oa *OAuth2CommonHandlers) AddApp(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(oa.BasePathList)
+		return
+	}
+
+	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
+		Name:         form.Name,
+		UserID:       oa.OwnerID,
+		RedirectURIs: splitRedirectURIs(form.RedirectURIs),
+	})
+	if err != nil {
+		ctx.Handle(500, "CreateOAuth2Application", err)
+		return
+	}
+	oa.renderAppWithSecret(ctx, app, ctx.Tr("settings.create_oauth2_application_success"))
+}
+
+// renderAppWithSecret generates a new client secret and renders the edit page
+// which is the only place the secret is shown in clear text.
+func (oa *OAuth2CommonHandlers) renderAppWithSecret(ctx *context.Context, app *models.OAuth2Application, msg string) {
+	secret, err := app.GenerateClientSecret()
+	if err != nil {
+		ctx.Handle(500, "GenerateClientSecret", err)
+		return
+	}
+	ctx.Data["App"] = app
+	ctx.Data["ClientSecret"] = secret
+	ctx.Data["OAuth2BasePathList"] = oa.BasePathList
+	ctx.Data["OAuth2BasePathEditPrefix"] = oa.BasePathEditPrefix
+	ctx.Flash.Success(msg, true)
+	ctx.HTML(200, oa.TemplateEdit)
+}
+
+// getApp returns the application of the owner given by :id
+func (oa *OAuth2CommonHandlers) getApp(ctx *context.Context) *models.OAuth2Application {
+	app, err := models.GetOAuth2ApplicationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetOAuth2ApplicationByID", models.IsErrOAuth2ApplicationNotExist, err)
+		return nil
+	}
+	if app.UID != oa.OwnerID {
+		ctx.Handle(404, "getApp", fmt.Errorf("application %d does not belong to owner %d", app.ID, oa.OwnerID))
+		return nil
+	}
+	ctx.Data["App"] = app
+	ctx.Data["OAuth2BasePathList"] = oa.BasePathList
+	ctx.Data["OAuth2BasePathEditPrefix"] = oa.BasePathEditPrefix
+	return app
+}
+
+// EditShow displays the edit page of an OAuth2 application
+func (oa *OAuth2CommonHandlers) EditShow(ctx *context.Context) {
+	if oa.getApp(ctx) == nil {
+		return
+	}
+	ctx.HTML(200, oa.TemplateEdit)
+}
+
+// EditSave saves the changes of an OAuth2 application
+func (oa *OAuth2CommonHandlers) EditSave(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	app := oa.getApp(ctx)
+	if app == nil {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(200, oa.TemplateEdit)
+		return
+	}
+
+	app.Name = form.Name
+	app.RedirectURIs = splitRedirectURIs(form.RedirectURIs)
+	if err := models.UpdateOAuth2Application(app); err != nil {
+		ctx.Handle(500, "UpdateOAuth2Application", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("settings.update_oauth2_application_success"))
+	ctx.Redirect(fmt.Sprintf("%s/%d", oa.BasePathEditPrefix, app.ID))
+}
+
+// RegenerateSecret replaces the client secret of an OAuth2 application
+func (oa *OAuth2CommonHandlers) RegenerateSecret(ctx *context.Context) {
+	app := oa.getApp(ctx)
+	if app == nil {
+		return
+	}
+	oa.renderAppWithSecret(ctx, app, ctx.Tr("settings.update_oauth2_application_success"))
+}
+
+// DeleteApp deletes an OAuth2 application
+func (oa *OAuth2CommonHandlers) DeleteApp(ctx *context.Context) {
+	if err := models.DeleteOAuth2Application(ctx.QueryInt64("id"), oa.OwnerID); err != nil {
+		ctx.Flash.Error("DeleteOAuth2Application: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.remove_oauth2_application_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": oa.BasePathList,
+	})
+}
+
+func userOAuth2Handlers(ctx *context.Context) *OAuth2CommonHandlers {
+	return &OAuth2CommonHandlers{
+		OwnerID:            ctx.User.ID,
+		BasePathList:       setting.AppSubURL + "/user/settings/applications",
+		BasePathEditPrefix: setting.AppSubURL + "/user/settings/applications/oauth2",
+		TemplateEdit:       tplSettingsOAuthApplicationEdit,
+	}
+}
+
+// SettingsOAuthApplicationsPost creates an OAuth2 application of the user
+func SettingsOAuthApplicationsPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2Handlers(ctx).AddApp(ctx, form)
+}
+
+// SettingsOAuthApplicationEdit renders the edit page of an OAuth2 application of the user
+func SettingsOAuthApplicationEdit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2Handlers(ctx).EditShow(ctx)
+}
+
+// SettingsOAuthApplicationEditPost saves an OAuth2 application of the user
+func SettingsOAuthApplicationEditPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2Handlers(ctx).EditSave(ctx, form)
+}
+
+// SettingsOAuthApplicationRegenerateSecret regenerates the client secret of an OAuth2 application of the user
+func SettingsOAuthApplicationRegenerateSecret(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+	userOAuth2Handlers(ctx).RegenerateSecret(ctx)
+}
+
+// SettingsDeleteOAuthApplication deletes an OAuth2 application of the user
+func SettingsDeleteOAuthApplication(ctx *context.Context) {
+	userOAuth2Handlers(ctx).DeleteApp(ctx)
+}
+
+// SettingsRevokeOAuthGrant revokes the authorization the user granted to an OAuth2 application
+func SettingsRevokeOAuthGrant(ctx *context.Context) {
+	if err := models.RevokeOAuth2Grant(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
+		ctx.Flash.Error("RevokeOAuth2Grant: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.revoke_oauth2_grant_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/applications",
+	})
+}